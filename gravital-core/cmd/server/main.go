@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"flag"
 	"fmt"
 	"net/http"
@@ -14,11 +15,14 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/celestial/gravital-core/internal/alert/engine"
+	"github.com/celestial/gravital-core/internal/alert/escalation"
 	"github.com/celestial/gravital-core/internal/api/router"
+	"github.com/celestial/gravital-core/internal/notification"
 	"github.com/celestial/gravital-core/internal/pkg/cache"
 	"github.com/celestial/gravital-core/internal/pkg/config"
 	"github.com/celestial/gravital-core/internal/pkg/database"
 	"github.com/celestial/gravital-core/internal/pkg/logger"
+	"github.com/celestial/gravital-core/internal/pkg/pki"
 	"github.com/celestial/gravital-core/internal/repository"
 	"github.com/celestial/gravital-core/internal/service"
 )
@@ -94,8 +98,24 @@ func main() {
 	// 设置 Gin 模式
 	gin.SetMode(cfg.Server.Mode)
 
+	// 加载 Sentinel 客户端证书 CA
+	var sentinelCA *pki.CA
+	if cfg.Sentinel.MTLS.Enabled {
+		sentinelCA, err = pki.LoadOrCreateCA(cfg.Sentinel.MTLS.CACertFile, cfg.Sentinel.MTLS.CAKeyFile)
+		if err != nil {
+			logger.Fatal("Failed to load sentinel CA", zap.Error(err))
+		}
+		logger.Info("Sentinel mTLS enabled",
+			zap.String("ca_cert_file", cfg.Sentinel.MTLS.CACertFile),
+			zap.Bool("required", cfg.Sentinel.MTLS.Required))
+	}
+
+	// 初始化通知服务，告警引擎、升级处理和 API 共用同一个发送队列
+	notificationSvc := notification.NewService(db, logger.Get())
+	registerNotificationChannels(notificationSvc, &cfg.Notification)
+
 	// 创建路由
-	r, forwarderService := router.Setup(cfg, db)
+	r, forwarderService := router.Setup(cfg, db, sentinelCA, notificationSvc)
 
 	// 启动转发服务
 	logger.Info("Starting forwarder service...")
@@ -146,12 +166,20 @@ func main() {
 	}
 
 	alertEngine := engine.NewAlertEngine(db, logger.Get(), &engine.Config{
-		VMURL:         vmURL,
-		CheckInterval: 30 * time.Second, // 每 30 秒检查一次
+		VMURL:           vmURL,
+		CheckInterval:   30 * time.Second, // 每 30 秒检查一次
+		NotificationSvc: notificationSvc,
 	})
 	alertEngine.Start()
 	logger.Info("Alert engine started")
 
+	// 启动告警升级处理
+	escalationWorker := escalation.NewWorker(db, logger.Get(), &escalation.Config{
+		CheckInterval:   30 * time.Second,
+		NotificationSvc: notificationSvc,
+	})
+	escalationWorker.Start()
+
 	// 创建 HTTP 服务器
 	srv := &http.Server{
 		Addr:           cfg.Server.GetAddr(),
@@ -161,10 +189,28 @@ func main() {
 		MaxHeaderBytes: cfg.Server.MaxHeaderBytes,
 	}
 
+	// 启用 mTLS 时请求客户端证书并用 Sentinel CA 校验，Web 界面等不带证书的请求不受影响
+	if sentinelCA != nil {
+		srv.TLSConfig = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			ClientAuth: tls.VerifyClientCertIfGiven,
+			ClientCAs:  sentinelCA.Pool(),
+		}
+	}
+
 	// 启动服务器
 	go func() {
-		logger.Info("Server starting", zap.String("addr", srv.Addr))
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		logger.Info("Server starting",
+			zap.String("addr", srv.Addr),
+			zap.Bool("tls", cfg.Server.TLS.Enabled))
+
+		var err error
+		if cfg.Server.TLS.Enabled {
+			err = srv.ListenAndServeTLS(cfg.Server.TLS.CertFile, cfg.Server.TLS.KeyFile)
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			logger.Fatal("Failed to start server", zap.Error(err))
 		}
 	}()
@@ -184,6 +230,13 @@ func main() {
 	logger.Info("Stopping alert engine...")
 	alertEngine.Stop()
 
+	// 停止告警升级处理
+	escalationWorker.Stop()
+
+	// 停止通知投递
+	logger.Info("Stopping notification service...")
+	notificationSvc.Stop()
+
 	// 停止设备监控服务
 	logger.Info("Stopping device monitor...")
 	deviceMonitor.Stop()
@@ -204,3 +257,58 @@ func main() {
 
 	logger.Info("Server exited")
 }
+
+// registerNotificationChannels 注册配置了地址的通知渠道
+func registerNotificationChannels(svc notification.Service, cfg *config.NotificationConfig) {
+	log := logger.Get()
+	register := func(channel notification.Channel, sender notification.Sender) {
+		if err := svc.RegisterChannel(channel, sender); err != nil {
+			logger.Error("Failed to register notification channel",
+				zap.String("channel", string(channel)), zap.Error(err))
+		}
+	}
+
+	if cfg.Email.SMTPHost != "" {
+		register(notification.ChannelEmail, notification.NewEmailSender(&notification.EmailConfig{
+			SMTPHost:     cfg.Email.SMTPHost,
+			SMTPPort:     cfg.Email.SMTPPort,
+			SMTPUser:     cfg.Email.SMTPUser,
+			SMTPPassword: cfg.Email.SMTPPassword,
+			From:         cfg.Email.From,
+			UseTLS:       cfg.Email.UseTLS,
+		}, log))
+	}
+	if cfg.DingTalk.WebhookURL != "" {
+		register(notification.ChannelDingTalk, notification.NewDingTalkSender(&notification.DingTalkConfig{
+			WebhookURL: cfg.DingTalk.WebhookURL,
+			Secret:     cfg.DingTalk.Secret,
+			AtMobiles:  cfg.DingTalk.AtMobiles,
+			AtAll:      cfg.DingTalk.AtAll,
+		}, log))
+	}
+	if cfg.WeChat.WebhookURL != "" {
+		register(notification.ChannelWeChat, notification.NewWeChatSender(&notification.WeChatConfig{
+			WebhookURL:          cfg.WeChat.WebhookURL,
+			MentionedList:       cfg.WeChat.MentionedList,
+			MentionedMobileList: cfg.WeChat.MentionedMobileList,
+		}, log))
+	}
+	if cfg.Webhook.URL != "" {
+		register(notification.ChannelWebhook, notification.NewWebhookSender(&notification.WebhookConfig{
+			URL:     cfg.Webhook.URL,
+			Method:  cfg.Webhook.Method,
+			Headers: cfg.Webhook.Headers,
+			Timeout: cfg.Webhook.Timeout,
+		}, log))
+	}
+	if cfg.Alertmanager.URL != "" {
+		register(notification.ChannelAlertmanager, notification.NewAlertmanagerSender(&notification.AlertmanagerConfig{
+			URL:          cfg.Alertmanager.URL,
+			Username:     cfg.Alertmanager.Username,
+			Password:     cfg.Alertmanager.Password,
+			BearerToken:  cfg.Alertmanager.BearerToken,
+			GeneratorURL: cfg.Alertmanager.GeneratorURL,
+			Timeout:      cfg.Alertmanager.Timeout,
+		}, log))
+	}
+}
@@ -0,0 +1,147 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// ErrInvalidCondition 告警条件无效
+var ErrInvalidCondition = errors.New("invalid condition")
+
+// ConditionMode 条件模式
+type ConditionMode string
+
+const (
+	// ConditionModeSimple 简单模式：metric op threshold，选择器由 Filters 构造
+	ConditionModeSimple ConditionMode = "simple"
+	// ConditionModeExpression 表达式模式：完整的 PromQL/MetricsQL 表达式，原样发送给 VM
+	ConditionModeExpression ConditionMode = "expression"
+)
+
+// Condition 解析后的告警条件
+type Condition struct {
+	Mode       ConditionMode
+	Query      string // 比较运算符左侧的查询表达式
+	MetricName string // 表达式中的指标名（用于告警事件展示）
+	Operator   string
+	Threshold  float64
+}
+
+// ParseCondition 解析告警条件
+// 条件的顶层必须是 "<expr> <op> <number>" 形式，例如：
+//
+//	device_status != 0
+//	rate(if_in_errors[5m]) > 10
+//	avg by (device_id) (ping_rtt_ms offset 5m) > 200
+//
+// 左侧仅为裸指标名时使用简单模式（兼容 Filters），否则为表达式模式。
+// 比较在引擎内完成，这样不满足条件的序列也能用于恢复告警。
+func ParseCondition(condition string) (*Condition, error) {
+	condition = strings.TrimSpace(condition)
+	if condition == "" {
+		return nil, fmt.Errorf("%w: empty condition", ErrInvalidCondition)
+	}
+
+	expr, err := parser.ParseExpr(condition)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCondition, err)
+	}
+
+	bin, ok := expr.(*parser.BinaryExpr)
+	if !ok || !bin.Op.IsComparisonOperator() {
+		return nil, fmt.Errorf("%w: condition must be '<expr> <op> <threshold>'", ErrInvalidCondition)
+	}
+	if bin.ReturnBool {
+		return nil, fmt.Errorf("%w: bool modifier is not supported", ErrInvalidCondition)
+	}
+
+	threshold, ok := bin.RHS.(*parser.NumberLiteral)
+	if !ok {
+		return nil, fmt.Errorf("%w: right side of '%s' must be a number", ErrInvalidCondition, bin.Op)
+	}
+	if bin.LHS.Type() != parser.ValueTypeVector {
+		return nil, fmt.Errorf("%w: left side must be an instant vector, got %s", ErrInvalidCondition, bin.LHS.Type())
+	}
+
+	cond := &Condition{
+		Mode:       ConditionModeExpression,
+		Query:      bin.LHS.String(),
+		MetricName: firstMetricName(bin.LHS),
+		Operator:   bin.Op.String(),
+		Threshold:  threshold.Val,
+	}
+
+	if isBareMetric(bin.LHS) {
+		cond.Mode = ConditionModeSimple
+	}
+
+	return cond, nil
+}
+
+// ValidateCondition 校验告警条件
+func ValidateCondition(condition string) error {
+	_, err := ParseCondition(condition)
+	return err
+}
+
+// isBareMetric 判断表达式是否为不带标签、offset 和 @ 修饰的裸指标名
+func isBareMetric(expr parser.Expr) bool {
+	vs, ok := expr.(*parser.VectorSelector)
+	if !ok || vs.Name == "" {
+		return false
+	}
+	if vs.OriginalOffset != 0 || vs.Timestamp != nil || vs.StartOrEnd != 0 {
+		return false
+	}
+	for _, m := range vs.LabelMatchers {
+		if m.Name != labels.MetricName {
+			return false
+		}
+	}
+	return true
+}
+
+// firstMetricName 返回表达式中第一个指标名
+func firstMetricName(expr parser.Expr) string {
+	name := ""
+	parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+		if vs, ok := node.(*parser.VectorSelector); ok && vs.Name != "" {
+			name = vs.Name
+			return errors.New("stop")
+		}
+		return nil
+	})
+	return name
+}
+
+// seriesKey 返回序列在规则内的告警键
+// 告警键由除 __name__ 外的全部标签组成，同一设备的多条序列（如每个接口的 rate）各自告警；
+// 只有 device_id 一个标签时直接使用设备 ID，没有任何标签时为空字符串，整条规则只产生一个告警。
+func seriesKey(seriesLabels map[string]string) string {
+	lset := labels.NewBuilder(labels.FromMap(seriesLabels)).Del(labels.MetricName).Labels()
+	if lset.IsEmpty() {
+		return ""
+	}
+	if deviceID := lset.Get("device_id"); deviceID != "" && lset.Len() == 1 {
+		return deviceID
+	}
+	return lset.String()
+}
+
+// alertIDPart 返回告警 ID 中标识序列的部分，非设备序列使用告警键的哈希
+func alertIDPart(key string) string {
+	if key == "" {
+		return "rule"
+	}
+	if !strings.HasPrefix(key, "{") {
+		return key
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return fmt.Sprintf("series%08x", h.Sum32())
+}
@@ -0,0 +1,85 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseCondition(t *testing.T) {
+	tests := []struct {
+		condition string
+		mode      ConditionMode
+		query     string
+		metric    string
+		operator  string
+		threshold float64
+	}{
+		{"device_status != 0", ConditionModeSimple, "device_status", "device_status", "!=", 0},
+		{"  cpu_usage >= 80.5 ", ConditionModeSimple, "cpu_usage", "cpu_usage", ">=", 80.5},
+		{`cpu_usage{env="prod"} > 80`, ConditionModeExpression, `cpu_usage{env="prod"}`, "cpu_usage", ">", 80},
+		{"rate(if_in_errors[5m]) > 10", ConditionModeExpression, "rate(if_in_errors[5m])", "if_in_errors", ">", 10},
+		{"avg by (device_id) (ping_rtt_ms offset 5m) > 200", ConditionModeExpression, "avg by (device_id) (ping_rtt_ms offset 5m)", "ping_rtt_ms", ">", 200},
+		{"sum(up) < 3", ConditionModeExpression, "sum(up)", "up", "<", 3},
+	}
+
+	for _, tt := range tests {
+		cond, err := ParseCondition(tt.condition)
+		if err != nil {
+			t.Errorf("ParseCondition(%q) failed: %v", tt.condition, err)
+			continue
+		}
+		if cond.Mode != tt.mode || cond.Query != tt.query || cond.MetricName != tt.metric ||
+			cond.Operator != tt.operator || cond.Threshold != tt.threshold {
+			t.Errorf("ParseCondition(%q) = %+v", tt.condition, cond)
+		}
+	}
+}
+
+func TestParseCondition_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"cpu_usage",
+		"cpu_usage + 1",
+		"cpu_usage > bool 80",
+		"cpu_usage > memory_usage",
+		"cpu_usage[5m] > 1",
+		"cpu_usage >",
+	}
+
+	for _, condition := range tests {
+		if _, err := ParseCondition(condition); !errors.Is(err, ErrInvalidCondition) {
+			t.Errorf("ParseCondition(%q) error = %v, want ErrInvalidCondition", condition, err)
+		}
+	}
+}
+
+func TestSeriesKey(t *testing.T) {
+	tests := []struct {
+		labels map[string]string
+		want   string
+	}{
+		{map[string]string{"__name__": "cpu_usage", "device_id": "dev-1"}, "dev-1"},
+		// 同一设备的多条序列按全部标签区分
+		{map[string]string{"__name__": "cpu_usage", "device_id": "dev-1", "cpu": "0"}, `{cpu="0", device_id="dev-1"}`},
+		{map[string]string{"region": "east", "__name__": "up"}, `{region="east"}`},
+		{map[string]string{"b": "2", "a": "1"}, `{a="1", b="2"}`},
+		{map[string]string{"__name__": "up"}, ""},
+		{nil, ""},
+	}
+
+	for _, tt := range tests {
+		if got := seriesKey(tt.labels); got != tt.want {
+			t.Errorf("seriesKey(%v) = %q, want %q", tt.labels, got, tt.want)
+		}
+	}
+
+	if got := alertIDPart("dev-1"); got != "dev-1" {
+		t.Errorf("alertIDPart(dev-1) = %q", got)
+	}
+	if got := alertIDPart(""); got != "rule" {
+		t.Errorf(`alertIDPart("") = %q`, got)
+	}
+	if got := alertIDPart(`{region="east"}`); got == `{region="east"}` || got != alertIDPart(`{region="east"}`) {
+		t.Errorf("alertIDPart should hash label keys stably, got %q", got)
+	}
+}
@@ -2,15 +2,20 @@ package engine
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"regexp"
+	"sort"
 	"strconv"
-	"strings"
 	"sync"
 	"time"
 
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 
+	"github.com/celestial/gravital-core/internal/alert/silence"
 	"github.com/celestial/gravital-core/internal/model"
 	"github.com/celestial/gravital-core/internal/notification"
 	"github.com/celestial/gravital-core/internal/repository"
@@ -23,21 +28,39 @@ type AlertEngine struct {
 	alertRepo        repository.AlertRepository
 	vmClient         *VMClient
 	notificationSvc  notification.Service
+	silences         *silence.Checker
 	checkInterval    time.Duration
 	ctx              context.Context
 	cancel           context.CancelFunc
 	wg               sync.WaitGroup
-	activeAlerts     map[uint]map[string]*ActiveAlert // rule_id -> device_id -> alert
+	activeAlerts     map[uint]map[string]*ActiveAlert // rule_id -> 告警键（见 seriesKey，通常为 device_id） -> alert
 	activeAlertsMu   sync.RWMutex
+	rules            map[uint]*model.AlertRule // 本轮评估的规则快照，用于抑制判断
+	rulesMu          sync.RWMutex
+	lockConn         *sql.Conn // 持有引擎 advisory lock 的连接，非空表示当前副本负责评估
+	needsReconcile   bool      // 恢复状态后需要在首轮评估后对账
 }
 
+// AlertState 活跃告警状态
+type AlertState string
+
+const (
+	// AlertStatePending 条件已满足，但持续时间未达到规则的 Duration
+	AlertStatePending AlertState = "pending"
+	// AlertStateFiring 已触发并创建告警事件
+	AlertStateFiring AlertState = "firing"
+)
+
 // ActiveAlert 活跃的告警
 type ActiveAlert struct {
 	RuleID       uint
 	DeviceID     string
-	EventID      uint
+	State        AlertState
+	EventID      uint      // 仅 firing 状态有效
+	PendingSince time.Time // 条件首次满足的时间
 	FirstFiredAt time.Time
 	LastFiredAt  time.Time
+	Restored     bool // 从数据库恢复，所属规则尚未成功评估过
 }
 
 // Config 引擎配置
@@ -74,10 +97,12 @@ func NewAlertEngine(db *gorm.DB, logger *zap.Logger, cfg *Config) *AlertEngine {
 		alertRepo:       repository.NewAlertRepository(db),
 		vmClient:        vmClient,
 		notificationSvc: cfg.NotificationSvc,
+		silences:        silence.NewChecker(db, logger),
 		checkInterval:   cfg.CheckInterval,
 		ctx:             ctx,
 		cancel:          cancel,
 		activeAlerts:    make(map[uint]map[string]*ActiveAlert),
+		rules:           make(map[uint]*model.AlertRule),
 	}
 }
 
@@ -95,6 +120,7 @@ func (e *AlertEngine) Stop() {
 	e.logger.Info("Stopping alert engine...")
 	e.cancel()
 	e.wg.Wait()
+	e.releaseLeadership()
 	e.logger.Info("Alert engine stopped")
 }
 
@@ -120,9 +146,15 @@ func (e *AlertEngine) evaluationLoop() {
 
 // evaluateAllRules 评估所有规则
 func (e *AlertEngine) evaluateAllRules() {
+	// 多副本部署时只有持有引擎锁的副本评估规则
+	if !e.ensureLeadership() {
+		return
+	}
+
 	// 获取所有启用的规则
 	rules, _, err := e.alertRepo.ListRules(e.ctx, &repository.AlertRuleFilter{
 		Enabled:  boolPtr(true),
+		Source:   model.AlertSourceEngine,
 		Page:     1,
 		PageSize: 1000,
 	})
@@ -131,13 +163,40 @@ func (e *AlertEngine) evaluateAllRules() {
 		return
 	}
 
+	defer func() {
+		if e.needsReconcile {
+			e.reconcileRestored(rules)
+			e.needsReconcile = false
+		}
+	}()
+
 	if len(rules) == 0 {
 		return
 	}
 
 	e.logger.Debug("Evaluating alert rules", zap.Int("count", len(rules)))
 
-	// 并发评估所有规则
+	ruleMap := make(map[uint]*model.AlertRule, len(rules))
+	var sources, targets []*model.AlertRule
+	for _, rule := range rules {
+		ruleMap[rule.ID] = rule
+		if len(rule.InhibitRules) > 0 {
+			sources = append(sources, rule)
+		} else {
+			targets = append(targets, rule)
+		}
+	}
+	e.rulesMu.Lock()
+	e.rules = ruleMap
+	e.rulesMu.Unlock()
+
+	// 先评估带抑制配置的源规则，使同一轮中触发的目标告警能被抑制
+	e.evaluateRules(sources)
+	e.evaluateRules(targets)
+}
+
+// evaluateRules 并发评估一组规则
+func (e *AlertEngine) evaluateRules(rules []*model.AlertRule) {
 	var wg sync.WaitGroup
 	for _, rule := range rules {
 		wg.Add(1)
@@ -151,47 +210,25 @@ func (e *AlertEngine) evaluateAllRules() {
 
 // evaluateRule 评估单个规则
 func (e *AlertEngine) evaluateRule(rule *model.AlertRule) {
-	// 解析条件：metric_name operator threshold
-	// 例如：device_status != 0
-	parts := strings.Fields(rule.Condition)
-	if len(parts) != 3 {
-		e.logger.Error("Invalid condition format",
-			zap.String("rule", rule.RuleName),
-			zap.String("condition", rule.Condition))
-		return
-	}
-
-	metricName := parts[0]
-	operator := parts[1]
-	thresholdStr := parts[2]
-
-	threshold, err := strconv.ParseFloat(thresholdStr, 64)
+	cond, err := ParseCondition(rule.Condition)
 	if err != nil {
-		e.logger.Error("Invalid threshold value",
+		e.logger.Error("Invalid condition format",
 			zap.String("rule", rule.RuleName),
-			zap.String("threshold", thresholdStr),
+			zap.String("condition", rule.Condition),
 			zap.Error(err))
 		return
 	}
 
 	// 查询指标数据
-	// 构造 PromQL 查询
-	query := metricName
-	if rule.Filters != nil && len(rule.Filters) > 0 {
-		// 添加过滤条件
-		var filters []string
-		for k, v := range rule.Filters {
-			if strVal, ok := v.(string); ok {
-				filters = append(filters, fmt.Sprintf(`%s="%s"`, k, strVal))
-			}
-		}
-		if len(filters) > 0 {
-			query = fmt.Sprintf("%s{%s}", metricName, strings.Join(filters, ","))
-		}
+	var query string
+	var results []MetricResult
+	if cond.Mode == ConditionModeSimple {
+		query = buildSelector(cond.MetricName, rule.Filters)
+		results, err = e.queryMetric(query)
+	} else {
+		query = cond.Query
+		results, err = e.queryExpression(query)
 	}
-
-	// 查询当前值
-	results, err := e.queryMetric(query)
 	if err != nil {
 		e.logger.Error("Failed to query metric",
 			zap.String("rule", rule.RuleName),
@@ -201,21 +238,60 @@ func (e *AlertEngine) evaluateRule(rule *model.AlertRule) {
 	}
 
 	// 评估每个时间序列
+	seen := make(map[string]bool, len(results))
 	for _, result := range results {
-		deviceID := result.Labels["device_id"]
-		if deviceID == "" {
-			continue
+		key := seriesKey(result.Labels)
+		seen[key] = true
+
+		metricName := cond.MetricName
+		if name := result.Labels["__name__"]; name != "" {
+			metricName = name
 		}
 
 		// 检查是否满足告警条件
-		if e.checkCondition(result.Value, operator, threshold) {
+		if e.checkCondition(result.Value, cond.Operator, cond.Threshold) {
 			// 满足条件，触发告警
-			e.triggerAlert(rule, deviceID, metricName, result.Value, threshold, operator)
+			e.triggerAlert(rule, key, metricName, result.Labels, result.Value, cond.Threshold, cond.Operator)
 		} else {
 			// 不满足条件，解决告警
-			e.resolveAlert(rule, deviceID)
+			e.resolveAlert(rule, key)
+		}
+	}
+
+	e.dropStalePending(rule.ID, seen)
+	e.resolveUnseenRestored(rule, seen)
+}
+
+// labelNamePattern Prometheus 标签名
+var labelNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// buildSelector 根据 Filters 构造指标选择器（简单模式）
+// 标签值按 PromQL 字符串转义，名称不合法的过滤条件被忽略，避免拼接出额外的匹配器
+func buildSelector(metricName string, filters map[string]interface{}) string {
+	selector := &parser.VectorSelector{Name: metricName}
+	for k, v := range filters {
+		strVal, ok := v.(string)
+		if !ok || !labelNamePattern.MatchString(k) {
+			continue
 		}
+		selector.LabelMatchers = append(selector.LabelMatchers, labels.MustNewMatcher(labels.MatchEqual, k, strVal))
+	}
+	if len(selector.LabelMatchers) == 0 {
+		return metricName
+	}
+	sort.Slice(selector.LabelMatchers, func(i, j int) bool {
+		return selector.LabelMatchers[i].Name < selector.LabelMatchers[j].Name
+	})
+	return selector.String()
+}
+
+// queryExpression 查询表达式（表达式模式）
+// 表达式可能包含聚合、rate、offset 等，数据库回退无法支持，因此必须走 VictoriaMetrics
+func (e *AlertEngine) queryExpression(query string) ([]MetricResult, error) {
+	if e.vmClient == nil || e.vmClient.baseURL == "" {
+		return nil, fmt.Errorf("expression conditions require VictoriaMetrics")
 	}
+	return e.vmClient.Query(query)
 }
 
 // queryMetric 查询指标数据
@@ -250,22 +326,17 @@ func (e *AlertEngine) queryMetric(query string) ([]MetricResult, error) {
 // queryMetricFromDB 从数据库查询指标（回退方案）
 func (e *AlertEngine) queryMetricFromDB(query string) ([]MetricResult, error) {
 	// 解析查询
-	metricName := query
+	matchers, err := parser.ParseMetricSelector(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector %q: %w", query, err)
+	}
+	metricName := ""
 	filters := make(map[string]string)
-	
-	if strings.Contains(query, "{") {
-		parts := strings.SplitN(query, "{", 2)
-		metricName = parts[0]
-		filterStr := strings.TrimSuffix(parts[1], "}")
-		
-		// 解析过滤条件
-		for _, filter := range strings.Split(filterStr, ",") {
-			kv := strings.SplitN(filter, "=", 2)
-			if len(kv) == 2 {
-				key := strings.TrimSpace(kv[0])
-				value := strings.Trim(strings.TrimSpace(kv[1]), `"`)
-				filters[key] = value
-			}
+	for _, m := range matchers {
+		if m.Name == labels.MetricName {
+			metricName = m.Value
+		} else if m.Type == labels.MatchEqual {
+			filters[m.Name] = m.Value
 		}
 	}
 
@@ -334,23 +405,56 @@ func (e *AlertEngine) checkCondition(value float64, operator string, threshold f
 }
 
 // triggerAlert 触发告警
-func (e *AlertEngine) triggerAlert(rule *model.AlertRule, deviceID, metricName string, currentValue, threshold float64, operator string) {
+// 规则配置了 Duration 时，先进入 pending 状态，条件持续满足 Duration 秒后才转为 firing
+// activeAlertsMu 只在读写活跃告警时持有，创建事件、静默和抑制检查在锁外进行
+func (e *AlertEngine) triggerAlert(rule *model.AlertRule, key, metricName string, seriesLabels map[string]string, currentValue, threshold float64, operator string) {
+	now := time.Now()
+	deviceID := seriesLabels["device_id"]
+
 	e.activeAlertsMu.Lock()
-	defer e.activeAlertsMu.Unlock()
+	alert, exists := e.activeAlerts[rule.ID][key]
+	if exists && alert.State == AlertStateFiring {
+		// 已经有活跃告警，更新最后触发时间
+		alert.LastFiredAt = now
+		e.activeAlertsMu.Unlock()
+		return
+	}
 
-	// 检查是否已经有活跃的告警
-	if deviceAlerts, ok := e.activeAlerts[rule.ID]; ok {
-		if alert, exists := deviceAlerts[deviceID]; exists {
-			// 已经有活跃告警，更新最后触发时间
-			alert.LastFiredAt = time.Now()
-			return
+	if !exists {
+		alert = &ActiveAlert{
+			RuleID:       rule.ID,
+			DeviceID:     deviceID,
+			State:        AlertStatePending,
+			PendingSince: now,
+		}
+		if e.activeAlerts[rule.ID] == nil {
+			e.activeAlerts[rule.ID] = make(map[string]*ActiveAlert)
 		}
+		e.activeAlerts[rule.ID][key] = alert
+	}
+	pendingFor := now.Sub(alert.PendingSince)
+	e.activeAlertsMu.Unlock()
+
+	if pendingFor < time.Duration(rule.Duration)*time.Second {
+		e.logger.Debug("Alert pending",
+			zap.String("rule", rule.RuleName),
+			zap.String("device_id", deviceID),
+			zap.Duration("pending_for", pendingFor),
+			zap.Int("duration", rule.Duration))
+		return
 	}
 
 	// 创建新的告警事件
-	alertID := fmt.Sprintf("alert-%s-%s-%d", rule.RuleName, deviceID, time.Now().Unix())
+	alertID := fmt.Sprintf("alert-%s-%s-%d", rule.RuleName, alertIDPart(key), time.Now().Unix())
 	message := fmt.Sprintf("%s: 当前值 %.2f %s 阈值 %.2f", rule.RuleName, currentValue, operator, threshold)
 
+	labels := make(map[string]interface{}, len(seriesLabels))
+	for k, v := range seriesLabels {
+		if k != "__name__" {
+			labels[k] = v
+		}
+	}
+
 	event := &model.AlertEvent{
 		AlertID:     alertID,
 		RuleID:      rule.ID,
@@ -358,11 +462,18 @@ func (e *AlertEngine) triggerAlert(rule *model.AlertRule, deviceID, metricName s
 		MetricName:  metricName,
 		Severity:    rule.Severity,
 		Message:     message,
-		Labels:      make(map[string]interface{}),
-		TriggeredAt: time.Now(),
+		Labels:      labels,
+		Source:      model.AlertSourceEngine,
+		TriggeredAt: now,
 		Status:      "firing",
 	}
 
+	// 命中静默的告警记录为 silenced 状态，不发送通知
+	matched := e.silences.CheckEvent(e.ctx, event, rule)
+	if matched != nil {
+		event.Status = "silenced"
+	}
+
 	if err := e.alertRepo.CreateEvent(e.ctx, event); err != nil {
 		e.logger.Error("Failed to create alert event",
 			zap.String("rule", rule.RuleName),
@@ -371,17 +482,20 @@ func (e *AlertEngine) triggerAlert(rule *model.AlertRule, deviceID, metricName s
 		return
 	}
 
-	// 记录活跃告警
-	if e.activeAlerts[rule.ID] == nil {
-		e.activeAlerts[rule.ID] = make(map[string]*ActiveAlert)
-	}
-	e.activeAlerts[rule.ID][deviceID] = &ActiveAlert{
-		RuleID:       rule.ID,
-		DeviceID:     deviceID,
-		EventID:      event.ID,
-		FirstFiredAt: time.Now(),
-		LastFiredAt:  time.Now(),
+	// 转为 firing 状态；创建事件期间失去引擎锁时内存状态已清空，由接管的副本从数据库恢复
+	e.activeAlertsMu.Lock()
+	if e.activeAlerts[rule.ID][key] != alert {
+		e.activeAlertsMu.Unlock()
+		e.logger.Warn("Alert state reset while creating event",
+			zap.String("rule", rule.RuleName),
+			zap.String("alert_id", event.AlertID))
+		return
 	}
+	alert.State = AlertStateFiring
+	alert.EventID = event.ID
+	alert.FirstFiredAt = now
+	alert.LastFiredAt = now
+	e.activeAlertsMu.Unlock()
 
 	e.logger.Info("Alert triggered",
 		zap.String("rule", rule.RuleName),
@@ -389,10 +503,27 @@ func (e *AlertEngine) triggerAlert(rule *model.AlertRule, deviceID, metricName s
 		zap.Float64("value", currentValue),
 		zap.Float64("threshold", threshold))
 	
+	if matched != nil {
+		e.logger.Info("Alert notification suppressed",
+			zap.String("rule", rule.RuleName),
+			zap.String("device_id", deviceID),
+			zap.String("reason", fmt.Sprintf("silence %d", matched.ID)))
+		return
+	}
+
+	// 抑制和静默时段内只记录事件，不发送通知
+	if reason := e.suppressionReason(rule, event, now); reason != "" {
+		e.logger.Info("Alert notification suppressed",
+			zap.String("rule", rule.RuleName),
+			zap.String("device_id", deviceID),
+			zap.String("reason", reason))
+		return
+	}
+
 	// 发送通知
 	if e.notificationSvc != nil && rule.NotificationConfig != nil {
 		go func() {
-			config := e.parseNotificationConfig(rule.NotificationConfig)
+			config := notification.ParseNotificationConfig(rule.NotificationConfig)
 			if err := e.notificationSvc.SendAlert(context.Background(), event, config); err != nil {
 				e.logger.Error("Failed to send alert notification",
 					zap.String("alert_id", event.AlertID),
@@ -402,94 +533,38 @@ func (e *AlertEngine) triggerAlert(rule *model.AlertRule, deviceID, metricName s
 	}
 }
 
-// parseNotificationConfig 解析通知配置
-func (e *AlertEngine) parseNotificationConfig(config map[string]interface{}) *notification.NotificationConfig {
-	notifConfig := &notification.NotificationConfig{
-		Enabled:           true,
-		DedupeInterval:    300,  // 默认 5 分钟
-		EscalationEnabled: false,
-		EscalationAfter:   1800, // 默认 30 分钟
-	}
-	
-	if enabled, ok := config["enabled"].(bool); ok {
-		notifConfig.Enabled = enabled
-	}
-	
-	if interval, ok := config["dedupe_interval"].(float64); ok {
-		notifConfig.DedupeInterval = int(interval)
-	}
-	
-	if escalationEnabled, ok := config["escalation_enabled"].(bool); ok {
-		notifConfig.EscalationEnabled = escalationEnabled
-	}
-	
-	if escalationAfter, ok := config["escalation_after"].(float64); ok {
-		notifConfig.EscalationAfter = int(escalationAfter)
-	}
-	
-	// 解析通知渠道
-	if channels, ok := config["channels"].([]interface{}); ok {
-		for _, ch := range channels {
-			if channelMap, ok := ch.(map[string]interface{}); ok {
-				channelConfig := notification.ChannelConfig{}
-				
-				if channel, ok := channelMap["channel"].(string); ok {
-					channelConfig.Channel = notification.Channel(channel)
-				}
-				
-				if enabled, ok := channelMap["enabled"].(bool); ok {
-					channelConfig.Enabled = enabled
-				}
-				
-				if recipients, ok := channelMap["recipients"].([]interface{}); ok {
-					for _, recipient := range recipients {
-						if recipientStr, ok := recipient.(string); ok {
-							channelConfig.Recipients = append(channelConfig.Recipients, recipientStr)
-						}
-					}
-				}
-				
-				notifConfig.Channels = append(notifConfig.Channels, channelConfig)
-			}
-		}
-	}
-	
-	// 解析升级渠道
-	if escalationChannels, ok := config["escalation_channels"].([]interface{}); ok {
-		for _, ch := range escalationChannels {
-			if channelStr, ok := ch.(string); ok {
-				notifConfig.EscalationChannels = append(notifConfig.EscalationChannels, notification.Channel(channelStr))
-			}
-		}
-	}
-	
-	return notifConfig
-}
-
-// resolveAlert 解决告警
-func (e *AlertEngine) resolveAlert(rule *model.AlertRule, deviceID string) {
+// resolveAlert 解决告警，key 为 seriesKey 返回的告警键
+func (e *AlertEngine) resolveAlert(rule *model.AlertRule, key string) {
 	e.activeAlertsMu.Lock()
-	defer e.activeAlertsMu.Unlock()
-
 	// 检查是否有活跃的告警
-	deviceAlerts, ok := e.activeAlerts[rule.ID]
-	if !ok {
+	alert, exists := e.activeAlerts[rule.ID][key]
+	if !exists {
+		e.activeAlertsMu.Unlock()
 		return
 	}
+	deviceID := alert.DeviceID
+	eventID := alert.EventID
 
-	alert, exists := deviceAlerts[deviceID]
-	if !exists {
+	// pending 状态的告警尚未产生事件，直接丢弃
+	if alert.State == AlertStatePending {
+		e.removeActiveAlert(rule.ID, key)
+		e.activeAlertsMu.Unlock()
+		e.logger.Debug("Pending alert recovered",
+			zap.String("rule", rule.RuleName),
+			zap.String("device_id", deviceID))
 		return
 	}
+	e.activeAlertsMu.Unlock()
 
-	// 更新告警事件状态为已解决
+	// 更新告警事件状态为已解决（已被手动解决的事件不再重复处理）
 	now := time.Now()
-	if err := e.db.Model(&model.AlertEvent{}).
-		Where("id = ?", alert.EventID).
+	result := e.db.Model(&model.AlertEvent{}).
+		Where("id = ? AND status <> ?", eventID, "resolved").
 		Updates(map[string]interface{}{
 			"status":      "resolved",
 			"resolved_at": now,
-		}).Error; err != nil {
+		})
+	if err := result.Error; err != nil {
 		e.logger.Error("Failed to resolve alert event",
 			zap.String("rule", rule.RuleName),
 			zap.String("device_id", deviceID),
@@ -498,9 +573,29 @@ func (e *AlertEngine) resolveAlert(rule *model.AlertRule, deviceID string) {
 	}
 
 	// 从活跃告警中移除
-	delete(deviceAlerts, deviceID)
-	if len(deviceAlerts) == 0 {
-		delete(e.activeAlerts, rule.ID)
+	e.activeAlertsMu.Lock()
+	if e.activeAlerts[rule.ID][key] == alert {
+		e.removeActiveAlert(rule.ID, key)
+	}
+	e.activeAlertsMu.Unlock()
+
+	// 发送恢复通知
+	if result.RowsAffected > 0 && e.notificationSvc != nil && rule.NotificationConfig != nil {
+		go func() {
+			var event model.AlertEvent
+			if err := e.db.First(&event, eventID).Error; err != nil {
+				e.logger.Error("Failed to load resolved alert event",
+					zap.Uint("event_id", eventID),
+					zap.Error(err))
+				return
+			}
+			config := notification.ParseNotificationConfig(rule.NotificationConfig)
+			if err := e.notificationSvc.SendLifecycle(context.Background(), &event, notification.TransitionResolved, config); err != nil {
+				e.logger.Error("Failed to send resolved notification",
+					zap.String("alert_id", event.AlertID),
+					zap.Error(err))
+			}
+		}()
 	}
 
 	e.logger.Info("Alert resolved",
@@ -508,6 +603,118 @@ func (e *AlertEngine) resolveAlert(rule *model.AlertRule, deviceID string) {
 		zap.String("device_id", deviceID))
 }
 
+// suppressionReason 判断告警通知是否被抑制，返回原因（未抑制时为空）
+// 调用方不能持有 activeAlertsMu
+func (e *AlertEngine) suppressionReason(rule *model.AlertRule, event *model.AlertEvent, now time.Time) string {
+	if len(rule.MutePeriods) > 0 {
+		muteCfg, err := ParseMuteConfig(rule.MutePeriods)
+		if err != nil {
+			e.logger.Warn("Invalid mute periods", zap.String("rule", rule.RuleName), zap.Error(err))
+		} else if muteCfg.Active(now) {
+			return "mute period"
+		}
+	}
+
+	labels := eventLabels(rule, event)
+
+	// 只在锁内收集同一设备上 firing 的源规则，解析和匹配在锁外进行
+	var sources []*model.AlertRule
+	e.activeAlertsMu.RLock()
+	e.rulesMu.RLock()
+	for sourceID, deviceAlerts := range e.activeAlerts {
+		if sourceID == rule.ID {
+			continue
+		}
+		source, ok := e.rules[sourceID]
+		if !ok || len(source.InhibitRules) == 0 {
+			continue
+		}
+		if alert, ok := deviceAlerts[event.DeviceID]; ok && alert.State == AlertStateFiring {
+			sources = append(sources, source)
+		}
+	}
+	e.rulesMu.RUnlock()
+	e.activeAlertsMu.RUnlock()
+
+	for _, source := range sources {
+		inhibitCfg, err := ParseInhibitConfig(source.InhibitRules)
+		if err != nil {
+			e.logger.Warn("Invalid inhibit rules", zap.String("rule", source.RuleName), zap.Error(err))
+			continue
+		}
+		for i := range inhibitCfg.Rules {
+			if inhibitCfg.Rules[i].Matches(labels) {
+				return "inhibited by " + source.RuleName
+			}
+		}
+	}
+
+	return ""
+}
+
+// eventLabels 构造用于匹配的告警标签
+func eventLabels(rule *model.AlertRule, event *model.AlertEvent) map[string]string {
+	labels := make(map[string]string, len(event.Labels)+5)
+	for k, v := range event.Labels {
+		labels[k] = fmt.Sprint(v)
+	}
+	labels["rule_id"] = strconv.FormatUint(uint64(rule.ID), 10)
+	labels["rule_name"] = rule.RuleName
+	labels["severity"] = event.Severity
+	labels["metric_name"] = event.MetricName
+	labels["device_id"] = event.DeviceID
+	return labels
+}
+
+// dropStalePending 丢弃本轮评估中没有返回数据的 pending 告警
+// 序列消失时条件不再被观察到，避免其在重新出现后直接跳过 Duration 触发
+func (e *AlertEngine) dropStalePending(ruleID uint, seen map[string]bool) {
+	e.activeAlertsMu.Lock()
+	defer e.activeAlertsMu.Unlock()
+
+	for key, alert := range e.activeAlerts[ruleID] {
+		if alert.State == AlertStatePending && !seen[key] {
+			e.removeActiveAlert(ruleID, key)
+		}
+	}
+}
+
+// resolveUnseenRestored 在规则恢复后第一次成功评估时，解决没有返回数据的恢复告警
+// 重启期间序列消失的告警不会再出现在评估结果中，不对账就会一直保持 firing
+func (e *AlertEngine) resolveUnseenRestored(rule *model.AlertRule, seen map[string]bool) {
+	var stale []string
+	e.activeAlertsMu.Lock()
+	for key, alert := range e.activeAlerts[rule.ID] {
+		if !alert.Restored {
+			continue
+		}
+		alert.Restored = false
+		if !seen[key] {
+			stale = append(stale, key)
+		}
+	}
+	e.activeAlertsMu.Unlock()
+
+	for _, key := range stale {
+		e.logger.Info("Resolving restored alert without series",
+			zap.String("rule", rule.RuleName),
+			zap.String("key", key))
+		e.resolveAlert(rule, key)
+	}
+}
+
+// removeActiveAlert 移除活跃告警（调用方需持有 activeAlertsMu）
+func (e *AlertEngine) removeActiveAlert(ruleID uint, key string) {
+	deviceAlerts, ok := e.activeAlerts[ruleID]
+	if !ok {
+		return
+	}
+	delete(deviceAlerts, key)
+	if len(deviceAlerts) == 0 {
+		delete(e.activeAlerts, ruleID)
+	}
+}
+
 func boolPtr(b bool) *bool {
 	return &b
 }
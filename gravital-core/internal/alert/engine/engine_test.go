@@ -0,0 +1,69 @@
+package engine
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/celestial/gravital-core/internal/model"
+)
+
+func TestBuildSelector(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters map[string]interface{}
+		want    string
+	}{
+		{"no filters", nil, "cpu_usage"},
+		{"sorted", map[string]interface{}{"region": "east", "device_id": "dev-1"}, `cpu_usage{device_id="dev-1",region="east"}`},
+		{"escaped value", map[string]interface{}{"device_id": `a",region=~".*`}, `cpu_usage{device_id="a\",region=~\".*"}`},
+		{"backslash", map[string]interface{}{"path": `C:\tmp`}, `cpu_usage{path="C:\\tmp"}`},
+		{"invalid name", map[string]interface{}{`a="b",c`: "x"}, "cpu_usage"},
+		{"non string", map[string]interface{}{"port": 80}, "cpu_usage"},
+	}
+
+	for _, tt := range tests {
+		if got := buildSelector("cpu_usage", tt.filters); got != tt.want {
+			t.Errorf("%s: buildSelector = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateRule_MultipleSeriesPerDevice(t *testing.T) {
+	// 同一设备两个接口：eth0 超过阈值，eth1 正常
+	vm := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[
+			{"metric":{"device_id":"dev-1","ifName":"eth0"},"value":[1700000000,"20"]},
+			{"metric":{"device_id":"dev-1","ifName":"eth1"},"value":[1700000000,"1"]}
+		]}}`)
+	}))
+	defer vm.Close()
+
+	logger := zap.NewNop()
+	e := &AlertEngine{
+		logger:       logger,
+		vmClient:     NewVMClient(vm.URL, logger),
+		activeAlerts: make(map[uint]map[string]*ActiveAlert),
+	}
+	// Duration 足够长，只停留在 pending，不访问数据库
+	rule := &model.AlertRule{ID: 1, RuleName: "if_errors", Condition: "rate(if_in_errors[5m]) > 10", Duration: 3600}
+
+	e.evaluateRule(rule)
+	first, ok := e.activeAlerts[rule.ID][`{device_id="dev-1", ifName="eth0"}`]
+	if !ok || first.State != AlertStatePending {
+		t.Fatalf("Expected pending alert for eth0, got %v", e.activeAlerts[rule.ID])
+	}
+
+	// 正常的接口不能重置同一设备上的 pending 告警
+	e.evaluateRule(rule)
+	alerts := e.activeAlerts[rule.ID]
+	if len(alerts) != 1 {
+		t.Fatalf("Expected 1 active alert, got %d: %v", len(alerts), alerts)
+	}
+	if got := alerts[`{device_id="dev-1", ifName="eth0"}`]; got != first || !got.PendingSince.Equal(first.PendingSince) {
+		t.Errorf("Expected pending alert to be kept across evaluations, got %+v", got)
+	}
+}
@@ -0,0 +1,64 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// EventCondition 事件规则条件，匹配设备推送的事件字段
+type EventCondition struct {
+	Type     string // 事件类型（trap、syslog），为空时匹配所有类型
+	Matchers []*labels.Matcher
+}
+
+// ParseEventCondition 解析事件规则条件
+// 条件使用 PromQL 选择器语法，指标名部分为事件类型，标签部分匹配事件字段，例如：
+//
+//	trap{trap_name="linkDown"}
+//	syslog{severity=~"emerg|alert|crit", app_name!="cron"}
+//	{device_id="sw-01"}
+func ParseEventCondition(condition string) (*EventCondition, error) {
+	condition = strings.TrimSpace(condition)
+	if condition == "" {
+		return nil, fmt.Errorf("%w: empty condition", ErrInvalidCondition)
+	}
+
+	matchers, err := parser.ParseMetricSelector(condition)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCondition, err)
+	}
+
+	cond := &EventCondition{}
+	for _, m := range matchers {
+		if m.Name == labels.MetricName {
+			if m.Type != labels.MatchEqual {
+				return nil, fmt.Errorf("%w: event type must be an exact name", ErrInvalidCondition)
+			}
+			cond.Type = m.Value
+			continue
+		}
+		cond.Matchers = append(cond.Matchers, m)
+	}
+	return cond, nil
+}
+
+// Matches 判断事件字段是否满足条件，filters 为规则的附加等值过滤
+func (c *EventCondition) Matches(eventType string, fields map[string]string, filters map[string]interface{}) bool {
+	if c.Type != "" && c.Type != eventType {
+		return false
+	}
+	for _, m := range c.Matchers {
+		if !m.Matches(fields[m.Name]) {
+			return false
+		}
+	}
+	for k, v := range filters {
+		if strVal, ok := v.(string); ok && fields[k] != strVal {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,195 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/celestial/gravital-core/internal/model"
+)
+
+// engineLockKey 告警引擎的 PostgreSQL advisory lock 键
+// 多个 core 副本同时运行时，只有持有该锁的副本评估规则，其余副本待命
+const engineLockKey int64 = 0x43454c4553544941 // "CELESTIA"
+
+// activeEventStatuses 仍处于活跃状态的告警事件
+var activeEventStatuses = []string{"firing", "silenced"}
+
+// ensureLeadership 确认或尝试获取引擎锁
+// 锁绑定在专用数据库连接的会话上，进程退出或连接断开时自动释放，由其他副本接管
+func (e *AlertEngine) ensureLeadership() bool {
+	if e.lockConn != nil {
+		if err := e.lockConn.PingContext(e.ctx); err == nil {
+			return true
+		}
+		e.logger.Warn("Alert engine lock connection lost, giving up leadership")
+		e.releaseLeadership()
+	}
+
+	sqlDB, err := e.db.DB()
+	if err != nil {
+		e.logger.Error("Failed to get database handle", zap.Error(err))
+		return false
+	}
+
+	conn, err := sqlDB.Conn(e.ctx)
+	if err != nil {
+		e.logger.Error("Failed to get lock connection", zap.Error(err))
+		return false
+	}
+
+	var locked bool
+	if err := conn.QueryRowContext(e.ctx, "SELECT pg_try_advisory_lock($1)", engineLockKey).Scan(&locked); err != nil {
+		conn.Close()
+		e.logger.Error("Failed to acquire alert engine lock", zap.Error(err))
+		return false
+	}
+	if !locked {
+		conn.Close()
+		e.logger.Debug("Alert engine lock held by another replica, standing by")
+		return false
+	}
+	e.lockConn = conn
+
+	// 成为主副本后，以数据库为准重建活跃告警
+	if err := e.loadActiveAlerts(); err != nil {
+		e.logger.Error("Failed to load active alerts", zap.Error(err))
+		e.releaseLeadership()
+		return false
+	}
+
+	e.logger.Info("Acquired alert engine leadership")
+	return true
+}
+
+// releaseLeadership 释放引擎锁并清空内存状态
+func (e *AlertEngine) releaseLeadership() {
+	if e.lockConn == nil {
+		return
+	}
+
+	// 使用独立的 context，保证 Stop 之后仍能解锁
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := e.lockConn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", engineLockKey); err != nil {
+		e.logger.Debug("Failed to release alert engine lock", zap.Error(err))
+	}
+	e.lockConn.Close()
+	e.lockConn = nil
+
+	e.activeAlertsMu.Lock()
+	e.activeAlerts = make(map[uint]map[string]*ActiveAlert)
+	e.activeAlertsMu.Unlock()
+	e.needsReconcile = false
+}
+
+// loadActiveAlerts 从 alert_events 加载活跃事件，重建 rule -> 告警键映射
+// 同一规则和告警键存在多条活跃事件时（重启前的重复事件），保留最新的一条，其余标记为已解决
+func (e *AlertEngine) loadActiveAlerts() error {
+	var events []model.AlertEvent
+	if err := e.db.WithContext(e.ctx).
+		Where("status IN ? AND source = ?", activeEventStatuses, model.AlertSourceEngine).
+		Order("triggered_at DESC").
+		Find(&events).Error; err != nil {
+		return err
+	}
+
+	restored := make(map[uint]map[string]*ActiveAlert)
+	var duplicates []uint
+	for _, event := range events {
+		if restored[event.RuleID] == nil {
+			restored[event.RuleID] = make(map[string]*ActiveAlert)
+		}
+		key := eventKey(&event)
+		if _, exists := restored[event.RuleID][key]; exists {
+			duplicates = append(duplicates, event.ID)
+			continue
+		}
+		restored[event.RuleID][key] = &ActiveAlert{
+			RuleID:       event.RuleID,
+			DeviceID:     event.DeviceID,
+			State:        AlertStateFiring,
+			EventID:      event.ID,
+			PendingSince: event.TriggeredAt,
+			FirstFiredAt: event.TriggeredAt,
+			LastFiredAt:  event.TriggeredAt,
+			Restored:     true,
+		}
+	}
+
+	if len(duplicates) > 0 {
+		if err := e.db.WithContext(e.ctx).Model(&model.AlertEvent{}).
+			Where("id IN ?", duplicates).
+			Updates(map[string]interface{}{
+				"status":      "resolved",
+				"resolved_at": time.Now(),
+			}).Error; err != nil {
+			return err
+		}
+		e.logger.Info("Resolved duplicate alert events", zap.Int("count", len(duplicates)))
+	}
+
+	e.activeAlertsMu.Lock()
+	e.activeAlerts = restored
+	e.activeAlertsMu.Unlock()
+	e.needsReconcile = true
+
+	e.logger.Info("Restored active alerts",
+		zap.Int("events", len(events)-len(duplicates)))
+	return nil
+}
+
+// reconcileRestored 在首轮评估后解决规则已删除或已停用的恢复告警
+// 规则仍启用的恢复告警在该规则第一次成功评估时对账（见 resolveUnseenRestored）
+func (e *AlertEngine) reconcileRestored(rules []*model.AlertRule) {
+	enabled := make(map[uint]bool, len(rules))
+	for _, rule := range rules {
+		enabled[rule.ID] = true
+	}
+
+	// 锁内只移除内存状态，更新事件在锁外进行
+	var orphaned []*ActiveAlert
+	e.activeAlertsMu.Lock()
+	for ruleID, deviceAlerts := range e.activeAlerts {
+		if enabled[ruleID] {
+			continue
+		}
+		for key, alert := range deviceAlerts {
+			if alert.State == AlertStateFiring {
+				orphaned = append(orphaned, alert)
+			}
+			e.removeActiveAlert(ruleID, key)
+		}
+	}
+	e.activeAlertsMu.Unlock()
+
+	now := time.Now()
+	for _, alert := range orphaned {
+		if err := e.db.Model(&model.AlertEvent{}).
+			Where("id = ? AND status <> ?", alert.EventID, "resolved").
+			Updates(map[string]interface{}{
+				"status":      "resolved",
+				"resolved_at": now,
+			}).Error; err != nil {
+			e.logger.Error("Failed to resolve orphaned alert event",
+				zap.Uint("rule_id", alert.RuleID),
+				zap.String("device_id", alert.DeviceID),
+				zap.Error(err))
+		}
+	}
+}
+
+// eventKey 返回告警事件对应的告警键，与评估时 seriesKey 的结果一致
+func eventKey(event *model.AlertEvent) string {
+	// 未记录标签的旧事件只能按设备区分
+	if len(event.Labels) == 0 {
+		return event.DeviceID
+	}
+	seriesLabels := make(map[string]string, len(event.Labels))
+	for k, v := range event.Labels {
+		seriesLabels[k] = fmt.Sprint(v)
+	}
+	return seriesKey(seriesLabels)
+}
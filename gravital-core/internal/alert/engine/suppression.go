@@ -0,0 +1,249 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSuppression 抑制规则或静默时段配置无效
+var ErrInvalidSuppression = errors.New("invalid suppression config")
+
+// InhibitConfig 抑制配置，存放在源规则的 inhibit_rules 字段
+//
+//	{"rules": [{"target_match": {"severity": "warning"}, "target_match_re": {"rule_name": "^port_.*"}}]}
+//
+// 源规则在某设备上处于 firing 时，同一设备上匹配任一条目的其他告警照常记录，但不发送通知。
+type InhibitConfig struct {
+	Rules []InhibitRule `json:"rules"`
+}
+
+// InhibitRule 单条抑制规则
+// 可匹配的标签：rule_id、rule_name、severity、metric_name、device_id 以及告警事件的标签，与静默匹配器一致
+type InhibitRule struct {
+	TargetMatch   map[string]string `json:"target_match"`
+	TargetMatchRE map[string]string `json:"target_match_re"`
+
+	targetRE map[string]*regexp.Regexp
+}
+
+// Matches 判断目标告警标签是否匹配
+func (r *InhibitRule) Matches(labels map[string]string) bool {
+	for k, v := range r.TargetMatch {
+		if labels[k] != v {
+			return false
+		}
+	}
+	for k, re := range r.targetRE {
+		if !re.MatchString(labels[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseInhibitConfig 解析抑制配置
+func ParseInhibitConfig(raw map[string]interface{}) (*InhibitConfig, error) {
+	cfg := &InhibitConfig{}
+	if len(raw) == 0 {
+		return cfg, nil
+	}
+	if err := decodeJSONB(raw, cfg); err != nil {
+		return nil, fmt.Errorf("%w: inhibit_rules: %v", ErrInvalidSuppression, err)
+	}
+
+	for i := range cfg.Rules {
+		rule := &cfg.Rules[i]
+		if len(rule.TargetMatch) == 0 && len(rule.TargetMatchRE) == 0 {
+			return nil, fmt.Errorf("%w: inhibit_rules[%d] has no target matchers", ErrInvalidSuppression, i)
+		}
+		rule.targetRE = make(map[string]*regexp.Regexp, len(rule.TargetMatchRE))
+		for k, expr := range rule.TargetMatchRE {
+			// 与 Alertmanager 一致，正则需完整匹配
+			re, err := regexp.Compile("^(?:" + expr + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("%w: inhibit_rules[%d].target_match_re.%s: %v", ErrInvalidSuppression, i, k, err)
+			}
+			rule.targetRE[k] = re
+		}
+	}
+	return cfg, nil
+}
+
+// MuteConfig 静默时段配置，存放在规则的 mute_periods 字段
+//
+//	{"timezone": "Asia/Shanghai", "periods": [{"weekdays": ["mon-fri"], "start": "22:00", "end": "06:00"}]}
+//
+// 处于静默时段内的告警照常记录事件，但不发送通知。
+type MuteConfig struct {
+	Timezone string       `json:"timezone"`
+	Periods  []MutePeriod `json:"periods"`
+
+	location *time.Location
+}
+
+// MutePeriod 每周重复的静默窗口
+// Weekdays 支持 cron 风格写法：sun/mon/.../sat、0-6（0 为周日）以及区间如 mon-fri、1-5，留空表示每天
+// Start/End 为 HH:MM，End 早于 Start 时表示跨越午夜
+type MutePeriod struct {
+	Weekdays []string `json:"weekdays"`
+	Start    string   `json:"start"`
+	End      string   `json:"end"`
+
+	days     [7]bool
+	startMin int
+	endMin   int
+}
+
+// ParseMuteConfig 解析静默时段配置
+func ParseMuteConfig(raw map[string]interface{}) (*MuteConfig, error) {
+	cfg := &MuteConfig{location: time.Local}
+	if len(raw) == 0 {
+		return cfg, nil
+	}
+	if err := decodeJSONB(raw, cfg); err != nil {
+		return nil, fmt.Errorf("%w: mute_periods: %v", ErrInvalidSuppression, err)
+	}
+
+	if cfg.Timezone != "" {
+		loc, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("%w: mute_periods.timezone: %v", ErrInvalidSuppression, err)
+		}
+		cfg.location = loc
+	}
+
+	for i := range cfg.Periods {
+		if err := cfg.Periods[i].compile(); err != nil {
+			return nil, fmt.Errorf("%w: mute_periods.periods[%d]: %v", ErrInvalidSuppression, i, err)
+		}
+	}
+	return cfg, nil
+}
+
+// Active 判断给定时间是否处于任一静默时段
+func (c *MuteConfig) Active(t time.Time) bool {
+	if len(c.Periods) == 0 {
+		return false
+	}
+	local := t.In(c.location)
+	for i := range c.Periods {
+		if c.Periods[i].contains(local) {
+			return true
+		}
+	}
+	return false
+}
+
+// compile 解析星期和时间
+func (p *MutePeriod) compile() error {
+	var err error
+	if p.startMin, err = parseClock(p.Start); err != nil {
+		return fmt.Errorf("start: %w", err)
+	}
+	if p.endMin, err = parseClock(p.End); err != nil {
+		return fmt.Errorf("end: %w", err)
+	}
+	if p.startMin == p.endMin {
+		return fmt.Errorf("start and end must differ")
+	}
+
+	if len(p.Weekdays) == 0 {
+		for d := range p.days {
+			p.days[d] = true
+		}
+		return nil
+	}
+	for _, spec := range p.Weekdays {
+		from, to, err := parseWeekdayRange(spec)
+		if err != nil {
+			return err
+		}
+		for d := from; ; d = (d + 1) % 7 {
+			p.days[d] = true
+			if d == to {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// contains 判断本地时间是否落在窗口内
+// 跨午夜的窗口以开始那天的星期为准，例如周五 22:00-06:00 覆盖到周六早上
+func (p *MutePeriod) contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	day := int(t.Weekday())
+
+	if p.startMin < p.endMin {
+		return p.days[day] && minute >= p.startMin && minute < p.endMin
+	}
+	if minute >= p.startMin {
+		return p.days[day]
+	}
+	if minute < p.endMin {
+		return p.days[(day+6)%7]
+	}
+	return false
+}
+
+var weekdayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// parseWeekdayRange 解析 mon、1、mon-fri、1-5 等写法
+func parseWeekdayRange(spec string) (int, int, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	parts := strings.SplitN(spec, "-", 2)
+	from, err := parseWeekday(parts[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(parts) == 1 {
+		return from, from, nil
+	}
+	to, err := parseWeekday(parts[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return from, to, nil
+}
+
+func parseWeekday(s string) (int, error) {
+	if d, ok := weekdayNames[s]; ok {
+		return d, nil
+	}
+	if len(s) > 3 {
+		if d, ok := weekdayNames[s[:3]]; ok {
+			return d, nil
+		}
+	}
+	d, err := strconv.Atoi(s)
+	if err != nil || d < 0 || d > 7 {
+		return 0, fmt.Errorf("invalid weekday: %q", s)
+	}
+	// cron 中 7 也表示周日
+	return d % 7, nil
+}
+
+// parseClock 解析 HH:MM，返回当天的分钟数
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// decodeJSONB 将 JSONB 字段解码到结构体
+func decodeJSONB(raw map[string]interface{}, v interface{}) error {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
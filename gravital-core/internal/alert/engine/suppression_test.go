@@ -0,0 +1,134 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/celestial/gravital-core/internal/model"
+)
+
+func TestInhibitRuleMatches(t *testing.T) {
+	cfg, err := ParseInhibitConfig(map[string]interface{}{
+		"rules": []interface{}{
+			map[string]interface{}{
+				"target_match":    map[string]interface{}{"severity": "warning"},
+				"target_match_re": map[string]interface{}{"rule_name": "port_.*"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ParseInhibitConfig failed: %v", err)
+	}
+	rule := &cfg.Rules[0]
+
+	tests := []struct {
+		labels map[string]string
+		want   bool
+	}{
+		{map[string]string{"severity": "warning", "rule_name": "port_down"}, true},
+		{map[string]string{"severity": "critical", "rule_name": "port_down"}, false},
+		// 正则需完整匹配
+		{map[string]string{"severity": "warning", "rule_name": "uplink_port_down"}, false},
+		{map[string]string{"severity": "warning"}, false},
+		{map[string]string{"severity": "warning", "rule": "port_down"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := rule.Matches(tt.labels); got != tt.want {
+			t.Errorf("Matches(%v) = %v, want %v", tt.labels, got, tt.want)
+		}
+	}
+}
+
+func TestParseInhibitConfig_Invalid(t *testing.T) {
+	tests := []map[string]interface{}{
+		{"rules": []interface{}{map[string]interface{}{}}},
+		{"rules": []interface{}{map[string]interface{}{"target_match_re": map[string]interface{}{"rule_name": "("}}}},
+		{"rules": "not-a-list"},
+	}
+
+	for _, raw := range tests {
+		if _, err := ParseInhibitConfig(raw); !errors.Is(err, ErrInvalidSuppression) {
+			t.Errorf("ParseInhibitConfig(%v) error = %v, want ErrInvalidSuppression", raw, err)
+		}
+	}
+
+	if cfg, err := ParseInhibitConfig(nil); err != nil || len(cfg.Rules) != 0 {
+		t.Errorf("ParseInhibitConfig(nil) = %+v, %v", cfg, err)
+	}
+}
+
+func TestEventLabels(t *testing.T) {
+	rule := &model.AlertRule{ID: 7, RuleName: "port_down"}
+	event := &model.AlertEvent{
+		DeviceID:   "dev-1",
+		Severity:   "warning",
+		MetricName: "if_oper_status",
+		Labels:     model.JSONB{"ifname": "eth0", "severity": "spoofed"},
+	}
+
+	labels := eventLabels(rule, event)
+	want := map[string]string{
+		"rule_id":     "7",
+		"rule_name":   "port_down",
+		"severity":    "warning",
+		"metric_name": "if_oper_status",
+		"device_id":   "dev-1",
+		"ifname":      "eth0",
+	}
+	for k, v := range want {
+		if labels[k] != v {
+			t.Errorf("eventLabels[%q] = %q, want %q", k, labels[k], v)
+		}
+	}
+}
+
+func TestMuteConfigActive(t *testing.T) {
+	cfg, err := ParseMuteConfig(map[string]interface{}{
+		"timezone": "UTC",
+		"periods": []interface{}{
+			map[string]interface{}{"weekdays": []interface{}{"fri"}, "start": "22:00", "end": "06:00"},
+			map[string]interface{}{"weekdays": []interface{}{"mon-wed"}, "start": "12:00", "end": "13:00"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ParseMuteConfig failed: %v", err)
+	}
+
+	// 2025-11-07 是周五
+	tests := []struct {
+		at   string
+		want bool
+	}{
+		{"2025-11-07T23:00:00Z", true},
+		{"2025-11-08T05:59:00Z", true},
+		{"2025-11-08T06:00:00Z", false},
+		{"2025-11-07T05:00:00Z", false},
+		{"2025-11-03T12:30:00Z", true},
+		{"2025-11-05T12:59:00Z", true},
+		{"2025-11-06T12:30:00Z", false},
+	}
+
+	for _, tt := range tests {
+		at, _ := time.Parse(time.RFC3339, tt.at)
+		if got := cfg.Active(at); got != tt.want {
+			t.Errorf("Active(%s) = %v, want %v", tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestParseMuteConfig_Invalid(t *testing.T) {
+	tests := []map[string]interface{}{
+		{"timezone": "Mars/Olympus"},
+		{"periods": []interface{}{map[string]interface{}{"start": "25:00", "end": "06:00"}}},
+		{"periods": []interface{}{map[string]interface{}{"start": "06:00", "end": "06:00"}}},
+		{"periods": []interface{}{map[string]interface{}{"weekdays": []interface{}{"funday"}, "start": "01:00", "end": "02:00"}}},
+	}
+
+	for _, raw := range tests {
+		if _, err := ParseMuteConfig(raw); !errors.Is(err, ErrInvalidSuppression) {
+			t.Errorf("ParseMuteConfig(%v) error = %v, want ErrInvalidSuppression", raw, err)
+		}
+	}
+}
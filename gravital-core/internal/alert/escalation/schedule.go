@@ -0,0 +1,154 @@
+package escalation
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/celestial/gravital-core/internal/alert/engine"
+	"github.com/celestial/gravital-core/internal/model"
+)
+
+// ErrInvalidEscalation 升级策略或值班表配置无效
+var ErrInvalidEscalation = errors.New("invalid escalation config")
+
+// Schedule 解析后的值班表
+type Schedule struct {
+	layers []scheduleLayer
+}
+
+type scheduleLayer struct {
+	userIDs      []uint
+	start        time.Time
+	rotation     time.Duration
+	restrictions *engine.MuteConfig
+}
+
+// CompileSchedule 解析并校验值班表
+func CompileSchedule(schedule *model.OnCallSchedule) (*Schedule, error) {
+	s := &Schedule{}
+	// 时区用于解析各层的限制窗口
+	if schedule.Timezone != "" {
+		if _, err := time.LoadLocation(schedule.Timezone); err != nil {
+			return nil, fmt.Errorf("%w: timezone: %v", ErrInvalidEscalation, err)
+		}
+	}
+
+	if len(schedule.Layers) == 0 {
+		return nil, fmt.Errorf("%w: at least one layer is required", ErrInvalidEscalation)
+	}
+
+	for i, layer := range schedule.Layers {
+		if len(layer.UserIDs) == 0 {
+			return nil, fmt.Errorf("%w: layers[%d]: user_ids is required", ErrInvalidEscalation, i)
+		}
+		if layer.Start.IsZero() {
+			return nil, fmt.Errorf("%w: layers[%d]: start is required", ErrInvalidEscalation, i)
+		}
+
+		compiled := scheduleLayer{
+			userIDs: layer.UserIDs,
+			start:   layer.Start,
+		}
+		switch layer.RotationType {
+		case model.RotationDaily:
+			compiled.rotation = 24 * time.Hour
+		case model.RotationWeekly, "":
+			compiled.rotation = 7 * 24 * time.Hour
+		case model.RotationCustom:
+			if layer.RotationHours <= 0 {
+				return nil, fmt.Errorf("%w: layers[%d]: rotation_hours must be positive", ErrInvalidEscalation, i)
+			}
+			compiled.rotation = time.Duration(layer.RotationHours) * time.Hour
+		default:
+			return nil, fmt.Errorf("%w: layers[%d]: unknown rotation_type %q", ErrInvalidEscalation, i, layer.RotationType)
+		}
+
+		if len(layer.Restrictions) > 0 {
+			// 限制窗口与规则静默时段使用相同的每周窗口写法
+			periods := make([]interface{}, 0, len(layer.Restrictions))
+			for _, w := range layer.Restrictions {
+				weekdays := make([]interface{}, 0, len(w.Weekdays))
+				for _, d := range w.Weekdays {
+					weekdays = append(weekdays, d)
+				}
+				periods = append(periods, map[string]interface{}{
+					"weekdays": weekdays,
+					"start":    w.Start,
+					"end":      w.End,
+				})
+			}
+			restrictions, err := engine.ParseMuteConfig(map[string]interface{}{
+				"timezone": schedule.Timezone,
+				"periods":  periods,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("%w: layers[%d].restrictions: %v", ErrInvalidEscalation, i, err)
+			}
+			compiled.restrictions = restrictions
+		}
+
+		s.layers = append(s.layers, compiled)
+	}
+
+	return s, nil
+}
+
+// OnCall 计算指定时间的值班人
+// 生效中的覆盖优先；其次从最后一层开始，取第一个在该时间生效的层的轮换人
+func (s *Schedule) OnCall(at time.Time, overrides []model.OnCallOverride) (uint, bool) {
+	for i := len(overrides) - 1; i >= 0; i-- {
+		o := overrides[i]
+		if !at.Before(o.StartsAt) && at.Before(o.EndsAt) {
+			return o.UserID, true
+		}
+	}
+
+	for i := len(s.layers) - 1; i >= 0; i-- {
+		layer := s.layers[i]
+		if at.Before(layer.start) {
+			continue
+		}
+		if layer.restrictions != nil && !layer.restrictions.Active(at) {
+			continue
+		}
+		idx := int(at.Sub(layer.start)/layer.rotation) % len(layer.userIDs)
+		return layer.userIDs[idx], true
+	}
+
+	return 0, false
+}
+
+// ValidatePolicy 校验升级策略
+func ValidatePolicy(policy *model.EscalationPolicy) error {
+	if len(policy.Levels) == 0 {
+		return fmt.Errorf("%w: at least one level is required", ErrInvalidEscalation)
+	}
+	if policy.RepeatTimes < 0 {
+		return fmt.Errorf("%w: repeat_times must not be negative", ErrInvalidEscalation)
+	}
+
+	for i, level := range policy.Levels {
+		if level.DelayMinutes < 0 {
+			return fmt.Errorf("%w: levels[%d]: delay_minutes must not be negative", ErrInvalidEscalation, i)
+		}
+		if len(level.Targets) == 0 {
+			return fmt.Errorf("%w: levels[%d]: at least one target is required", ErrInvalidEscalation, i)
+		}
+		for j, target := range level.Targets {
+			switch target.Type {
+			case model.EscalationTargetUser, model.EscalationTargetSchedule:
+				if target.ID == 0 {
+					return fmt.Errorf("%w: levels[%d].targets[%d]: id is required", ErrInvalidEscalation, i, j)
+				}
+			case model.EscalationTargetChannel:
+				if target.Channel == "" || len(target.Recipients) == 0 {
+					return fmt.Errorf("%w: levels[%d].targets[%d]: channel and recipients are required", ErrInvalidEscalation, i, j)
+				}
+			default:
+				return fmt.Errorf("%w: levels[%d].targets[%d]: unknown type %q", ErrInvalidEscalation, i, j, target.Type)
+			}
+		}
+	}
+	return nil
+}
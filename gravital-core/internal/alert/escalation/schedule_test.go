@@ -0,0 +1,151 @@
+package escalation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/celestial/gravital-core/internal/model"
+)
+
+func mustTime(t *testing.T, s string) time.Time {
+	t.Helper()
+	v, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t.Fatalf("invalid time %q: %v", s, err)
+	}
+	return v
+}
+
+func TestScheduleOnCall(t *testing.T) {
+	// 2025-11-03 是周一
+	start := mustTime(t, "2025-11-03T09:00:00Z")
+	schedule, err := CompileSchedule(&model.OnCallSchedule{
+		Timezone: "UTC",
+		Layers: model.ScheduleLayers{
+			{UserIDs: []uint{1, 2, 3}, Start: start, RotationType: model.RotationDaily},
+			// 工作日夜间由第二层接管
+			{
+				UserIDs:      []uint{10, 11},
+				Start:        start,
+				RotationType: model.RotationWeekly,
+				Restrictions: []model.ScheduleWindow{{Weekdays: []string{"mon-fri"}, Start: "20:00", End: "08:00"}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CompileSchedule failed: %v", err)
+	}
+
+	overrides := []model.OnCallOverride{
+		{UserID: 99, StartsAt: mustTime(t, "2025-11-05T12:00:00Z"), EndsAt: mustTime(t, "2025-11-05T14:00:00Z")},
+		{UserID: 98, StartsAt: mustTime(t, "2025-11-05T13:00:00Z"), EndsAt: mustTime(t, "2025-11-05T15:00:00Z")},
+	}
+
+	tests := []struct {
+		at     string
+		want   uint
+		wantOK bool
+	}{
+		{"2025-11-03T08:59:00Z", 0, false},
+		{"2025-11-03T10:00:00Z", 1, true},
+		{"2025-11-04T10:00:00Z", 2, true},
+		{"2025-11-05T10:00:00Z", 3, true},
+		{"2025-11-06T10:00:00Z", 1, true},
+		{"2025-11-03T21:00:00Z", 10, true},
+		// 周五 20:00 开始的夜间窗口覆盖到周六早上
+		{"2025-11-08T07:00:00Z", 10, true},
+		{"2025-11-08T21:00:00Z", 3, true},
+		{"2025-11-11T21:00:00Z", 11, true},
+		{"2025-11-05T12:30:00Z", 99, true},
+		// 重叠时后创建的覆盖优先
+		{"2025-11-05T13:30:00Z", 98, true},
+		{"2025-11-05T14:30:00Z", 98, true},
+		{"2025-11-05T15:00:00Z", 3, true},
+	}
+
+	for _, tt := range tests {
+		got, ok := schedule.OnCall(mustTime(t, tt.at), overrides)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("OnCall(%s) = %d, %v, want %d, %v", tt.at, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestScheduleCustomRotation(t *testing.T) {
+	start := mustTime(t, "2025-11-03T00:00:00Z")
+	schedule, err := CompileSchedule(&model.OnCallSchedule{
+		Layers: model.ScheduleLayers{
+			{UserIDs: []uint{1, 2}, Start: start, RotationType: model.RotationCustom, RotationHours: 12},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CompileSchedule failed: %v", err)
+	}
+
+	for at, want := range map[string]uint{
+		"2025-11-03T11:59:00Z": 1,
+		"2025-11-03T12:00:00Z": 2,
+		"2025-11-04T00:00:00Z": 1,
+	} {
+		if got, _ := schedule.OnCall(mustTime(t, at), nil); got != want {
+			t.Errorf("OnCall(%s) = %d, want %d", at, got, want)
+		}
+	}
+}
+
+func TestCompileSchedule_Invalid(t *testing.T) {
+	start := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		schedule model.OnCallSchedule
+	}{
+		{"no layers", model.OnCallSchedule{}},
+		{"bad timezone", model.OnCallSchedule{Timezone: "Mars/Olympus", Layers: model.ScheduleLayers{{UserIDs: []uint{1}, Start: start}}}},
+		{"no users", model.OnCallSchedule{Layers: model.ScheduleLayers{{Start: start}}}},
+		{"no start", model.OnCallSchedule{Layers: model.ScheduleLayers{{UserIDs: []uint{1}}}}},
+		{"custom without hours", model.OnCallSchedule{Layers: model.ScheduleLayers{{UserIDs: []uint{1}, Start: start, RotationType: model.RotationCustom}}}},
+		{"unknown rotation", model.OnCallSchedule{Layers: model.ScheduleLayers{{UserIDs: []uint{1}, Start: start, RotationType: "monthly"}}}},
+		{"bad restriction", model.OnCallSchedule{Layers: model.ScheduleLayers{{
+			UserIDs: []uint{1}, Start: start,
+			Restrictions: []model.ScheduleWindow{{Start: "25:00", End: "08:00"}},
+		}}}},
+	}
+
+	for _, tt := range tests {
+		if _, err := CompileSchedule(&tt.schedule); !errors.Is(err, ErrInvalidEscalation) {
+			t.Errorf("%s: CompileSchedule error = %v, want ErrInvalidEscalation", tt.name, err)
+		}
+	}
+}
+
+func TestValidatePolicy(t *testing.T) {
+	email := model.EscalationTarget{Type: model.EscalationTargetChannel, Channel: "email", Recipients: []string{"ops@example.com"}}
+	tests := []struct {
+		name    string
+		policy  model.EscalationPolicy
+		wantErr bool
+	}{
+		{"valid", model.EscalationPolicy{Levels: model.EscalationLevels{
+			{DelayMinutes: 0, Targets: []model.EscalationTarget{email}},
+			{DelayMinutes: 15, Targets: []model.EscalationTarget{{Type: model.EscalationTargetSchedule, ID: 1}}},
+		}}, false},
+		{"no levels", model.EscalationPolicy{}, true},
+		{"negative repeat", model.EscalationPolicy{RepeatTimes: -1, Levels: model.EscalationLevels{{Targets: []model.EscalationTarget{email}}}}, true},
+		{"negative delay", model.EscalationPolicy{Levels: model.EscalationLevels{{DelayMinutes: -1, Targets: []model.EscalationTarget{email}}}}, true},
+		{"no targets", model.EscalationPolicy{Levels: model.EscalationLevels{{}}}, true},
+		{"user without id", model.EscalationPolicy{Levels: model.EscalationLevels{{Targets: []model.EscalationTarget{{Type: model.EscalationTargetUser}}}}}, true},
+		{"channel without recipients", model.EscalationPolicy{Levels: model.EscalationLevels{{Targets: []model.EscalationTarget{{Type: model.EscalationTargetChannel, Channel: "email"}}}}}, true},
+		{"unknown type", model.EscalationPolicy{Levels: model.EscalationLevels{{Targets: []model.EscalationTarget{{Type: "team", ID: 1}}}}}, true},
+	}
+
+	for _, tt := range tests {
+		err := ValidatePolicy(&tt.policy)
+		if tt.wantErr && !errors.Is(err, ErrInvalidEscalation) {
+			t.Errorf("%s: ValidatePolicy error = %v, want ErrInvalidEscalation", tt.name, err)
+		}
+		if !tt.wantErr && err != nil {
+			t.Errorf("%s: ValidatePolicy error = %v", tt.name, err)
+		}
+	}
+}
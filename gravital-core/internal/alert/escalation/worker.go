@@ -0,0 +1,374 @@
+package escalation
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/celestial/gravital-core/internal/model"
+	"github.com/celestial/gravital-core/internal/notification"
+)
+
+const (
+	batchSize = 100
+	// retryDelay 通知发送失败或未配置通知服务时，升级推迟重试的间隔
+	retryDelay = time.Minute
+)
+
+// Config 升级处理配置
+type Config struct {
+	CheckInterval   time.Duration
+	NotificationSvc notification.Service
+}
+
+// Worker 告警升级处理器
+// 未确认的触发中告警按规则关联的升级策略逐级通知，告警被确认或恢复后停止升级。
+// 多副本部署时通过行锁（SKIP LOCKED）保证同一告警只由一个副本处理。
+type Worker struct {
+	db              *gorm.DB
+	logger          *zap.Logger
+	notificationSvc notification.Service
+	checkInterval   time.Duration
+	ctx             context.Context
+	cancel          context.CancelFunc
+	wg              sync.WaitGroup
+}
+
+// NewWorker 创建告警升级处理器
+func NewWorker(db *gorm.DB, logger *zap.Logger, cfg *Config) *Worker {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	interval := cfg.CheckInterval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	return &Worker{
+		db:              db,
+		logger:          logger,
+		notificationSvc: cfg.NotificationSvc,
+		checkInterval:   interval,
+		ctx:             ctx,
+		cancel:          cancel,
+	}
+}
+
+// Start 启动升级处理
+func (w *Worker) Start() {
+	w.logger.Info("Starting escalation worker",
+		zap.Duration("check_interval", w.checkInterval))
+	if w.notificationSvc == nil {
+		w.logger.Warn("Escalation worker has no notification service, escalations will not advance")
+	}
+
+	w.wg.Add(1)
+	go w.loop()
+}
+
+// Stop 停止升级处理
+func (w *Worker) Stop() {
+	w.logger.Info("Stopping escalation worker...")
+	w.cancel()
+	w.wg.Wait()
+	w.logger.Info("Escalation worker stopped")
+}
+
+// loop 处理循环
+func (w *Worker) loop() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.process(time.Now())
+		case <-w.ctx.Done():
+			return
+		}
+	}
+}
+
+// process 为新告警建立升级进度，并执行到期的升级级别
+func (w *Worker) process(now time.Time) {
+	policies := make(map[uint]*model.EscalationPolicy)
+
+	if err := w.startEscalations(policies); err != nil {
+		w.logger.Error("Failed to start escalations", zap.Error(err))
+	}
+
+	if err := drain(func() (int, error) {
+		return w.processDue(now, policies)
+	}); err != nil {
+		w.logger.Error("Failed to process escalations", zap.Error(err))
+	}
+}
+
+// drain 逐批处理到期的升级，直到某一批不足 batchSize
+// 每条领取的升级都会推进 next_at（发送失败时推迟 retryDelay），同一轮不会重复领取
+func drain(batch func() (int, error)) error {
+	for {
+		n, err := batch()
+		if err != nil {
+			return err
+		}
+		if n < batchSize {
+			return nil
+		}
+	}
+}
+
+// startEscalations 为关联了升级策略、尚未建立升级进度的触发中告警创建进度
+// 第一级从告警触发时间开始计时
+func (w *Worker) startEscalations(policies map[uint]*model.EscalationPolicy) error {
+	var events []*model.AlertEvent
+	if err := w.db.WithContext(w.ctx).
+		Select("alert_events.*").
+		Preload("Rule").
+		Joins("JOIN alert_rules ON alert_rules.id = alert_events.rule_id").
+		Where("alert_events.status = ? AND alert_events.acknowledged = ?", "firing", false).
+		Where("alert_rules.escalation_policy_id IS NOT NULL").
+		Where("NOT EXISTS (SELECT 1 FROM alert_escalations WHERE alert_escalations.alert_event_id = alert_events.id)").
+		Limit(batchSize * 5).
+		Find(&events).Error; err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		if event.Rule == nil || event.Rule.EscalationPolicyID == nil {
+			continue
+		}
+		policy := w.policy(*event.Rule.EscalationPolicyID, policies)
+		if policy == nil || len(policy.Levels) == 0 {
+			continue
+		}
+
+		nextAt := event.TriggeredAt.Add(time.Duration(policy.Levels[0].DelayMinutes) * time.Minute)
+		escalation := &model.AlertEscalation{
+			AlertEventID: event.ID,
+			PolicyID:     policy.ID,
+			Status:       model.EscalationStatusActive,
+			NextAt:       &nextAt,
+		}
+		if err := w.db.WithContext(w.ctx).
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(escalation).Error; err != nil {
+			w.logger.Error("Failed to create escalation",
+				zap.String("alert_id", event.AlertID),
+				zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// processDue 领取并执行到期的升级，返回处理数量
+func (w *Worker) processDue(now time.Time, policies map[uint]*model.EscalationPolicy) (int, error) {
+	count := 0
+	err := w.db.WithContext(w.ctx).Transaction(func(tx *gorm.DB) error {
+		var escalations []*model.AlertEscalation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_at <= ?", model.EscalationStatusActive, now).
+			Order("next_at").
+			Limit(batchSize).
+			Find(&escalations).Error; err != nil {
+			return err
+		}
+
+		for _, escalation := range escalations {
+			updates := w.step(escalation, now, policies)
+			updates["updated_at"] = now
+			if err := tx.Model(&model.AlertEscalation{}).
+				Where("id = ?", escalation.ID).
+				Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		count = len(escalations)
+		return nil
+	})
+	return count, err
+}
+
+// step 执行当前级别并计算下一步，返回需要更新的字段
+func (w *Worker) step(escalation *model.AlertEscalation, now time.Time, policies map[uint]*model.EscalationPolicy) map[string]interface{} {
+	stop := map[string]interface{}{
+		"status":  model.EscalationStatusStopped,
+		"next_at": nil,
+	}
+	// 保持当前级别，推迟到下一次重试，避免未发送的级别被记为已通知
+	retry := map[string]interface{}{
+		"next_at": now.Add(retryDelay),
+	}
+
+	if w.notificationSvc == nil {
+		return retry
+	}
+
+	var event model.AlertEvent
+	if err := w.db.WithContext(w.ctx).Preload("Rule").First(&event, escalation.AlertEventID).Error; err != nil {
+		return stop
+	}
+	// 告警已确认或不再处于触发状态时停止升级
+	if event.Status != "firing" || event.Acknowledged {
+		return stop
+	}
+
+	policy := w.policy(escalation.PolicyID, policies)
+	if policy == nil || escalation.Level >= len(policy.Levels) {
+		return stop
+	}
+
+	level := policy.Levels[escalation.Level]
+	channels := w.resolveTargets(level.Targets, now)
+	if len(channels) == 0 {
+		w.logger.Warn("Escalation level has no reachable targets",
+			zap.String("alert_id", event.AlertID),
+			zap.Uint("policy_id", policy.ID),
+			zap.Int("level", escalation.Level+1))
+	} else if err := w.notificationSvc.SendEscalation(w.ctx, &event, escalation.Level+1, channels); err != nil {
+		w.logger.Error("Failed to send escalation",
+			zap.String("alert_id", event.AlertID),
+			zap.Int("level", escalation.Level+1),
+			zap.Error(err))
+		return retry
+	}
+
+	updates := map[string]interface{}{
+		"last_notified_at": now,
+	}
+
+	nextLevel := escalation.Level + 1
+	repeats := escalation.Repeats
+	if nextLevel >= len(policy.Levels) {
+		if repeats >= policy.RepeatTimes {
+			updates["level"] = nextLevel
+			updates["status"] = model.EscalationStatusCompleted
+			updates["next_at"] = nil
+			return updates
+		}
+		nextLevel = 0
+		repeats++
+	}
+
+	nextAt := now.Add(time.Duration(policy.Levels[nextLevel].DelayMinutes) * time.Minute)
+	updates["level"] = nextLevel
+	updates["repeats"] = repeats
+	updates["next_at"] = nextAt
+	return updates
+}
+
+// resolveTargets 将升级目标解析为通知渠道
+// 用户和值班人通过邮件通知，同一渠道的接收人合并并去重
+func (w *Worker) resolveTargets(targets []model.EscalationTarget, now time.Time) []notification.ChannelConfig {
+	var channels []notification.ChannelConfig
+	index := make(map[string]int)
+	seen := make(map[string]bool)
+
+	add := func(channel notification.Channel, template string, recipients ...string) {
+		key := string(channel) + "|" + template
+		i, ok := index[key]
+		if !ok {
+			channels = append(channels, notification.ChannelConfig{
+				Channel:  channel,
+				Enabled:  true,
+				Template: template,
+			})
+			i = len(channels) - 1
+			index[key] = i
+		}
+		for _, r := range recipients {
+			if r == "" || seen[key+"|"+r] {
+				continue
+			}
+			seen[key+"|"+r] = true
+			channels[i].Recipients = append(channels[i].Recipients, r)
+		}
+	}
+
+	for _, target := range targets {
+		switch target.Type {
+		case model.EscalationTargetChannel:
+			add(notification.Channel(target.Channel), target.Template, target.Recipients...)
+		case model.EscalationTargetUser:
+			add(notification.ChannelEmail, target.Template, w.userEmail(target.ID))
+		case model.EscalationTargetSchedule:
+			if userID, ok := w.onCallUser(target.ID, now); ok {
+				add(notification.ChannelEmail, target.Template, w.userEmail(userID))
+			}
+		}
+	}
+
+	// 去掉没有接收人的渠道
+	result := channels[:0]
+	for _, ch := range channels {
+		if len(ch.Recipients) > 0 {
+			result = append(result, ch)
+		}
+	}
+	return result
+}
+
+// onCallUser 获取值班表当前值班人
+func (w *Worker) onCallUser(scheduleID uint, now time.Time) (uint, bool) {
+	var schedule model.OnCallSchedule
+	if err := w.db.WithContext(w.ctx).First(&schedule, scheduleID).Error; err != nil {
+		w.logger.Warn("On-call schedule not found", zap.Uint("schedule_id", scheduleID))
+		return 0, false
+	}
+
+	compiled, err := CompileSchedule(&schedule)
+	if err != nil {
+		w.logger.Warn("Invalid on-call schedule",
+			zap.Uint("schedule_id", scheduleID),
+			zap.Error(err))
+		return 0, false
+	}
+
+	overrides, err := ActiveOverrides(w.ctx, w.db, scheduleID, now)
+	if err != nil {
+		w.logger.Warn("Failed to load on-call overrides",
+			zap.Uint("schedule_id", scheduleID),
+			zap.Error(err))
+	}
+
+	return compiled.OnCall(now, overrides)
+}
+
+// userEmail 获取用户邮箱，用户不存在或已禁用时返回空
+func (w *Worker) userEmail(userID uint) string {
+	var user model.User
+	if err := w.db.WithContext(w.ctx).Select("id", "email", "enabled").First(&user, userID).Error; err != nil || !user.Enabled {
+		return ""
+	}
+	return user.Email
+}
+
+// policy 获取升级策略，同一轮处理内缓存
+func (w *Worker) policy(id uint, cache map[uint]*model.EscalationPolicy) *model.EscalationPolicy {
+	if p, ok := cache[id]; ok {
+		return p
+	}
+	var policy model.EscalationPolicy
+	if err := w.db.WithContext(w.ctx).First(&policy, id).Error; err != nil {
+		cache[id] = nil
+		return nil
+	}
+	cache[id] = &policy
+	return &policy
+}
+
+// ActiveOverrides 获取指定时间生效的值班覆盖，按创建时间排序（后创建的优先）
+func ActiveOverrides(ctx context.Context, db *gorm.DB, scheduleID uint, at time.Time) ([]model.OnCallOverride, error) {
+	var overrides []model.OnCallOverride
+	err := db.WithContext(ctx).
+		Where("schedule_id = ? AND starts_at <= ? AND ends_at > ?", scheduleID, at, at).
+		Order("created_at").
+		Find(&overrides).Error
+	return overrides, err
+}
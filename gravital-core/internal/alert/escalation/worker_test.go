@@ -0,0 +1,73 @@
+package escalation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/celestial/gravital-core/internal/model"
+)
+
+func TestDrainWithoutNotificationService(t *testing.T) {
+	now := time.Now()
+	due := now.Add(-time.Minute)
+	w := NewWorker(nil, zap.NewNop(), &Config{})
+
+	// 超过一批的到期升级，按 processDue 的方式领取并写回 step 的结果
+	escalations := make([]*model.AlertEscalation, 2*batchSize+50)
+	for i := range escalations {
+		escalations[i] = &model.AlertEscalation{ID: uint(i + 1), Status: model.EscalationStatusActive, NextAt: &due}
+	}
+
+	batches := 0
+	err := drain(func() (int, error) {
+		batches++
+		if batches > 10 {
+			return 0, errors.New("due escalations reselected")
+		}
+		n := 0
+		for _, e := range escalations {
+			if n == batchSize {
+				break
+			}
+			if e.Status != model.EscalationStatusActive || e.NextAt == nil || e.NextAt.After(now) {
+				continue
+			}
+			updates := w.step(e, now, nil)
+			next, ok := updates["next_at"].(time.Time)
+			if !ok {
+				t.Fatalf("step updates = %v, want next_at", updates)
+			}
+			e.NextAt = &next
+			if _, ok := updates["level"]; ok {
+				t.Fatalf("step advanced level without sending: %v", updates)
+			}
+			n++
+		}
+		return n, nil
+	})
+	if err != nil {
+		t.Fatalf("drain failed: %v", err)
+	}
+	if batches != 3 {
+		t.Errorf("drain ran %d batches, want 3", batches)
+	}
+	for _, e := range escalations {
+		if !e.NextAt.Equal(now.Add(retryDelay)) {
+			t.Fatalf("escalation %d next_at = %v, want %v", e.ID, e.NextAt, now.Add(retryDelay))
+		}
+	}
+}
+
+func TestDrainError(t *testing.T) {
+	calls := 0
+	err := drain(func() (int, error) {
+		calls++
+		return batchSize, errors.New("db down")
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("drain = %v after %d calls, want error after 1 call", err, calls)
+	}
+}
@@ -0,0 +1,194 @@
+package silence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/celestial/gravital-core/internal/model"
+	"github.com/celestial/gravital-core/internal/repository"
+)
+
+// ErrInvalidSilence 静默配置无效
+var ErrInvalidSilence = errors.New("invalid silence")
+
+// cacheTTL 生效静默的缓存时间
+const cacheTTL = 10 * time.Second
+
+// Validate 校验静默配置
+func Validate(s *model.AlertSilence) error {
+	if len(s.Matchers) == 0 {
+		return fmt.Errorf("%w: at least one matcher is required", ErrInvalidSilence)
+	}
+	if !s.EndsAt.After(s.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidSilence)
+	}
+	for i, m := range s.Matchers {
+		if m.Name == "" {
+			return fmt.Errorf("%w: matchers[%d] has empty name", ErrInvalidSilence, i)
+		}
+		switch m.Type {
+		case model.SilenceMatchEqual, model.SilenceMatchNotEqual:
+		case model.SilenceMatchRegex:
+			if _, err := compileRegex(m.Value); err != nil {
+				return fmt.Errorf("%w: matchers[%d]: %v", ErrInvalidSilence, i, err)
+			}
+		default:
+			return fmt.Errorf("%w: matchers[%d] has unknown type %q", ErrInvalidSilence, i, m.Type)
+		}
+	}
+	return nil
+}
+
+// Matches 判断告警标签是否满足静默的全部匹配器
+func Matches(s *model.AlertSilence, labels map[string]string) bool {
+	for _, m := range s.Matchers {
+		value := labels[m.Name]
+		switch m.Type {
+		case model.SilenceMatchEqual:
+			if value != m.Value {
+				return false
+			}
+		case model.SilenceMatchNotEqual:
+			if value == m.Value {
+				return false
+			}
+		case model.SilenceMatchRegex:
+			re, err := compileRegex(m.Value)
+			if err != nil || !re.MatchString(value) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+var (
+	regexCache   = make(map[string]*regexp.Regexp)
+	regexCacheMu sync.RWMutex
+)
+
+// compileRegex 编译并缓存正则，与 Alertmanager 一致需完整匹配
+func compileRegex(expr string) (*regexp.Regexp, error) {
+	regexCacheMu.RLock()
+	re, ok := regexCache[expr]
+	regexCacheMu.RUnlock()
+	if ok {
+		return re, nil
+	}
+
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return nil, err
+	}
+
+	regexCacheMu.Lock()
+	regexCache[expr] = re
+	regexCacheMu.Unlock()
+	return re, nil
+}
+
+// Checker 静默检查器，供告警引擎和通知服务使用
+type Checker struct {
+	db       *gorm.DB
+	logger   *zap.Logger
+	repo     repository.SilenceRepository
+	mu       sync.Mutex
+	active   []*model.AlertSilence
+	loadedAt time.Time
+}
+
+// NewChecker 创建静默检查器
+func NewChecker(db *gorm.DB, logger *zap.Logger) *Checker {
+	return &Checker{
+		db:     db,
+		logger: logger,
+		repo:   repository.NewSilenceRepository(db),
+	}
+}
+
+// Check 返回匹配告警标签的生效静默，没有匹配时返回 nil
+func (c *Checker) Check(ctx context.Context, labels map[string]string) *model.AlertSilence {
+	now := time.Now()
+	for _, s := range c.activeSilences(ctx, now) {
+		if s.StateAt(now) == model.SilenceStateActive && Matches(s, labels) {
+			return s
+		}
+	}
+	return nil
+}
+
+// CheckEvent 检查告警事件是否被静默，rule 为空时从数据库加载
+func (c *Checker) CheckEvent(ctx context.Context, event *model.AlertEvent, rule *model.AlertRule) *model.AlertSilence {
+	if len(c.activeSilences(ctx, time.Now())) == 0 {
+		return nil
+	}
+	return c.Check(ctx, c.EventLabels(ctx, event, rule))
+}
+
+// EventLabels 构造告警事件的匹配标签：事件标签、设备标签以及规则和事件的内置字段
+func (c *Checker) EventLabels(ctx context.Context, event *model.AlertEvent, rule *model.AlertRule) map[string]string {
+	labels := make(map[string]string)
+
+	if event.DeviceID != "" {
+		var device model.Device
+		if err := c.db.WithContext(ctx).
+			Select("labels").
+			Where("device_id = ?", event.DeviceID).
+			First(&device).Error; err == nil {
+			for k, v := range device.Labels {
+				labels[k] = fmt.Sprint(v)
+			}
+		}
+	}
+	for k, v := range event.Labels {
+		labels[k] = fmt.Sprint(v)
+	}
+
+	if rule == nil {
+		rule = event.Rule
+	}
+	if rule == nil && event.RuleID != 0 {
+		var r model.AlertRule
+		if err := c.db.WithContext(ctx).Select("id", "rule_name").First(&r, event.RuleID).Error; err == nil {
+			rule = &r
+		}
+	}
+	if rule != nil {
+		labels["rule_name"] = rule.RuleName
+	}
+
+	labels["rule_id"] = strconv.FormatUint(uint64(event.RuleID), 10)
+	labels["device_id"] = event.DeviceID
+	labels["severity"] = event.Severity
+	labels["metric_name"] = event.MetricName
+	return labels
+}
+
+// activeSilences 获取生效的静默（带缓存）
+func (c *Checker) activeSilences(ctx context.Context, now time.Time) []*model.AlertSilence {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now.Sub(c.loadedAt) < cacheTTL {
+		return c.active
+	}
+
+	silences, err := c.repo.ListActive(ctx, now)
+	if err != nil {
+		c.logger.Error("Failed to load active silences", zap.Error(err))
+		return c.active
+	}
+	c.active = silences
+	c.loadedAt = now
+	return c.active
+}
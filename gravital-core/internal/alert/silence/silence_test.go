@@ -0,0 +1,116 @@
+package silence
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/celestial/gravital-core/internal/model"
+)
+
+func matcher(name, typ, value string) model.SilenceMatcher {
+	return model.SilenceMatcher{Name: name, Type: typ, Value: value}
+}
+
+func TestMatches(t *testing.T) {
+	labels := map[string]string{
+		"rule_name": "port_down",
+		"device_id": "sw-01",
+		"severity":  "warning",
+		"region":    "east",
+	}
+
+	tests := []struct {
+		name     string
+		matchers model.SilenceMatchers
+		want     bool
+	}{
+		{"equal", model.SilenceMatchers{matcher("device_id", model.SilenceMatchEqual, "sw-01")}, true},
+		{"equal mismatch", model.SilenceMatchers{matcher("device_id", model.SilenceMatchEqual, "sw-02")}, false},
+		{"not equal", model.SilenceMatchers{matcher("severity", model.SilenceMatchNotEqual, "critical")}, true},
+		{"not equal mismatch", model.SilenceMatchers{matcher("severity", model.SilenceMatchNotEqual, "warning")}, false},
+		{"regex", model.SilenceMatchers{matcher("rule_name", model.SilenceMatchRegex, "port_.*")}, true},
+		{"regex anchored", model.SilenceMatchers{matcher("rule_name", model.SilenceMatchRegex, "down")}, false},
+		{"regex alternation anchored", model.SilenceMatchers{matcher("region", model.SilenceMatchRegex, "west|east")}, true},
+		{"missing label equals empty", model.SilenceMatchers{matcher("site", model.SilenceMatchEqual, "")}, true},
+		{"invalid regex", model.SilenceMatchers{matcher("rule_name", model.SilenceMatchRegex, "(")}, false},
+		{"unknown type", model.SilenceMatchers{matcher("rule_name", "like", "port_down")}, false},
+		{"all must match", model.SilenceMatchers{
+			matcher("device_id", model.SilenceMatchEqual, "sw-01"),
+			matcher("severity", model.SilenceMatchEqual, "critical"),
+		}, false},
+	}
+
+	for _, tt := range tests {
+		s := &model.AlertSilence{Matchers: tt.matchers}
+		if got := Matches(s, labels); got != tt.want {
+			t.Errorf("%s: Matches = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Now()
+	valid := model.SilenceMatchers{matcher("device_id", model.SilenceMatchEqual, "sw-01")}
+
+	tests := []struct {
+		name    string
+		silence model.AlertSilence
+		wantErr bool
+	}{
+		{"valid", model.AlertSilence{Matchers: valid, StartsAt: now, EndsAt: now.Add(time.Hour)}, false},
+		{"no matchers", model.AlertSilence{StartsAt: now, EndsAt: now.Add(time.Hour)}, true},
+		{"ends before starts", model.AlertSilence{Matchers: valid, StartsAt: now, EndsAt: now}, true},
+		{"empty name", model.AlertSilence{
+			Matchers: model.SilenceMatchers{matcher("", model.SilenceMatchEqual, "x")},
+			StartsAt: now, EndsAt: now.Add(time.Hour),
+		}, true},
+		{"bad regex", model.AlertSilence{
+			Matchers: model.SilenceMatchers{matcher("rule_name", model.SilenceMatchRegex, "[")},
+			StartsAt: now, EndsAt: now.Add(time.Hour),
+		}, true},
+		{"unknown type", model.AlertSilence{
+			Matchers: model.SilenceMatchers{matcher("rule_name", "like", "x")},
+			StartsAt: now, EndsAt: now.Add(time.Hour),
+		}, true},
+	}
+
+	for _, tt := range tests {
+		err := Validate(&tt.silence)
+		if tt.wantErr && !errors.Is(err, ErrInvalidSilence) {
+			t.Errorf("%s: Validate error = %v, want ErrInvalidSilence", tt.name, err)
+		}
+		if !tt.wantErr && err != nil {
+			t.Errorf("%s: Validate error = %v", tt.name, err)
+		}
+	}
+}
+
+func TestCheckerCheck(t *testing.T) {
+	now := time.Now()
+	active := &model.AlertSilence{
+		ID:       1,
+		Matchers: model.SilenceMatchers{matcher("device_id", model.SilenceMatchEqual, "sw-01")},
+		StartsAt: now.Add(-time.Hour),
+		EndsAt:   now.Add(time.Hour),
+	}
+	pending := &model.AlertSilence{
+		ID:       2,
+		Matchers: model.SilenceMatchers{matcher("device_id", model.SilenceMatchEqual, "sw-02")},
+		StartsAt: now.Add(time.Hour),
+		EndsAt:   now.Add(2 * time.Hour),
+	}
+	// 预置缓存，避免访问数据库
+	c := &Checker{active: []*model.AlertSilence{active, pending}, loadedAt: now}
+
+	if s := c.Check(context.Background(), map[string]string{"device_id": "sw-01"}); s == nil || s.ID != 1 {
+		t.Errorf("Check(sw-01) = %v, want silence 1", s)
+	}
+	if s := c.Check(context.Background(), map[string]string{"device_id": "sw-02"}); s != nil {
+		t.Errorf("Check(sw-02) = %v, want nil for pending silence", s)
+	}
+	if s := c.Check(context.Background(), map[string]string{"device_id": "sw-03"}); s != nil {
+		t.Errorf("Check(sw-03) = %v, want nil", s)
+	}
+}
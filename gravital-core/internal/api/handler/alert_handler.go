@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -8,6 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 
+	"github.com/celestial/gravital-core/internal/alert/engine"
 	"github.com/celestial/gravital-core/internal/service"
 )
 
@@ -25,6 +27,11 @@ func NewAlertHandler(alertService service.AlertService, db *gorm.DB) *AlertHandl
 	}
 }
 
+// isInvalidRuleError 判断是否为规则配置校验错误
+func isInvalidRuleError(err error) bool {
+	return errors.Is(err, engine.ErrInvalidCondition) || errors.Is(err, engine.ErrInvalidSuppression)
+}
+
 // ListRules 获取告警规则列表
 func (h *AlertHandler) ListRules(c *gin.Context) {
 	var req service.ListAlertRuleRequest
@@ -95,6 +102,13 @@ func (h *AlertHandler) CreateRule(c *gin.Context) {
 
 	rule, err := h.alertService.CreateRule(c.Request.Context(), &req)
 	if err != nil {
+		if isInvalidRuleError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    40001,
+				"message": "规则配置无效: " + err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"code":    10001,
 			"message": "创建规则失败: " + err.Error(),
@@ -131,6 +145,13 @@ func (h *AlertHandler) UpdateRule(c *gin.Context) {
 	}
 
 	if err := h.alertService.UpdateRule(c.Request.Context(), uint(id), &req); err != nil {
+		if isInvalidRuleError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    40001,
+				"message": "规则配置无效: " + err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"code":    10001,
 			"message": "更新规则失败: " + err.Error(),
@@ -519,3 +540,29 @@ func (h *AlertHandler) ResolveByRule(c *gin.Context) {
 	})
 }
 
+
+// IngestAlertmanager 接收 Alertmanager webhook
+func (h *AlertHandler) IngestAlertmanager(c *gin.Context) {
+	var req service.AlertmanagerWebhook
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	result, err := h.alertService.IngestAlertmanager(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "接入告警失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": result,
+	})
+}
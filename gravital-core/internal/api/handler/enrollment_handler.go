@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/celestial/gravital-core/internal/service"
+)
+
+// EnrollmentHandler 注册密钥处理器
+type EnrollmentHandler struct {
+	enrollmentService service.EnrollmentService
+}
+
+// NewEnrollmentHandler 创建注册密钥处理器
+func NewEnrollmentHandler(enrollmentService service.EnrollmentService) *EnrollmentHandler {
+	return &EnrollmentHandler{
+		enrollmentService: enrollmentService,
+	}
+}
+
+// ListKeys 获取注册密钥列表
+func (h *EnrollmentHandler) ListKeys(c *gin.Context) {
+	var req service.ListEnrollmentKeyRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	items, total, err := h.enrollmentService.ListKeys(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "获取注册密钥列表失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": gin.H{
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+			"items":     items,
+		},
+	})
+}
+
+// GetKey 获取注册密钥详情
+func (h *EnrollmentHandler) GetKey(c *gin.Context) {
+	id, ok := parseEnrollmentKeyID(c)
+	if !ok {
+		return
+	}
+
+	key, err := h.enrollmentService.GetKey(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "获取注册密钥失败: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": key,
+	})
+}
+
+// CreateKey 创建注册密钥，明文密钥只在响应中返回一次
+func (h *EnrollmentHandler) CreateKey(c *gin.Context) {
+	var req service.CreateEnrollmentKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		userID = uint(0)
+	}
+
+	resp, err := h.enrollmentService.CreateKey(c.Request.Context(), &req, userID.(uint))
+	if err != nil {
+		h.handleError(c, err, "创建注册密钥失败: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": resp,
+	})
+}
+
+// UpdateKey 更新注册密钥
+func (h *EnrollmentHandler) UpdateKey(c *gin.Context) {
+	id, ok := parseEnrollmentKeyID(c)
+	if !ok {
+		return
+	}
+
+	var req service.UpdateEnrollmentKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	key, err := h.enrollmentService.UpdateKey(c.Request.Context(), id, &req)
+	if err != nil {
+		h.handleError(c, err, "更新注册密钥失败: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": key,
+	})
+}
+
+// DeleteKey 删除注册密钥
+func (h *EnrollmentHandler) DeleteKey(c *gin.Context) {
+	id, ok := parseEnrollmentKeyID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.enrollmentService.DeleteKey(c.Request.Context(), id); err != nil {
+		h.handleError(c, err, "删除注册密钥失败: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+	})
+}
+
+// RevokeKey 吊销注册密钥
+func (h *EnrollmentHandler) RevokeKey(c *gin.Context) {
+	id, ok := parseEnrollmentKeyID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.enrollmentService.RevokeKey(c.Request.Context(), id); err != nil {
+		h.handleError(c, err, "吊销注册密钥失败: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+	})
+}
+
+// ListEnrollments 获取 Sentinel 注册审计记录
+func (h *EnrollmentHandler) ListEnrollments(c *gin.Context) {
+	var req service.ListSentinelEnrollmentRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	items, total, err := h.enrollmentService.ListEnrollments(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "获取注册记录失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": gin.H{
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+			"items":     items,
+		},
+	})
+}
+
+// handleError 处理注册密钥服务错误
+func (h *EnrollmentHandler) handleError(c *gin.Context, err error, prefix string) {
+	switch {
+	case errors.Is(err, service.ErrEnrollmentKeyNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    50001,
+			"message": "注册密钥不存在",
+		})
+	case errors.Is(err, service.ErrInvalidEnrollmentKey):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "参数错误: " + err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": prefix + err.Error(),
+		})
+	}
+}
+
+// parseEnrollmentKeyID 解析注册密钥 ID
+func parseEnrollmentKeyID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "无效的注册密钥 ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
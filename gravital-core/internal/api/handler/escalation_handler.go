@@ -0,0 +1,498 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/celestial/gravital-core/internal/alert/escalation"
+	"github.com/celestial/gravital-core/internal/service"
+)
+
+// EscalationHandler 升级策略和值班表处理器
+type EscalationHandler struct {
+	escalationService service.EscalationService
+}
+
+// NewEscalationHandler 创建升级策略处理器
+func NewEscalationHandler(escalationService service.EscalationService) *EscalationHandler {
+	return &EscalationHandler{
+		escalationService: escalationService,
+	}
+}
+
+// ListPolicies 获取升级策略列表
+func (h *EscalationHandler) ListPolicies(c *gin.Context) {
+	var req service.ListEscalationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	items, total, err := h.escalationService.ListPolicies(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "获取升级策略列表失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": gin.H{
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+			"items":     items,
+		},
+	})
+}
+
+// GetPolicy 获取升级策略详情
+func (h *EscalationHandler) GetPolicy(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "无效的升级策略 ID",
+		})
+		return
+	}
+
+	policy, err := h.escalationService.GetPolicy(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    50001,
+			"message": "升级策略不存在",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": policy,
+	})
+}
+
+// CreatePolicy 创建升级策略
+func (h *EscalationHandler) CreatePolicy(c *gin.Context) {
+	var req service.CreateEscalationPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		userID = uint(0)
+	}
+
+	policy, err := h.escalationService.CreatePolicy(c.Request.Context(), &req, userID.(uint))
+	if err != nil {
+		if errors.Is(err, escalation.ErrInvalidEscalation) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    40001,
+				"message": "升级策略配置无效: " + err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "创建升级策略失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": policy,
+	})
+}
+
+// UpdatePolicy 更新升级策略
+func (h *EscalationHandler) UpdatePolicy(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "无效的升级策略 ID",
+		})
+		return
+	}
+
+	var req service.UpdateEscalationPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	if err := h.escalationService.UpdatePolicy(c.Request.Context(), uint(id), &req); err != nil {
+		if errors.Is(err, escalation.ErrInvalidEscalation) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    40001,
+				"message": "升级策略配置无效: " + err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "更新升级策略失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+	})
+}
+
+// DeletePolicy 删除升级策略
+func (h *EscalationHandler) DeletePolicy(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "无效的升级策略 ID",
+		})
+		return
+	}
+
+	if err := h.escalationService.DeletePolicy(c.Request.Context(), uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "删除升级策略失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+	})
+}
+
+// ListSchedules 获取值班表列表
+func (h *EscalationHandler) ListSchedules(c *gin.Context) {
+	var req service.ListEscalationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	items, total, err := h.escalationService.ListSchedules(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "获取值班表列表失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": gin.H{
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+			"items":     items,
+		},
+	})
+}
+
+// GetSchedule 获取值班表详情
+func (h *EscalationHandler) GetSchedule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "无效的值班表 ID",
+		})
+		return
+	}
+
+	schedule, err := h.escalationService.GetSchedule(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    50001,
+			"message": "值班表不存在",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": schedule,
+	})
+}
+
+// CreateSchedule 创建值班表
+func (h *EscalationHandler) CreateSchedule(c *gin.Context) {
+	var req service.CreateOnCallScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		userID = uint(0)
+	}
+
+	schedule, err := h.escalationService.CreateSchedule(c.Request.Context(), &req, userID.(uint))
+	if err != nil {
+		if errors.Is(err, escalation.ErrInvalidEscalation) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    40001,
+				"message": "值班表配置无效: " + err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "创建值班表失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": schedule,
+	})
+}
+
+// UpdateSchedule 更新值班表
+func (h *EscalationHandler) UpdateSchedule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "无效的值班表 ID",
+		})
+		return
+	}
+
+	var req service.UpdateOnCallScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	if err := h.escalationService.UpdateSchedule(c.Request.Context(), uint(id), &req); err != nil {
+		if errors.Is(err, escalation.ErrInvalidEscalation) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    40001,
+				"message": "值班表配置无效: " + err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "更新值班表失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+	})
+}
+
+// DeleteSchedule 删除值班表
+func (h *EscalationHandler) DeleteSchedule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "无效的值班表 ID",
+		})
+		return
+	}
+
+	if err := h.escalationService.DeleteSchedule(c.Request.Context(), uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "删除值班表失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+	})
+}
+
+// GetOnCall 查询值班人，at 参数为 RFC3339 时间，默认当前时间
+func (h *EscalationHandler) GetOnCall(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "无效的值班表 ID",
+		})
+		return
+	}
+
+	at := time.Now()
+	if v := c.Query("at"); v != "" {
+		at, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    40001,
+				"message": "无效的时间参数: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	result, err := h.escalationService.GetOnCall(c.Request.Context(), uint(id), at)
+	if err != nil {
+		if errors.Is(err, escalation.ErrInvalidEscalation) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    40001,
+				"message": "值班表配置无效: " + err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    50001,
+			"message": "值班表不存在",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": result,
+	})
+}
+
+// ListOverrides 获取值班覆盖列表
+func (h *EscalationHandler) ListOverrides(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "无效的值班表 ID",
+		})
+		return
+	}
+
+	items, err := h.escalationService.ListOverrides(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "获取值班覆盖列表失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": items,
+	})
+}
+
+// CreateOverride 创建值班覆盖
+func (h *EscalationHandler) CreateOverride(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "无效的值班表 ID",
+		})
+		return
+	}
+
+	var req service.CreateOnCallOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		userID = uint(0)
+	}
+
+	override, err := h.escalationService.CreateOverride(c.Request.Context(), uint(id), &req, userID.(uint))
+	if err != nil {
+		if errors.Is(err, escalation.ErrInvalidEscalation) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    40001,
+				"message": "值班覆盖配置无效: " + err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "创建值班覆盖失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": override,
+	})
+}
+
+// DeleteOverride 删除值班覆盖
+func (h *EscalationHandler) DeleteOverride(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "无效的值班表 ID",
+		})
+		return
+	}
+	overrideID, err := strconv.ParseUint(c.Param("overrideId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "无效的值班覆盖 ID",
+		})
+		return
+	}
+
+	if err := h.escalationService.DeleteOverride(c.Request.Context(), uint(id), uint(overrideID)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "删除值班覆盖失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+	})
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/celestial/gravital-core/internal/service"
+)
+
+// EventHandler 设备事件处理器
+type EventHandler struct {
+	eventService service.EventIngestService
+}
+
+// NewEventHandler 创建设备事件处理器
+func NewEventHandler(eventService service.EventIngestService) *EventHandler {
+	return &EventHandler{
+		eventService: eventService,
+	}
+}
+
+// Ingest 接收 Sentinel 上报的 SNMP Trap、Syslog 事件
+func (h *EventHandler) Ingest(c *gin.Context) {
+	var req service.IngestEventsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	result, err := h.eventService.Ingest(c.Request.Context(), c.GetString("sentinel_id"), req.Events)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "接入事件失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": result,
+	})
+}
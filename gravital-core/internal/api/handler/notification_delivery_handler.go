@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/celestial/gravital-core/internal/service"
+)
+
+// NotificationDeliveryHandler 通知投递处理器
+type NotificationDeliveryHandler struct {
+	deliveryService service.NotificationDeliveryService
+}
+
+// NewNotificationDeliveryHandler 创建通知投递处理器
+func NewNotificationDeliveryHandler(deliveryService service.NotificationDeliveryService) *NotificationDeliveryHandler {
+	return &NotificationDeliveryHandler{
+		deliveryService: deliveryService,
+	}
+}
+
+// List 获取通知投递列表
+func (h *NotificationDeliveryHandler) List(c *gin.Context) {
+	var req service.ListNotificationDeliveryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	items, total, err := h.deliveryService.ListDeliveries(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "获取投递列表失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": gin.H{
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+			"items":     items,
+		},
+	})
+}
+
+// Get 获取通知投递详情
+func (h *NotificationDeliveryHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "无效的投递 ID",
+		})
+		return
+	}
+
+	item, err := h.deliveryService.GetDelivery(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    50001,
+			"message": "投递记录不存在",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": item,
+	})
+}
+
+// Retry 重试失败的通知投递
+func (h *NotificationDeliveryHandler) Retry(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "无效的投递 ID",
+		})
+		return
+	}
+
+	if err := h.deliveryService.RetryDelivery(c.Request.Context(), uint(id)); err != nil {
+		if errors.Is(err, service.ErrDeliveryNotRetryable) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    40001,
+				"message": "只能重试失败或死信状态的投递",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "重试投递失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+	})
+}
+
+// BatchRetry 批量重试失败的通知投递
+func (h *NotificationDeliveryHandler) BatchRetry(c *gin.Context) {
+	var req service.RetryNotificationDeliveryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	affected, err := h.deliveryService.RetryDeliveries(c.Request.Context(), req.IDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "重试投递失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": gin.H{
+			"retried": affected,
+		},
+	})
+}
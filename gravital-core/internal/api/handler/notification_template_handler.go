@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/celestial/gravital-core/internal/notification"
+	"github.com/celestial/gravital-core/internal/service"
+)
+
+// NotificationTemplateHandler 通知模板处理器
+type NotificationTemplateHandler struct {
+	templateService service.NotificationTemplateService
+}
+
+// NewNotificationTemplateHandler 创建通知模板处理器
+func NewNotificationTemplateHandler(templateService service.NotificationTemplateService) *NotificationTemplateHandler {
+	return &NotificationTemplateHandler{
+		templateService: templateService,
+	}
+}
+
+// List 获取通知模板列表
+func (h *NotificationTemplateHandler) List(c *gin.Context) {
+	var req service.ListNotificationTemplateRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	items, total, err := h.templateService.ListTemplates(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "获取模板列表失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": gin.H{
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+			"items":     items,
+		},
+	})
+}
+
+// Get 获取通知模板详情
+func (h *NotificationTemplateHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "无效的模板 ID",
+		})
+		return
+	}
+
+	item, err := h.templateService.GetTemplate(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    50001,
+			"message": "模板不存在",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": item,
+	})
+}
+
+// Create 创建通知模板
+func (h *NotificationTemplateHandler) Create(c *gin.Context) {
+	var req service.CreateNotificationTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		userID = uint(0)
+	}
+
+	item, err := h.templateService.CreateTemplate(c.Request.Context(), &req, userID.(uint))
+	if err != nil {
+		if errors.Is(err, notification.ErrInvalidTemplate) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    40001,
+				"message": "模板无效: " + err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "创建模板失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": item,
+	})
+}
+
+// Update 更新通知模板
+func (h *NotificationTemplateHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "无效的模板 ID",
+		})
+		return
+	}
+
+	var req service.UpdateNotificationTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	if err := h.templateService.UpdateTemplate(c.Request.Context(), uint(id), &req); err != nil {
+		if errors.Is(err, notification.ErrInvalidTemplate) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    40001,
+				"message": "模板无效: " + err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "更新模板失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+	})
+}
+
+// Delete 删除通知模板
+func (h *NotificationTemplateHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "无效的模板 ID",
+		})
+		return
+	}
+
+	if err := h.templateService.DeleteTemplate(c.Request.Context(), uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "删除模板失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+	})
+}
+
+// Preview 预览通知模板，默认使用示例告警事件渲染
+func (h *NotificationTemplateHandler) Preview(c *gin.Context) {
+	var req service.PreviewNotificationTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	preview, err := h.templateService.PreviewTemplate(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, notification.ErrInvalidTemplate) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    40001,
+				"message": "模板渲染失败: " + err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "预览模板失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": preview,
+	})
+}
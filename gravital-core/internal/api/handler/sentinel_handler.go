@@ -1,11 +1,14 @@
 package handler
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/celestial/gravital-core/internal/pkg/pki"
 	"github.com/celestial/gravital-core/internal/service"
 )
 
@@ -32,8 +35,49 @@ func (h *SentinelHandler) Register(c *gin.Context) {
 		return
 	}
 
+	if req.RegistrationKey == "" {
+		req.RegistrationKey = c.GetHeader("X-Registration-Key")
+	}
+	req.SourceIP = c.ClientIP()
+	// 重新注册需出示当前凭证：Token 或 TLS 握手已校验的客户端证书
+	req.SentinelID = c.GetHeader("X-Sentinel-ID")
+	req.APIToken = c.GetHeader("X-API-Token")
+	if tlsState := c.Request.TLS; tlsState != nil && len(tlsState.VerifiedChains) > 0 {
+		cert := tlsState.VerifiedChains[0][0]
+		req.CertSentinelID = pki.SentinelID(cert)
+		req.CertSerial = cert.SerialNumber.Text(16)
+	}
+
 	resp, err := h.sentinelService.Register(c.Request.Context(), &req)
 	if err != nil {
+		if errors.Is(err, pki.ErrInvalidCSR) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    40001,
+				"message": "参数错误: " + err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrEnrollmentRejected) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"code":    20002,
+				"message": "注册被拒绝: " + err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrSentinelRevoked) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"code":    20004,
+				"message": "Sentinel 已被吊销",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrSentinelUnauthorized) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"code":    20002,
+				"message": "重新注册的凭证无效",
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"code":    10001,
 			"message": "注册失败: " + err.Error(),
@@ -67,7 +111,8 @@ func (h *SentinelHandler) Heartbeat(c *gin.Context) {
 		return
 	}
 
-	if err := h.sentinelService.Heartbeat(c.Request.Context(), sentinelID, &req); err != nil {
+	resp, err := h.sentinelService.Heartbeat(c.Request.Context(), sentinelID, &req)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"code":    10001,
 			"message": "心跳处理失败: " + err.Error(),
@@ -77,10 +122,7 @@ func (h *SentinelHandler) Heartbeat(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"code": 0,
-		"data": gin.H{
-			"status":         "ok",
-			"config_version": 1,
-		},
+		"data": resp,
 	})
 }
 
@@ -167,14 +209,18 @@ func (h *SentinelHandler) Delete(c *gin.Context) {
 }
 
 // Control 远程控制 Sentinel
+// 命令进入该 Sentinel 的队列，随下一次心跳下发
 func (h *SentinelHandler) Control(c *gin.Context) {
-	sentinelID := c.Param("id")
-
-	var req struct {
-		Action string                 `json:"action" binding:"required"`
-		Params map[string]interface{} `json:"params"`
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "无效的 Sentinel ID",
+		})
+		return
 	}
 
+	var req service.ControlSentinelRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"code":    40001,
@@ -183,7 +229,34 @@ func (h *SentinelHandler) Control(c *gin.Context) {
 		return
 	}
 
-	if err := h.sentinelService.Control(c.Request.Context(), sentinelID, req.Action); err != nil {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		userID = uint(0)
+	}
+
+	command, err := h.sentinelService.Control(c.Request.Context(), uint(id), &req, userID.(uint))
+	if err != nil {
+		if errors.Is(err, service.ErrSentinelNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"code":    50001,
+				"message": "Sentinel 不存在",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidSentinelCommand) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    40001,
+				"message": "控制命令无效: " + err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrSentinelRevoked) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    40001,
+				"message": "Sentinel 已被吊销",
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"code":    10001,
 			"message": "控制失败: " + err.Error(),
@@ -191,9 +264,278 @@ func (h *SentinelHandler) Control(c *gin.Context) {
 		return
 	}
 
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": command,
+	})
+}
+
+// ListCommands 获取 Sentinel 控制命令及执行结果
+func (h *SentinelHandler) ListCommands(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "无效的 Sentinel ID",
+		})
+		return
+	}
+
+	var req service.ListSentinelCommandRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	commands, total, err := h.sentinelService.ListCommands(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrSentinelNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"code":    50001,
+				"message": "Sentinel 不存在",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "获取命令列表失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": gin.H{
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+			"items":     commands,
+		},
+	})
+}
+
+// RotateToken 轮换 Sentinel API Token
+func (h *SentinelHandler) RotateToken(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "无效的 Sentinel ID",
+		})
+		return
+	}
+
+	var req service.RotateSentinelTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		userID = uint(0)
+	}
+
+	resp, err := h.sentinelService.RotateToken(c.Request.Context(), uint(id), &req, userID.(uint))
+	if err != nil {
+		if errors.Is(err, service.ErrSentinelNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"code":    50001,
+				"message": "Sentinel 不存在",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidSentinelCommand) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    40001,
+				"message": "参数错误: " + err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrSentinelRevoked) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    40001,
+				"message": "Sentinel 已被吊销，请使用 return_token 重新签发 Token",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "轮换 Token 失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": resp,
+	})
+}
+
+// Revoke 吊销 Sentinel，所有 Token 立即失效
+func (h *SentinelHandler) Revoke(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "无效的 Sentinel ID",
+		})
+		return
+	}
+
+	if err := h.sentinelService.Revoke(c.Request.Context(), uint(id)); err != nil {
+		if errors.Is(err, service.ErrSentinelNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"code":    50001,
+				"message": "Sentinel 不存在",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "吊销失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+	})
+}
+
+// Approve 审批通过待审批的 Sentinel
+func (h *SentinelHandler) Approve(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "无效的 Sentinel ID",
+		})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		userID = uint(0)
+	}
+
+	sentinel, err := h.sentinelService.Approve(c.Request.Context(), uint(id), userID.(uint))
+	if err != nil {
+		h.handleApprovalError(c, err, "审批失败: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": sentinel,
+	})
+}
+
+// Reject 拒绝待审批的 Sentinel，同时吊销其凭证
+func (h *SentinelHandler) Reject(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "无效的 Sentinel ID",
+		})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		userID = uint(0)
+	}
+
+	if err := h.sentinelService.Reject(c.Request.Context(), uint(id), userID.(uint)); err != nil {
+		h.handleApprovalError(c, err, "拒绝失败: ")
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"code":    0,
 		"message": "success",
 	})
 }
 
+// handleApprovalError 处理审批错误
+func (h *SentinelHandler) handleApprovalError(c *gin.Context, err error, prefix string) {
+	switch {
+	case errors.Is(err, service.ErrSentinelNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    50001,
+			"message": "Sentinel 不存在",
+		})
+	case errors.Is(err, service.ErrSentinelNotPending):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "Sentinel 不处于待审批状态",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": prefix + err.Error(),
+		})
+	}
+}
+
+// IssueCertificate 签发或续期 Sentinel 客户端证书（Sentinel 调用）
+func (h *SentinelHandler) IssueCertificate(c *gin.Context) {
+	var req service.SentinelCertificateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	cert, err := h.sentinelService.IssueCertificate(c.Request.Context(), c.GetString("sentinel_id"), &req, c.GetBool("sentinel_cert_verified"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrMTLSDisabled):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    40001,
+				"message": "中心端未启用 mTLS",
+			})
+		case errors.Is(err, service.ErrClientCertRequired):
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"code":    20001,
+				"message": "续期证书需要出示当前客户端证书",
+			})
+		case errors.Is(err, pki.ErrInvalidCSR):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    40001,
+				"message": "参数错误: " + err.Error(),
+			})
+		case errors.Is(err, service.ErrSentinelNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"code":    50001,
+				"message": "Sentinel 不存在",
+			})
+		case errors.Is(err, service.ErrSentinelRevoked):
+			c.JSON(http.StatusForbidden, gin.H{
+				"code":    20004,
+				"message": "Sentinel 已被吊销",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"code":    10001,
+				"message": "签发证书失败: " + err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": cert,
+	})
+}
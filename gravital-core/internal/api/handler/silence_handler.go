@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/celestial/gravital-core/internal/alert/silence"
+	"github.com/celestial/gravital-core/internal/service"
+)
+
+// SilenceHandler 告警静默处理器
+type SilenceHandler struct {
+	silenceService service.SilenceService
+}
+
+// NewSilenceHandler 创建告警静默处理器
+func NewSilenceHandler(silenceService service.SilenceService) *SilenceHandler {
+	return &SilenceHandler{
+		silenceService: silenceService,
+	}
+}
+
+// List 获取静默列表
+func (h *SilenceHandler) List(c *gin.Context) {
+	var req service.ListSilenceRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	items, total, err := h.silenceService.ListSilences(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "获取静默列表失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": gin.H{
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+			"items":     items,
+		},
+	})
+}
+
+// Get 获取静默详情
+func (h *SilenceHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "无效的静默 ID",
+		})
+		return
+	}
+
+	item, err := h.silenceService.GetSilence(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    50001,
+			"message": "静默不存在",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": item,
+	})
+}
+
+// Create 创建静默
+func (h *SilenceHandler) Create(c *gin.Context) {
+	var req service.CreateSilenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		userID = uint(0)
+	}
+	username := c.GetString("username")
+
+	item, err := h.silenceService.CreateSilence(c.Request.Context(), &req, userID.(uint), username)
+	if err != nil {
+		if errors.Is(err, silence.ErrInvalidSilence) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    40001,
+				"message": "静默配置无效: " + err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "创建静默失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": item,
+	})
+}
+
+// Update 更新静默
+func (h *SilenceHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "无效的静默 ID",
+		})
+		return
+	}
+
+	var req service.UpdateSilenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	if err := h.silenceService.UpdateSilence(c.Request.Context(), uint(id), &req); err != nil {
+		if errors.Is(err, silence.ErrInvalidSilence) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    40001,
+				"message": "静默配置无效: " + err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "更新静默失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+	})
+}
+
+// Expire 结束静默
+func (h *SilenceHandler) Expire(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    40001,
+			"message": "无效的静默 ID",
+		})
+		return
+	}
+
+	if err := h.silenceService.ExpireSilence(c.Request.Context(), uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    10001,
+			"message": "结束静默失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+	})
+}
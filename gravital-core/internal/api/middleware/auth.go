@@ -1,12 +1,17 @@
 package middleware
 
 import (
+	"context"
+	"crypto/subtle"
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/celestial/gravital-core/internal/pkg/auth"
+	"github.com/celestial/gravital-core/internal/pkg/pki"
+	"github.com/celestial/gravital-core/internal/service"
 )
 
 // Auth JWT 认证中间件
@@ -108,8 +113,44 @@ func RequirePermission(required string) gin.HandlerFunc {
 	}
 }
 
+// StaticToken 静态 Bearer Token 认证中间件，用于 Alertmanager 等外部系统接入
+// token 为空时拒绝所有请求
+func StaticToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.JSON(http.StatusForbidden, gin.H{
+				"code":    20004,
+				"message": "接入 Token 未配置",
+				"error":   "Forbidden",
+			})
+			c.Abort()
+			return
+		}
+
+		authHeader := c.GetHeader("Authorization")
+		parts := strings.SplitN(authHeader, " ", 2)
+		if len(parts) != 2 || parts[0] != "Bearer" ||
+			subtle.ConstantTimeCompare([]byte(parts[1]), []byte(token)) != 1 {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"code":    20002,
+				"message": "Token 无效",
+				"error":   "InvalidToken",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// SentinelAuthenticator Sentinel 凭证校验接口
+type SentinelAuthenticator interface {
+	Authenticate(ctx context.Context, sentinelID, token string) error
+}
+
 // SentinelAuth Sentinel 认证中间件
-func SentinelAuth() gin.HandlerFunc {
+func SentinelAuth(authenticator SentinelAuthenticator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 获取 Sentinel ID
 		sentinelID := c.GetHeader("X-Sentinel-ID")
@@ -135,9 +176,30 @@ func SentinelAuth() gin.HandlerFunc {
 			return
 		}
 
-		// TODO: 验证 API Token
-		// 这里应该从数据库查询验证 token 是否有效
-		// 为了简化，暂时只检查是否存在
+		// 验证 API Token
+		if err := authenticator.Authenticate(c.Request.Context(), sentinelID, apiToken); err != nil {
+			switch {
+			case errors.Is(err, service.ErrSentinelRevoked):
+				c.JSON(http.StatusForbidden, gin.H{
+					"code":    20004,
+					"message": "Sentinel 已被吊销",
+					"error":   "Revoked",
+				})
+			case errors.Is(err, service.ErrSentinelUnauthorized):
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"code":    20002,
+					"message": "API Token 无效",
+					"error":   "InvalidToken",
+				})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"code":    10001,
+					"message": "认证失败: " + err.Error(),
+				})
+			}
+			c.Abort()
+			return
+		}
 
 		// 将 Sentinel ID 存入上下文
 		c.Set("sentinel_id", sentinelID)
@@ -146,3 +208,69 @@ func SentinelAuth() gin.HandlerFunc {
 	}
 }
 
+// SentinelCertVerifier Sentinel 客户端证书校验接口
+type SentinelCertVerifier interface {
+	VerifyCertificate(ctx context.Context, sentinelID, serial string) error
+}
+
+// SentinelCert Sentinel 客户端证书校验中间件，需要在 SentinelAuth 之后使用
+// 证书链由 TLS 握手校验，这里校验证书绑定的 Sentinel ID 与请求一致，且是该 Sentinel 当前的证书；
+// required 为 true 时必须携带证书
+func SentinelCert(verifier SentinelCertVerifier, required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tlsState := c.Request.TLS
+		if tlsState == nil || len(tlsState.VerifiedChains) == 0 {
+			if required {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"code":    20001,
+					"message": "未提供客户端证书",
+					"error":   "Unauthorized",
+				})
+				c.Abort()
+				return
+			}
+			c.Next()
+			return
+		}
+
+		cert := tlsState.VerifiedChains[0][0]
+		sentinelID := c.GetString("sentinel_id")
+		if pki.SentinelID(cert) != sentinelID {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"code":    20002,
+				"message": "客户端证书与 Sentinel ID 不匹配",
+				"error":   "InvalidCertificate",
+			})
+			c.Abort()
+			return
+		}
+
+		// 重新注册或续期后被替换的证书不再有效
+		if err := verifier.VerifyCertificate(c.Request.Context(), sentinelID, cert.SerialNumber.Text(16)); err != nil {
+			switch {
+			case errors.Is(err, service.ErrSentinelRevoked):
+				c.JSON(http.StatusForbidden, gin.H{
+					"code":    20004,
+					"message": "Sentinel 已被吊销",
+					"error":   "Revoked",
+				})
+			case errors.Is(err, service.ErrSentinelUnauthorized):
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"code":    20002,
+					"message": "客户端证书已被替换",
+					"error":   "InvalidCertificate",
+				})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"code":    10001,
+					"message": "认证失败: " + err.Error(),
+				})
+			}
+			c.Abort()
+			return
+		}
+
+		c.Set("sentinel_cert_verified", true)
+		c.Next()
+	}
+}
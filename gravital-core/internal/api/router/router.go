@@ -5,18 +5,23 @@ import (
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 
+	"github.com/celestial/gravital-core/internal/alert/silence"
 	"github.com/celestial/gravital-core/internal/api/handler"
 	"github.com/celestial/gravital-core/internal/api/middleware"
+	"github.com/celestial/gravital-core/internal/notification"
 	"github.com/celestial/gravital-core/internal/pkg/auth"
 	"github.com/celestial/gravital-core/internal/pkg/config"
 	"github.com/celestial/gravital-core/internal/pkg/logger"
+	"github.com/celestial/gravital-core/internal/pkg/pki"
 	"github.com/celestial/gravital-core/internal/repository"
 	"github.com/celestial/gravital-core/internal/service"
 	"github.com/celestial/gravital-core/internal/timeseries"
 )
 
 // Setup 设置路由
-func Setup(cfg *config.Config, db *gorm.DB) (*gin.Engine, service.ForwarderService) {
+// ca 为 Sentinel 客户端证书 CA，未启用 mTLS 时为 nil
+// notificationSvc 为 main 中创建的通知服务，与告警引擎共用
+func Setup(cfg *config.Config, db *gorm.DB, ca *pki.CA, notificationSvc notification.Service) (*gin.Engine, service.ForwarderService) {
 	r := gin.New()
 
 	// 全局中间件
@@ -40,10 +45,16 @@ func Setup(cfg *config.Config, db *gorm.DB) (*gin.Engine, service.ForwarderServi
 	userRepo := repository.NewUserRepository(db)
 	deviceRepo := repository.NewDeviceRepository(db)
 	sentinelRepo := repository.NewSentinelRepository(db)
+	sentinelCommandRepo := repository.NewSentinelCommandRepository(db)
+	enrollmentRepo := repository.NewEnrollmentRepository(db)
 	taskRepo := repository.NewTaskRepository(db)
 	alertRepo := repository.NewAlertRepository(db)
 	forwarderRepo := repository.NewForwarderRepository(db)
 	topologyRepo := repository.NewTopologyRepository(db)
+	silenceRepo := repository.NewSilenceRepository(db)
+	notificationTemplateRepo := repository.NewNotificationTemplateRepository(db)
+	notificationOutboxRepo := repository.NewNotificationOutboxRepository(db)
+	escalationRepo := repository.NewEscalationRepository(db)
 
 	// 获取 logger
 	log := logger.Get()
@@ -65,9 +76,15 @@ func Setup(cfg *config.Config, db *gorm.DB) (*gin.Engine, service.ForwarderServi
 	// 初始化 Service
 	authService := service.NewAuthService(userRepo, jwtManager, cfg.Auth.BcryptCost)
 	deviceService := service.NewDeviceService(deviceRepo, db, tsClient)
-	sentinelService := service.NewSentinelService(sentinelRepo)
+	sentinelService := service.NewSentinelService(sentinelRepo, sentinelCommandRepo, enrollmentRepo, cfg.Sentinel, ca)
+	enrollmentService := service.NewEnrollmentService(enrollmentRepo)
 	taskService := service.NewTaskService(taskRepo, deviceRepo, sentinelRepo)
-	alertService := service.NewAlertService(alertRepo)
+	alertService := service.NewAlertService(alertRepo, silence.NewChecker(db, log), notificationSvc)
+	silenceService := service.NewSilenceService(silenceRepo)
+	notificationTemplateService := service.NewNotificationTemplateService(notificationTemplateRepo, db)
+	notificationDeliveryService := service.NewNotificationDeliveryService(notificationOutboxRepo)
+	escalationService := service.NewEscalationService(escalationRepo)
+	eventIngestService := service.NewEventIngestService(db, alertRepo, notificationSvc, log)
 	forwarderService := service.NewForwarderService(forwarderRepo, cfg, log)
 	// 初始化拓扑发现服务
 	topologyDiscoveryService := service.NewTopologyDiscoveryService(topologyRepo, deviceRepo, log)
@@ -77,8 +94,14 @@ func Setup(cfg *config.Config, db *gorm.DB) (*gin.Engine, service.ForwarderServi
 	authHandler := handler.NewAuthHandler(authService)
 	deviceHandler := handler.NewDeviceHandler(deviceService)
 	sentinelHandler := handler.NewSentinelHandler(sentinelService)
+	enrollmentHandler := handler.NewEnrollmentHandler(enrollmentService)
 	taskHandler := handler.NewTaskHandler(taskService)
 	alertHandler := handler.NewAlertHandler(alertService, db)
+	silenceHandler := handler.NewSilenceHandler(silenceService)
+	notificationTemplateHandler := handler.NewNotificationTemplateHandler(notificationTemplateService)
+	notificationDeliveryHandler := handler.NewNotificationDeliveryHandler(notificationDeliveryService)
+	escalationHandler := handler.NewEscalationHandler(escalationService)
+	eventHandler := handler.NewEventHandler(eventIngestService)
 	forwarderHandler := handler.NewForwarderHandler(forwarderService, topologyService, db, log)
 	topologyHandler := handler.NewTopologyHandler(topologyService, log)
 	dashboardHandler := handler.NewDashboardHandler(db)
@@ -96,6 +119,9 @@ func Setup(cfg *config.Config, db *gorm.DB) (*gin.Engine, service.ForwarderServi
 			auth.GET("/me", middleware.Auth(jwtManager), authHandler.GetCurrentUser)
 		}
 
+		// Alertmanager webhook 接入（静态 Token 认证）
+		v1.POST("/alerts/ingest", middleware.StaticToken(cfg.Alert.IngestToken), alertHandler.IngestAlertmanager)
+
 		// 需要认证的路由
 		authenticated := v1.Group("")
 		authenticated.Use(middleware.Auth(jwtManager))
@@ -150,6 +176,60 @@ func Setup(cfg *config.Config, db *gorm.DB) (*gin.Engine, service.ForwarderServi
 				alertEvents.POST("/batch-resolve", middleware.RequirePermission("alerts.write"), alertHandler.BatchResolve)
 			}
 
+			// 告警静默
+			silences := authenticated.Group("/silences")
+			{
+				silences.GET("", silenceHandler.List)
+				silences.GET("/:id", silenceHandler.Get)
+				silences.POST("", middleware.RequirePermission("alerts.write"), silenceHandler.Create)
+				silences.PUT("/:id", middleware.RequirePermission("alerts.write"), silenceHandler.Update)
+				silences.DELETE("/:id", middleware.RequirePermission("alerts.write"), silenceHandler.Expire)
+			}
+
+			// 通知模板
+			notificationTemplates := authenticated.Group("/notification-templates")
+			{
+				notificationTemplates.GET("", notificationTemplateHandler.List)
+				notificationTemplates.GET("/:id", notificationTemplateHandler.Get)
+				notificationTemplates.POST("", middleware.RequirePermission("alerts.write"), notificationTemplateHandler.Create)
+				notificationTemplates.POST("/preview", notificationTemplateHandler.Preview)
+				notificationTemplates.PUT("/:id", middleware.RequirePermission("alerts.write"), notificationTemplateHandler.Update)
+				notificationTemplates.DELETE("/:id", middleware.RequirePermission("alerts.write"), notificationTemplateHandler.Delete)
+			}
+
+			// 通知投递队列
+			notificationDeliveries := authenticated.Group("/notification-deliveries")
+			{
+				notificationDeliveries.GET("", notificationDeliveryHandler.List)
+				notificationDeliveries.GET("/:id", notificationDeliveryHandler.Get)
+				notificationDeliveries.POST("/retry", middleware.RequirePermission("alerts.write"), notificationDeliveryHandler.BatchRetry)
+				notificationDeliveries.POST("/:id/retry", middleware.RequirePermission("alerts.write"), notificationDeliveryHandler.Retry)
+			}
+
+			// 升级策略
+			escalationPolicies := authenticated.Group("/escalation-policies")
+			{
+				escalationPolicies.GET("", escalationHandler.ListPolicies)
+				escalationPolicies.GET("/:id", escalationHandler.GetPolicy)
+				escalationPolicies.POST("", middleware.RequirePermission("alerts.write"), escalationHandler.CreatePolicy)
+				escalationPolicies.PUT("/:id", middleware.RequirePermission("alerts.write"), escalationHandler.UpdatePolicy)
+				escalationPolicies.DELETE("/:id", middleware.RequirePermission("alerts.write"), escalationHandler.DeletePolicy)
+			}
+
+			// 值班表
+			oncallSchedules := authenticated.Group("/oncall-schedules")
+			{
+				oncallSchedules.GET("", escalationHandler.ListSchedules)
+				oncallSchedules.GET("/:id", escalationHandler.GetSchedule)
+				oncallSchedules.GET("/:id/oncall", escalationHandler.GetOnCall)
+				oncallSchedules.POST("", middleware.RequirePermission("alerts.write"), escalationHandler.CreateSchedule)
+				oncallSchedules.PUT("/:id", middleware.RequirePermission("alerts.write"), escalationHandler.UpdateSchedule)
+				oncallSchedules.DELETE("/:id", middleware.RequirePermission("alerts.write"), escalationHandler.DeleteSchedule)
+				oncallSchedules.GET("/:id/overrides", escalationHandler.ListOverrides)
+				oncallSchedules.POST("/:id/overrides", middleware.RequirePermission("alerts.write"), escalationHandler.CreateOverride)
+				oncallSchedules.DELETE("/:id/overrides/:overrideId", middleware.RequirePermission("alerts.write"), escalationHandler.DeleteOverride)
+			}
+
 			// 告警统计和聚合
 			authenticated.GET("/alert-stats", alertHandler.GetStats)
 			authenticated.GET("/alert-aggregations", alertHandler.GetAggregations)
@@ -174,8 +254,27 @@ func Setup(cfg *config.Config, db *gorm.DB) (*gin.Engine, service.ForwarderServi
 				sentinels.GET("/:id", sentinelHandler.Get)
 				sentinels.DELETE("/:id", middleware.RequirePermission("sentinels.delete"), sentinelHandler.Delete)
 				sentinels.POST("/:id/control", middleware.RequirePermission("sentinels.control"), sentinelHandler.Control)
+				sentinels.GET("/:id/commands", sentinelHandler.ListCommands)
+				sentinels.POST("/:id/rotate-token", middleware.RequirePermission("sentinels.control"), sentinelHandler.RotateToken)
+				sentinels.POST("/:id/revoke", middleware.RequirePermission("sentinels.control"), sentinelHandler.Revoke)
+				sentinels.POST("/:id/approve", middleware.RequirePermission("sentinels.control"), sentinelHandler.Approve)
+				sentinels.POST("/:id/reject", middleware.RequirePermission("sentinels.control"), sentinelHandler.Reject)
 			}
 
+			// Sentinel 注册密钥
+			enrollmentKeys := authenticated.Group("/enrollment-keys")
+			{
+				enrollmentKeys.GET("", enrollmentHandler.ListKeys)
+				enrollmentKeys.GET("/:id", enrollmentHandler.GetKey)
+				enrollmentKeys.POST("", middleware.RequirePermission("sentinels.control"), enrollmentHandler.CreateKey)
+				enrollmentKeys.PUT("/:id", middleware.RequirePermission("sentinels.control"), enrollmentHandler.UpdateKey)
+				enrollmentKeys.DELETE("/:id", middleware.RequirePermission("sentinels.control"), enrollmentHandler.DeleteKey)
+				enrollmentKeys.POST("/:id/revoke", middleware.RequirePermission("sentinels.control"), enrollmentHandler.RevokeKey)
+			}
+
+			// Sentinel 注册审计
+			authenticated.GET("/sentinel-enrollments", enrollmentHandler.ListEnrollments)
+
 			// 拓扑管理
 			topologies := authenticated.Group("/topologies")
 			{
@@ -258,12 +357,24 @@ func Setup(cfg *config.Config, db *gorm.DB) (*gin.Engine, service.ForwarderServi
 		// 注册接口（不需要任何认证，因为注册的目的就是获取凭证）
 		v1.POST("/sentinels/register", sentinelHandler.Register)
 
+		// Sentinel 接口认证：API Token，启用 mTLS 时同时校验客户端证书
+		sentinelAuth := []gin.HandlerFunc{
+			middleware.SentinelAuth(sentinelService),
+			middleware.SentinelCert(sentinelService, ca != nil && cfg.Sentinel.MTLS.Required),
+		}
+
 		// Sentinel 心跳接口（需要 Sentinel 认证）
-		v1.POST("/sentinels/heartbeat", middleware.SentinelAuth(), sentinelHandler.Heartbeat)
+		v1.POST("/sentinels/heartbeat", append(sentinelAuth, sentinelHandler.Heartbeat)...)
+
+		// 客户端证书签发和续期，不强制要求证书，由服务端判断是否需要出示当前证书
+		v1.POST("/sentinels/certificate",
+			middleware.SentinelAuth(sentinelService),
+			middleware.SentinelCert(sentinelService, false),
+			sentinelHandler.IssueCertificate)
 
 		// 任务 API（Sentinel 调用）- 使用不同的路径避免冲突
 		sentinelTasks := v1.Group("/sentinel-tasks")
-		sentinelTasks.Use(middleware.SentinelAuth())
+		sentinelTasks.Use(sentinelAuth...)
 		{
 			sentinelTasks.GET("", taskHandler.GetSentinelTasks)
 			sentinelTasks.POST("/:id/report", taskHandler.ReportExecution)
@@ -271,14 +382,15 @@ func Setup(cfg *config.Config, db *gorm.DB) (*gin.Engine, service.ForwarderServi
 
 		// 数据采集 API（Sentinel 调用）
 		data := v1.Group("/data")
-		data.Use(middleware.SentinelAuth())
+		data.Use(sentinelAuth...)
 		{
 			data.POST("/ingest", forwarderHandler.IngestMetrics)
+			data.POST("/events", eventHandler.Ingest)
 		}
 
 		// 拓扑数据 API（Sentinel 调用）
 		topologyData := v1.Group("/topology")
-		topologyData.Use(middleware.SentinelAuth())
+		topologyData.Use(sentinelAuth...)
 		{
 			topologyData.POST("/lldp", topologyHandler.IngestLLDP)
 		}
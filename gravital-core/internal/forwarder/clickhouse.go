@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -72,7 +73,15 @@ func NewClickHouseForwarder(config *ForwarderConfig, logger *zap.Logger) (*Click
 	return forwarder, nil
 }
 
-// ensureTable 确保表存在
+// distributionColumnsSQL 直方图和摘要的分布数据列，其他类型的指标为零值和空数组
+const distributionColumnsSQL = `metric_sum Float64,
+			metric_count UInt64,
+			bucket_bounds Array(Float64),
+			bucket_counts Array(UInt64),
+			quantiles Array(Float64),
+			quantile_values Array(Float64)`
+
+// ensureTable 确保表存在，旧版本创建的表补充分布数据列
 func (f *ClickHouseForwarder) ensureTable() error {
 	// 创建数据库（如果不存在）
 	createDBSQL := `CREATE DATABASE IF NOT EXISTS metrics`
@@ -90,18 +99,29 @@ func (f *ClickHouseForwarder) ensureTable() error {
 			device_id String,
 			sentinel_id String,
 			labels Map(String, String),
+			%s,
 			date Date DEFAULT toDate(timestamp)
 		) ENGINE = MergeTree()
 		PARTITION BY toYYYYMM(date)
 		ORDER BY (metric_name, device_id, timestamp)
 		TTL date + INTERVAL 90 DAY
 		SETTINGS index_granularity = 8192
-	`, f.tableName)
+	`, f.tableName, distributionColumnsSQL)
 
 	if _, err := f.db.Exec(createTableSQL); err != nil {
 		return fmt.Errorf("failed to create table: %w", err)
 	}
 
+	// 补充分布数据列
+	columns := strings.Split(distributionColumnsSQL, ",\n")
+	for i, column := range columns {
+		columns[i] = "ADD COLUMN IF NOT EXISTS " + strings.TrimSpace(column)
+	}
+	alterTableSQL := fmt.Sprintf("ALTER TABLE %s %s", f.tableName, strings.Join(columns, ", "))
+	if _, err := f.db.Exec(alterTableSQL); err != nil {
+		return fmt.Errorf("failed to add distribution columns: %w", err)
+	}
+
 	return nil
 }
 
@@ -126,8 +146,9 @@ func (f *ClickHouseForwarder) Write(metrics []*Metric) error {
 
 	// 准备批量插入语句
 	insertSQL := fmt.Sprintf(`
-		INSERT INTO %s (timestamp, metric_name, metric_value, metric_type, device_id, sentinel_id, labels)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO %s (timestamp, metric_name, metric_value, metric_type, device_id, sentinel_id, labels,
+			metric_sum, metric_count, bucket_bounds, bucket_counts, quantiles, quantile_values)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, f.tableName)
 
 	stmt, err := tx.PrepareContext(ctx, insertSQL)
@@ -157,6 +178,28 @@ func (f *ClickHouseForwarder) Write(metrics []*Metric) error {
 			sentinelID = "unknown"
 		}
 
+		// 分布数据
+		var sum float64
+		var count uint64
+		bucketBounds := []float64{}
+		bucketCounts := []uint64{}
+		quantiles := []float64{}
+		quantileValues := []float64{}
+		switch {
+		case metric.Histogram != nil:
+			sum, count = metric.Histogram.Sum, metric.Histogram.Count
+			for _, b := range metric.Histogram.Buckets {
+				bucketBounds = append(bucketBounds, b.UpperBound)
+				bucketCounts = append(bucketCounts, b.Count)
+			}
+		case metric.Summary != nil:
+			sum, count = metric.Summary.Sum, metric.Summary.Count
+			for _, q := range metric.Summary.Quantiles {
+				quantiles = append(quantiles, q.Quantile)
+				quantileValues = append(quantileValues, q.Value)
+			}
+		}
+
 		// 执行插入
 		_, err := stmt.ExecContext(ctx,
 			timestamp,
@@ -166,6 +209,12 @@ func (f *ClickHouseForwarder) Write(metrics []*Metric) error {
 			deviceID,
 			sentinelID,
 			metric.Labels,
+			sum,
+			count,
+			bucketBounds,
+			bucketCounts,
+			quantiles,
+			quantileValues,
 		)
 		if err != nil {
 			f.recordError()
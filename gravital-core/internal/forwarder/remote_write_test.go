@@ -0,0 +1,77 @@
+package forwarder
+
+import (
+	"math"
+	"testing"
+)
+
+// TestToTimeSeries_Distribution 与 orbital-sentinels/internal/sender 使用相同的测试数据
+func TestToTimeSeries_Distribution(t *testing.T) {
+	metrics := []*Metric{
+		{
+			Name:      "request_seconds",
+			Value:     6,
+			Type:      "histogram",
+			Timestamp: 1700000000,
+			Labels:    map[string]string{"host": "server1"},
+			Histogram: &Histogram{
+				// 显式给出的 +Inf 桶不能产生重复序列
+				Buckets: []Bucket{{UpperBound: 0.1, Count: 2}, {UpperBound: 1, Count: 5}, {UpperBound: math.Inf(1), Count: 6}},
+				Sum:     3.2,
+				Count:   6,
+			},
+		},
+		{
+			Name:      "rpc_seconds",
+			Value:     10,
+			Type:      "summary",
+			Timestamp: 1700000000,
+			Summary: &Summary{
+				Quantiles: []Quantile{{Quantile: 0.5, Value: 0.02}},
+				Sum:       0.9,
+				Count:     10,
+			},
+		},
+	}
+
+	// 序列名 + le/quantile 标签 → 值
+	got := make(map[string]float64)
+	total := 0
+	for _, metric := range metrics {
+		for _, ts := range toTimeSeries(metric) {
+			total++
+			var name, extra string
+			for _, label := range ts.Labels {
+				switch label.Name {
+				case "__name__":
+					name = label.Value
+				case "le", "quantile":
+					extra = label.Value
+				}
+			}
+			if ts.Samples[0].Timestamp != 1700000000000 {
+				t.Errorf("%s: unexpected timestamp %d", name, ts.Samples[0].Timestamp)
+			}
+			got[name+"{"+extra+"}"] = ts.Samples[0].Value
+		}
+	}
+
+	want := map[string]float64{
+		"request_seconds_bucket{0.1}":  2,
+		"request_seconds_bucket{1}":    5,
+		"request_seconds_bucket{+Inf}": 6,
+		"request_seconds_sum{}":        3.2,
+		"request_seconds_count{}":      6,
+		"rpc_seconds{0.5}":             0.02,
+		"rpc_seconds_sum{}":            0.9,
+		"rpc_seconds_count{}":          10,
+	}
+	if total != len(want) || len(got) != len(want) {
+		t.Errorf("Expected %d timeseries, got %d: %v", len(want), total, got)
+	}
+	for key, value := range want {
+		if v, ok := got[key]; !ok || v != value {
+			t.Errorf("%s: expected %v, got %v (found=%v)", key, value, v, ok)
+		}
+	}
+}
@@ -3,12 +3,41 @@ package forwarder
 import "time"
 
 // Metric 指标数据
+// Type 为 histogram、summary 时分布数据在 Histogram、Summary 中，Value 为观测总数。
 type Metric struct {
 	Name      string            `json:"name"`
 	Value     float64           `json:"value"`
 	Type      string            `json:"type"`
 	Labels    map[string]string `json:"labels"`
 	Timestamp int64             `json:"timestamp"`
+	Histogram *Histogram        `json:"histogram,omitempty"`
+	Summary   *Summary          `json:"summary,omitempty"`
+}
+
+// Histogram 直方图，Buckets 为累计计数，按上界升序且不含 +Inf 桶（+Inf 桶的计数即 Count）
+type Histogram struct {
+	Buckets []Bucket `json:"buckets"`
+	Sum     float64  `json:"sum"`
+	Count   uint64   `json:"count"`
+}
+
+// Bucket 直方图的桶
+type Bucket struct {
+	UpperBound float64 `json:"upper_bound"`
+	Count      uint64  `json:"count"`
+}
+
+// Summary 摘要
+type Summary struct {
+	Quantiles []Quantile `json:"quantiles"`
+	Sum       float64    `json:"sum"`
+	Count     uint64     `json:"count"`
+}
+
+// Quantile 摘要的分位数
+type Quantile struct {
+	Quantile float64 `json:"quantile"`
+	Value    float64 `json:"value"`
 }
 
 // ForwarderType 转发器类型
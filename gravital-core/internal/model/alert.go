@@ -2,6 +2,13 @@ package model
 
 import "time"
 
+// 告警来源
+const (
+	AlertSourceEngine       = "engine"       // 内置告警引擎评估
+	AlertSourceAlertmanager = "alertmanager" // Alertmanager webhook 接入
+	AlertSourceEvent        = "event"        // 匹配设备推送的事件（SNMP Trap、Syslog）
+)
+
 // AlertRule 告警规则
 type AlertRule struct {
 	ID                 uint      `gorm:"primaryKey" json:"id"`
@@ -14,6 +21,8 @@ type AlertRule struct {
 	NotificationConfig JSONB     `gorm:"type:jsonb" json:"notification_config"`
 	InhibitRules       JSONB     `gorm:"type:jsonb" json:"inhibit_rules"`
 	MutePeriods        JSONB     `gorm:"type:jsonb" json:"mute_periods"`
+	Source             string    `gorm:"size:32;default:'engine'" json:"source"`
+	EscalationPolicyID *uint     `gorm:"index" json:"escalation_policy_id"`
 	Description        string    `gorm:"type:text" json:"description"`
 	CreatedBy          *uint     `json:"created_by"`
 	CreatedAt          time.Time `json:"created_at"`
@@ -31,6 +40,8 @@ type AlertEvent struct {
 	Severity         string     `gorm:"size:32" json:"severity"`
 	Message          string     `gorm:"type:text" json:"message"`
 	Labels           JSONB      `gorm:"type:jsonb" json:"labels"`
+	Source           string     `gorm:"size:32;default:'engine'" json:"source"`
+	Fingerprint      string     `gorm:"size:64;index" json:"fingerprint,omitempty"`
 	TriggeredAt      time.Time  `gorm:"index" json:"triggered_at"`
 	ResolvedAt       *time.Time `json:"resolved_at"`
 	Status           string     `gorm:"size:32;index" json:"status"`
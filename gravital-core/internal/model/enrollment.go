@@ -0,0 +1,59 @@
+package model
+
+import "time"
+
+// Sentinel 审批状态
+const (
+	SentinelApprovalApproved = "approved"
+	SentinelApprovalPending  = "pending"
+	SentinelApprovalRejected = "rejected"
+)
+
+// 注册审计结果
+const (
+	EnrollmentResultEnrolled   = "enrolled"    // 新注册
+	EnrollmentResultReEnrolled = "re_enrolled" // 已存在的 Sentinel 重新注册
+	EnrollmentResultRejected   = "rejected"    // 注册被拒绝
+)
+
+// EnrollmentKey 注册密钥
+// 采集端注册时出示密钥，密钥限定注册后的区域和默认标签，可设置有效期和最大使用次数
+type EnrollmentKey struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	Name            string     `gorm:"size:128;not null;uniqueIndex" json:"name"`
+	Description     string     `gorm:"type:text" json:"description"`
+	KeyHash         string     `gorm:"size:64;not null;uniqueIndex" json:"-"` // 密钥的 SHA-256 哈希，不保存明文
+	KeyPrefix       string     `gorm:"size:16" json:"key_prefix"`             // 密钥前缀，用于识别
+	Region          string     `gorm:"size:64" json:"region"`                 // 为空时不限制区域
+	Labels          JSONB      `gorm:"type:jsonb" json:"labels"`              // 注册时合并的默认标签
+	RequireApproval bool       `json:"require_approval"`
+	MaxUses         int        `json:"max_uses"` // 0 表示不限
+	UsedCount       int        `json:"used_count"`
+	ExpiresAt       *time.Time `json:"expires_at"`
+	RevokedAt       *time.Time `json:"revoked_at"`
+	LastUsedAt      *time.Time `json:"last_used_at"`
+	CreatedBy       *uint      `json:"created_by"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+func (EnrollmentKey) TableName() string {
+	return "enrollment_keys"
+}
+
+// SentinelEnrollment 注册审计记录
+type SentinelEnrollment struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	SentinelID      string    `gorm:"size:64;index" json:"sentinel_id"`
+	EnrollmentKeyID *uint     `gorm:"index" json:"enrollment_key_id"`
+	KeyName         string    `gorm:"size:128" json:"key_name"`
+	Hostname        string    `gorm:"size:255" json:"hostname"`
+	IPAddress       string    `gorm:"size:64" json:"ip_address"` // 注册请求的来源地址
+	Result          string    `gorm:"size:16;index" json:"result"`
+	Reason          string    `gorm:"type:text" json:"reason"`
+	CreatedAt       time.Time `gorm:"index" json:"created_at"`
+}
+
+func (SentinelEnrollment) TableName() string {
+	return "sentinel_enrollments"
+}
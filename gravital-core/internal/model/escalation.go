@@ -0,0 +1,183 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// 升级目标类型
+const (
+	EscalationTargetUser     = "user"     // 用户，通过邮件通知
+	EscalationTargetChannel  = "channel"  // 通知渠道及接收人
+	EscalationTargetSchedule = "schedule" // 值班表，通知当前值班人
+)
+
+// 告警升级状态
+const (
+	EscalationStatusActive    = "active"    // 升级中
+	EscalationStatusCompleted = "completed" // 所有级别已通知
+	EscalationStatusStopped   = "stopped"   // 告警已确认或恢复
+)
+
+// 值班轮换方式
+const (
+	RotationDaily  = "daily"
+	RotationWeekly = "weekly"
+	RotationCustom = "custom"
+)
+
+// EscalationPolicy 告警升级策略
+// 未确认的告警依次通知各级别目标，每一级在上一级通知后等待 DelayMinutes 分钟（第一级从告警触发开始计时）
+type EscalationPolicy struct {
+	ID          uint             `gorm:"primaryKey" json:"id"`
+	Name        string           `gorm:"size:128;not null;uniqueIndex" json:"name"`
+	Description string           `gorm:"type:text" json:"description"`
+	Levels      EscalationLevels `gorm:"type:jsonb;not null" json:"levels"`
+	RepeatTimes int              `json:"repeat_times"` // 最后一级通知后仍未确认时，从第一级重复的次数
+	CreatedBy   *uint            `json:"created_by"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// EscalationLevel 升级级别
+type EscalationLevel struct {
+	DelayMinutes int                `json:"delay_minutes"`
+	Targets      []EscalationTarget `json:"targets"`
+}
+
+// EscalationTarget 升级通知目标
+// Type 为 user/schedule 时 ID 为用户或值班表 ID；Type 为 channel 时使用 Channel 和 Recipients
+type EscalationTarget struct {
+	Type       string   `json:"type"`
+	ID         uint     `json:"id,omitempty"`
+	Channel    string   `json:"channel,omitempty"`
+	Recipients []string `json:"recipients,omitempty"`
+	Template   string   `json:"template,omitempty"`
+}
+
+// EscalationLevels 升级级别列表，存储为 JSONB 数组
+type EscalationLevels []EscalationLevel
+
+// Scan 实现 sql.Scanner 接口
+func (l *EscalationLevels) Scan(value interface{}) error {
+	if value == nil {
+		*l = EscalationLevels{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to unmarshal JSONB value")
+	}
+
+	return json.Unmarshal(bytes, l)
+}
+
+// Value 实现 driver.Valuer 接口
+func (l EscalationLevels) Value() (driver.Value, error) {
+	if len(l) == 0 {
+		return "[]", nil
+	}
+	return json.Marshal(l)
+}
+
+// OnCallSchedule 值班表
+// 由多层轮换组成，后面的层优先级更高；覆盖（OnCallOverride）优先于所有层
+type OnCallSchedule struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	Name        string         `gorm:"size:128;not null;uniqueIndex" json:"name"`
+	Description string         `gorm:"type:text" json:"description"`
+	Timezone    string         `gorm:"size:64" json:"timezone"`
+	Layers      ScheduleLayers `gorm:"type:jsonb;not null" json:"layers"`
+	CreatedBy   *uint          `json:"created_by"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// ScheduleLayer 值班层
+// UserIDs 按顺序轮换，从 Start 开始每个轮换周期换一人；Restrictions 限定该层每周生效的时间窗口，留空表示全天
+type ScheduleLayer struct {
+	Name          string           `json:"name"`
+	UserIDs       []uint           `json:"user_ids"`
+	Start         time.Time        `json:"start"`
+	RotationType  string           `json:"rotation_type"`  // daily/weekly/custom
+	RotationHours int              `json:"rotation_hours"` // custom 时的轮换周期（小时）
+	Restrictions  []ScheduleWindow `json:"restrictions,omitempty"`
+}
+
+// ScheduleWindow 每周重复的时间窗口，写法与规则静默时段相同
+type ScheduleWindow struct {
+	Weekdays []string `json:"weekdays"`
+	Start    string   `json:"start"`
+	End      string   `json:"end"`
+}
+
+// ScheduleLayers 值班层列表，存储为 JSONB 数组
+type ScheduleLayers []ScheduleLayer
+
+// Scan 实现 sql.Scanner 接口
+func (l *ScheduleLayers) Scan(value interface{}) error {
+	if value == nil {
+		*l = ScheduleLayers{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to unmarshal JSONB value")
+	}
+
+	return json.Unmarshal(bytes, l)
+}
+
+// Value 实现 driver.Valuer 接口
+func (l ScheduleLayers) Value() (driver.Value, error) {
+	if len(l) == 0 {
+		return "[]", nil
+	}
+	return json.Marshal(l)
+}
+
+// OnCallOverride 值班覆盖，在 [StartsAt, EndsAt) 内由指定用户值班
+type OnCallOverride struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	ScheduleID uint      `gorm:"not null;index" json:"schedule_id"`
+	UserID     uint      `gorm:"not null" json:"user_id"`
+	StartsAt   time.Time `gorm:"not null" json:"starts_at"`
+	EndsAt     time.Time `gorm:"not null" json:"ends_at"`
+	Comment    string    `gorm:"type:text" json:"comment"`
+	CreatedBy  *uint     `json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// AlertEscalation 告警事件的升级进度
+type AlertEscalation struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	AlertEventID   uint       `gorm:"not null;uniqueIndex" json:"alert_event_id"`
+	PolicyID       uint       `gorm:"not null" json:"policy_id"`
+	Level          int        `json:"level"`   // 下一个要通知的级别（从 0 开始）
+	Repeats        int        `json:"repeats"` // 已重复的轮数
+	Status         string     `gorm:"size:32;not null;index" json:"status"`
+	NextAt         *time.Time `gorm:"index" json:"next_at"`
+	LastNotifiedAt *time.Time `json:"last_notified_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func (EscalationPolicy) TableName() string {
+	return "escalation_policies"
+}
+
+func (OnCallSchedule) TableName() string {
+	return "oncall_schedules"
+}
+
+func (OnCallOverride) TableName() string {
+	return "oncall_overrides"
+}
+
+func (AlertEscalation) TableName() string {
+	return "alert_escalations"
+}
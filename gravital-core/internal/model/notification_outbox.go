@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// NotificationOutbox 通知发送队列（outbox）
+// 告警通知先持久化到队列，再由后台投递，失败时按渠道退避重试，超过最大次数后进入死信状态
+type NotificationOutbox struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	NotificationID string     `gorm:"size:255;index" json:"notification_id"`
+	AlertEventID   uint       `gorm:"index" json:"alert_event_id"`
+	AlertID        string     `gorm:"size:255" json:"alert_id"`
+	AlertRuleID    uint       `json:"alert_rule_id"`
+	Channel        string     `gorm:"size:32;not null" json:"channel"`
+	Recipient      string     `gorm:"size:255" json:"recipient"`
+	Subject        string     `gorm:"type:text" json:"subject"`
+	Content        string     `gorm:"type:text" json:"content"`
+	Templated      bool       `json:"templated"`
+	Transition     string     `gorm:"size:32;default:firing" json:"transition"` // firing/resolved/acknowledged
+	ThreadID       string     `gorm:"size:255" json:"thread_id"`
+	Priority       string     `gorm:"size:16" json:"priority"`
+	Metadata       JSONB      `gorm:"type:jsonb" json:"metadata"`
+	Status         string     `gorm:"size:32;not null;index" json:"status"` // pending/sending/sent/failed/dead_letter
+	Attempts       int        `json:"attempts"`
+	MaxAttempts    int        `json:"max_attempts"`
+	NextAttemptAt  time.Time  `gorm:"index" json:"next_attempt_at"`
+	LastError      string     `gorm:"type:text" json:"last_error"`
+	SentAt         *time.Time `json:"sent_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func (NotificationOutbox) TableName() string {
+	return "notification_outbox"
+}
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// NotificationTemplate 通知模板
+// 同名模板可按渠道提供不同版本（如钉钉/企业微信使用 Markdown，邮件使用 HTML），Channel 为空表示通用版本
+type NotificationTemplate struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Name        string    `gorm:"size:128;not null;uniqueIndex:idx_notification_templates_name_channel" json:"name"`
+	Channel     string    `gorm:"size:32;uniqueIndex:idx_notification_templates_name_channel" json:"channel"`
+	Subject     string    `gorm:"type:text" json:"subject"`
+	Content     string    `gorm:"type:text;not null" json:"content"`
+	Description string    `gorm:"type:text" json:"description"`
+	CreatedBy   *uint     `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (NotificationTemplate) TableName() string {
+	return "notification_templates"
+}
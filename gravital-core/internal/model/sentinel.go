@@ -4,34 +4,49 @@ import "time"
 
 // Sentinel 采集端模型
 type Sentinel struct {
-	ID            uint       `gorm:"primaryKey" json:"id"`
-	SentinelID    string     `gorm:"uniqueIndex;size:64;not null" json:"sentinel_id"`
-	Name          string     `gorm:"size:255" json:"name"`
-	Hostname      string     `gorm:"size:255" json:"hostname"`
-	IPAddress     string     `gorm:"size:64" json:"ip_address"`
-	Version       string     `gorm:"size:32" json:"version"`
-	OS            string     `gorm:"size:64" json:"os"`
-	Arch          string     `gorm:"size:32" json:"arch"`
-	Region        string     `gorm:"size:64;index" json:"region"`
-	Labels        JSONB      `gorm:"type:jsonb" json:"labels"`
-	APIToken      string     `gorm:"size:255" json:"-"` // 不返回给前端
-	Status        string     `gorm:"size:32;index" json:"status"`
-	LastHeartbeat *time.Time `json:"last_heartbeat"`
-	RegisteredAt  time.Time  `json:"registered_at"`
-	UpdatedAt     time.Time  `json:"updated_at"`
+	ID                     uint       `gorm:"primaryKey" json:"id"`
+	SentinelID             string     `gorm:"uniqueIndex;size:64;not null" json:"sentinel_id"`
+	Name                   string     `gorm:"size:255" json:"name"`
+	Hostname               string     `gorm:"size:255" json:"hostname"`
+	IPAddress              string     `gorm:"size:64" json:"ip_address"`
+	Version                string     `gorm:"size:32" json:"version"`
+	OS                     string     `gorm:"size:64" json:"os"`
+	Arch                   string     `gorm:"size:32" json:"arch"`
+	Region                 string     `gorm:"size:64;index" json:"region"`
+	Labels                 JSONB      `gorm:"type:jsonb" json:"labels"`
+	APITokenHash           string     `gorm:"size:64" json:"-"` // API Token 的 SHA-256 哈希，不保存明文
+	PreviousTokenHash      string     `gorm:"size:64" json:"-"` // 轮换前的 Token，重叠期内仍然有效
+	PreviousTokenExpiresAt *time.Time `json:"-"`
+	PendingTokenHash       string     `gorm:"size:64" json:"-"` // 已下发、等待采集端启用的新 Token
+	PendingTokenOverlap    int        `json:"-"`                // 新 Token 生效后旧 Token 的重叠秒数
+	TokenRotatedAt         *time.Time `json:"token_rotated_at"`
+	RevokedAt              *time.Time `json:"revoked_at"`
+	ApprovalStatus         string     `gorm:"size:16;index;default:approved" json:"approval_status"` // approved/pending/rejected
+	ApprovedBy             *uint      `json:"approved_by"`                                           // 审批人（通过或拒绝）
+	ApprovedAt             *time.Time `json:"approved_at"`
+	EnrollmentKeyID        *uint      `json:"enrollment_key_id"`          // 最近一次注册使用的密钥
+	CertSerial             string     `gorm:"size:64" json:"cert_serial"` // 最近签发的客户端证书序列号（十六进制）
+	CertExpiresAt          *time.Time `json:"cert_expires_at"`
+	PreviousCertSerial     string     `gorm:"size:64" json:"-"` // 续期前的证书序列号，重叠期内仍然有效
+	PreviousCertExpiresAt  *time.Time `json:"-"`
+	Status                 string     `gorm:"size:32;index" json:"status"`
+	LastHeartbeat          *time.Time `json:"last_heartbeat"`
+	Plugins                JSONB      `gorm:"type:jsonb" json:"plugins"` // 已加载插件及版本（名称 -> 版本），随心跳更新
+	RegisteredAt           time.Time  `json:"registered_at"`
+	UpdatedAt              time.Time  `json:"updated_at"`
 }
 
 // SentinelHeartbeat 心跳记录
 type SentinelHeartbeat struct {
-	ID           uint      `gorm:"primaryKey" json:"id"`
-	SentinelID   string    `gorm:"size:64;not null;index:idx_sentinel_time" json:"sentinel_id"`
-	CPUUsage     float64   `json:"cpu_usage"`
-	MemoryUsage  float64   `json:"memory_usage"`
-	DiskUsage    float64   `json:"disk_usage"`
-	TaskCount    int       `json:"task_count"`
-	PluginCount  int       `json:"plugin_count"`
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	SentinelID    string    `gorm:"size:64;not null;index:idx_sentinel_time" json:"sentinel_id"`
+	CPUUsage      float64   `json:"cpu_usage"`
+	MemoryUsage   float64   `json:"memory_usage"`
+	DiskUsage     float64   `json:"disk_usage"`
+	TaskCount     int       `json:"task_count"`
+	PluginCount   int       `json:"plugin_count"`
 	UptimeSeconds int64     `json:"uptime_seconds"`
-	ReceivedAt   time.Time `gorm:"index:idx_sentinel_time" json:"received_at"`
+	ReceivedAt    time.Time `gorm:"index:idx_sentinel_time" json:"received_at"`
 }
 
 // TableName 指定表名
@@ -42,4 +57,3 @@ func (Sentinel) TableName() string {
 func (SentinelHeartbeat) TableName() string {
 	return "sentinel_heartbeats"
 }
-
@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// 控制命令类型
+const (
+	SentinelCommandReloadConfig      = "reload_config"      // 重新加载本地配置
+	SentinelCommandRestartScheduler  = "restart_scheduler"  // 重启调度器
+	SentinelCommandRefetchTasks      = "refetch_tasks"      // 立即从中心端拉取任务
+	SentinelCommandRunTask           = "run_task"           // 立即执行指定任务
+	SentinelCommandRotateToken       = "rotate_token"       // 轮换 API Token
+	SentinelCommandUploadDiagnostics = "upload_diagnostics" // 上报诊断信息
+	SentinelCommandShutdown          = "shutdown"           // 停止采集端
+)
+
+// 控制命令状态
+const (
+	SentinelCommandStatusPending   = "pending"
+	SentinelCommandStatusDelivered = "delivered"
+	SentinelCommandStatusSucceeded = "succeeded"
+	SentinelCommandStatusFailed    = "failed"
+	SentinelCommandStatusExpired   = "expired"
+)
+
+// SentinelCommand Sentinel 控制命令
+// 命令按 Sentinel 排队，随心跳响应下发，执行结果在后续心跳中回传
+type SentinelCommand struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	SentinelID  string     `gorm:"size:64;not null;index" json:"sentinel_id"`
+	Type        string     `gorm:"size:32;not null" json:"type"`
+	Payload     JSONB      `gorm:"type:jsonb" json:"payload"`
+	Status      string     `gorm:"size:16;not null;index" json:"status"` // pending/delivered/succeeded/failed/expired
+	Result      JSONB      `gorm:"type:jsonb" json:"result"`
+	Error       string     `gorm:"type:text" json:"error"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	DeliveredAt *time.Time `json:"delivered_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	CreatedBy   *uint      `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func (SentinelCommand) TableName() string {
+	return "sentinel_commands"
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// 静默匹配类型
+const (
+	SilenceMatchEqual    = "eq"
+	SilenceMatchNotEqual = "neq"
+	SilenceMatchRegex    = "regex"
+)
+
+// 静默状态
+const (
+	SilenceStatePending = "pending"
+	SilenceStateActive  = "active"
+	SilenceStateExpired = "expired"
+)
+
+// AlertSilence 告警静默
+// 所有匹配器都满足的告警在 [StartsAt, EndsAt) 时间段内不发送通知
+type AlertSilence struct {
+	ID        uint            `gorm:"primaryKey" json:"id"`
+	Matchers  SilenceMatchers `gorm:"type:jsonb;not null" json:"matchers"`
+	StartsAt  time.Time       `gorm:"not null;index" json:"starts_at"`
+	EndsAt    time.Time       `gorm:"not null;index" json:"ends_at"`
+	CreatedBy *uint           `json:"created_by"`
+	Creator   string          `gorm:"size:64" json:"creator"`
+	Comment   string          `gorm:"type:text" json:"comment"`
+	State     string          `gorm:"-" json:"state"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// SilenceMatcher 静默匹配器
+// Name 可以是 device_id、rule_name、rule_id、severity、metric_name，其他名称匹配事件或设备标签
+// 与抑制规则（engine.InhibitRule）使用相同的标签名
+type SilenceMatcher struct {
+	Name  string `json:"name" binding:"required"`
+	Value string `json:"value"`
+	Type  string `json:"type" binding:"required,oneof=eq neq regex"`
+}
+
+// SilenceMatchers 匹配器列表，存储为 JSONB 数组
+type SilenceMatchers []SilenceMatcher
+
+// Scan 实现 sql.Scanner 接口
+func (m *SilenceMatchers) Scan(value interface{}) error {
+	if value == nil {
+		*m = SilenceMatchers{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to unmarshal JSONB value")
+	}
+
+	return json.Unmarshal(bytes, m)
+}
+
+// Value 实现 driver.Valuer 接口
+func (m SilenceMatchers) Value() (driver.Value, error) {
+	if len(m) == 0 {
+		return "[]", nil
+	}
+	return json.Marshal(m)
+}
+
+// StateAt 计算静默在指定时间的状态
+func (s *AlertSilence) StateAt(now time.Time) string {
+	if now.Before(s.StartsAt) {
+		return SilenceStatePending
+	}
+	if now.Before(s.EndsAt) {
+		return SilenceStateActive
+	}
+	return SilenceStateExpired
+}
+
+func (AlertSilence) TableName() string {
+	return "alert_silences"
+}
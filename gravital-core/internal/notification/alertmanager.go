@@ -0,0 +1,153 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// AlertmanagerSender Alertmanager 发送器，通过 v2 API 推送告警
+type AlertmanagerSender struct {
+	config *AlertmanagerConfig
+	client *http.Client
+	logger *zap.Logger
+}
+
+// alertmanagerAlert Alertmanager v2 postableAlert
+type alertmanagerAlert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	StartsAt     string            `json:"startsAt,omitempty"`
+	EndsAt       string            `json:"endsAt,omitempty"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+// NewAlertmanagerSender 创建 Alertmanager 发送器
+func NewAlertmanagerSender(config *AlertmanagerConfig, logger *zap.Logger) *AlertmanagerSender {
+	timeout := 30 * time.Second
+	if config.Timeout > 0 {
+		timeout = time.Duration(config.Timeout) * time.Second
+	}
+
+	return &AlertmanagerSender{
+		config: config,
+		client: &http.Client{
+			Timeout: timeout,
+		},
+		logger: logger,
+	}
+}
+
+// Name 获取发送器名称
+func (s *AlertmanagerSender) Name() string {
+	return "Alertmanager"
+}
+
+// Validate 验证配置
+func (s *AlertmanagerSender) Validate() error {
+	if s.config.URL == "" {
+		return fmt.Errorf("alertmanager URL is required")
+	}
+	return nil
+}
+
+// Send 推送告警到 Alertmanager
+// 接收人为 http(s) 地址时作为目标 Alertmanager，否则使用配置中的地址
+func (s *AlertmanagerSender) Send(ctx context.Context, notification *Notification) error {
+	baseURL := s.config.URL
+	if strings.HasPrefix(notification.Recipient, "http://") || strings.HasPrefix(notification.Recipient, "https://") {
+		baseURL = notification.Recipient
+	}
+	url := strings.TrimSuffix(baseURL, "/") + "/api/v2/alerts"
+
+	jsonData, err := json.Marshal([]alertmanagerAlert{s.buildAlert(notification)})
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// 设置请求头
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Celestial-Alert-System/1.0")
+	if s.config.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+s.config.BearerToken)
+	} else if s.config.Username != "" {
+		req.SetBasicAuth(s.config.Username, s.config.Password)
+	}
+
+	s.logger.Debug("Sending alert to Alertmanager",
+		zap.String("url", url),
+		zap.String("alert_id", notification.AlertID))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send alert: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("alertmanager returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// buildAlert 将通知转换为 Alertmanager 告警
+func (s *AlertmanagerSender) buildAlert(notification *Notification) alertmanagerAlert {
+	labels := map[string]string{
+		"source": "celestial",
+	}
+	if eventLabels, ok := notification.Metadata["labels"].(map[string]interface{}); ok {
+		for k, v := range eventLabels {
+			labels[k] = fmt.Sprint(v)
+		}
+	}
+	for _, key := range []string{"device_id", "metric_name", "severity"} {
+		if v, ok := notification.Metadata[key].(string); ok && v != "" {
+			labels[key] = v
+		}
+	}
+
+	alertName, _ := notification.Metadata["rule_name"].(string)
+	if alertName == "" {
+		alertName = fmt.Sprintf("celestial_rule_%d", notification.AlertRuleID)
+	}
+	labels["alertname"] = alertName
+	labels["alert_id"] = notification.AlertID
+
+	alert := alertmanagerAlert{
+		Labels: labels,
+		Annotations: map[string]string{
+			"summary":     notification.Subject,
+			"description": notification.Content,
+		},
+	}
+
+	if triggeredAt, ok := notification.Metadata["triggered_at"].(time.Time); ok && !triggeredAt.IsZero() {
+		alert.StartsAt = triggeredAt.Format(time.RFC3339)
+	}
+	// 已解决的告警设置结束时间，Alertmanager 据此发送恢复通知
+	if status, _ := notification.Metadata["status"].(string); status == "resolved" {
+		alert.EndsAt = time.Now().Format(time.RFC3339)
+	}
+	if s.config.GeneratorURL != "" {
+		if eventID, ok := notification.Metadata["event_id"]; ok {
+			alert.GeneratorURL = fmt.Sprintf("%s/%v", strings.TrimSuffix(s.config.GeneratorURL, "/"), eventID)
+		}
+	}
+
+	return alert
+}
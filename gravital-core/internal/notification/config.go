@@ -0,0 +1,66 @@
+package notification
+
+// ParseNotificationConfig 解析告警规则中的通知配置
+func ParseNotificationConfig(config map[string]interface{}) *NotificationConfig {
+	notifConfig := &NotificationConfig{
+		Enabled:        true,
+		DedupeInterval: 300, // 默认 5 分钟
+	}
+
+	if enabled, ok := config["enabled"].(bool); ok {
+		notifConfig.Enabled = enabled
+	}
+
+	if interval, ok := config["dedupe_interval"].(float64); ok {
+		notifConfig.DedupeInterval = int(interval)
+	}
+
+	if notifyResolved, ok := config["notify_resolved"].(bool); ok {
+		notifConfig.NotifyResolved = notifyResolved
+	}
+
+	if notifyAcknowledged, ok := config["notify_acknowledged"].(bool); ok {
+		notifConfig.NotifyAcknowledged = notifyAcknowledged
+	}
+
+	// 解析通知渠道
+	if channels, ok := config["channels"].([]interface{}); ok {
+		for _, ch := range channels {
+			if channelMap, ok := ch.(map[string]interface{}); ok {
+				channelConfig := ChannelConfig{}
+
+				if channel, ok := channelMap["channel"].(string); ok {
+					channelConfig.Channel = Channel(channel)
+				}
+
+				if enabled, ok := channelMap["enabled"].(bool); ok {
+					channelConfig.Enabled = enabled
+				}
+
+				if tpl, ok := channelMap["template"].(string); ok {
+					channelConfig.Template = tpl
+				}
+
+				if tpl, ok := channelMap["resolved_template"].(string); ok {
+					channelConfig.ResolvedTemplate = tpl
+				}
+
+				if tpl, ok := channelMap["acknowledged_template"].(string); ok {
+					channelConfig.AcknowledgedTemplate = tpl
+				}
+
+				if recipients, ok := channelMap["recipients"].([]interface{}); ok {
+					for _, recipient := range recipients {
+						if recipientStr, ok := recipient.(string); ok {
+							channelConfig.Recipients = append(channelConfig.Recipients, recipientStr)
+						}
+					}
+				}
+
+				notifConfig.Channels = append(notifConfig.Channels, channelConfig)
+			}
+		}
+	}
+
+	return notifConfig
+}
@@ -104,6 +104,10 @@ func (s *DingTalkSender) Send(ctx context.Context, notification *Notification) e
 
 // buildMessage 构建钉钉消息
 func (s *DingTalkSender) buildMessage(notification *Notification) map[string]interface{} {
+	if notification.Templated {
+		return s.wrapMessage(notification.Subject, notification.Content)
+	}
+	
 	// 构建 Markdown 内容
 	content := fmt.Sprintf("### %s\n\n", notification.Subject)
 	content += fmt.Sprintf("**优先级**: %s\n\n", s.getPriorityText(notification.Priority))
@@ -119,10 +123,15 @@ func (s *DingTalkSender) buildMessage(notification *Notification) map[string]int
 	
 	content += fmt.Sprintf("\n> 发送时间: %s", notification.CreatedAt.Format("2006-01-02 15:04:05"))
 	
+	return s.wrapMessage(notification.Subject, content)
+}
+
+// wrapMessage 封装钉钉 Markdown 消息
+func (s *DingTalkSender) wrapMessage(title, content string) map[string]interface{} {
 	message := map[string]interface{}{
 		"msgtype": "markdown",
 		"markdown": map[string]interface{}{
-			"title": notification.Subject,
+			"title": title,
 			"text":  content,
 		},
 	}
@@ -2,7 +2,9 @@ package notification
 
 import (
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
+	"encoding/hex"
 	"fmt"
 	"net/smtp"
 	"strings"
@@ -124,10 +126,28 @@ func (s *EmailSender) buildMessage(notification *Notification) string {
 	builder.WriteString(fmt.Sprintf("From: %s\r\n", s.config.From))
 	builder.WriteString(fmt.Sprintf("To: %s\r\n", notification.Recipient))
 	builder.WriteString(fmt.Sprintf("Subject: %s\r\n", notification.Subject))
+	// 同一告警的邮件归入同一会话：触发邮件的 Message-ID 由告警 ID 生成，
+	// 升级、恢复、确认邮件通过 In-Reply-To/References 引用触发邮件
+	if notification.ThreadID != "" {
+		thread := messageID(notification.ThreadID)
+		if isThreadStart(notification) {
+			builder.WriteString(fmt.Sprintf("Message-ID: %s\r\n", thread))
+		} else {
+			builder.WriteString(fmt.Sprintf("Message-ID: %s\r\n", replyMessageID(notification)))
+			builder.WriteString(fmt.Sprintf("In-Reply-To: %s\r\n", thread))
+			builder.WriteString(fmt.Sprintf("References: %s\r\n", thread))
+		}
+	}
 	builder.WriteString("MIME-Version: 1.0\r\n")
 	builder.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
 	builder.WriteString("\r\n")
 	
+	// 模板已渲染为 HTML，直接作为正文
+	if notification.Templated {
+		builder.WriteString(notification.Content)
+		return builder.String()
+	}
+	
 	// 邮件正文（HTML 格式）
 	builder.WriteString("<html><body>")
 	builder.WriteString("<div style='font-family: Arial, sans-serif;'>")
@@ -169,6 +189,30 @@ func (s *EmailSender) buildMessage(notification *Notification) string {
 	return builder.String()
 }
 
+// isThreadStart 判断是否为告警的首封触发邮件（非升级）
+func isThreadStart(notification *Notification) bool {
+	if notification.Transition != "" && notification.Transition != TransitionFiring {
+		return false
+	}
+	_, escalated := notification.Metadata["escalation_level"]
+	return !escalated
+}
+
+// replyMessageID 后续邮件的 Message-ID，重试时保持不变
+func replyMessageID(notification *Notification) string {
+	if outboxID, ok := notification.Metadata["outbox_id"]; ok {
+		return messageID(fmt.Sprintf("%s\x00%v", notification.ID, outboxID))
+	}
+	return messageID(notification.ID)
+}
+
+// messageID 以 key 的哈希作为 Message-ID 的本地部分
+// 告警 ID 中含规则名，可能有空格、中文或尖括号，不能直接用于 msg-id
+func messageID(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return fmt.Sprintf("<%s@celestial>", hex.EncodeToString(sum[:16]))
+}
+
 // getPriorityColor 获取优先级颜色
 func (s *EmailSender) getPriorityColor(priority Priority) string {
 	switch priority {
@@ -0,0 +1,70 @@
+package notification
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// headerValue 获取邮件头的值，不存在时返回空
+func headerValue(message, name string) string {
+	head := strings.SplitN(message, "\r\n\r\n", 2)[0]
+	for _, line := range strings.Split(head, "\r\n") {
+		if strings.HasPrefix(line, name+": ") {
+			return strings.TrimPrefix(line, name+": ")
+		}
+	}
+	return ""
+}
+
+// msgIDPattern RFC 5322 msg-id 中 dot-atom 形式的一个子集
+var msgIDPattern = regexp.MustCompile(`^<[0-9a-f]+@celestial>$`)
+
+func TestEmailThreadHeaders(t *testing.T) {
+	sender := NewEmailSender(&EmailConfig{From: "alert@example.com"}, zap.NewNop())
+	// 告警 ID 含规则名，可能有空格、中文和尖括号
+	alertID := "1:CPU 使用率 <过高>:dev-1"
+	thread := messageID(alertID)
+
+	tests := []struct {
+		name       string
+		transition Transition
+		metadata   map[string]interface{}
+		messageID  string
+		inReplyTo  string
+	}{
+		{"firing", TransitionFiring, nil, thread, ""},
+		{"escalation", TransitionFiring, map[string]interface{}{"escalation_level": 2, "outbox_id": 9}, messageID("notif-1\x009"), thread},
+		{"resolved", TransitionResolved, map[string]interface{}{"outbox_id": 10}, messageID("notif-1\x0010"), thread},
+		{"acknowledged", TransitionAcknowledged, nil, messageID("notif-1"), thread},
+	}
+
+	for _, tt := range tests {
+		message := sender.buildMessage(&Notification{
+			ID:         "notif-1",
+			Recipient:  "ops@example.com",
+			Subject:    "test",
+			Content:    "body",
+			Templated:  true,
+			Transition: tt.transition,
+			ThreadID:   alertID,
+			Metadata:   tt.metadata,
+			CreatedAt:  time.Now(),
+		})
+
+		if got := headerValue(message, "Message-ID"); got != tt.messageID {
+			t.Errorf("%s: Message-ID = %q, want %q", tt.name, got, tt.messageID)
+		} else if !msgIDPattern.MatchString(got) {
+			t.Errorf("%s: invalid Message-ID %q", tt.name, got)
+		}
+		if got := headerValue(message, "In-Reply-To"); got != tt.inReplyTo {
+			t.Errorf("%s: In-Reply-To = %q, want %q", tt.name, got, tt.inReplyTo)
+		}
+		if got := headerValue(message, "References"); got != tt.inReplyTo {
+			t.Errorf("%s: References = %q, want %q", tt.name, got, tt.inReplyTo)
+		}
+	}
+}
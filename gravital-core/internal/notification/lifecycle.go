@@ -0,0 +1,230 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/celestial/gravital-core/internal/model"
+)
+
+// SendLifecycle 发送告警恢复、确认通知
+// 只发往已收到触发通知的渠道和接收人，未发送过触发通知（如被静默）的告警不会通知
+func (s *service) SendLifecycle(ctx context.Context, event *model.AlertEvent, transition Transition, config *NotificationConfig) error {
+	if config == nil || !config.Enabled {
+		return nil
+	}
+	switch transition {
+	case TransitionResolved:
+		if !config.NotifyResolved {
+			return nil
+		}
+	case TransitionAcknowledged:
+		if !config.NotifyAcknowledged {
+			return nil
+		}
+	default:
+		return fmt.Errorf("unsupported transition: %s", transition)
+	}
+
+	// 同一事件的同一生命周期变化只通知一次
+	var sent int64
+	if err := s.db.WithContext(ctx).Model(&model.NotificationOutbox{}).
+		Where("alert_event_id = ? AND transition = ?", event.ID, string(transition)).
+		Count(&sent).Error; err != nil {
+		return fmt.Errorf("failed to check lifecycle notifications: %w", err)
+	}
+	if sent > 0 {
+		return nil
+	}
+
+	var targets []model.NotificationOutbox
+	if err := s.db.WithContext(ctx).Model(&model.NotificationOutbox{}).
+		Distinct("channel", "recipient").
+		Where("alert_event_id = ? AND transition = ?", event.ID, string(TransitionFiring)).
+		Find(&targets).Error; err != nil {
+		return fmt.Errorf("failed to load firing notifications: %w", err)
+	}
+	if len(targets) == 0 {
+		s.logger.Debug("Lifecycle notification skipped, alert was never notified",
+			zap.String("alert_id", event.AlertID),
+			zap.String("transition", string(transition)))
+		return nil
+	}
+
+	duration := lifecycleDuration(event, transition)
+	actor := s.lifecycleActor(ctx, event, transition)
+	subject := lifecycleSubject(event, transition)
+	content := s.formatLifecycleContent(event, transition, duration, actor)
+	ruleName := s.ruleName(ctx, event)
+
+	var tplRule *model.AlertRule
+	var tplDevice *model.Device
+	tplLoaded := false
+
+	var notifications []*Notification
+	for _, target := range targets {
+		channel := Channel(target.Channel)
+		// Alertmanager 没有确认的概念，只同步恢复
+		if channel == ChannelAlertmanager && transition == TransitionAcknowledged {
+			continue
+		}
+
+		channelSubject, channelContent, templated := subject, content, false
+		if name := lifecycleTemplate(config, channel, transition); name != "" {
+			if !tplLoaded {
+				tplRule, tplDevice = s.loadTemplateContext(ctx, event)
+				tplLoaded = true
+			}
+			data := NewTemplateData(event, tplRule, tplDevice, channel)
+			data.Transition = string(transition)
+			data.Duration = humanizeDuration(duration)
+			data.Actor = actor
+			tplSubject, tplContent, err := s.renderTemplate(ctx, name, channel, data)
+			if err != nil {
+				s.logger.Warn("Failed to render notification template, using default content",
+					zap.String("template", name),
+					zap.String("channel", string(channel)),
+					zap.Error(err))
+			} else {
+				channelContent = tplContent
+				templated = true
+				if tplSubject != "" {
+					channelSubject = tplSubject
+				}
+			}
+		}
+
+		notifications = append(notifications, &Notification{
+			ID:          fmt.Sprintf("notif-%s-%s-%s-%d", event.AlertID, transition, channel, time.Now().Unix()),
+			Channel:     channel,
+			Recipient:   target.Recipient,
+			Subject:     channelSubject,
+			Content:     channelContent,
+			Templated:   templated,
+			Transition:  transition,
+			ThreadID:    event.AlertID,
+			Priority:    PriorityNormal,
+			AlertID:     event.AlertID,
+			AlertRuleID: event.RuleID,
+			CreatedAt:   time.Now(),
+			Metadata: map[string]interface{}{
+				"event_id":     event.ID,
+				"device_id":    event.DeviceID,
+				"metric_name":  event.MetricName,
+				"rule_name":    ruleName,
+				"severity":     event.Severity,
+				"status":       event.Status,
+				"transition":   string(transition),
+				"duration":     humanizeDuration(duration),
+				"labels":       map[string]interface{}(event.Labels),
+				"triggered_at": event.TriggeredAt,
+			},
+		})
+		if actor != "" {
+			notifications[len(notifications)-1].Metadata["acknowledged_by"] = actor
+		}
+	}
+
+	if err := s.enqueue(ctx, event, notifications); err != nil {
+		return fmt.Errorf("failed to enqueue notifications: %w", err)
+	}
+	return nil
+}
+
+// lifecycleTemplate 获取渠道配置的生命周期通知模板
+func lifecycleTemplate(config *NotificationConfig, channel Channel, transition Transition) string {
+	for _, ch := range config.Channels {
+		if ch.Channel != channel {
+			continue
+		}
+		if transition == TransitionResolved {
+			return ch.ResolvedTemplate
+		}
+		return ch.AcknowledgedTemplate
+	}
+	return ""
+}
+
+// lifecycleDuration 计算告警从触发到恢复或确认的时长
+func lifecycleDuration(event *model.AlertEvent, transition Transition) time.Duration {
+	end := time.Now()
+	switch transition {
+	case TransitionResolved:
+		if event.ResolvedAt != nil {
+			end = *event.ResolvedAt
+		}
+	case TransitionAcknowledged:
+		if event.AcknowledgedAt != nil {
+			end = *event.AcknowledgedAt
+		}
+	}
+	return end.Sub(event.TriggeredAt)
+}
+
+// lifecycleActor 获取确认人用户名
+func (s *service) lifecycleActor(ctx context.Context, event *model.AlertEvent, transition Transition) string {
+	if transition != TransitionAcknowledged || event.AcknowledgedBy == nil {
+		return ""
+	}
+	var user model.User
+	if err := s.db.WithContext(ctx).Select("id", "username").First(&user, *event.AcknowledgedBy).Error; err != nil {
+		return fmt.Sprintf("user-%d", *event.AcknowledgedBy)
+	}
+	return user.Username
+}
+
+// lifecycleSubject 生命周期通知标题
+func lifecycleSubject(event *model.AlertEvent, transition Transition) string {
+	if transition == TransitionResolved {
+		return fmt.Sprintf("[已恢复] [%s] %s", event.Severity, event.Message)
+	}
+	return fmt.Sprintf("[已确认] [%s] %s", event.Severity, event.Message)
+}
+
+// formatLifecycleContent 格式化生命周期通知内容
+func (s *service) formatLifecycleContent(event *model.AlertEvent, transition Transition, duration time.Duration, actor string) string {
+	if transition == TransitionResolved {
+		resolvedAt := time.Now()
+		if event.ResolvedAt != nil {
+			resolvedAt = *event.ResolvedAt
+		}
+		return fmt.Sprintf(`告警已恢复：
+- 告警ID: %s
+- 设备ID: %s
+- 指标名称: %s
+- 告警消息: %s
+- 触发时间: %s
+- 恢复时间: %s
+- 持续时间: %s`,
+			event.AlertID,
+			event.DeviceID,
+			event.MetricName,
+			event.Message,
+			event.TriggeredAt.Format("2006-01-02 15:04:05"),
+			resolvedAt.Format("2006-01-02 15:04:05"),
+			humanizeDuration(duration))
+	}
+
+	content := fmt.Sprintf(`告警已确认：
+- 告警ID: %s
+- 设备ID: %s
+- 指标名称: %s
+- 告警消息: %s
+- 触发时间: %s
+- 确认人: %s
+- 已持续: %s`,
+		event.AlertID,
+		event.DeviceID,
+		event.MetricName,
+		event.Message,
+		event.TriggeredAt.Format("2006-01-02 15:04:05"),
+		actor,
+		humanizeDuration(duration))
+	if event.Comment != "" {
+		content += "\n- 备注: " + event.Comment
+	}
+	return content
+}
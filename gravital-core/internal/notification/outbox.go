@@ -0,0 +1,288 @@
+package notification
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/celestial/gravital-core/internal/model"
+)
+
+const (
+	outboxPollInterval = 5 * time.Second
+	outboxBatchSize    = 50
+	// outboxSendingTimeout 发送中状态超过该时间视为投递进程异常退出，重新投递
+	outboxSendingTimeout = 5 * time.Minute
+)
+
+// RetryPolicy 通知重试策略（指数退避）
+type RetryPolicy struct {
+	MaxAttempts    int           `json:"max_attempts"`    // 最大尝试次数（含首次发送）
+	InitialBackoff time.Duration `json:"initial_backoff"` // 首次重试间隔
+	MaxBackoff     time.Duration `json:"max_backoff"`     // 最大重试间隔
+	Multiplier     float64       `json:"multiplier"`      // 退避倍数
+}
+
+// Backoff 计算第 attempts 次失败后的重试间隔
+func (p RetryPolicy) Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+	backoff := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempts-1))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		return p.MaxBackoff
+	}
+	return time.Duration(backoff)
+}
+
+// defaultRetryPolicy 未单独配置的渠道使用的重试策略
+var defaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: 30 * time.Second,
+	MaxBackoff:     30 * time.Minute,
+	Multiplier:     2,
+}
+
+// defaultRetryPolicies 各渠道默认重试策略
+// 钉钉/企业微信机器人有频率限制，退避间隔较长；Webhook 类接口恢复通常较快
+var defaultRetryPolicies = map[Channel]RetryPolicy{
+	ChannelEmail:        {MaxAttempts: 6, InitialBackoff: time.Minute, MaxBackoff: time.Hour, Multiplier: 2},
+	ChannelDingTalk:     {MaxAttempts: 6, InitialBackoff: time.Minute, MaxBackoff: 30 * time.Minute, Multiplier: 2},
+	ChannelWeChat:       {MaxAttempts: 6, InitialBackoff: time.Minute, MaxBackoff: 30 * time.Minute, Multiplier: 2},
+	ChannelWebhook:      {MaxAttempts: 8, InitialBackoff: 10 * time.Second, MaxBackoff: 15 * time.Minute, Multiplier: 2},
+	ChannelAlertmanager: {MaxAttempts: 8, InitialBackoff: 10 * time.Second, MaxBackoff: 15 * time.Minute, Multiplier: 2},
+	ChannelSMS:          {MaxAttempts: 3, InitialBackoff: time.Minute, MaxBackoff: 10 * time.Minute, Multiplier: 3},
+}
+
+// SetRetryPolicy 设置渠道的重试策略
+func (s *service) SetRetryPolicy(channel Channel, policy RetryPolicy) {
+	s.retryMu.Lock()
+	defer s.retryMu.Unlock()
+	s.retryPolicies[channel] = policy
+}
+
+// retryPolicy 获取渠道的重试策略
+func (s *service) retryPolicy(channel Channel) RetryPolicy {
+	s.retryMu.RLock()
+	defer s.retryMu.RUnlock()
+	if policy, ok := s.retryPolicies[channel]; ok {
+		return policy
+	}
+	return defaultRetryPolicy
+}
+
+// enqueue 将通知写入发送队列并唤醒投递协程
+func (s *service) enqueue(ctx context.Context, event *model.AlertEvent, notifications []*Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	rows := make([]*model.NotificationOutbox, 0, len(notifications))
+	for _, n := range notifications {
+		rows = append(rows, &model.NotificationOutbox{
+			NotificationID: n.ID,
+			AlertEventID:   event.ID,
+			AlertID:        n.AlertID,
+			AlertRuleID:    n.AlertRuleID,
+			Channel:        string(n.Channel),
+			Recipient:      n.Recipient,
+			Subject:        n.Subject,
+			Content:        n.Content,
+			Templated:      n.Templated,
+			Transition:     string(n.Transition),
+			ThreadID:       n.ThreadID,
+			Priority:       string(n.Priority),
+			Metadata:       model.JSONB(n.Metadata),
+			Status:         string(StatusPending),
+			MaxAttempts:    s.retryPolicy(n.Channel).MaxAttempts,
+			NextAttemptAt:  now,
+		})
+	}
+
+	if err := s.db.WithContext(ctx).Create(&rows).Error; err != nil {
+		return err
+	}
+
+	s.wakeOutbox()
+	return nil
+}
+
+// wakeOutbox 通知投递协程立即处理队列
+func (s *service) wakeOutbox() {
+	select {
+	case s.outboxWake <- struct{}{}:
+	default:
+	}
+}
+
+// runOutbox 投递协程，定期处理到期的通知
+func (s *service) runOutbox() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+		case <-s.outboxWake:
+		}
+
+		// 一次处理到队列为空或不足一批
+		for {
+			n, err := s.dispatchOutbox()
+			if err != nil {
+				s.logger.Error("Failed to dispatch notification outbox", zap.Error(err))
+				break
+			}
+			if n < outboxBatchSize {
+				break
+			}
+		}
+	}
+}
+
+// dispatchOutbox 领取一批到期的通知并发送，返回处理数量
+func (s *service) dispatchOutbox() (int, error) {
+	ctx := context.Background()
+	rows, err := s.claimOutbox(ctx)
+	if err != nil || len(rows) == 0 {
+		return 0, err
+	}
+
+	notifications := make([]*Notification, len(rows))
+	for i, row := range rows {
+		notifications[i] = outboxNotification(row)
+	}
+
+	results, _ := s.SendBatch(ctx, notifications)
+	for i, row := range rows {
+		s.finishOutbox(ctx, row, results[i])
+	}
+
+	return len(rows), nil
+}
+
+// claimOutbox 领取到期的通知，并将其标记为发送中
+// 使用 SKIP LOCKED 保证多副本部署时同一通知只被一个实例领取
+func (s *service) claimOutbox(ctx context.Context) ([]*model.NotificationOutbox, error) {
+	now := time.Now()
+	var rows []*model.NotificationOutbox
+	err := s.db.WithContext(ctx).Raw(`
+		UPDATE notification_outbox
+		SET status = ?, attempts = attempts + 1, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM notification_outbox
+			WHERE (status IN (?, ?) AND next_attempt_at <= ?)
+			   OR (status = ? AND updated_at < ?)
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		string(StatusSending), now,
+		string(StatusPending), string(StatusFailed), now,
+		string(StatusSending), now.Add(-outboxSendingTimeout),
+		outboxBatchSize,
+	).Scan(&rows).Error
+	return rows, err
+}
+
+// finishOutbox 根据发送结果更新队列状态，并记录通知历史
+func (s *service) finishOutbox(ctx context.Context, row *model.NotificationOutbox, result *NotificationResult) {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"updated_at": now,
+	}
+
+	if result.Status == StatusSent {
+		updates["status"] = string(StatusSent)
+		updates["sent_at"] = result.SentAt
+		updates["last_error"] = ""
+	} else {
+		updates["last_error"] = result.Error
+		if row.MaxAttempts > 0 && row.Attempts >= row.MaxAttempts {
+			updates["status"] = string(StatusDeadLetter)
+			s.logger.Warn("Notification moved to dead letter",
+				zap.Uint("outbox_id", row.ID),
+				zap.String("channel", row.Channel),
+				zap.String("recipient", row.Recipient),
+				zap.Int("attempts", row.Attempts))
+		} else {
+			backoff := s.retryPolicy(Channel(row.Channel)).Backoff(row.Attempts)
+			updates["status"] = string(StatusFailed)
+			updates["next_attempt_at"] = now.Add(backoff)
+			s.logger.Info("Notification scheduled for retry",
+				zap.Uint("outbox_id", row.ID),
+				zap.String("channel", row.Channel),
+				zap.Int("attempts", row.Attempts),
+				zap.Duration("backoff", backoff))
+		}
+	}
+
+	if err := s.db.WithContext(ctx).Model(&model.NotificationOutbox{}).
+		Where("id = ?", row.ID).
+		Updates(updates).Error; err != nil {
+		s.logger.Error("Failed to update notification outbox",
+			zap.Uint("outbox_id", row.ID),
+			zap.Error(err))
+	}
+
+	// 每次尝试都记录到通知历史
+	record := &model.AlertNotification{
+		AlertEventID: row.AlertEventID,
+		Channel:      row.Channel,
+		Recipient:    row.Recipient,
+		Status:       string(result.Status),
+		ErrorMessage: result.Error,
+	}
+	if result.Status == StatusSent {
+		record.SentAt = &result.SentAt
+	}
+	if err := s.RecordNotification(ctx, record); err != nil {
+		s.logger.Error("Failed to record notification",
+			zap.String("alert_id", row.AlertID),
+			zap.Error(err))
+	}
+}
+
+// outboxNotification 将队列记录还原为通知消息
+func outboxNotification(row *model.NotificationOutbox) *Notification {
+	metadata := make(map[string]interface{}, len(row.Metadata))
+	for k, v := range row.Metadata {
+		metadata[k] = v
+	}
+	// JSON 存储后时间变为字符串，还原为 time.Time 供发送器使用
+	if v, ok := metadata["triggered_at"].(string); ok {
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			metadata["triggered_at"] = t
+		}
+	}
+	metadata["outbox_id"] = row.ID
+	metadata["attempt"] = row.Attempts
+
+	return &Notification{
+		ID:          row.NotificationID,
+		Channel:     Channel(row.Channel),
+		Recipient:   row.Recipient,
+		Subject:     row.Subject,
+		Content:     row.Content,
+		Templated:   row.Templated,
+		Transition:  Transition(row.Transition),
+		ThreadID:    row.ThreadID,
+		Priority:    Priority(row.Priority),
+		Metadata:    metadata,
+		AlertID:     row.AlertID,
+		AlertRuleID: row.AlertRuleID,
+		CreatedAt:   row.CreatedAt,
+	}
+}
@@ -0,0 +1,66 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/celestial/gravital-core/internal/model"
+)
+
+// alertmanagerRepostInterval 重新推送 firing 告警的间隔
+// 推送的告警不带 endsAt，Alertmanager 超过 resolve_timeout（默认 5m）未收到更新会自动解决，
+// 与 Prometheus 的 resend_delay 一致每分钟推送一次
+const alertmanagerRepostInterval = time.Minute
+
+// runAlertmanagerRepost 定期将仍处于 firing 的告警重新推送到 Alertmanager
+func (s *service) runAlertmanagerRepost() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(alertmanagerRepostInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+		}
+
+		sender, err := s.GetChannel(ChannelAlertmanager)
+		if err != nil {
+			continue
+		}
+		if err := s.repostAlertmanager(context.Background(), sender); err != nil {
+			s.logger.Error("Failed to repost alerts to Alertmanager", zap.Error(err))
+		}
+	}
+}
+
+// repostAlertmanager 按每个告警和接收地址最近一次成功推送的触发通知重新发送
+// 重新推送不写入发送队列和通知历史，失败时等待下一轮
+func (s *service) repostAlertmanager(ctx context.Context, sender Sender) error {
+	var rows []*model.NotificationOutbox
+	err := s.db.WithContext(ctx).Raw(`
+		SELECT DISTINCT ON (o.alert_event_id, o.recipient) o.*
+		FROM notification_outbox o
+		JOIN alert_events e ON e.id = o.alert_event_id
+		WHERE o.channel = ? AND o.status = ? AND o.transition IN ('', ?) AND e.status = ?
+		ORDER BY o.alert_event_id, o.recipient, o.id DESC`,
+		string(ChannelAlertmanager), string(StatusSent), string(TransitionFiring), "firing",
+	).Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if err := sender.Send(ctx, outboxNotification(row)); err != nil {
+			s.logger.Warn("Failed to repost alert to Alertmanager",
+				zap.String("alert_id", row.AlertID),
+				zap.String("recipient", row.Recipient),
+				zap.Error(err))
+		}
+	}
+	return nil
+}
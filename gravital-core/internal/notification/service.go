@@ -9,6 +9,7 @@ import (
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 
+	"github.com/celestial/gravital-core/internal/alert/silence"
 	"github.com/celestial/gravital-core/internal/model"
 )
 
@@ -23,6 +24,12 @@ type Service interface {
 	// SendAlert 发送告警通知
 	SendAlert(ctx context.Context, event *model.AlertEvent, config *NotificationConfig) error
 	
+	// SendEscalation 发送告警升级通知，level 从 1 开始
+	SendEscalation(ctx context.Context, event *model.AlertEvent, level int, channels []ChannelConfig) error
+	
+	// SendLifecycle 发送告警恢复、确认通知，发往触发通知使用的渠道和接收人
+	SendLifecycle(ctx context.Context, event *model.AlertEvent, transition Transition, config *NotificationConfig) error
+	
 	// RegisterChannel 注册通知渠道
 	RegisterChannel(channel Channel, sender Sender) error
 	
@@ -37,6 +44,12 @@ type Service interface {
 	
 	// GetNotificationHistory 获取通知历史
 	GetNotificationHistory(ctx context.Context, alertEventID uint) ([]*model.AlertNotification, error)
+	
+	// SetRetryPolicy 设置渠道的重试策略
+	SetRetryPolicy(channel Channel, policy RetryPolicy)
+	
+	// Stop 停止后台投递
+	Stop()
 }
 
 // Sender 通知发送器接口
@@ -59,8 +72,12 @@ type service struct {
 	channelsMu      sync.RWMutex
 	dedupeCache     map[string]time.Time // alertID -> lastNotifyTime
 	dedupeCacheMu   sync.RWMutex
-	escalationCache map[string]time.Time // alertID -> firstNotifyTime
-	escalationMu    sync.RWMutex
+	silences        *silence.Checker
+	retryPolicies   map[Channel]RetryPolicy
+	retryMu         sync.RWMutex
+	outboxWake      chan struct{}
+	stopCh          chan struct{}
+	wg              sync.WaitGroup
 }
 
 // NewService 创建通知服务
@@ -70,12 +87,26 @@ func NewService(db *gorm.DB, logger *zap.Logger) Service {
 		logger:          logger,
 		channels:        make(map[Channel]Sender),
 		dedupeCache:     make(map[string]time.Time),
-		escalationCache: make(map[string]time.Time),
+		silences:        silence.NewChecker(db, logger),
+		retryPolicies:   make(map[Channel]RetryPolicy),
+		outboxWake:      make(chan struct{}, 1),
+		stopCh:          make(chan struct{}),
+	}
+	for channel, policy := range defaultRetryPolicies {
+		s.retryPolicies[channel] = policy
 	}
 	
 	// 启动清理协程
 	go s.cleanupCache()
 	
+	// 启动发送队列投递协程
+	s.wg.Add(1)
+	go s.runOutbox()
+	
+	// 启动 Alertmanager 告警续期协程
+	s.wg.Add(1)
+	go s.runAlertmanagerRepost()
+	
 	return s
 }
 
@@ -177,6 +208,14 @@ func (s *service) SendAlert(ctx context.Context, event *model.AlertEvent, config
 		return nil
 	}
 	
+	// 静默检查
+	if matched := s.silences.CheckEvent(ctx, event, event.Rule); matched != nil {
+		s.logger.Debug("Alert notification skipped due to silence",
+			zap.String("alert_id", event.AlertID),
+			zap.Uint("silence_id", matched.ID))
+		return nil
+	}
+	
 	// 去重检查
 	shouldNotify, err := s.ShouldNotify(ctx, event.AlertID, event.RuleID)
 	if err != nil {
@@ -188,100 +227,180 @@ func (s *service) SendAlert(ctx context.Context, event *model.AlertEvent, config
 		return nil
 	}
 	
-	// 检查是否需要升级通知
-	shouldEscalate := s.shouldEscalate(event.AlertID, config)
-	
 	// 准备通知内容
 	subject := fmt.Sprintf("[%s] %s", event.Severity, event.Message)
 	content := s.formatAlertContent(event)
 	
-	// 确定使用的通知渠道
-	channels := config.Channels
-	if shouldEscalate && config.EscalationEnabled {
-		// 使用升级渠道
-		s.logger.Info("Alert escalation triggered",
+	notifications := s.buildNotifications(ctx, event, config.Channels, subject, content, nil)
+	
+	// 写入发送队列，由后台投递并在失败时重试
+	if err := s.enqueue(ctx, event, notifications); err != nil {
+		return fmt.Errorf("failed to enqueue notifications: %w", err)
+	}
+	
+	// 更新去重缓存
+	s.updateDedupeCache(event.AlertID)
+	
+	return nil
+}
+
+// SendEscalation 发送告警升级通知
+// 升级通知按触发通知记录，告警恢复、确认时同样会通知升级目标
+func (s *service) SendEscalation(ctx context.Context, event *model.AlertEvent, level int, channels []ChannelConfig) error {
+	// 静默检查
+	if matched := s.silences.CheckEvent(ctx, event, event.Rule); matched != nil {
+		s.logger.Debug("Alert escalation skipped due to silence",
 			zap.String("alert_id", event.AlertID),
-			zap.Uint("rule_id", event.RuleID))
-		
-		// 添加升级渠道
-		for _, escalationChannel := range config.EscalationChannels {
-			found := false
-			for _, ch := range channels {
-				if ch.Channel == escalationChannel {
-					found = true
-					break
-				}
-			}
-			if !found {
-				channels = append(channels, ChannelConfig{
-					Channel: escalationChannel,
-					Enabled: true,
-				})
-			}
-		}
+			zap.Uint("silence_id", matched.ID))
+		return nil
 	}
 	
-	// 发送通知到所有启用的渠道
+	subject := fmt.Sprintf("[升级 L%d] [%s] %s", level, event.Severity, event.Message)
+	content := fmt.Sprintf("告警未确认，已升级到第 %d 级\n%s", level, s.formatAlertContent(event))
+	
+	notifications := s.buildNotifications(ctx, event, channels, subject, content, map[string]interface{}{
+		"escalation_level": level,
+	})
+	
+	if err := s.enqueue(ctx, event, notifications); err != nil {
+		return fmt.Errorf("failed to enqueue notifications: %w", err)
+	}
+	
+	s.logger.Info("Alert escalated",
+		zap.String("alert_id", event.AlertID),
+		zap.Int("level", level),
+		zap.Int("notifications", len(notifications)))
+	
+	return nil
+}
+
+// buildNotifications 按渠道配置生成告警触发通知，渠道配置了模板时使用模板渲染内容
+func (s *service) buildNotifications(ctx context.Context, event *model.AlertEvent, channels []ChannelConfig, subject, content string, extra map[string]interface{}) []*Notification {
+	ruleName := s.ruleName(ctx, event)
+	
+	// 模板渲染所需的规则和设备信息，仅在有渠道配置模板时加载
+	var tplRule *model.AlertRule
+	var tplDevice *model.Device
+	tplLoaded := false
+	
 	var notifications []*Notification
 	for _, channelConfig := range channels {
 		if !channelConfig.Enabled {
 			continue
 		}
 		
+		channelSubject, channelContent, templated := subject, content, false
+		if channelConfig.Template != "" {
+			if !tplLoaded {
+				tplRule, tplDevice = s.loadTemplateContext(ctx, event)
+				tplLoaded = true
+			}
+			data := NewTemplateData(event, tplRule, tplDevice, channelConfig.Channel)
+			tplSubject, tplContent, err := s.renderTemplate(ctx, channelConfig.Template, channelConfig.Channel, data)
+			if err != nil {
+				s.logger.Warn("Failed to render notification template, using default content",
+					zap.String("template", channelConfig.Template),
+					zap.String("channel", string(channelConfig.Channel)),
+					zap.Error(err))
+			} else {
+				channelContent = tplContent
+				templated = true
+				if tplSubject != "" {
+					channelSubject = tplSubject
+				}
+			}
+		}
+		
 		for _, recipient := range channelConfig.Recipients {
 			notification := &Notification{
 				ID:          fmt.Sprintf("notif-%s-%s-%d", event.AlertID, channelConfig.Channel, time.Now().Unix()),
 				Channel:     channelConfig.Channel,
 				Recipient:   recipient,
-				Subject:     subject,
-				Content:     content,
+				Subject:     channelSubject,
+				Content:     channelContent,
+				Templated:   templated,
+				Transition:  TransitionFiring,
+				ThreadID:    event.AlertID,
 				Priority:    s.severityToPriority(event.Severity),
 				AlertID:     event.AlertID,
 				AlertRuleID: event.RuleID,
 				CreatedAt:   time.Now(),
 				Metadata: map[string]interface{}{
-					"event_id":   event.ID,
-					"device_id":  event.DeviceID,
-					"metric_name": event.MetricName,
-					"escalated":  shouldEscalate,
+					"event_id":     event.ID,
+					"device_id":    event.DeviceID,
+					"metric_name":  event.MetricName,
+					"rule_name":    ruleName,
+					"severity":     event.Severity,
+					"status":       event.Status,
+					"labels":       map[string]interface{}(event.Labels),
+					"triggered_at": event.TriggeredAt,
 				},
 			}
+			for k, v := range extra {
+				notification.Metadata[k] = v
+			}
 			notifications = append(notifications, notification)
 		}
 	}
 	
-	// 批量发送
-	results, _ := s.SendBatch(ctx, notifications)
-	
-	// 记录通知结果
-	for _, result := range results {
-		notificationRecord := &model.AlertNotification{
-			AlertEventID: event.ID,
-			Channel:      string(result.Channel),
-			Recipient:    result.Recipient,
-			Status:       string(result.Status),
-			SentAt:       &result.SentAt,
-			ErrorMessage: result.Error,
-		}
-		
-		if err := s.RecordNotification(ctx, notificationRecord); err != nil {
-			s.logger.Error("Failed to record notification",
-				zap.String("alert_id", event.AlertID),
-				zap.Error(err))
+	return notifications
+}
+
+// renderTemplate 按名称渲染通知模板，优先使用渠道专用版本
+func (s *service) renderTemplate(ctx context.Context, name string, channel Channel, data *TemplateData) (string, string, error) {
+	var templates []model.NotificationTemplate
+	if err := s.db.WithContext(ctx).
+		Where("name = ? AND channel IN ?", name, []string{string(channel), ""}).
+		Find(&templates).Error; err != nil {
+		return "", "", err
+	}
+	if len(templates) == 0 {
+		return "", "", fmt.Errorf("template %s not found", name)
+	}
+	
+	tpl := &templates[0]
+	for i := range templates {
+		if templates[i].Channel == string(channel) {
+			tpl = &templates[i]
 		}
 	}
 	
-	// 更新去重缓存
-	s.updateDedupeCache(event.AlertID)
+	return RenderTemplate(tpl, data)
+}
+
+// loadTemplateContext 加载模板渲染所需的规则和设备
+func (s *service) loadTemplateContext(ctx context.Context, event *model.AlertEvent) (*model.AlertRule, *model.Device) {
+	rule := event.Rule
+	if rule == nil {
+		var r model.AlertRule
+		if err := s.db.WithContext(ctx).First(&r, event.RuleID).Error; err == nil {
+			rule = &r
+		}
+	}
 	
-	// 更新升级缓存
-	if shouldEscalate {
-		s.updateEscalationCache(event.AlertID, true)
-	} else {
-		s.updateEscalationCache(event.AlertID, false)
+	var device *model.Device
+	if event.DeviceID != "" {
+		var d model.Device
+		if err := s.db.WithContext(ctx).Preload("Group").
+			Where("device_id = ?", event.DeviceID).
+			First(&d).Error; err == nil {
+			device = &d
+		}
 	}
 	
-	return nil
+	return rule, device
+}
+
+// ruleName 获取告警事件的规则名称
+func (s *service) ruleName(ctx context.Context, event *model.AlertEvent) string {
+	if event.Rule != nil {
+		return event.Rule.RuleName
+	}
+	var rule model.AlertRule
+	if err := s.db.WithContext(ctx).Select("id", "rule_name").First(&rule, event.RuleID).Error; err != nil {
+		return ""
+	}
+	return rule.RuleName
 }
 
 // ShouldNotify 判断是否应该发送通知（去重检查）
@@ -313,24 +432,6 @@ func (s *service) ShouldNotify(ctx context.Context, alertID string, ruleID uint)
 	return elapsed >= float64(dedupeInterval), nil
 }
 
-// shouldEscalate 判断是否应该升级通知
-func (s *service) shouldEscalate(alertID string, config *NotificationConfig) bool {
-	if !config.EscalationEnabled {
-		return false
-	}
-	
-	s.escalationMu.RLock()
-	firstNotifyTime, exists := s.escalationCache[alertID]
-	s.escalationMu.RUnlock()
-	
-	if !exists {
-		return false
-	}
-	
-	elapsed := time.Since(firstNotifyTime).Seconds()
-	return elapsed >= float64(config.EscalationAfter)
-}
-
 // updateDedupeCache 更新去重缓存
 func (s *service) updateDedupeCache(alertID string) {
 	s.dedupeCacheMu.Lock()
@@ -338,18 +439,10 @@ func (s *service) updateDedupeCache(alertID string) {
 	s.dedupeCache[alertID] = time.Now()
 }
 
-// updateEscalationCache 更新升级缓存
-func (s *service) updateEscalationCache(alertID string, reset bool) {
-	s.escalationMu.Lock()
-	defer s.escalationMu.Unlock()
-	
-	if reset {
-		delete(s.escalationCache, alertID)
-	} else {
-		if _, exists := s.escalationCache[alertID]; !exists {
-			s.escalationCache[alertID] = time.Now()
-		}
-	}
+// Stop 停止后台投递，已领取的通知会在当前批次处理完成后退出
+func (s *service) Stop() {
+	close(s.stopCh)
+	s.wg.Wait()
 }
 
 // RecordNotification 记录通知
@@ -417,15 +510,6 @@ func (s *service) cleanupCache() {
 		}
 		s.dedupeCacheMu.Unlock()
 		
-		// 清理升级缓存（保留 24 小时内的）
-		s.escalationMu.Lock()
-		for alertID, firstTime := range s.escalationCache {
-			if now.Sub(firstTime) > 24*time.Hour {
-				delete(s.escalationCache, alertID)
-			}
-		}
-		s.escalationMu.Unlock()
-		
 		s.logger.Debug("Notification cache cleaned up")
 	}
 }
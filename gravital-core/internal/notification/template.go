@@ -0,0 +1,302 @@
+package notification
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	htmltemplate "html/template"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/celestial/gravital-core/internal/model"
+)
+
+// ErrInvalidTemplate 通知模板无效
+var ErrInvalidTemplate = errors.New("invalid template")
+
+// 模板内容格式
+const (
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// ChannelFormat 获取渠道的内容格式
+func ChannelFormat(channel Channel) string {
+	switch channel {
+	case ChannelDingTalk, ChannelWeChat:
+		return FormatMarkdown
+	case ChannelEmail:
+		return FormatHTML
+	default:
+		return FormatText
+	}
+}
+
+// TemplateData 模板渲染数据
+type TemplateData struct {
+	Event      *model.AlertEvent
+	Rule       *model.AlertRule
+	Device     *model.Device
+	DeviceName string
+	GroupName  string
+	Labels     map[string]string
+	Channel    string
+	Format     string
+	Now        time.Time
+	Transition string // firing/resolved/acknowledged
+	Duration   string // 告警持续时间
+	Actor      string // 确认人
+}
+
+// NewTemplateData 构造模板渲染数据，rule 和 device 可以为空
+func NewTemplateData(event *model.AlertEvent, rule *model.AlertRule, device *model.Device, channel Channel) *TemplateData {
+	data := &TemplateData{
+		Event:      event,
+		Rule:       rule,
+		Device:     device,
+		Labels:     make(map[string]string),
+		Channel:    string(channel),
+		Format:     ChannelFormat(channel),
+		Now:        time.Now(),
+		Transition: string(TransitionFiring),
+		Duration:   humanizeDuration(time.Since(event.TriggeredAt)),
+	}
+
+	if device != nil {
+		data.DeviceName = device.Name
+		if device.Group != nil {
+			data.GroupName = device.Group.Name
+		}
+		for k, v := range device.Labels {
+			data.Labels[k] = fmt.Sprint(v)
+		}
+	}
+	if data.DeviceName == "" {
+		data.DeviceName = event.DeviceID
+	}
+	for k, v := range event.Labels {
+		data.Labels[k] = fmt.Sprint(v)
+	}
+
+	return data
+}
+
+// templateFuncs 模板辅助函数
+var templateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"join":  strings.Join,
+	"trim":  strings.TrimSpace,
+	"replace": func(old, new, s string) string {
+		return strings.ReplaceAll(s, old, new)
+	},
+	"default": func(def string, v interface{}) string {
+		if v == nil {
+			return def
+		}
+		if s := fmt.Sprint(v); s != "" {
+			return s
+		}
+		return def
+	},
+	"truncate": func(n int, s string) string {
+		r := []rune(s)
+		if len(r) <= n {
+			return s
+		}
+		return string(r[:n]) + "..."
+	},
+	"formatTime": func(layout string, t interface{}) string {
+		switch v := t.(type) {
+		case time.Time:
+			return v.Format(layout)
+		case *time.Time:
+			if v != nil {
+				return v.Format(layout)
+			}
+		}
+		return ""
+	},
+	"localTime": func(t time.Time) string {
+		return t.Format("2006-01-02 15:04:05")
+	},
+	"since": func(t time.Time) string {
+		return humanizeDuration(time.Since(t))
+	},
+	"duration": func(from time.Time, to interface{}) string {
+		end := time.Now()
+		switch v := to.(type) {
+		case time.Time:
+			end = v
+		case *time.Time:
+			if v != nil {
+				end = *v
+			}
+		}
+		return humanizeDuration(end.Sub(from))
+	},
+	"severityText": func(severity string) string {
+		switch severity {
+		case "critical":
+			return "严重"
+		case "warning":
+			return "警告"
+		case "info":
+			return "提示"
+		default:
+			return severity
+		}
+	},
+	"severityColor": func(severity string) string {
+		switch severity {
+		case "critical":
+			return "#d32f2f"
+		case "warning":
+			return "#f57c00"
+		case "info":
+			return "#1976d2"
+		default:
+			return "#616161"
+		}
+	},
+	"sortedKeys": func(m map[string]string) []string {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return keys
+	},
+	"escapeHTML": html.EscapeString,
+	"escapeMarkdown": func(s string) string {
+		replacer := strings.NewReplacer(
+			`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`",
+			"[", `\[`, "]", `\]`, "#", `\#`,
+		)
+		return replacer.Replace(s)
+	},
+}
+
+// htmlTemplateFuncs HTML 模板的辅助函数
+// html/template 会按上下文自动转义，escapeHTML 原样返回以免已有模板被重复转义
+var htmlTemplateFuncs = func() htmltemplate.FuncMap {
+	funcs := make(htmltemplate.FuncMap, len(templateFuncs))
+	for k, v := range templateFuncs {
+		funcs[k] = v
+	}
+	funcs["escapeHTML"] = func(s string) string { return s }
+	return funcs
+}()
+
+// ParseTemplate 解析文本和 Markdown 模板
+func ParseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(templateFuncs).Option("missingkey=zero").Parse(text)
+}
+
+// parseHTMLTemplate 解析 HTML 模板，告警消息、标签等字段自动转义
+func parseHTMLTemplate(name, text string) (*htmltemplate.Template, error) {
+	return htmltemplate.New(name).Funcs(htmlTemplateFuncs).Option("missingkey=zero").Parse(text)
+}
+
+// ValidateTemplate 校验模板语法，并使用示例告警试渲染以发现字段引用错误
+func ValidateTemplate(tpl *model.NotificationTemplate) error {
+	event, rule, device := SampleAlertEvent()
+	if _, _, err := RenderTemplate(tpl, NewTemplateData(event, rule, device, Channel(tpl.Channel))); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return nil
+}
+
+// RenderTemplate 渲染通知模板，返回标题和内容
+// 模板未设置标题时返回空标题，由调用方使用默认标题；标题始终按纯文本渲染
+func RenderTemplate(tpl *model.NotificationTemplate, data *TemplateData) (string, string, error) {
+	subject := ""
+	if tpl.Subject != "" {
+		out, err := execute(tpl.Name+".subject", tpl.Subject, FormatText, data)
+		if err != nil {
+			return "", "", err
+		}
+		subject = strings.TrimSpace(out)
+	}
+
+	content, err := execute(tpl.Name+".content", tpl.Content, data.Format, data)
+	if err != nil {
+		return "", "", err
+	}
+
+	return subject, content, nil
+}
+
+// SampleAlertEvent 预览模板使用的示例告警事件
+func SampleAlertEvent() (*model.AlertEvent, *model.AlertRule, *model.Device) {
+	now := time.Now()
+	rule := &model.AlertRule{
+		ID:          1,
+		RuleName:    "设备 Ping 延迟过高",
+		Severity:    "warning",
+		Condition:   "avg by (device_id) (ping_rtt_ms) > 200",
+		Description: "Ping 平均延迟超过 200ms",
+	}
+	device := &model.Device{
+		DeviceID:   "dev-sample-001",
+		Name:       "核心交换机-01",
+		DeviceType: "switch",
+		Group:      &model.DeviceGroup{Name: "数据中心 A"},
+		Labels:     model.JSONB{"region": "cn-east", "vendor": "huawei"},
+	}
+	event := &model.AlertEvent{
+		ID:          1,
+		AlertID:     "alert-sample-1",
+		RuleID:      rule.ID,
+		DeviceID:    device.DeviceID,
+		MetricName:  "ping_rtt_ms",
+		Severity:    rule.Severity,
+		Message:     "设备 Ping 延迟过高: 当前值 356.20 > 阈值 200.00",
+		Labels:      model.JSONB{"device_id": device.DeviceID, "sentinel_id": "sentinel-01"},
+		TriggeredAt: now.Add(-15 * time.Minute),
+		Status:      "firing",
+	}
+	return event, rule, device
+}
+
+// execute 按内容格式渲染模板，HTML 使用 html/template
+func execute(name, text, format string, data *TemplateData) (string, error) {
+	var buf bytes.Buffer
+	if format == FormatHTML {
+		t, err := parseHTMLTemplate(name, text)
+		if err != nil {
+			return "", err
+		}
+		if err := t.Execute(&buf, data); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+
+	t, err := ParseTemplate(name, text)
+	if err != nil {
+		return "", err
+	}
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// humanizeDuration 格式化持续时间，如 1h5m、3m20s
+func humanizeDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	d = d.Round(time.Second)
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	if days > 0 {
+		return fmt.Sprintf("%dd%s", days, strings.TrimSuffix(d.Truncate(time.Minute).String(), "0s"))
+	}
+	return d.String()
+}
@@ -0,0 +1,141 @@
+package notification
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/celestial/gravital-core/internal/model"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	event, rule, device := SampleAlertEvent()
+	event.Message = `<script>alert(1)</script> & "quoted"`
+
+	tests := []struct {
+		name    string
+		channel Channel
+		subject string
+		content string
+		want    string
+		wantSub string
+	}{
+		{
+			name:    "text fields and funcs",
+			channel: ChannelWebhook,
+			subject: "[{{ .Event.Severity | upper }}] {{ .Rule.RuleName }}",
+			content: "{{ .DeviceName }} {{ severityText .Event.Severity }} {{ index .Labels \"region\" }} {{ truncate 4 .Event.MetricName }}",
+			want:    "核心交换机-01 警告 cn-east ping...",
+			wantSub: "[WARNING] 设备 Ping 延迟过高",
+		},
+		{
+			name:    "text keeps message as is",
+			channel: ChannelWebhook,
+			content: "{{ .Event.Message }}",
+			want:    `<script>alert(1)</script> & "quoted"`,
+		},
+		{
+			name:    "markdown escape",
+			channel: ChannelDingTalk,
+			content: "{{ escapeMarkdown \"a_b*c\" }}",
+			want:    `a\_b\*c`,
+		},
+		{
+			name:    "html escapes message",
+			channel: ChannelEmail,
+			content: "<p>{{ .Event.Message }}</p>",
+			want:    "<p>&lt;script&gt;alert(1)&lt;/script&gt; &amp; &#34;quoted&#34;</p>",
+		},
+		{
+			name:    "html escapeHTML is not applied twice",
+			channel: ChannelEmail,
+			content: "<p>{{ escapeHTML .Event.Message }}</p>",
+			want:    "<p>&lt;script&gt;alert(1)&lt;/script&gt; &amp; &#34;quoted&#34;</p>",
+		},
+		{
+			name:    "html subject stays plain text",
+			channel: ChannelEmail,
+			subject: "{{ .Rule.RuleName }} <{{ .Event.Severity }}>",
+			content: "<b>{{ .GroupName }}</b>",
+			want:    "<b>数据中心 A</b>",
+			wantSub: "设备 Ping 延迟过高 <warning>",
+		},
+		{
+			name:    "missing label renders empty",
+			channel: ChannelWebhook,
+			content: "[{{ index .Labels \"missing\" }}]{{ default \"n/a\" .Event.Comment }}",
+			want:    "[]n/a",
+		},
+	}
+
+	for _, tt := range tests {
+		tpl := &model.NotificationTemplate{Name: "test", Channel: string(tt.channel), Subject: tt.subject, Content: tt.content}
+		subject, content, err := RenderTemplate(tpl, NewTemplateData(event, rule, device, tt.channel))
+		if err != nil {
+			t.Errorf("%s: RenderTemplate failed: %v", tt.name, err)
+			continue
+		}
+		if content != tt.want {
+			t.Errorf("%s: content = %q, want %q", tt.name, content, tt.want)
+		}
+		if subject != tt.wantSub {
+			t.Errorf("%s: subject = %q, want %q", tt.name, subject, tt.wantSub)
+		}
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	tests := []struct {
+		content string
+		wantErr bool
+	}{
+		{"{{ .Event.AlertID }}", false},
+		{"{{ .Event.AlertID ", true},
+		{"{{ .Event.NoSuchField }}", true},
+		{"{{ unknownFunc .Event }}", true},
+	}
+
+	for _, tt := range tests {
+		for _, channel := range []Channel{ChannelWebhook, ChannelEmail} {
+			err := ValidateTemplate(&model.NotificationTemplate{Name: "test", Channel: string(channel), Content: tt.content})
+			if tt.wantErr && !errors.Is(err, ErrInvalidTemplate) {
+				t.Errorf("ValidateTemplate(%q, %s) error = %v, want ErrInvalidTemplate", tt.content, channel, err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("ValidateTemplate(%q, %s) error = %v", tt.content, channel, err)
+			}
+		}
+	}
+}
+
+func TestHumanizeDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{-time.Second, "0s"},
+		{3*time.Minute + 20*time.Second, "3m20s"},
+		{time.Hour + 5*time.Minute, "1h5m0s"},
+		{26*time.Hour + 30*time.Minute, "1d2h30m"},
+		{48 * time.Hour, "2d"},
+	}
+
+	for _, tt := range tests {
+		if got := humanizeDuration(tt.d); got != tt.want {
+			t.Errorf("humanizeDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestChannelFormat(t *testing.T) {
+	for channel, want := range map[Channel]string{
+		ChannelEmail:    FormatHTML,
+		ChannelDingTalk: FormatMarkdown,
+		ChannelWeChat:   FormatMarkdown,
+		ChannelWebhook:  FormatText,
+	} {
+		if got := ChannelFormat(channel); got != want {
+			t.Errorf("ChannelFormat(%s) = %q, want %q", channel, got, want)
+		}
+	}
+}
@@ -13,6 +13,7 @@ const (
 	ChannelSMS        Channel = "sms"         // 短信
 	ChannelSlack      Channel = "slack"       // Slack
 	ChannelTelegram   Channel = "telegram"    // Telegram
+	ChannelAlertmanager Channel = "alertmanager" // Alertmanager v2 API
 )
 
 // Status 通知状态
@@ -23,6 +24,16 @@ const (
 	StatusSending Status = "sending" // 发送中
 	StatusSent    Status = "sent"    // 已发送
 	StatusFailed  Status = "failed"  // 发送失败
+	StatusDeadLetter Status = "dead_letter" // 超过最大重试次数，不再自动重试
+)
+
+// Transition 告警生命周期变化
+type Transition string
+
+const (
+	TransitionFiring       Transition = "firing"       // 告警触发
+	TransitionResolved     Transition = "resolved"     // 告警恢复
+	TransitionAcknowledged Transition = "acknowledged" // 告警被确认
 )
 
 // Priority 通知优先级
@@ -46,6 +57,9 @@ type Notification struct {
 	Metadata    map[string]interface{} `json:"metadata"`
 	AlertID     string                 `json:"alert_id,omitempty"`
 	AlertRuleID uint                   `json:"alert_rule_id,omitempty"`
+	Templated   bool                   `json:"templated,omitempty"` // 内容已由通知模板渲染为渠道格式，发送器直接使用
+	Transition  Transition             `json:"transition,omitempty"`
+	ThreadID    string                 `json:"thread_id,omitempty"` // 同一告警的通知共用，支持的渠道据此归入同一会话
 	CreatedAt   time.Time              `json:"created_at"`
 }
 
@@ -54,9 +68,8 @@ type NotificationConfig struct {
 	Enabled           bool                   `json:"enabled"`
 	Channels          []ChannelConfig        `json:"channels"`
 	DedupeInterval    int                    `json:"dedupe_interval"`     // 去重间隔（秒）
-	EscalationEnabled bool                   `json:"escalation_enabled"`  // 是否启用升级
-	EscalationAfter   int                    `json:"escalation_after"`    // 升级时间（秒）
-	EscalationChannels []Channel             `json:"escalation_channels"` // 升级通知渠道
+	NotifyResolved     bool                  `json:"notify_resolved"`     // 告警恢复时通知
+	NotifyAcknowledged bool                  `json:"notify_acknowledged"` // 告警被确认时通知
 	Metadata          map[string]interface{} `json:"metadata,omitempty"`
 }
 
@@ -66,6 +79,8 @@ type ChannelConfig struct {
 	Enabled    bool     `json:"enabled"`
 	Recipients []string `json:"recipients"`
 	Template   string   `json:"template,omitempty"`
+	ResolvedTemplate     string `json:"resolved_template,omitempty"`     // 恢复通知模板
+	AcknowledgedTemplate string `json:"acknowledged_template,omitempty"` // 确认通知模板
 	Config     map[string]interface{} `json:"config,omitempty"`
 }
 
@@ -97,6 +112,16 @@ type WebhookConfig struct {
 	Timeout int               `json:"timeout"` // 超时时间（秒）
 }
 
+// AlertmanagerConfig Alertmanager 配置
+type AlertmanagerConfig struct {
+	URL          string `json:"url"` // Alertmanager 地址，如 http://alertmanager:9093
+	Username     string `json:"username,omitempty"`
+	Password     string `json:"password,omitempty"`
+	BearerToken  string `json:"bearer_token,omitempty"`
+	GeneratorURL string `json:"generator_url,omitempty"` // 告警来源链接前缀，如 https://celestial.example.com/alerts/events
+	Timeout      int    `json:"timeout"`                 // 超时时间（秒）
+}
+
 // DingTalkConfig 钉钉配置
 type DingTalkConfig struct {
 	WebhookURL string `json:"webhook_url"`
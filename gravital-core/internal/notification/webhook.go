@@ -68,6 +68,8 @@ func (s *WebhookSender) Send(ctx context.Context, notification *Notification) er
 		"content":    notification.Content,
 		"priority":   notification.Priority,
 		"alert_id":   notification.AlertID,
+		"transition": notification.Transition,
+		"thread_id":  notification.ThreadID,
 		"metadata":   notification.Metadata,
 		"created_at": notification.CreatedAt.Format(time.RFC3339),
 	}
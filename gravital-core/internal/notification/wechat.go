@@ -97,6 +97,10 @@ func (s *WeChatSender) Send(ctx context.Context, notification *Notification) err
 
 // buildMessage 构建企业微信消息
 func (s *WeChatSender) buildMessage(notification *Notification) map[string]interface{} {
+	if notification.Templated {
+		return s.wrapMessage(notification.Content)
+	}
+	
 	// 构建 Markdown 内容
 	content := fmt.Sprintf("### %s\n", notification.Subject)
 	content += fmt.Sprintf("> **优先级**: <font color=\"%s\">%s</font>\n", 
@@ -116,6 +120,11 @@ func (s *WeChatSender) buildMessage(notification *Notification) map[string]inter
 	content += fmt.Sprintf("\n<font color=\"comment\">发送时间: %s</font>", 
 		notification.CreatedAt.Format("2006-01-02 15:04:05"))
 	
+	return s.wrapMessage(content)
+}
+
+// wrapMessage 封装企业微信 Markdown 消息
+func (s *WeChatSender) wrapMessage(content string) map[string]interface{} {
 	message := map[string]interface{}{
 		"msgtype": "markdown",
 		"markdown": map[string]interface{}{
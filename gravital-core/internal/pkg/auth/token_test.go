@@ -0,0 +1,43 @@
+package auth
+
+import "testing"
+
+func TestHashToken(t *testing.T) {
+	tests := []struct {
+		token string
+		want  string
+	}{
+		{"", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{"sentinel_abc", "527b2b4171027408f3fb64ede632482fb730733f1794b7719dd14db5bca25e3c"},
+	}
+
+	for _, tt := range tests {
+		if got := HashToken(tt.token); got != tt.want {
+			t.Errorf("HashToken(%q) = %s, want %s", tt.token, got, tt.want)
+		}
+	}
+}
+
+func TestCheckTokenHash(t *testing.T) {
+	hash := HashToken("sentinel_abc")
+
+	tests := []struct {
+		name  string
+		token string
+		hash  string
+		want  bool
+	}{
+		{"match", "sentinel_abc", hash, true},
+		{"mismatch", "sentinel_abd", hash, false},
+		{"uppercase hash", "sentinel_abc", "527B2B4171027408F3FB64EDE632482FB730733F1794B7719DD14DB5BCA25E3C", false},
+		// 未设置的哈希（如没有旧 Token）不能被任何 Token 匹配
+		{"empty hash", "", "", false},
+		{"empty hash with token", "sentinel_abc", "", false},
+	}
+
+	for _, tt := range tests {
+		if got := CheckTokenHash(tt.token, tt.hash); got != tt.want {
+			t.Errorf("%s: CheckTokenHash = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
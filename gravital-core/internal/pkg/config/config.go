@@ -9,18 +9,19 @@ import (
 
 // Config 全局配置
 type Config struct {
-	Server     ServerConfig     `mapstructure:"server"`
-	Database   DatabaseConfig   `mapstructure:"database"`
-	Redis      RedisConfig      `mapstructure:"redis"`
-	Auth       AuthConfig       `mapstructure:"auth"`
-	Alert      AlertConfig      `mapstructure:"alert"`
-	Forwarder  ForwarderConfig  `mapstructure:"forwarder"`
-	Sentinel   SentinelConfig   `mapstructure:"sentinel"`
-	Scheduler  SchedulerConfig  `mapstructure:"scheduler"`
-	Logging    LoggingConfig    `mapstructure:"logging"`
-	Grafana    GrafanaConfig    `mapstructure:"grafana"`
-	System     SystemConfig     `mapstructure:"system"`
-	TimeSeries TimeSeriesConfig `mapstructure:"timeseries"`
+	Server       ServerConfig       `mapstructure:"server"`
+	Database     DatabaseConfig     `mapstructure:"database"`
+	Redis        RedisConfig        `mapstructure:"redis"`
+	Auth         AuthConfig         `mapstructure:"auth"`
+	Alert        AlertConfig        `mapstructure:"alert"`
+	Notification NotificationConfig `mapstructure:"notification"`
+	Forwarder    ForwarderConfig    `mapstructure:"forwarder"`
+	Sentinel     SentinelConfig     `mapstructure:"sentinel"`
+	Scheduler    SchedulerConfig    `mapstructure:"scheduler"`
+	Logging      LoggingConfig      `mapstructure:"logging"`
+	Grafana      GrafanaConfig      `mapstructure:"grafana"`
+	System       SystemConfig       `mapstructure:"system"`
+	TimeSeries   TimeSeriesConfig   `mapstructure:"timeseries"`
 }
 
 // ServerConfig 服务器配置
@@ -31,6 +32,14 @@ type ServerConfig struct {
 	ReadTimeout    time.Duration `mapstructure:"read_timeout"`
 	WriteTimeout   time.Duration `mapstructure:"write_timeout"`
 	MaxHeaderBytes int           `mapstructure:"max_header_bytes"`
+	TLS            TLSConfig     `mapstructure:"tls"`
+}
+
+// TLSConfig HTTPS 配置
+type TLSConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	CertFile string `mapstructure:"cert_file"`
+	KeyFile  string `mapstructure:"key_file"`
 }
 
 // DatabaseConfig 数据库配置
@@ -74,6 +83,59 @@ type AlertConfig struct {
 	NotificationTimeout     time.Duration `mapstructure:"notification_timeout"`
 	MaxConcurrentEvaluations int           `mapstructure:"max_concurrent_evaluations"`
 	RetentionDays           int           `mapstructure:"retention_days"`
+	IngestToken             string        `mapstructure:"ingest_token"` // Alertmanager webhook 接入 Token
+}
+
+// NotificationConfig 通知渠道配置，只注册配置了地址的渠道
+type NotificationConfig struct {
+	Email        EmailNotifyConfig        `mapstructure:"email"`
+	DingTalk     DingTalkNotifyConfig     `mapstructure:"dingtalk"`
+	WeChat       WeChatNotifyConfig       `mapstructure:"wechat"`
+	Webhook      WebhookNotifyConfig      `mapstructure:"webhook"`
+	Alertmanager AlertmanagerNotifyConfig `mapstructure:"alertmanager"`
+}
+
+// EmailNotifyConfig 邮件渠道配置
+type EmailNotifyConfig struct {
+	SMTPHost     string `mapstructure:"smtp_host"`
+	SMTPPort     int    `mapstructure:"smtp_port"`
+	SMTPUser     string `mapstructure:"smtp_user"`
+	SMTPPassword string `mapstructure:"smtp_password"`
+	From         string `mapstructure:"from"`
+	UseTLS       bool   `mapstructure:"use_tls"`
+}
+
+// DingTalkNotifyConfig 钉钉渠道配置
+type DingTalkNotifyConfig struct {
+	WebhookURL string   `mapstructure:"webhook_url"`
+	Secret     string   `mapstructure:"secret"`
+	AtMobiles  []string `mapstructure:"at_mobiles"`
+	AtAll      bool     `mapstructure:"at_all"`
+}
+
+// WeChatNotifyConfig 企业微信渠道配置
+type WeChatNotifyConfig struct {
+	WebhookURL          string   `mapstructure:"webhook_url"`
+	MentionedList       []string `mapstructure:"mentioned_list"`
+	MentionedMobileList []string `mapstructure:"mentioned_mobile_list"`
+}
+
+// WebhookNotifyConfig Webhook 渠道配置
+type WebhookNotifyConfig struct {
+	URL     string            `mapstructure:"url"`
+	Method  string            `mapstructure:"method"`
+	Headers map[string]string `mapstructure:"headers"`
+	Timeout int               `mapstructure:"timeout"` // 超时时间（秒）
+}
+
+// AlertmanagerNotifyConfig Alertmanager 渠道配置
+type AlertmanagerNotifyConfig struct {
+	URL          string `mapstructure:"url"`
+	Username     string `mapstructure:"username"`
+	Password     string `mapstructure:"password"`
+	BearerToken  string `mapstructure:"bearer_token"`
+	GeneratorURL string `mapstructure:"generator_url"`
+	Timeout      int    `mapstructure:"timeout"` // 超时时间（秒）
 }
 
 // ForwarderConfig 转发器配置
@@ -102,10 +164,28 @@ type ForwarderTarget struct {
 
 // SentinelConfig Sentinel 配置
 type SentinelConfig struct {
-	HeartbeatTimeout  time.Duration `mapstructure:"heartbeat_timeout"`
-	OfflineThreshold  time.Duration `mapstructure:"offline_threshold"`
-	TaskFetchInterval time.Duration `mapstructure:"task_fetch_interval"`
-	AutoAssign        bool          `mapstructure:"auto_assign"`
+	HeartbeatTimeout  time.Duration      `mapstructure:"heartbeat_timeout"`
+	OfflineThreshold  time.Duration      `mapstructure:"offline_threshold"`
+	TaskFetchInterval time.Duration      `mapstructure:"task_fetch_interval"`
+	AutoAssign        bool               `mapstructure:"auto_assign"`
+	Registration      RegistrationConfig `mapstructure:"registration"`
+	MTLS              MTLSConfig         `mapstructure:"mtls"`
+}
+
+// RegistrationConfig Sentinel 注册配置
+type RegistrationConfig struct {
+	Mode            string `mapstructure:"mode"`             // key: 必须使用注册密钥（默认）；open: 允许不带密钥注册
+	RequireApproval bool   `mapstructure:"require_approval"` // 新注册的 Sentinel 需要管理员审批后才下发任务
+}
+
+// MTLSConfig Sentinel 双向 TLS 配置
+// 中心端作为 CA 在注册时为 Sentinel 签发客户端证书，需要同时启用 server.tls
+type MTLSConfig struct {
+	Enabled      bool          `mapstructure:"enabled"`       // 启用内置 CA，签发并校验客户端证书
+	Required     bool          `mapstructure:"required"`      // Sentinel 接口必须携带客户端证书
+	CACertFile   string        `mapstructure:"ca_cert_file"`  // 文件不存在时自动生成 CA
+	CAKeyFile    string        `mapstructure:"ca_key_file"`
+	CertValidity time.Duration `mapstructure:"cert_validity"` // 客户端证书有效期，默认 30 天
 }
 
 // SchedulerConfig 调度器配置
@@ -213,6 +293,25 @@ func (c *Config) Validate() error {
 	if c.Auth.JWTSecret == "" {
 		return fmt.Errorf("jwt secret is required")
 	}
+
+	if c.Server.TLS.Enabled && (c.Server.TLS.CertFile == "" || c.Server.TLS.KeyFile == "") {
+		return fmt.Errorf("server tls cert_file and key_file are required")
+	}
+
+	if c.Sentinel.MTLS.Enabled {
+		if !c.Server.TLS.Enabled {
+			return fmt.Errorf("sentinel mtls requires server tls")
+		}
+		if c.Sentinel.MTLS.CACertFile == "" || c.Sentinel.MTLS.CAKeyFile == "" {
+			return fmt.Errorf("sentinel mtls ca_cert_file and ca_key_file are required")
+		}
+	}
+
+	switch c.Sentinel.Registration.Mode {
+	case "", "key", "open":
+	default:
+		return fmt.Errorf("invalid sentinel registration mode: %s", c.Sentinel.Registration.Mode)
+	}
 	
 	return nil
 }
@@ -0,0 +1,158 @@
+package pki
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestCA(t *testing.T) *CA {
+	t.Helper()
+	dir := t.TempDir()
+	ca, err := LoadOrCreateCA(filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key"))
+	if err != nil {
+		t.Fatalf("LoadOrCreateCA failed: %v", err)
+	}
+	return ca
+}
+
+// newTestCSR 生成 CSR，主题故意与 Sentinel ID 不同
+func newTestCSR(t *testing.T, cn string) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: cn, OrganizationalUnit: []string{"admin"}},
+		DNSNames: []string{"core.example.com"},
+	}, key)
+	if err != nil {
+		t.Fatalf("CreateCertificateRequest failed: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})
+}
+
+func TestLoadOrCreateCA(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key")
+
+	created, err := LoadOrCreateCA(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("LoadOrCreateCA failed: %v", err)
+	}
+
+	// 文件已存在时加载同一个 CA
+	loaded, err := LoadOrCreateCA(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("LoadOrCreateCA reload failed: %v", err)
+	}
+	if !bytes.Equal(created.CertPEM(), loaded.CertPEM()) {
+		t.Error("Expected reloaded ca to match created ca")
+	}
+
+	if _, err := LoadOrCreateCA("", keyFile); err == nil {
+		t.Error("Expected error without cert file")
+	}
+}
+
+func TestSignSentinelCSR(t *testing.T) {
+	ca := newTestCA(t)
+
+	cert, certPEM, err := ca.SignSentinelCSR(newTestCSR(t, "someone-else"), "sentinel-01", time.Hour)
+	if err != nil {
+		t.Fatalf("SignSentinelCSR failed: %v", err)
+	}
+
+	// 主题由 CA 决定，忽略 CSR 中的主题和扩展
+	if got := SentinelID(cert); got != "sentinel-01" {
+		t.Errorf("SentinelID = %q, want sentinel-01", got)
+	}
+	if len(cert.DNSNames) != 0 {
+		t.Errorf("Expected no DNS names from csr, got %v", cert.DNSNames)
+	}
+	if cert.IsCA {
+		t.Error("Expected leaf certificate")
+	}
+	if len(cert.ExtKeyUsage) != 1 || cert.ExtKeyUsage[0] != x509.ExtKeyUsageClientAuth {
+		t.Errorf("Expected client auth only, got %v", cert.ExtKeyUsage)
+	}
+	if cert.NotAfter.After(time.Now().Add(time.Hour)) {
+		t.Errorf("NotAfter %v exceeds validity", cert.NotAfter)
+	}
+
+	// 只能用于客户端认证
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:     ca.Pool(),
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		t.Errorf("Verify client auth failed: %v", err)
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{Roots: ca.Pool()}); err == nil {
+		t.Error("Expected server auth verification to fail")
+	}
+
+	if block, _ := pem.Decode(certPEM); block == nil || !bytes.Equal(block.Bytes, cert.Raw) {
+		t.Error("Expected pem to contain the signed certificate")
+	}
+
+	// 每张证书的序列号不同
+	other, _, err := ca.SignSentinelCSR(newTestCSR(t, "sentinel-01"), "sentinel-01", time.Hour)
+	if err != nil {
+		t.Fatalf("SignSentinelCSR failed: %v", err)
+	}
+	if other.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+		t.Error("Expected unique serial numbers")
+	}
+}
+
+func TestSignSentinelCSR_Invalid(t *testing.T) {
+	ca := newTestCA(t)
+
+	csrPEM := newTestCSR(t, "sentinel-01")
+	block, _ := pem.Decode(csrPEM)
+	tampered := append([]byte(nil), block.Bytes...)
+	tampered[len(tampered)-1] ^= 0xff
+
+	tests := []struct {
+		name string
+		csr  []byte
+	}{
+		{"not pem", []byte("not a csr")},
+		{"wrong block type", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: block.Bytes})},
+		{"garbage der", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: []byte{1, 2, 3}})},
+		{"bad signature", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: tampered})},
+	}
+
+	for _, tt := range tests {
+		if _, _, err := ca.SignSentinelCSR(tt.csr, "sentinel-01", time.Hour); !errors.Is(err, ErrInvalidCSR) {
+			t.Errorf("%s: SignSentinelCSR error = %v, want ErrInvalidCSR", tt.name, err)
+		}
+	}
+}
+
+func TestSentinelID(t *testing.T) {
+	tests := []struct {
+		name    string
+		subject pkix.Name
+		want    string
+	}{
+		{"sentinel", pkix.Name{CommonName: "sentinel-01", OrganizationalUnit: []string{sentinelOU}}, "sentinel-01"},
+		{"other ou", pkix.Name{CommonName: "sentinel-01", OrganizationalUnit: []string{"admin"}}, ""},
+		{"no ou", pkix.Name{CommonName: "sentinel-01"}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := SentinelID(&x509.Certificate{Subject: tt.subject}); got != tt.want {
+			t.Errorf("%s: SentinelID = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
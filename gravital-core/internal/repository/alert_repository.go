@@ -16,12 +16,15 @@ type AlertRepository interface {
 	UpdateRule(ctx context.Context, rule *model.AlertRule) error
 	DeleteRule(ctx context.Context, id uint) error
 	ListRules(ctx context.Context, filter *AlertRuleFilter) ([]*model.AlertRule, int64, error)
+	FirstOrCreateRule(ctx context.Context, rule *model.AlertRule) error
 
 	// 告警事件
 	CreateEvent(ctx context.Context, event *model.AlertEvent) error
 	GetEventByID(ctx context.Context, id uint) (*model.AlertEvent, error)
 	UpdateEvent(ctx context.Context, event *model.AlertEvent) error
 	ListEvents(ctx context.Context, filter *AlertEventFilter) ([]*model.AlertEvent, int64, error)
+	FindActiveEvent(ctx context.Context, source, fingerprint string) (*model.AlertEvent, error)
+	FirstOrCreateEvent(ctx context.Context, event *model.AlertEvent) (bool, error)
 }
 
 // AlertRuleFilter 告警规则过滤条件
@@ -31,6 +34,7 @@ type AlertRuleFilter struct {
 	Enabled  *bool
 	Severity string
 	Keyword  string
+	Source   string
 }
 
 // AlertEventFilter 告警事件过滤条件
@@ -89,6 +93,9 @@ func (r *alertRepository) ListRules(ctx context.Context, filter *AlertRuleFilter
 	if filter.Keyword != "" {
 		query = query.Where("rule_name LIKE ?", "%"+filter.Keyword+"%")
 	}
+	if filter.Source != "" {
+		query = query.Where("source = ?", filter.Source)
+	}
 
 	// 计算总数
 	if err := query.Count(&total).Error; err != nil {
@@ -102,6 +109,13 @@ func (r *alertRepository) ListRules(ctx context.Context, filter *AlertRuleFilter
 	return rules, total, err
 }
 
+// FirstOrCreateRule 按规则名称和来源获取规则，不存在时创建
+func (r *alertRepository) FirstOrCreateRule(ctx context.Context, rule *model.AlertRule) error {
+	return r.db.WithContext(ctx).
+		Where("rule_name = ? AND source = ?", rule.RuleName, rule.Source).
+		FirstOrCreate(rule).Error
+}
+
 func (r *alertRepository) CreateEvent(ctx context.Context, event *model.AlertEvent) error {
 	return r.db.WithContext(ctx).Create(event).Error
 }
@@ -151,3 +165,27 @@ func (r *alertRepository) ListEvents(ctx context.Context, filter *AlertEventFilt
 	return events, total, err
 }
 
+
+// FindActiveEvent 查找指定来源和指纹的最新活跃事件（firing 或 silenced）
+func (r *alertRepository) FindActiveEvent(ctx context.Context, source, fingerprint string) (*model.AlertEvent, error) {
+	var event model.AlertEvent
+	err := r.db.WithContext(ctx).Preload("Rule").
+		Where("source = ? AND fingerprint = ? AND status IN ?", source, fingerprint, []string{"firing", "silenced"}).
+		Order("triggered_at DESC").
+		First(&event).Error
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// FirstOrCreateEvent 按 alert_id 获取事件，不存在时创建，返回是否新建
+func (r *alertRepository) FirstOrCreateEvent(ctx context.Context, event *model.AlertEvent) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("alert_id = ?", event.AlertID).
+		FirstOrCreate(event)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
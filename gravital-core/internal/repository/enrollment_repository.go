@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/celestial/gravital-core/internal/model"
+)
+
+// EnrollmentRepository 注册密钥与注册审计仓库接口
+type EnrollmentRepository interface {
+	CreateKey(ctx context.Context, key *model.EnrollmentKey) error
+	GetKeyByID(ctx context.Context, id uint) (*model.EnrollmentKey, error)
+	GetKeyByHash(ctx context.Context, hash string) (*model.EnrollmentKey, error)
+	UpdateKey(ctx context.Context, key *model.EnrollmentKey) error
+	DeleteKey(ctx context.Context, id uint) error
+	ListKeys(ctx context.Context, filter *EnrollmentKeyFilter) ([]*model.EnrollmentKey, int64, error)
+	ConsumeKey(ctx context.Context, id uint, now time.Time) (bool, error)
+	ReleaseKey(ctx context.Context, id uint) error
+	RevokeKey(ctx context.Context, id uint, now time.Time) error
+
+	CreateEnrollment(ctx context.Context, enrollment *model.SentinelEnrollment) error
+	ListEnrollments(ctx context.Context, filter *SentinelEnrollmentFilter) ([]*model.SentinelEnrollment, int64, error)
+}
+
+// EnrollmentKeyFilter 注册密钥过滤条件
+type EnrollmentKeyFilter struct {
+	Page     int
+	PageSize int
+	Keyword  string
+	Region   string
+}
+
+// SentinelEnrollmentFilter 注册审计过滤条件
+type SentinelEnrollmentFilter struct {
+	Page            int
+	PageSize        int
+	SentinelID      string
+	EnrollmentKeyID uint
+	Result          string
+}
+
+type enrollmentRepository struct {
+	db *gorm.DB
+}
+
+// NewEnrollmentRepository 创建注册密钥仓库
+func NewEnrollmentRepository(db *gorm.DB) EnrollmentRepository {
+	return &enrollmentRepository{db: db}
+}
+
+func (r *enrollmentRepository) CreateKey(ctx context.Context, key *model.EnrollmentKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+func (r *enrollmentRepository) GetKeyByID(ctx context.Context, id uint) (*model.EnrollmentKey, error) {
+	var key model.EnrollmentKey
+	err := r.db.WithContext(ctx).First(&key, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *enrollmentRepository) GetKeyByHash(ctx context.Context, hash string) (*model.EnrollmentKey, error) {
+	var key model.EnrollmentKey
+	err := r.db.WithContext(ctx).Where("key_hash = ?", hash).First(&key).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *enrollmentRepository) UpdateKey(ctx context.Context, key *model.EnrollmentKey) error {
+	return r.db.WithContext(ctx).Model(key).Select(
+		"name", "description", "region", "labels", "require_approval", "max_uses", "expires_at", "updated_at",
+	).Updates(key).Error
+}
+
+func (r *enrollmentRepository) DeleteKey(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&model.EnrollmentKey{}, id).Error
+}
+
+func (r *enrollmentRepository) ListKeys(ctx context.Context, filter *EnrollmentKeyFilter) ([]*model.EnrollmentKey, int64, error) {
+	var keys []*model.EnrollmentKey
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.EnrollmentKey{})
+
+	if filter.Keyword != "" {
+		query = query.Where("name LIKE ? OR description LIKE ?", "%"+filter.Keyword+"%", "%"+filter.Keyword+"%")
+	}
+	if filter.Region != "" {
+		query = query.Where("region = ?", filter.Region)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (filter.Page - 1) * filter.PageSize
+	err := query.Offset(offset).Limit(filter.PageSize).Order("created_at DESC").Find(&keys).Error
+
+	return keys, total, err
+}
+
+// ConsumeKey 占用一次密钥使用次数
+// 通过条件更新保证并发注册时不会超过最大使用次数，密钥已吊销、过期或用尽时返回 false
+func (r *enrollmentRepository) ConsumeKey(ctx context.Context, id uint, now time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&model.EnrollmentKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Where("expires_at IS NULL OR expires_at > ?", now).
+		Where("max_uses = 0 OR used_count < max_uses").
+		Updates(map[string]interface{}{
+			"used_count":   gorm.Expr("used_count + 1"),
+			"last_used_at": now,
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+// ReleaseKey 注册失败时归还占用的使用次数
+func (r *enrollmentRepository) ReleaseKey(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Model(&model.EnrollmentKey{}).
+		Where("id = ? AND used_count > 0", id).
+		Update("used_count", gorm.Expr("used_count - 1")).Error
+}
+
+func (r *enrollmentRepository) RevokeKey(ctx context.Context, id uint, now time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.EnrollmentKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"revoked_at": now,
+			"updated_at": now,
+		}).Error
+}
+
+func (r *enrollmentRepository) CreateEnrollment(ctx context.Context, enrollment *model.SentinelEnrollment) error {
+	return r.db.WithContext(ctx).Create(enrollment).Error
+}
+
+func (r *enrollmentRepository) ListEnrollments(ctx context.Context, filter *SentinelEnrollmentFilter) ([]*model.SentinelEnrollment, int64, error) {
+	var enrollments []*model.SentinelEnrollment
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.SentinelEnrollment{})
+
+	if filter.SentinelID != "" {
+		query = query.Where("sentinel_id = ?", filter.SentinelID)
+	}
+	if filter.EnrollmentKeyID > 0 {
+		query = query.Where("enrollment_key_id = ?", filter.EnrollmentKeyID)
+	}
+	if filter.Result != "" {
+		query = query.Where("result = ?", filter.Result)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (filter.Page - 1) * filter.PageSize
+	err := query.Offset(offset).Limit(filter.PageSize).Order("created_at DESC, id DESC").Find(&enrollments).Error
+
+	return enrollments, total, err
+}
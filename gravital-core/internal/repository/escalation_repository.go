@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/celestial/gravital-core/internal/model"
+)
+
+// EscalationRepository 升级策略和值班表仓库接口
+type EscalationRepository interface {
+	// 升级策略
+	CreatePolicy(ctx context.Context, policy *model.EscalationPolicy) error
+	GetPolicyByID(ctx context.Context, id uint) (*model.EscalationPolicy, error)
+	UpdatePolicy(ctx context.Context, policy *model.EscalationPolicy) error
+	DeletePolicy(ctx context.Context, id uint) error
+	ListPolicies(ctx context.Context, page, pageSize int) ([]*model.EscalationPolicy, int64, error)
+
+	// 值班表
+	CreateSchedule(ctx context.Context, schedule *model.OnCallSchedule) error
+	GetScheduleByID(ctx context.Context, id uint) (*model.OnCallSchedule, error)
+	UpdateSchedule(ctx context.Context, schedule *model.OnCallSchedule) error
+	DeleteSchedule(ctx context.Context, id uint) error
+	ListSchedules(ctx context.Context, page, pageSize int) ([]*model.OnCallSchedule, int64, error)
+
+	// 值班覆盖
+	CreateOverride(ctx context.Context, override *model.OnCallOverride) error
+	DeleteOverride(ctx context.Context, scheduleID, id uint) error
+	ListOverrides(ctx context.Context, scheduleID uint) ([]*model.OnCallOverride, error)
+}
+
+type escalationRepository struct {
+	db *gorm.DB
+}
+
+// NewEscalationRepository 创建升级策略仓库
+func NewEscalationRepository(db *gorm.DB) EscalationRepository {
+	return &escalationRepository{db: db}
+}
+
+func (r *escalationRepository) CreatePolicy(ctx context.Context, policy *model.EscalationPolicy) error {
+	return r.db.WithContext(ctx).Create(policy).Error
+}
+
+func (r *escalationRepository) GetPolicyByID(ctx context.Context, id uint) (*model.EscalationPolicy, error) {
+	var policy model.EscalationPolicy
+	err := r.db.WithContext(ctx).First(&policy, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (r *escalationRepository) UpdatePolicy(ctx context.Context, policy *model.EscalationPolicy) error {
+	return r.db.WithContext(ctx).Save(policy).Error
+}
+
+func (r *escalationRepository) DeletePolicy(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&model.EscalationPolicy{}, id).Error
+}
+
+func (r *escalationRepository) ListPolicies(ctx context.Context, page, pageSize int) ([]*model.EscalationPolicy, int64, error) {
+	var policies []*model.EscalationPolicy
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.EscalationPolicy{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Offset(offset).Limit(pageSize).Order("name").Find(&policies).Error
+
+	return policies, total, err
+}
+
+func (r *escalationRepository) CreateSchedule(ctx context.Context, schedule *model.OnCallSchedule) error {
+	return r.db.WithContext(ctx).Create(schedule).Error
+}
+
+func (r *escalationRepository) GetScheduleByID(ctx context.Context, id uint) (*model.OnCallSchedule, error) {
+	var schedule model.OnCallSchedule
+	err := r.db.WithContext(ctx).First(&schedule, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (r *escalationRepository) UpdateSchedule(ctx context.Context, schedule *model.OnCallSchedule) error {
+	return r.db.WithContext(ctx).Save(schedule).Error
+}
+
+func (r *escalationRepository) DeleteSchedule(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&model.OnCallSchedule{}, id).Error
+}
+
+func (r *escalationRepository) ListSchedules(ctx context.Context, page, pageSize int) ([]*model.OnCallSchedule, int64, error) {
+	var schedules []*model.OnCallSchedule
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.OnCallSchedule{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Offset(offset).Limit(pageSize).Order("name").Find(&schedules).Error
+
+	return schedules, total, err
+}
+
+func (r *escalationRepository) CreateOverride(ctx context.Context, override *model.OnCallOverride) error {
+	return r.db.WithContext(ctx).Create(override).Error
+}
+
+func (r *escalationRepository) DeleteOverride(ctx context.Context, scheduleID, id uint) error {
+	return r.db.WithContext(ctx).
+		Where("schedule_id = ?", scheduleID).
+		Delete(&model.OnCallOverride{}, id).Error
+}
+
+func (r *escalationRepository) ListOverrides(ctx context.Context, scheduleID uint) ([]*model.OnCallOverride, error) {
+	var overrides []*model.OnCallOverride
+	err := r.db.WithContext(ctx).
+		Where("schedule_id = ?", scheduleID).
+		Order("starts_at DESC").
+		Find(&overrides).Error
+	return overrides, err
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/celestial/gravital-core/internal/model"
+)
+
+// NotificationOutboxRepository 通知发送队列仓库接口
+type NotificationOutboxRepository interface {
+	GetByID(ctx context.Context, id uint) (*model.NotificationOutbox, error)
+	List(ctx context.Context, filter *NotificationOutboxFilter) ([]*model.NotificationOutbox, int64, error)
+	Requeue(ctx context.Context, ids []uint, fromStatuses []string, status string, now time.Time) (int64, error)
+}
+
+// NotificationOutboxFilter 发送队列过滤条件
+type NotificationOutboxFilter struct {
+	Page         int
+	PageSize     int
+	Statuses     []string
+	Channel      string
+	AlertEventID uint
+}
+
+type notificationOutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationOutboxRepository 创建通知发送队列仓库
+func NewNotificationOutboxRepository(db *gorm.DB) NotificationOutboxRepository {
+	return &notificationOutboxRepository{db: db}
+}
+
+func (r *notificationOutboxRepository) GetByID(ctx context.Context, id uint) (*model.NotificationOutbox, error) {
+	var item model.NotificationOutbox
+	err := r.db.WithContext(ctx).First(&item, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *notificationOutboxRepository) List(ctx context.Context, filter *NotificationOutboxFilter) ([]*model.NotificationOutbox, int64, error) {
+	var items []*model.NotificationOutbox
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.NotificationOutbox{})
+
+	// 应用过滤条件
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+	if filter.Channel != "" {
+		query = query.Where("channel = ?", filter.Channel)
+	}
+	if filter.AlertEventID > 0 {
+		query = query.Where("alert_event_id = ?", filter.AlertEventID)
+	}
+
+	// 计算总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 分页查询
+	offset := (filter.Page - 1) * filter.PageSize
+	err := query.Offset(offset).Limit(filter.PageSize).Order("updated_at DESC").Find(&items).Error
+
+	return items, total, err
+}
+
+// Requeue 将处于 fromStatuses 状态的记录重新放回队列，并清零尝试次数
+func (r *notificationOutboxRepository) Requeue(ctx context.Context, ids []uint, fromStatuses []string, status string, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&model.NotificationOutbox{}).
+		Where("id IN ? AND status IN ?", ids, fromStatuses).
+		Updates(map[string]interface{}{
+			"status":          status,
+			"attempts":        0,
+			"next_attempt_at": now,
+			"updated_at":      now,
+		})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/celestial/gravital-core/internal/model"
+)
+
+// NotificationTemplateRepository 通知模板仓库接口
+type NotificationTemplateRepository interface {
+	Create(ctx context.Context, tpl *model.NotificationTemplate) error
+	GetByID(ctx context.Context, id uint) (*model.NotificationTemplate, error)
+	Update(ctx context.Context, tpl *model.NotificationTemplate) error
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context, filter *NotificationTemplateFilter) ([]*model.NotificationTemplate, int64, error)
+}
+
+// NotificationTemplateFilter 通知模板过滤条件
+type NotificationTemplateFilter struct {
+	Page     int
+	PageSize int
+	Channel  string
+	Keyword  string
+}
+
+type notificationTemplateRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationTemplateRepository 创建通知模板仓库
+func NewNotificationTemplateRepository(db *gorm.DB) NotificationTemplateRepository {
+	return &notificationTemplateRepository{db: db}
+}
+
+func (r *notificationTemplateRepository) Create(ctx context.Context, tpl *model.NotificationTemplate) error {
+	return r.db.WithContext(ctx).Create(tpl).Error
+}
+
+func (r *notificationTemplateRepository) GetByID(ctx context.Context, id uint) (*model.NotificationTemplate, error) {
+	var tpl model.NotificationTemplate
+	err := r.db.WithContext(ctx).First(&tpl, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tpl, nil
+}
+
+func (r *notificationTemplateRepository) Update(ctx context.Context, tpl *model.NotificationTemplate) error {
+	return r.db.WithContext(ctx).Save(tpl).Error
+}
+
+func (r *notificationTemplateRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&model.NotificationTemplate{}, id).Error
+}
+
+func (r *notificationTemplateRepository) List(ctx context.Context, filter *NotificationTemplateFilter) ([]*model.NotificationTemplate, int64, error) {
+	var templates []*model.NotificationTemplate
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.NotificationTemplate{})
+
+	// 应用过滤条件
+	if filter.Channel != "" {
+		query = query.Where("channel = ?", filter.Channel)
+	}
+	if filter.Keyword != "" {
+		query = query.Where("name LIKE ?", "%"+filter.Keyword+"%")
+	}
+
+	// 计算总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 分页查询
+	offset := (filter.Page - 1) * filter.PageSize
+	err := query.Offset(offset).Limit(filter.PageSize).Order("name, channel").Find(&templates).Error
+
+	return templates, total, err
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/celestial/gravital-core/internal/model"
+)
+
+// SentinelCommandRepository Sentinel 控制命令仓库接口
+type SentinelCommandRepository interface {
+	Create(ctx context.Context, command *model.SentinelCommand) error
+	GetByID(ctx context.Context, id uint) (*model.SentinelCommand, error)
+	List(ctx context.Context, filter *SentinelCommandFilter) ([]*model.SentinelCommand, int64, error)
+	ClaimPending(ctx context.Context, sentinelID string, now time.Time, limit int) ([]*model.SentinelCommand, error)
+	Complete(ctx context.Context, sentinelID string, id uint, status string, result model.JSONB, errMsg string, now time.Time) (bool, error)
+	ExpireStale(ctx context.Context, sentinelID string, now time.Time) (int64, error)
+}
+
+// SentinelCommandFilter 控制命令过滤条件
+type SentinelCommandFilter struct {
+	Page       int
+	PageSize   int
+	SentinelID string
+	Status     string
+	Type       string
+}
+
+type sentinelCommandRepository struct {
+	db *gorm.DB
+}
+
+// NewSentinelCommandRepository 创建 Sentinel 控制命令仓库
+func NewSentinelCommandRepository(db *gorm.DB) SentinelCommandRepository {
+	return &sentinelCommandRepository{db: db}
+}
+
+func (r *sentinelCommandRepository) Create(ctx context.Context, command *model.SentinelCommand) error {
+	return r.db.WithContext(ctx).Create(command).Error
+}
+
+func (r *sentinelCommandRepository) GetByID(ctx context.Context, id uint) (*model.SentinelCommand, error) {
+	var command model.SentinelCommand
+	err := r.db.WithContext(ctx).First(&command, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &command, nil
+}
+
+func (r *sentinelCommandRepository) List(ctx context.Context, filter *SentinelCommandFilter) ([]*model.SentinelCommand, int64, error) {
+	var commands []*model.SentinelCommand
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.SentinelCommand{}).
+		Where("sentinel_id = ?", filter.SentinelID)
+
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (filter.Page - 1) * filter.PageSize
+	err := query.Offset(offset).Limit(filter.PageSize).Order("created_at DESC, id DESC").Find(&commands).Error
+
+	return commands, total, err
+}
+
+// ClaimPending 领取待下发的命令并标记为已下发，按创建顺序返回
+// 多副本同时处理同一 Sentinel 的心跳时通过行锁（SKIP LOCKED）保证命令只下发一次
+func (r *sentinelCommandRepository) ClaimPending(ctx context.Context, sentinelID string, now time.Time, limit int) ([]*model.SentinelCommand, error) {
+	var commands []*model.SentinelCommand
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("sentinel_id = ? AND status = ? AND expires_at > ?", sentinelID, model.SentinelCommandStatusPending, now).
+			Order("id").
+			Limit(limit).
+			Find(&commands).Error; err != nil {
+			return err
+		}
+		if len(commands) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, 0, len(commands))
+		for _, command := range commands {
+			ids = append(ids, command.ID)
+			command.Status = model.SentinelCommandStatusDelivered
+			command.DeliveredAt = &now
+		}
+		return tx.Model(&model.SentinelCommand{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"status":       model.SentinelCommandStatusDelivered,
+				"delivered_at": now,
+				"updated_at":   now,
+			}).Error
+	})
+	return commands, err
+}
+
+// Complete 记录命令执行结果，只更新已下发且属于该 Sentinel 的命令
+func (r *sentinelCommandRepository) Complete(ctx context.Context, sentinelID string, id uint, status string, result model.JSONB, errMsg string, now time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&model.SentinelCommand{}).
+		Where("id = ? AND sentinel_id = ? AND status = ?", id, sentinelID, model.SentinelCommandStatusDelivered).
+		Updates(map[string]interface{}{
+			"status":       status,
+			"result":       result,
+			"error":        errMsg,
+			"completed_at": now,
+			"updated_at":   now,
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+// ExpireStale 将超过有效期仍未完成的命令标记为过期
+func (r *sentinelCommandRepository) ExpireStale(ctx context.Context, sentinelID string, now time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Model(&model.SentinelCommand{}).
+		Where("sentinel_id = ? AND status IN ? AND expires_at <= ?", sentinelID,
+			[]string{model.SentinelCommandStatusPending, model.SentinelCommandStatusDelivered}, now).
+		Updates(map[string]interface{}{
+			"status":       model.SentinelCommandStatusExpired,
+			"completed_at": now,
+			"updated_at":   now,
+		})
+	return res.RowsAffected, res.Error
+}
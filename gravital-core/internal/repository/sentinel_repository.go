@@ -20,14 +20,17 @@ type SentinelRepository interface {
 	UpdateHeartbeat(ctx context.Context, sentinelID string, heartbeat *model.SentinelHeartbeat) error
 	UpdateStatus(ctx context.Context, sentinelID string, status string) error
 	GetByHostname(ctx context.Context, hostname string) (*model.Sentinel, error)
+	UpdateFields(ctx context.Context, id uint, fields map[string]interface{}) error
+	PromotePendingToken(ctx context.Context, id uint, pendingHash string, previousExpiresAt, now time.Time) (bool, error)
 }
 
 // SentinelFilter Sentinel 过滤条件
 type SentinelFilter struct {
-	Page     int
-	PageSize int
-	Status   string
-	Region   string
+	Page           int
+	PageSize       int
+	Status         string
+	Region         string
+	ApprovalStatus string
 }
 
 type sentinelRepository struct {
@@ -91,6 +94,9 @@ func (r *sentinelRepository) List(ctx context.Context, filter *SentinelFilter) (
 	if filter.Region != "" {
 		query = query.Where("region = ?", filter.Region)
 	}
+	if filter.ApprovalStatus != "" {
+		query = query.Where("approval_status = ?", filter.ApprovalStatus)
+	}
 
 	// 计算总数
 	if err := query.Count(&total).Error; err != nil {
@@ -126,3 +132,27 @@ func (r *sentinelRepository) UpdateStatus(ctx context.Context, sentinelID string
 		Where("sentinel_id = ?", sentinelID).
 		Update("status", status).Error
 }
+
+// UpdateFields 只更新指定字段，避免并发请求用整行保存覆盖 Token 等字段
+func (r *sentinelRepository) UpdateFields(ctx context.Context, id uint, fields map[string]interface{}) error {
+	return r.db.WithContext(ctx).Model(&model.Sentinel{}).
+		Where("id = ?", id).
+		Updates(fields).Error
+}
+
+// PromotePendingToken 启用待生效的新 Token，当前 Token 转为旧 Token 并在重叠期内保持有效
+// 只有待生效 Token 仍为 pendingHash 时才更新，保证并发请求只启用一次
+func (r *sentinelRepository) PromotePendingToken(ctx context.Context, id uint, pendingHash string, previousExpiresAt, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.Sentinel{}).
+		Where("id = ? AND pending_token_hash = ?", id, pendingHash).
+		Updates(map[string]interface{}{
+			"previous_token_hash":       gorm.Expr("api_token_hash"),
+			"previous_token_expires_at": previousExpiresAt,
+			"api_token_hash":            pendingHash,
+			"pending_token_hash":        "",
+			"pending_token_overlap":     0,
+			"token_rotated_at":          now,
+			"updated_at":                now,
+		})
+	return result.RowsAffected > 0, result.Error
+}
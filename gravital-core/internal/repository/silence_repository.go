@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/celestial/gravital-core/internal/model"
+)
+
+// SilenceRepository 告警静默仓库接口
+type SilenceRepository interface {
+	Create(ctx context.Context, silence *model.AlertSilence) error
+	GetByID(ctx context.Context, id uint) (*model.AlertSilence, error)
+	Update(ctx context.Context, silence *model.AlertSilence) error
+	List(ctx context.Context, filter *SilenceFilter) ([]*model.AlertSilence, int64, error)
+	ListActive(ctx context.Context, now time.Time) ([]*model.AlertSilence, error)
+}
+
+// SilenceFilter 静默过滤条件
+type SilenceFilter struct {
+	Page     int
+	PageSize int
+	State    string // pending/active/expired
+	Now      time.Time
+}
+
+type silenceRepository struct {
+	db *gorm.DB
+}
+
+// NewSilenceRepository 创建静默仓库
+func NewSilenceRepository(db *gorm.DB) SilenceRepository {
+	return &silenceRepository{db: db}
+}
+
+func (r *silenceRepository) Create(ctx context.Context, silence *model.AlertSilence) error {
+	return r.db.WithContext(ctx).Create(silence).Error
+}
+
+func (r *silenceRepository) GetByID(ctx context.Context, id uint) (*model.AlertSilence, error) {
+	var silence model.AlertSilence
+	err := r.db.WithContext(ctx).First(&silence, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &silence, nil
+}
+
+func (r *silenceRepository) Update(ctx context.Context, silence *model.AlertSilence) error {
+	return r.db.WithContext(ctx).Save(silence).Error
+}
+
+func (r *silenceRepository) List(ctx context.Context, filter *SilenceFilter) ([]*model.AlertSilence, int64, error) {
+	var silences []*model.AlertSilence
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.AlertSilence{})
+
+	// 应用过滤条件
+	switch filter.State {
+	case model.SilenceStatePending:
+		query = query.Where("starts_at > ?", filter.Now)
+	case model.SilenceStateActive:
+		query = query.Where("starts_at <= ? AND ends_at > ?", filter.Now, filter.Now)
+	case model.SilenceStateExpired:
+		query = query.Where("ends_at <= ?", filter.Now)
+	}
+
+	// 计算总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 分页查询
+	offset := (filter.Page - 1) * filter.PageSize
+	err := query.Offset(offset).Limit(filter.PageSize).Order("ends_at DESC").Find(&silences).Error
+
+	return silences, total, err
+}
+
+func (r *silenceRepository) ListActive(ctx context.Context, now time.Time) ([]*model.AlertSilence, error) {
+	var silences []*model.AlertSilence
+	err := r.db.WithContext(ctx).
+		Where("starts_at <= ? AND ends_at > ?", now, now).
+		Find(&silences).Error
+	return silences, err
+}
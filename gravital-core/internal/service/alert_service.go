@@ -6,9 +6,14 @@ import (
 	"fmt"
 	"time"
 
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 
+	"github.com/celestial/gravital-core/internal/alert/engine"
+	"github.com/celestial/gravital-core/internal/alert/silence"
 	"github.com/celestial/gravital-core/internal/model"
+	"github.com/celestial/gravital-core/internal/notification"
+	"github.com/celestial/gravital-core/internal/pkg/logger"
 	"github.com/celestial/gravital-core/internal/repository"
 )
 
@@ -29,6 +34,9 @@ type AlertService interface {
 	ResolveEvent(ctx context.Context, id uint, comment string) error
 	SilenceEvent(ctx context.Context, id uint, duration time.Duration, comment string) error
 	GetStats(ctx context.Context) (map[string]interface{}, error)
+
+	// 外部告警接入
+	IngestAlertmanager(ctx context.Context, payload *AlertmanagerWebhook) (*AlertmanagerIngestResult, error)
 }
 
 // CreateAlertRuleRequest 创建告警规则请求
@@ -37,9 +45,13 @@ type CreateAlertRuleRequest struct {
 	Enabled            bool                   `json:"enabled"`
 	Severity           string                 `json:"severity" binding:"required"`
 	Condition          string                 `json:"condition" binding:"required"`
+	Source             string                 `json:"source"` // engine（默认）或 event
 	Filters            map[string]interface{} `json:"filters"`
 	Duration           int                    `json:"duration"`
 	NotificationConfig map[string]interface{} `json:"notification_config"`
+	InhibitRules       map[string]interface{} `json:"inhibit_rules"`
+	MutePeriods        map[string]interface{} `json:"mute_periods"`
+	EscalationPolicyID *uint                  `json:"escalation_policy_id"`
 	Description        string                 `json:"description"`
 }
 
@@ -52,6 +64,9 @@ type UpdateAlertRuleRequest struct {
 	Filters            map[string]interface{} `json:"filters"`
 	Duration           int                    `json:"duration"`
 	NotificationConfig map[string]interface{} `json:"notification_config"`
+	InhibitRules       map[string]interface{} `json:"inhibit_rules"`
+	MutePeriods        map[string]interface{} `json:"mute_periods"`
+	EscalationPolicyID *uint                  `json:"escalation_policy_id"`
 	Description        string                 `json:"description"`
 }
 
@@ -62,6 +77,7 @@ type ListAlertRuleRequest struct {
 	Enabled  *bool  `form:"enabled"`
 	Severity string `form:"severity"`
 	Keyword  string `form:"keyword"`
+	Source   string `form:"source"`
 }
 
 // ListAlertEventRequest 告警事件列表请求
@@ -75,17 +91,35 @@ type ListAlertEventRequest struct {
 }
 
 type alertService struct {
-	alertRepo repository.AlertRepository
+	alertRepo       repository.AlertRepository
+	silences        *silence.Checker
+	notificationSvc notification.Service
 }
 
-// NewAlertService 创建告警服务
-func NewAlertService(alertRepo repository.AlertRepository) AlertService {
+// NewAlertService 创建告警服务，notificationSvc 为空时不发送通知
+func NewAlertService(alertRepo repository.AlertRepository, silences *silence.Checker, notificationSvc notification.Service) AlertService {
 	return &alertService{
-		alertRepo: alertRepo,
+		alertRepo:       alertRepo,
+		silences:        silences,
+		notificationSvc: notificationSvc,
 	}
 }
 
 func (s *alertService) CreateRule(ctx context.Context, req *CreateAlertRuleRequest) (*model.AlertRule, error) {
+	if req.Source == "" {
+		req.Source = model.AlertSourceEngine
+	}
+	if err := validateRuleCondition(req.Source, req.Condition); err != nil {
+		return nil, err
+	}
+	if err := validateSuppression(req.InhibitRules, req.MutePeriods); err != nil {
+		return nil, err
+	}
+	if req.EscalationPolicyID != nil && *req.EscalationPolicyID == 0 {
+		req.EscalationPolicyID = nil
+	}
+	warnLegacyEscalation(req.RuleName, req.NotificationConfig)
+
 	rule := &model.AlertRule{
 		RuleName:           req.RuleName,
 		Enabled:            req.Enabled,
@@ -94,6 +128,10 @@ func (s *alertService) CreateRule(ctx context.Context, req *CreateAlertRuleReque
 		Filters:            req.Filters,
 		Duration:           req.Duration,
 		NotificationConfig: req.NotificationConfig,
+		InhibitRules:       req.InhibitRules,
+		MutePeriods:        req.MutePeriods,
+		Source:             req.Source,
+		EscalationPolicyID: req.EscalationPolicyID,
 		Description:        req.Description,
 	}
 
@@ -132,6 +170,9 @@ func (s *alertService) UpdateRule(ctx context.Context, id uint, req *UpdateAlert
 		rule.Severity = req.Severity
 	}
 	if req.Condition != "" {
+		if err := validateRuleCondition(rule.Source, req.Condition); err != nil {
+			return err
+		}
 		rule.Condition = req.Condition
 	}
 	if req.Filters != nil {
@@ -141,8 +182,26 @@ func (s *alertService) UpdateRule(ctx context.Context, id uint, req *UpdateAlert
 		rule.Duration = req.Duration
 	}
 	if req.NotificationConfig != nil {
+		warnLegacyEscalation(rule.RuleName, req.NotificationConfig)
 		rule.NotificationConfig = req.NotificationConfig
 	}
+	if err := validateSuppression(req.InhibitRules, req.MutePeriods); err != nil {
+		return err
+	}
+	if req.InhibitRules != nil {
+		rule.InhibitRules = req.InhibitRules
+	}
+	if req.MutePeriods != nil {
+		rule.MutePeriods = req.MutePeriods
+	}
+	if req.EscalationPolicyID != nil {
+		// 传 0 表示取消关联升级策略
+		if *req.EscalationPolicyID == 0 {
+			rule.EscalationPolicyID = nil
+		} else {
+			rule.EscalationPolicyID = req.EscalationPolicyID
+		}
+	}
 	if req.Description != "" {
 		rule.Description = req.Description
 	}
@@ -150,6 +209,43 @@ func (s *alertService) UpdateRule(ctx context.Context, id uint, req *UpdateAlert
 	return s.alertRepo.UpdateRule(ctx, rule)
 }
 
+// validateRuleCondition 按规则来源校验条件，外部接入的规则不能手动创建
+func validateRuleCondition(source, condition string) error {
+	switch source {
+	case model.AlertSourceEngine:
+		return engine.ValidateCondition(condition)
+	case model.AlertSourceEvent:
+		_, err := engine.ParseEventCondition(condition)
+		return err
+	default:
+		return fmt.Errorf("%w: unsupported rule source %q", engine.ErrInvalidCondition, source)
+	}
+}
+
+// warnLegacyEscalation 旧版内联升级配置已不再生效，提示改用升级策略
+// 已有规则的旧配置由迁移 000021 转换为升级策略
+func warnLegacyEscalation(ruleName string, notificationConfig map[string]interface{}) {
+	for _, key := range []string{"escalation_enabled", "escalation_after", "escalation_channels"} {
+		if _, ok := notificationConfig[key]; ok {
+			logger.Warn("notification_config escalation fields are deprecated and ignored, use escalation_policy_id",
+				zap.String("rule", ruleName),
+				zap.String("field", key))
+			return
+		}
+	}
+}
+
+// validateSuppression 校验抑制规则和静默时段配置
+func validateSuppression(inhibitRules, mutePeriods map[string]interface{}) error {
+	if _, err := engine.ParseInhibitConfig(inhibitRules); err != nil {
+		return err
+	}
+	if _, err := engine.ParseMuteConfig(mutePeriods); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (s *alertService) DeleteRule(ctx context.Context, id uint) error {
 	return s.alertRepo.DeleteRule(ctx, id)
 }
@@ -172,6 +268,7 @@ func (s *alertService) ListRules(ctx context.Context, req *ListAlertRuleRequest)
 		Enabled:  req.Enabled,
 		Severity: req.Severity,
 		Keyword:  req.Keyword,
+		Source:   req.Source,
 	}
 
 	return s.alertRepo.ListRules(ctx, filter)
@@ -228,13 +325,22 @@ func (s *alertService) AcknowledgeEvent(ctx context.Context, id uint, userID uin
 		return fmt.Errorf("event not found")
 	}
 
+	alreadyAcknowledged := event.Acknowledged
+
 	now := time.Now()
 	event.Acknowledged = true
 	event.AcknowledgedBy = &userID
 	event.AcknowledgedAt = &now
 	event.Comment = comment
 
-	return s.alertRepo.UpdateEvent(ctx, event)
+	if err := s.alertRepo.UpdateEvent(ctx, event); err != nil {
+		return err
+	}
+
+	if !alreadyAcknowledged {
+		s.notifyLifecycle(event, notification.TransitionAcknowledged)
+	}
+	return nil
 }
 
 func (s *alertService) ResolveEvent(ctx context.Context, id uint, comment string) error {
@@ -243,12 +349,38 @@ func (s *alertService) ResolveEvent(ctx context.Context, id uint, comment string
 		return fmt.Errorf("event not found")
 	}
 
+	alreadyResolved := event.Status == "resolved"
+
 	now := time.Now()
 	event.Status = "resolved"
 	event.ResolvedAt = &now
 	event.Comment = comment
 
-	return s.alertRepo.UpdateEvent(ctx, event)
+	if err := s.alertRepo.UpdateEvent(ctx, event); err != nil {
+		return err
+	}
+
+	if !alreadyResolved {
+		s.notifyLifecycle(event, notification.TransitionResolved)
+	}
+	return nil
+}
+
+// notifyLifecycle 异步发送告警确认、恢复通知
+func (s *alertService) notifyLifecycle(event *model.AlertEvent, transition notification.Transition) {
+	if s.notificationSvc == nil || event.Rule == nil || event.Rule.NotificationConfig == nil {
+		return
+	}
+
+	config := notification.ParseNotificationConfig(event.Rule.NotificationConfig)
+	go func() {
+		if err := s.notificationSvc.SendLifecycle(context.Background(), event, transition, config); err != nil {
+			logger.Error("Failed to send lifecycle notification",
+				zap.String("alert_id", event.AlertID),
+				zap.String("transition", string(transition)),
+				zap.Error(err))
+		}
+	}()
 }
 
 func (s *alertService) SilenceEvent(ctx context.Context, id uint, duration time.Duration, comment string) error {
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/celestial/gravital-core/internal/alert/engine"
+	"github.com/celestial/gravital-core/internal/model"
+	"github.com/celestial/gravital-core/internal/notification"
+	"github.com/celestial/gravital-core/internal/pkg/logger"
+)
+
+// AlertmanagerWebhook Alertmanager webhook 负载（version 4）
+type AlertmanagerWebhook struct {
+	Version           string              `json:"version"`
+	GroupKey          string              `json:"groupKey"`
+	TruncatedAlerts   int                 `json:"truncatedAlerts"`
+	Status            string              `json:"status"`
+	Receiver          string              `json:"receiver"`
+	GroupLabels       map[string]string   `json:"groupLabels"`
+	CommonLabels      map[string]string   `json:"commonLabels"`
+	CommonAnnotations map[string]string   `json:"commonAnnotations"`
+	ExternalURL       string              `json:"externalURL"`
+	Alerts            []AlertmanagerAlert `json:"alerts" binding:"required"`
+}
+
+// AlertmanagerAlert Alertmanager 单条告警
+type AlertmanagerAlert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// AlertmanagerIngestResult 接入结果
+type AlertmanagerIngestResult struct {
+	Created  int `json:"created"`
+	Updated  int `json:"updated"`
+	Resolved int `json:"resolved"`
+	Skipped  int `json:"skipped"`
+}
+
+// IngestAlertmanager 将 Alertmanager webhook 中的告警映射为告警事件
+// 以 fingerprint 去重：同一指纹的活跃事件只更新，resolved 状态会解决对应事件。
+// 每个 alertname 对应一条 source=alertmanager 的规则，告警引擎不会评估这些规则；
+// 新告警与设备事件告警一样检查静默和静默时段，并按规则的通知配置发送通知。
+func (s *alertService) IngestAlertmanager(ctx context.Context, payload *AlertmanagerWebhook) (*AlertmanagerIngestResult, error) {
+	result := &AlertmanagerIngestResult{}
+	ruleCache := make(map[string]*model.AlertRule)
+
+	for i := range payload.Alerts {
+		alert := &payload.Alerts[i]
+		if alert.Labels == nil {
+			alert.Labels = make(map[string]string)
+		}
+		fingerprint := alert.Fingerprint
+		if fingerprint == "" {
+			fingerprint = alertFingerprint(alert.Labels)
+		}
+
+		// 查找同一指纹的活跃事件
+		existing, err := s.alertRepo.FindActiveEvent(ctx, model.AlertSourceAlertmanager, fingerprint)
+		found := err == nil
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return result, fmt.Errorf("failed to find event: %w", err)
+		}
+
+		if alert.Status == "resolved" {
+			if !found {
+				result.Skipped++
+				continue
+			}
+			resolvedAt := alert.EndsAt
+			if resolvedAt.IsZero() {
+				resolvedAt = time.Now()
+			}
+			existing.Status = "resolved"
+			existing.ResolvedAt = &resolvedAt
+			if err := s.alertRepo.UpdateEvent(ctx, existing); err != nil {
+				return result, fmt.Errorf("failed to resolve event: %w", err)
+			}
+			s.notifyLifecycle(existing, notification.TransitionResolved)
+			result.Resolved++
+			continue
+		}
+
+		labels := make(model.JSONB, len(alert.Labels)+1)
+		for k, v := range alert.Labels {
+			labels[k] = v
+		}
+		if alert.GeneratorURL != "" {
+			labels["generator_url"] = alert.GeneratorURL
+		}
+
+		if found {
+			existing.Message = alertmanagerMessage(alert)
+			existing.Labels = labels
+			if err := s.alertRepo.UpdateEvent(ctx, existing); err != nil {
+				return result, fmt.Errorf("failed to update event: %w", err)
+			}
+			result.Updated++
+			continue
+		}
+
+		rule, err := s.externalRule(ctx, ruleCache, alert)
+		if err != nil {
+			return result, err
+		}
+
+		triggeredAt := alert.StartsAt
+		if triggeredAt.IsZero() {
+			triggeredAt = time.Now()
+		}
+
+		event := &model.AlertEvent{
+			AlertID:     fmt.Sprintf("am-%s-%d", fingerprint, triggeredAt.Unix()),
+			RuleID:      rule.ID,
+			DeviceID:    alertDeviceID(alert.Labels),
+			MetricName:  alert.Labels["alertname"],
+			Severity:    alertSeverity(alert.Labels),
+			Message:     alertmanagerMessage(alert),
+			Labels:      labels,
+			Source:      model.AlertSourceAlertmanager,
+			Fingerprint: fingerprint,
+			TriggeredAt: triggeredAt,
+			Status:      "firing",
+		}
+
+		// 命中静默的告警记录为 silenced 状态，不发送通知
+		matched := s.silences.CheckEvent(ctx, event, rule)
+		if matched != nil {
+			event.Status = "silenced"
+		}
+
+		// 同一告警实例（指纹 + 开始时间）已被解决后重复推送时忽略
+		created, err := s.alertRepo.FirstOrCreateEvent(ctx, event)
+		if err != nil {
+			return result, fmt.Errorf("failed to create event: %w", err)
+		}
+		if !created {
+			result.Skipped++
+			continue
+		}
+		result.Created++
+
+		if matched == nil {
+			s.notifyFiring(event, rule)
+		}
+	}
+
+	return result, nil
+}
+
+// notifyFiring 异步发送外部告警的触发通知，处于规则静默时段内时不发送
+func (s *alertService) notifyFiring(event *model.AlertEvent, rule *model.AlertRule) {
+	if s.notificationSvc == nil || rule.NotificationConfig == nil {
+		return
+	}
+	if muteCfg, err := engine.ParseMuteConfig(rule.MutePeriods); err == nil && muteCfg.Active(time.Now()) {
+		return
+	}
+
+	event.Rule = rule
+	config := notification.ParseNotificationConfig(rule.NotificationConfig)
+	go func() {
+		if err := s.notificationSvc.SendAlert(context.Background(), event, config); err != nil {
+			logger.Error("Failed to send alert notification",
+				zap.String("alert_id", event.AlertID),
+				zap.Error(err))
+		}
+	}()
+}
+
+// externalRule 获取或创建 alertname 对应的外部规则
+func (s *alertService) externalRule(ctx context.Context, cache map[string]*model.AlertRule, alert *AlertmanagerAlert) (*model.AlertRule, error) {
+	name := alert.Labels["alertname"]
+	if name == "" {
+		name = "alertmanager"
+	}
+	if rule, ok := cache[name]; ok {
+		return rule, nil
+	}
+
+	rule := &model.AlertRule{
+		RuleName:    name,
+		Enabled:     true,
+		Severity:    alertSeverity(alert.Labels),
+		Condition:   "alertmanager",
+		Source:      model.AlertSourceAlertmanager,
+		Description: alert.Annotations["description"],
+	}
+	if err := s.alertRepo.FirstOrCreateRule(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to get external rule: %w", err)
+	}
+
+	cache[name] = rule
+	return rule, nil
+}
+
+// alertFingerprint 按排序后的标签计算指纹（负载未携带 fingerprint 时使用）
+func alertFingerprint(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write([]byte{0xff})
+		h.Write([]byte(labels[k]))
+		h.Write([]byte{0xff})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// alertDeviceID 从 device_id 标签中识别设备 ID
+// instance 是抓取目标地址（如 exporter 的 host:port），不是设备 ID，只保留在标签中
+func alertDeviceID(labels map[string]string) string {
+	return labels["device_id"]
+}
+
+// alertSeverity 从标签中获取告警级别
+func alertSeverity(labels map[string]string) string {
+	if v := labels["severity"]; v != "" {
+		return v
+	}
+	return "warning"
+}
+
+// alertmanagerMessage 由注解生成告警消息
+func alertmanagerMessage(alert *AlertmanagerAlert) string {
+	var parts []string
+	for _, key := range []string{"summary", "description", "message"} {
+		if v := alert.Annotations[key]; v != "" {
+			parts = append(parts, v)
+		}
+	}
+	if len(parts) == 0 {
+		return alert.Labels["alertname"]
+	}
+	return strings.Join(parts, ": ")
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/celestial/gravital-core/internal/model"
+	"github.com/celestial/gravital-core/internal/pkg/auth"
+	"github.com/celestial/gravital-core/internal/repository"
+)
+
+var (
+	// ErrEnrollmentKeyNotFound 注册密钥不存在
+	ErrEnrollmentKeyNotFound = errors.New("enrollment key not found")
+	// ErrInvalidEnrollmentKey 注册密钥参数无效
+	ErrInvalidEnrollmentKey = errors.New("invalid enrollment key")
+)
+
+// enrollmentKeyPrefixLen 保存并展示的密钥前缀长度
+const enrollmentKeyPrefixLen = 12
+
+// EnrollmentService 注册密钥服务接口
+type EnrollmentService interface {
+	CreateKey(ctx context.Context, req *CreateEnrollmentKeyRequest, userID uint) (*CreateEnrollmentKeyResponse, error)
+	GetKey(ctx context.Context, id uint) (*model.EnrollmentKey, error)
+	UpdateKey(ctx context.Context, id uint, req *UpdateEnrollmentKeyRequest) (*model.EnrollmentKey, error)
+	DeleteKey(ctx context.Context, id uint) error
+	RevokeKey(ctx context.Context, id uint) error
+	ListKeys(ctx context.Context, req *ListEnrollmentKeyRequest) ([]*model.EnrollmentKey, int64, error)
+	ListEnrollments(ctx context.Context, req *ListSentinelEnrollmentRequest) ([]*model.SentinelEnrollment, int64, error)
+}
+
+// CreateEnrollmentKeyRequest 创建注册密钥请求
+type CreateEnrollmentKeyRequest struct {
+	Name            string                 `json:"name" binding:"required"`
+	Description     string                 `json:"description"`
+	Region          string                 `json:"region"`
+	Labels          map[string]interface{} `json:"labels"`
+	RequireApproval bool                   `json:"require_approval"`
+	MaxUses         int                    `json:"max_uses"`   // 0 表示不限
+	ExpiresAt       *time.Time             `json:"expires_at"` // 为空表示永不过期
+}
+
+// CreateEnrollmentKeyResponse 创建注册密钥响应，明文密钥只在创建时返回一次
+type CreateEnrollmentKeyResponse struct {
+	Key           string               `json:"key"`
+	EnrollmentKey *model.EnrollmentKey `json:"enrollment_key"`
+}
+
+// UpdateEnrollmentKeyRequest 更新注册密钥请求
+type UpdateEnrollmentKeyRequest struct {
+	Name            string                 `json:"name"`
+	Description     *string                `json:"description"`
+	Region          *string                `json:"region"`
+	Labels          map[string]interface{} `json:"labels"`
+	RequireApproval *bool                  `json:"require_approval"`
+	MaxUses         *int                   `json:"max_uses"`
+	ExpiresAt       *time.Time             `json:"expires_at"`
+	ClearExpiresAt  bool                   `json:"clear_expires_at"` // true 时取消过期时间
+}
+
+// ListEnrollmentKeyRequest 注册密钥列表请求
+type ListEnrollmentKeyRequest struct {
+	Page     int    `form:"page"`
+	PageSize int    `form:"page_size"`
+	Keyword  string `form:"keyword"`
+	Region   string `form:"region"`
+}
+
+// ListSentinelEnrollmentRequest 注册审计列表请求
+type ListSentinelEnrollmentRequest struct {
+	Page            int    `form:"page"`
+	PageSize        int    `form:"page_size"`
+	SentinelID      string `form:"sentinel_id"`
+	EnrollmentKeyID uint   `form:"enrollment_key_id"`
+	Result          string `form:"result"`
+}
+
+type enrollmentService struct {
+	enrollmentRepo repository.EnrollmentRepository
+}
+
+// NewEnrollmentService 创建注册密钥服务
+func NewEnrollmentService(enrollmentRepo repository.EnrollmentRepository) EnrollmentService {
+	return &enrollmentService{
+		enrollmentRepo: enrollmentRepo,
+	}
+}
+
+func (s *enrollmentService) CreateKey(ctx context.Context, req *CreateEnrollmentKeyRequest, userID uint) (*CreateEnrollmentKeyResponse, error) {
+	if req.MaxUses < 0 {
+		return nil, fmt.Errorf("%w: max_uses must not be negative", ErrInvalidEnrollmentKey)
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: expires_at must be in the future", ErrInvalidEnrollmentKey)
+	}
+
+	secret, err := generateEnrollmentKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate enrollment key: %w", err)
+	}
+
+	key := &model.EnrollmentKey{
+		Name:            req.Name,
+		Description:     req.Description,
+		KeyHash:         auth.HashToken(secret),
+		KeyPrefix:       secret[:enrollmentKeyPrefixLen],
+		Region:          req.Region,
+		Labels:          req.Labels,
+		RequireApproval: req.RequireApproval,
+		MaxUses:         req.MaxUses,
+		ExpiresAt:       req.ExpiresAt,
+	}
+	if userID > 0 {
+		key.CreatedBy = &userID
+	}
+
+	if err := s.enrollmentRepo.CreateKey(ctx, key); err != nil {
+		return nil, fmt.Errorf("failed to create enrollment key: %w", err)
+	}
+
+	return &CreateEnrollmentKeyResponse{
+		Key:           secret,
+		EnrollmentKey: key,
+	}, nil
+}
+
+func (s *enrollmentService) GetKey(ctx context.Context, id uint) (*model.EnrollmentKey, error) {
+	key, err := s.enrollmentRepo.GetKeyByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEnrollmentKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to get enrollment key: %w", err)
+	}
+	return key, nil
+}
+
+func (s *enrollmentService) UpdateKey(ctx context.Context, id uint, req *UpdateEnrollmentKeyRequest) (*model.EnrollmentKey, error) {
+	key, err := s.GetKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 更新字段
+	if req.Name != "" {
+		key.Name = req.Name
+	}
+	if req.Description != nil {
+		key.Description = *req.Description
+	}
+	if req.Region != nil {
+		key.Region = *req.Region
+	}
+	if req.Labels != nil {
+		key.Labels = req.Labels
+	}
+	if req.RequireApproval != nil {
+		key.RequireApproval = *req.RequireApproval
+	}
+	if req.MaxUses != nil {
+		if *req.MaxUses < 0 {
+			return nil, fmt.Errorf("%w: max_uses must not be negative", ErrInvalidEnrollmentKey)
+		}
+		key.MaxUses = *req.MaxUses
+	}
+	if req.ClearExpiresAt {
+		key.ExpiresAt = nil
+	} else if req.ExpiresAt != nil {
+		key.ExpiresAt = req.ExpiresAt
+	}
+	key.UpdatedAt = time.Now()
+
+	if err := s.enrollmentRepo.UpdateKey(ctx, key); err != nil {
+		return nil, fmt.Errorf("failed to update enrollment key: %w", err)
+	}
+	return key, nil
+}
+
+// DeleteKey 删除注册密钥，注册审计记录保留密钥名称
+func (s *enrollmentService) DeleteKey(ctx context.Context, id uint) error {
+	if _, err := s.GetKey(ctx, id); err != nil {
+		return err
+	}
+	return s.enrollmentRepo.DeleteKey(ctx, id)
+}
+
+// RevokeKey 吊销注册密钥，已注册的 Sentinel 不受影响
+func (s *enrollmentService) RevokeKey(ctx context.Context, id uint) error {
+	if _, err := s.GetKey(ctx, id); err != nil {
+		return err
+	}
+	return s.enrollmentRepo.RevokeKey(ctx, id, time.Now())
+}
+
+func (s *enrollmentService) ListKeys(ctx context.Context, req *ListEnrollmentKeyRequest) ([]*model.EnrollmentKey, int64, error) {
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+
+	filter := &repository.EnrollmentKeyFilter{
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		Keyword:  req.Keyword,
+		Region:   req.Region,
+	}
+
+	return s.enrollmentRepo.ListKeys(ctx, filter)
+}
+
+func (s *enrollmentService) ListEnrollments(ctx context.Context, req *ListSentinelEnrollmentRequest) ([]*model.SentinelEnrollment, int64, error) {
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+
+	filter := &repository.SentinelEnrollmentFilter{
+		Page:            req.Page,
+		PageSize:        req.PageSize,
+		SentinelID:      req.SentinelID,
+		EnrollmentKeyID: req.EnrollmentKeyID,
+		Result:          req.Result,
+	}
+
+	return s.enrollmentRepo.ListEnrollments(ctx, filter)
+}
+
+// generateEnrollmentKey 生成注册密钥
+func generateEnrollmentKey() (string, error) {
+	bytes := make([]byte, 24)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return "enroll_" + hex.EncodeToString(bytes), nil
+}
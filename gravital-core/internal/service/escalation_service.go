@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/celestial/gravital-core/internal/alert/escalation"
+	"github.com/celestial/gravital-core/internal/model"
+	"github.com/celestial/gravital-core/internal/repository"
+)
+
+// EscalationService 升级策略和值班表服务接口
+type EscalationService interface {
+	CreatePolicy(ctx context.Context, req *CreateEscalationPolicyRequest, userID uint) (*model.EscalationPolicy, error)
+	GetPolicy(ctx context.Context, id uint) (*model.EscalationPolicy, error)
+	UpdatePolicy(ctx context.Context, id uint, req *UpdateEscalationPolicyRequest) error
+	DeletePolicy(ctx context.Context, id uint) error
+	ListPolicies(ctx context.Context, req *ListEscalationRequest) ([]*model.EscalationPolicy, int64, error)
+
+	CreateSchedule(ctx context.Context, req *CreateOnCallScheduleRequest, userID uint) (*model.OnCallSchedule, error)
+	GetSchedule(ctx context.Context, id uint) (*model.OnCallSchedule, error)
+	UpdateSchedule(ctx context.Context, id uint, req *UpdateOnCallScheduleRequest) error
+	DeleteSchedule(ctx context.Context, id uint) error
+	ListSchedules(ctx context.Context, req *ListEscalationRequest) ([]*model.OnCallSchedule, int64, error)
+	GetOnCall(ctx context.Context, id uint, at time.Time) (*OnCallResult, error)
+
+	CreateOverride(ctx context.Context, scheduleID uint, req *CreateOnCallOverrideRequest, userID uint) (*model.OnCallOverride, error)
+	DeleteOverride(ctx context.Context, scheduleID, id uint) error
+	ListOverrides(ctx context.Context, scheduleID uint) ([]*model.OnCallOverride, error)
+}
+
+// CreateEscalationPolicyRequest 创建升级策略请求
+type CreateEscalationPolicyRequest struct {
+	Name        string                  `json:"name" binding:"required"`
+	Description string                  `json:"description"`
+	Levels      []model.EscalationLevel `json:"levels" binding:"required,min=1"`
+	RepeatTimes int                     `json:"repeat_times"`
+}
+
+// UpdateEscalationPolicyRequest 更新升级策略请求
+type UpdateEscalationPolicyRequest struct {
+	Name        string                  `json:"name"`
+	Description *string                 `json:"description"`
+	Levels      []model.EscalationLevel `json:"levels"`
+	RepeatTimes *int                    `json:"repeat_times"`
+}
+
+// CreateOnCallScheduleRequest 创建值班表请求
+type CreateOnCallScheduleRequest struct {
+	Name        string                `json:"name" binding:"required"`
+	Description string                `json:"description"`
+	Timezone    string                `json:"timezone"`
+	Layers      []model.ScheduleLayer `json:"layers" binding:"required,min=1"`
+}
+
+// UpdateOnCallScheduleRequest 更新值班表请求
+type UpdateOnCallScheduleRequest struct {
+	Name        string                `json:"name"`
+	Description *string               `json:"description"`
+	Timezone    *string               `json:"timezone"`
+	Layers      []model.ScheduleLayer `json:"layers"`
+}
+
+// CreateOnCallOverrideRequest 创建值班覆盖请求
+type CreateOnCallOverrideRequest struct {
+	UserID   uint      `json:"user_id" binding:"required"`
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	EndsAt   time.Time `json:"ends_at" binding:"required"`
+	Comment  string    `json:"comment"`
+}
+
+// ListEscalationRequest 升级策略/值班表列表请求
+type ListEscalationRequest struct {
+	Page     int `form:"page"`
+	PageSize int `form:"page_size"`
+}
+
+// OnCallResult 值班查询结果
+type OnCallResult struct {
+	ScheduleID uint      `json:"schedule_id"`
+	At         time.Time `json:"at"`
+	UserID     *uint     `json:"user_id"`
+	Override   bool      `json:"override"`
+}
+
+type escalationService struct {
+	escalationRepo repository.EscalationRepository
+}
+
+// NewEscalationService 创建升级策略服务
+func NewEscalationService(escalationRepo repository.EscalationRepository) EscalationService {
+	return &escalationService{
+		escalationRepo: escalationRepo,
+	}
+}
+
+func (s *escalationService) CreatePolicy(ctx context.Context, req *CreateEscalationPolicyRequest, userID uint) (*model.EscalationPolicy, error) {
+	policy := &model.EscalationPolicy{
+		Name:        req.Name,
+		Description: req.Description,
+		Levels:      req.Levels,
+		RepeatTimes: req.RepeatTimes,
+		CreatedBy:   &userID,
+	}
+	if err := escalation.ValidatePolicy(policy); err != nil {
+		return nil, err
+	}
+
+	if err := s.escalationRepo.CreatePolicy(ctx, policy); err != nil {
+		return nil, fmt.Errorf("failed to create escalation policy: %w", err)
+	}
+	return policy, nil
+}
+
+func (s *escalationService) GetPolicy(ctx context.Context, id uint) (*model.EscalationPolicy, error) {
+	policy, err := s.escalationRepo.GetPolicyByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("escalation policy not found")
+		}
+		return nil, fmt.Errorf("failed to get escalation policy: %w", err)
+	}
+	return policy, nil
+}
+
+func (s *escalationService) UpdatePolicy(ctx context.Context, id uint, req *UpdateEscalationPolicyRequest) error {
+	policy, err := s.escalationRepo.GetPolicyByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("escalation policy not found")
+	}
+
+	// 更新字段
+	if req.Name != "" {
+		policy.Name = req.Name
+	}
+	if req.Description != nil {
+		policy.Description = *req.Description
+	}
+	if len(req.Levels) > 0 {
+		policy.Levels = req.Levels
+	}
+	if req.RepeatTimes != nil {
+		policy.RepeatTimes = *req.RepeatTimes
+	}
+
+	if err := escalation.ValidatePolicy(policy); err != nil {
+		return err
+	}
+
+	return s.escalationRepo.UpdatePolicy(ctx, policy)
+}
+
+// DeletePolicy 删除升级策略，关联规则的升级策略置空（外键 ON DELETE SET NULL）
+func (s *escalationService) DeletePolicy(ctx context.Context, id uint) error {
+	return s.escalationRepo.DeletePolicy(ctx, id)
+}
+
+func (s *escalationService) ListPolicies(ctx context.Context, req *ListEscalationRequest) ([]*model.EscalationPolicy, int64, error) {
+	normalizeEscalationPaging(req)
+	return s.escalationRepo.ListPolicies(ctx, req.Page, req.PageSize)
+}
+
+func (s *escalationService) CreateSchedule(ctx context.Context, req *CreateOnCallScheduleRequest, userID uint) (*model.OnCallSchedule, error) {
+	schedule := &model.OnCallSchedule{
+		Name:        req.Name,
+		Description: req.Description,
+		Timezone:    req.Timezone,
+		Layers:      req.Layers,
+		CreatedBy:   &userID,
+	}
+	if _, err := escalation.CompileSchedule(schedule); err != nil {
+		return nil, err
+	}
+
+	if err := s.escalationRepo.CreateSchedule(ctx, schedule); err != nil {
+		return nil, fmt.Errorf("failed to create on-call schedule: %w", err)
+	}
+	return schedule, nil
+}
+
+func (s *escalationService) GetSchedule(ctx context.Context, id uint) (*model.OnCallSchedule, error) {
+	schedule, err := s.escalationRepo.GetScheduleByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("on-call schedule not found")
+		}
+		return nil, fmt.Errorf("failed to get on-call schedule: %w", err)
+	}
+	return schedule, nil
+}
+
+func (s *escalationService) UpdateSchedule(ctx context.Context, id uint, req *UpdateOnCallScheduleRequest) error {
+	schedule, err := s.escalationRepo.GetScheduleByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("on-call schedule not found")
+	}
+
+	// 更新字段
+	if req.Name != "" {
+		schedule.Name = req.Name
+	}
+	if req.Description != nil {
+		schedule.Description = *req.Description
+	}
+	if req.Timezone != nil {
+		schedule.Timezone = *req.Timezone
+	}
+	if len(req.Layers) > 0 {
+		schedule.Layers = req.Layers
+	}
+
+	if _, err := escalation.CompileSchedule(schedule); err != nil {
+		return err
+	}
+
+	return s.escalationRepo.UpdateSchedule(ctx, schedule)
+}
+
+func (s *escalationService) DeleteSchedule(ctx context.Context, id uint) error {
+	return s.escalationRepo.DeleteSchedule(ctx, id)
+}
+
+func (s *escalationService) ListSchedules(ctx context.Context, req *ListEscalationRequest) ([]*model.OnCallSchedule, int64, error) {
+	normalizeEscalationPaging(req)
+	return s.escalationRepo.ListSchedules(ctx, req.Page, req.PageSize)
+}
+
+// GetOnCall 查询指定时间的值班人
+func (s *escalationService) GetOnCall(ctx context.Context, id uint, at time.Time) (*OnCallResult, error) {
+	schedule, err := s.GetSchedule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	compiled, err := escalation.CompileSchedule(schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := s.escalationRepo.ListOverrides(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list on-call overrides: %w", err)
+	}
+	// OnCall 要求覆盖按创建时间升序（后创建的优先）
+	active := make([]model.OnCallOverride, 0, len(overrides))
+	for _, o := range overrides {
+		if !at.Before(o.StartsAt) && at.Before(o.EndsAt) {
+			active = append(active, *o)
+		}
+	}
+	sort.SliceStable(active, func(i, j int) bool {
+		return active[i].CreatedAt.Before(active[j].CreatedAt)
+	})
+
+	result := &OnCallResult{ScheduleID: id, At: at, Override: len(active) > 0}
+	if userID, ok := compiled.OnCall(at, active); ok {
+		result.UserID = &userID
+	}
+	return result, nil
+}
+
+func (s *escalationService) CreateOverride(ctx context.Context, scheduleID uint, req *CreateOnCallOverrideRequest, userID uint) (*model.OnCallOverride, error) {
+	if _, err := s.GetSchedule(ctx, scheduleID); err != nil {
+		return nil, err
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		return nil, fmt.Errorf("%w: ends_at must be after starts_at", escalation.ErrInvalidEscalation)
+	}
+
+	override := &model.OnCallOverride{
+		ScheduleID: scheduleID,
+		UserID:     req.UserID,
+		StartsAt:   req.StartsAt,
+		EndsAt:     req.EndsAt,
+		Comment:    req.Comment,
+		CreatedBy:  &userID,
+	}
+	if err := s.escalationRepo.CreateOverride(ctx, override); err != nil {
+		return nil, fmt.Errorf("failed to create on-call override: %w", err)
+	}
+	return override, nil
+}
+
+func (s *escalationService) DeleteOverride(ctx context.Context, scheduleID, id uint) error {
+	return s.escalationRepo.DeleteOverride(ctx, scheduleID, id)
+}
+
+func (s *escalationService) ListOverrides(ctx context.Context, scheduleID uint) ([]*model.OnCallOverride, error) {
+	return s.escalationRepo.ListOverrides(ctx, scheduleID)
+}
+
+// normalizeEscalationPaging 设置分页默认值
+func normalizeEscalationPaging(req *ListEscalationRequest) {
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+}
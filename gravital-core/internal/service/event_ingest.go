@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/celestial/gravital-core/internal/alert/engine"
+	"github.com/celestial/gravital-core/internal/alert/silence"
+	"github.com/celestial/gravital-core/internal/model"
+	"github.com/celestial/gravital-core/internal/notification"
+	"github.com/celestial/gravital-core/internal/repository"
+)
+
+// 设备事件类型
+const (
+	DeviceEventTrap   = "trap"
+	DeviceEventSyslog = "syslog"
+)
+
+// eventRuleCacheTTL 事件规则的缓存时间
+const eventRuleCacheTTL = 10 * time.Second
+
+// volatileEventFields 不参与去重指纹的事件字段
+var volatileEventFields = map[string]bool{
+	"message":    true,
+	"timestamp":  true,
+	"sys_uptime": true,
+	"request_id": true,
+	"proc_id":    true,
+}
+
+// DeviceEvent Sentinel 上报的设备事件
+type DeviceEvent struct {
+	Type      string            `json:"type" binding:"required,oneof=trap syslog"`
+	DeviceID  string            `json:"device_id"`
+	SourceIP  string            `json:"source_ip" binding:"required"`
+	Timestamp time.Time         `json:"timestamp"`
+	Severity  string            `json:"severity"`
+	Message   string            `json:"message"`
+	Fields    map[string]string `json:"fields"`
+}
+
+// IngestEventsRequest 设备事件上报请求
+type IngestEventsRequest struct {
+	Events []DeviceEvent `json:"events" binding:"required,min=1,max=1000,dive"`
+}
+
+// EventIngestResult 设备事件接入结果
+type EventIngestResult struct {
+	Received int `json:"received"`
+	Matched  int `json:"matched"`
+	Created  int `json:"created"`
+	Updated  int `json:"updated"`
+}
+
+// EventIngestService 设备事件接入服务接口
+type EventIngestService interface {
+	Ingest(ctx context.Context, sentinelID string, events []DeviceEvent) (*EventIngestResult, error)
+}
+
+type eventIngestService struct {
+	db              *gorm.DB
+	logger          *zap.Logger
+	alertRepo       repository.AlertRepository
+	silences        *silence.Checker
+	notificationSvc notification.Service
+
+	mu       sync.Mutex
+	rules    []*eventRule
+	loadedAt time.Time
+}
+
+// eventRule 已解析条件的事件规则
+type eventRule struct {
+	rule *model.AlertRule
+	cond *engine.EventCondition
+}
+
+// NewEventIngestService 创建设备事件接入服务，notificationSvc 为空时只记录告警事件
+func NewEventIngestService(db *gorm.DB, alertRepo repository.AlertRepository, notificationSvc notification.Service, logger *zap.Logger) EventIngestService {
+	return &eventIngestService{
+		db:              db,
+		logger:          logger,
+		alertRepo:       alertRepo,
+		silences:        silence.NewChecker(db, logger),
+		notificationSvc: notificationSvc,
+	}
+}
+
+// Ingest 用 source=event 的规则匹配设备事件，命中时立即产生告警事件
+// 同一规则、设备和事件字段（不含消息和时间）的活跃告警只更新消息，不重复创建。
+// 事件告警没有恢复条件，需要手动解决；规则的 Duration 和抑制配置不生效，静默和静默时段生效。
+func (s *eventIngestService) Ingest(ctx context.Context, sentinelID string, events []DeviceEvent) (*EventIngestResult, error) {
+	result := &EventIngestResult{Received: len(events)}
+
+	rules, err := s.eventRules(ctx)
+	if err != nil {
+		return result, err
+	}
+	if len(rules) == 0 {
+		return result, nil
+	}
+
+	for i := range events {
+		ev := &events[i]
+		fields := deviceEventFields(ev, sentinelID)
+
+		for _, r := range rules {
+			if !r.cond.Matches(ev.Type, fields, r.rule.Filters) {
+				continue
+			}
+			result.Matched++
+
+			created, err := s.raise(ctx, r.rule, ev, fields)
+			if err != nil {
+				return result, err
+			}
+			if created {
+				result.Created++
+			} else {
+				result.Updated++
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// raise 为命中规则的事件创建或更新告警事件，返回是否新建
+func (s *eventIngestService) raise(ctx context.Context, rule *model.AlertRule, ev *DeviceEvent, fields map[string]string) (bool, error) {
+	fingerprint := eventFingerprint(rule.ID, fields)
+	message := fmt.Sprintf("%s: %s", rule.RuleName, ev.Message)
+
+	labels := make(model.JSONB, len(fields))
+	for k, v := range fields {
+		labels[k] = v
+	}
+
+	var existing model.AlertEvent
+	err := s.db.WithContext(ctx).
+		Where("source = ? AND fingerprint = ? AND status IN ?",
+			model.AlertSourceEvent, fingerprint, []string{"firing", "silenced"}).
+		Order("triggered_at DESC").
+		First(&existing).Error
+	if err == nil {
+		if err := s.db.WithContext(ctx).Model(&model.AlertEvent{}).
+			Where("id = ?", existing.ID).
+			Updates(map[string]interface{}{
+				"message": message,
+				"labels":  labels,
+			}).Error; err != nil {
+			return false, fmt.Errorf("failed to update event: %w", err)
+		}
+		return false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, fmt.Errorf("failed to find event: %w", err)
+	}
+
+	now := time.Now()
+	triggeredAt := ev.Timestamp
+	if triggeredAt.IsZero() || triggeredAt.After(now) {
+		triggeredAt = now
+	}
+
+	event := &model.AlertEvent{
+		AlertID:     fmt.Sprintf("evt-%s-%d", fingerprint, now.UnixNano()),
+		RuleID:      rule.ID,
+		DeviceID:    ev.DeviceID,
+		MetricName:  deviceEventName(ev),
+		Severity:    rule.Severity,
+		Message:     message,
+		Labels:      labels,
+		Source:      model.AlertSourceEvent,
+		Fingerprint: fingerprint,
+		TriggeredAt: triggeredAt,
+		Status:      "firing",
+	}
+
+	// 命中静默的告警记录为 silenced 状态，不发送通知
+	matched := s.silences.CheckEvent(ctx, event, rule)
+	if matched != nil {
+		event.Status = "silenced"
+	}
+
+	if err := s.alertRepo.CreateEvent(ctx, event); err != nil {
+		return false, fmt.Errorf("failed to create event: %w", err)
+	}
+
+	s.logger.Info("Event alert triggered",
+		zap.String("rule", rule.RuleName),
+		zap.String("device_id", ev.DeviceID),
+		zap.String("source_ip", ev.SourceIP),
+		zap.String("status", event.Status))
+
+	if matched != nil || s.notificationSvc == nil || rule.NotificationConfig == nil {
+		return true, nil
+	}
+	if muteCfg, err := engine.ParseMuteConfig(rule.MutePeriods); err == nil && muteCfg.Active(now) {
+		return true, nil
+	}
+
+	go func() {
+		config := notification.ParseNotificationConfig(rule.NotificationConfig)
+		if err := s.notificationSvc.SendAlert(context.Background(), event, config); err != nil {
+			s.logger.Error("Failed to send alert notification",
+				zap.String("alert_id", event.AlertID),
+				zap.Error(err))
+		}
+	}()
+
+	return true, nil
+}
+
+// eventRules 获取启用的事件规则（带缓存），条件无效的规则被跳过
+func (s *eventIngestService) eventRules(ctx context.Context) ([]*eventRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.loadedAt) < eventRuleCacheTTL {
+		return s.rules, nil
+	}
+
+	enabled := true
+	rules, _, err := s.alertRepo.ListRules(ctx, &repository.AlertRuleFilter{
+		Enabled:  &enabled,
+		Source:   model.AlertSourceEvent,
+		Page:     1,
+		PageSize: 1000,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list event rules: %w", err)
+	}
+
+	parsed := make([]*eventRule, 0, len(rules))
+	for _, rule := range rules {
+		cond, err := engine.ParseEventCondition(rule.Condition)
+		if err != nil {
+			s.logger.Warn("Invalid event rule condition",
+				zap.String("rule", rule.RuleName),
+				zap.String("condition", rule.Condition),
+				zap.Error(err))
+			continue
+		}
+		parsed = append(parsed, &eventRule{rule: rule, cond: cond})
+	}
+
+	s.rules = parsed
+	s.loadedAt = now
+	return s.rules, nil
+}
+
+// deviceEventFields 构造用于匹配的事件字段：事件自带字段以及内置字段
+func deviceEventFields(ev *DeviceEvent, sentinelID string) map[string]string {
+	fields := make(map[string]string, len(ev.Fields)+6)
+	for k, v := range ev.Fields {
+		fields[k] = v
+	}
+	fields["type"] = ev.Type
+	fields["message"] = ev.Message
+	fields["device_id"] = ev.DeviceID
+	fields["source_ip"] = ev.SourceIP
+	fields["sentinel_id"] = sentinelID
+	if ev.Severity != "" {
+		fields["severity"] = ev.Severity
+	}
+	return fields
+}
+
+// deviceEventName 告警事件展示的事件名称
+func deviceEventName(ev *DeviceEvent) string {
+	switch ev.Type {
+	case DeviceEventTrap:
+		if name := ev.Fields["trap_name"]; name != "" {
+			return name
+		}
+		if oid := ev.Fields["trap_oid"]; oid != "" {
+			return oid
+		}
+	case DeviceEventSyslog:
+		if app := ev.Fields["app_name"]; app != "" {
+			return "syslog:" + app
+		}
+	}
+	return ev.Type
+}
+
+// eventFingerprint 按规则和稳定的事件字段计算去重指纹
+func eventFingerprint(ruleID uint, fields map[string]string) string {
+	stable := make(map[string]string, len(fields)+1)
+	for k, v := range fields {
+		if !volatileEventFields[k] {
+			stable[k] = v
+		}
+	}
+	stable["rule_id"] = fmt.Sprint(ruleID)
+	return alertFingerprint(stable)
+}
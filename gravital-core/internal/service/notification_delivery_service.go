@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/celestial/gravital-core/internal/model"
+	"github.com/celestial/gravital-core/internal/notification"
+	"github.com/celestial/gravital-core/internal/repository"
+)
+
+// ErrDeliveryNotRetryable 通知未处于失败状态，不能重试
+var ErrDeliveryNotRetryable = errors.New("delivery is not failed")
+
+// retryableStatuses 可手动重试的投递状态
+var retryableStatuses = []string{
+	string(notification.StatusFailed),
+	string(notification.StatusDeadLetter),
+}
+
+// NotificationDeliveryService 通知投递服务接口
+type NotificationDeliveryService interface {
+	ListDeliveries(ctx context.Context, req *ListNotificationDeliveryRequest) ([]*model.NotificationOutbox, int64, error)
+	GetDelivery(ctx context.Context, id uint) (*model.NotificationOutbox, error)
+	RetryDelivery(ctx context.Context, id uint) error
+	RetryDeliveries(ctx context.Context, ids []uint) (int64, error)
+}
+
+// ListNotificationDeliveryRequest 通知投递列表请求
+type ListNotificationDeliveryRequest struct {
+	Page         int    `form:"page"`
+	PageSize     int    `form:"page_size"`
+	Status       string `form:"status"` // pending/sending/sent/failed/dead_letter，failed_all 表示失败和死信
+	Channel      string `form:"channel"`
+	AlertEventID uint   `form:"alert_event_id"`
+}
+
+// RetryNotificationDeliveryRequest 批量重试请求
+type RetryNotificationDeliveryRequest struct {
+	IDs []uint `json:"ids" binding:"required,min=1"`
+}
+
+type notificationDeliveryService struct {
+	outboxRepo repository.NotificationOutboxRepository
+}
+
+// NewNotificationDeliveryService 创建通知投递服务
+func NewNotificationDeliveryService(outboxRepo repository.NotificationOutboxRepository) NotificationDeliveryService {
+	return &notificationDeliveryService{
+		outboxRepo: outboxRepo,
+	}
+}
+
+func (s *notificationDeliveryService) ListDeliveries(ctx context.Context, req *ListNotificationDeliveryRequest) ([]*model.NotificationOutbox, int64, error) {
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+
+	filter := &repository.NotificationOutboxFilter{
+		Page:         req.Page,
+		PageSize:     req.PageSize,
+		Channel:      req.Channel,
+		AlertEventID: req.AlertEventID,
+	}
+	switch req.Status {
+	case "":
+	case "failed_all":
+		filter.Statuses = retryableStatuses
+	default:
+		filter.Statuses = []string{req.Status}
+	}
+
+	return s.outboxRepo.List(ctx, filter)
+}
+
+func (s *notificationDeliveryService) GetDelivery(ctx context.Context, id uint) (*model.NotificationOutbox, error) {
+	item, err := s.outboxRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("delivery not found")
+		}
+		return nil, fmt.Errorf("failed to get delivery: %w", err)
+	}
+	return item, nil
+}
+
+func (s *notificationDeliveryService) RetryDelivery(ctx context.Context, id uint) error {
+	if _, err := s.GetDelivery(ctx, id); err != nil {
+		return err
+	}
+
+	affected, err := s.RetryDeliveries(ctx, []uint{id})
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrDeliveryNotRetryable
+	}
+	return nil
+}
+
+// RetryDeliveries 将失败和死信的通知重新放回队列，返回重新入队的数量
+func (s *notificationDeliveryService) RetryDeliveries(ctx context.Context, ids []uint) (int64, error) {
+	affected, err := s.outboxRepo.Requeue(ctx, ids, retryableStatuses, string(notification.StatusPending), time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to retry deliveries: %w", err)
+	}
+	return affected, nil
+}
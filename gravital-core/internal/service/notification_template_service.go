@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/celestial/gravital-core/internal/model"
+	"github.com/celestial/gravital-core/internal/notification"
+	"github.com/celestial/gravital-core/internal/repository"
+)
+
+// NotificationTemplateService 通知模板服务接口
+type NotificationTemplateService interface {
+	CreateTemplate(ctx context.Context, req *CreateNotificationTemplateRequest, userID uint) (*model.NotificationTemplate, error)
+	GetTemplate(ctx context.Context, id uint) (*model.NotificationTemplate, error)
+	UpdateTemplate(ctx context.Context, id uint, req *UpdateNotificationTemplateRequest) error
+	DeleteTemplate(ctx context.Context, id uint) error
+	ListTemplates(ctx context.Context, req *ListNotificationTemplateRequest) ([]*model.NotificationTemplate, int64, error)
+	PreviewTemplate(ctx context.Context, req *PreviewNotificationTemplateRequest) (*NotificationTemplatePreview, error)
+}
+
+// CreateNotificationTemplateRequest 创建通知模板请求
+type CreateNotificationTemplateRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Channel     string `json:"channel"`
+	Subject     string `json:"subject"`
+	Content     string `json:"content" binding:"required"`
+	Description string `json:"description"`
+}
+
+// UpdateNotificationTemplateRequest 更新通知模板请求
+type UpdateNotificationTemplateRequest struct {
+	Subject     *string `json:"subject"`
+	Content     string  `json:"content"`
+	Description string  `json:"description"`
+}
+
+// ListNotificationTemplateRequest 通知模板列表请求
+type ListNotificationTemplateRequest struct {
+	Page     int    `form:"page"`
+	PageSize int    `form:"page_size"`
+	Channel  string `form:"channel"`
+	Keyword  string `form:"keyword"`
+}
+
+// PreviewNotificationTemplateRequest 模板预览请求
+// 指定 TemplateID 时预览已保存的模板，否则使用请求中的 Subject/Content；
+// 指定 EventID 时使用真实告警事件渲染，否则使用示例事件
+type PreviewNotificationTemplateRequest struct {
+	TemplateID *uint  `json:"template_id"`
+	Channel    string `json:"channel"`
+	Subject    string `json:"subject"`
+	Content    string `json:"content"`
+	EventID    *uint  `json:"event_id"`
+}
+
+// NotificationTemplatePreview 模板预览结果
+type NotificationTemplatePreview struct {
+	Channel string `json:"channel"`
+	Format  string `json:"format"`
+	Subject string `json:"subject"`
+	Content string `json:"content"`
+}
+
+type notificationTemplateService struct {
+	templateRepo repository.NotificationTemplateRepository
+	db           *gorm.DB
+}
+
+// NewNotificationTemplateService 创建通知模板服务
+func NewNotificationTemplateService(templateRepo repository.NotificationTemplateRepository, db *gorm.DB) NotificationTemplateService {
+	return &notificationTemplateService{
+		templateRepo: templateRepo,
+		db:           db,
+	}
+}
+
+func (s *notificationTemplateService) CreateTemplate(ctx context.Context, req *CreateNotificationTemplateRequest, userID uint) (*model.NotificationTemplate, error) {
+	tpl := &model.NotificationTemplate{
+		Name:        req.Name,
+		Channel:     req.Channel,
+		Subject:     req.Subject,
+		Content:     req.Content,
+		Description: req.Description,
+		CreatedBy:   &userID,
+	}
+
+	if err := notification.ValidateTemplate(tpl); err != nil {
+		return nil, err
+	}
+
+	if err := s.templateRepo.Create(ctx, tpl); err != nil {
+		return nil, fmt.Errorf("failed to create template: %w", err)
+	}
+
+	return tpl, nil
+}
+
+func (s *notificationTemplateService) GetTemplate(ctx context.Context, id uint) (*model.NotificationTemplate, error) {
+	tpl, err := s.templateRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("template not found")
+		}
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+	return tpl, nil
+}
+
+func (s *notificationTemplateService) UpdateTemplate(ctx context.Context, id uint, req *UpdateNotificationTemplateRequest) error {
+	tpl, err := s.templateRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("template not found")
+	}
+
+	// 更新字段
+	if req.Subject != nil {
+		tpl.Subject = *req.Subject
+	}
+	if req.Content != "" {
+		tpl.Content = req.Content
+	}
+	if req.Description != "" {
+		tpl.Description = req.Description
+	}
+
+	if err := notification.ValidateTemplate(tpl); err != nil {
+		return err
+	}
+
+	return s.templateRepo.Update(ctx, tpl)
+}
+
+func (s *notificationTemplateService) DeleteTemplate(ctx context.Context, id uint) error {
+	return s.templateRepo.Delete(ctx, id)
+}
+
+func (s *notificationTemplateService) ListTemplates(ctx context.Context, req *ListNotificationTemplateRequest) ([]*model.NotificationTemplate, int64, error) {
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+
+	filter := &repository.NotificationTemplateFilter{
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		Channel:  req.Channel,
+		Keyword:  req.Keyword,
+	}
+
+	return s.templateRepo.List(ctx, filter)
+}
+
+func (s *notificationTemplateService) PreviewTemplate(ctx context.Context, req *PreviewNotificationTemplateRequest) (*NotificationTemplatePreview, error) {
+	tpl := &model.NotificationTemplate{
+		Name:    "preview",
+		Channel: req.Channel,
+		Subject: req.Subject,
+		Content: req.Content,
+	}
+	if req.TemplateID != nil {
+		saved, err := s.GetTemplate(ctx, *req.TemplateID)
+		if err != nil {
+			return nil, err
+		}
+		tpl = saved
+	}
+	if tpl.Content == "" {
+		return nil, fmt.Errorf("%w: content is required", notification.ErrInvalidTemplate)
+	}
+
+	channel := notification.Channel(tpl.Channel)
+	if req.Channel != "" {
+		channel = notification.Channel(req.Channel)
+	}
+
+	event, rule, device := notification.SampleAlertEvent()
+	if req.EventID != nil {
+		var err error
+		if event, rule, device, err = s.loadEvent(ctx, *req.EventID); err != nil {
+			return nil, err
+		}
+	}
+
+	subject, content, err := notification.RenderTemplate(tpl, notification.NewTemplateData(event, rule, device, channel))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", notification.ErrInvalidTemplate, err)
+	}
+
+	return &NotificationTemplatePreview{
+		Channel: string(channel),
+		Format:  notification.ChannelFormat(channel),
+		Subject: subject,
+		Content: content,
+	}, nil
+}
+
+// loadEvent 加载预览使用的告警事件及其规则和设备
+func (s *notificationTemplateService) loadEvent(ctx context.Context, id uint) (*model.AlertEvent, *model.AlertRule, *model.Device, error) {
+	var event model.AlertEvent
+	if err := s.db.WithContext(ctx).Preload("Rule").First(&event, id).Error; err != nil {
+		return nil, nil, nil, fmt.Errorf("event not found")
+	}
+
+	var device *model.Device
+	if event.DeviceID != "" {
+		var d model.Device
+		if err := s.db.WithContext(ctx).Preload("Group").
+			Where("device_id = ?", event.DeviceID).
+			First(&d).Error; err == nil {
+			device = &d
+		}
+	}
+
+	return &event, event.Rule, device, nil
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/celestial/gravital-core/internal/model"
+)
+
+var (
+	// ErrMTLSDisabled 未启用 Sentinel 双向 TLS
+	ErrMTLSDisabled = errors.New("sentinel mtls is not enabled")
+	// ErrClientCertRequired 续期证书需要出示当前客户端证书
+	ErrClientCertRequired = errors.New("client certificate required")
+)
+
+const (
+	// defaultCertValidity 客户端证书默认有效期
+	defaultCertValidity = 30 * 24 * time.Hour
+	// defaultCertOverlap 续期后旧证书继续有效的时间，覆盖采集端切换证书期间的请求
+	defaultCertOverlap = 5 * time.Minute
+)
+
+// SentinelCertificateRequest 申请客户端证书请求
+type SentinelCertificateRequest struct {
+	CSR string `json:"csr" binding:"required"` // PEM 格式的证书签名请求
+}
+
+// SentinelCertificate 签发的客户端证书
+type SentinelCertificate struct {
+	Certificate   string    `json:"certificate"`    // PEM 格式的客户端证书
+	CACertificate string    `json:"ca_certificate"` // PEM 格式的 CA 证书
+	SerialNumber  string    `json:"serial_number"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+// issueCertificate 使用 CSR 的公钥为 Sentinel 签发客户端证书
+func (s *sentinelService) issueCertificate(sentinelID, csr string) (*SentinelCertificate, error) {
+	if s.ca == nil {
+		return nil, ErrMTLSDisabled
+	}
+
+	cert, certPEM, err := s.ca.SignSentinelCSR([]byte(csr), sentinelID, s.certValidity)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SentinelCertificate{
+		Certificate:   string(certPEM),
+		CACertificate: string(s.ca.CertPEM()),
+		SerialNumber:  cert.SerialNumber.Text(16),
+		ExpiresAt:     cert.NotAfter,
+	}, nil
+}
+
+// registerCertificate 注册时签发客户端证书，未提交 CSR 或未启用 mTLS 时返回 nil
+func (s *sentinelService) registerCertificate(sentinelID, csr string) (*SentinelCertificate, error) {
+	if csr == "" || s.ca == nil {
+		return nil, nil
+	}
+	return s.issueCertificate(sentinelID, csr)
+}
+
+// IssueCertificate 为已认证的 Sentinel 签发或续期客户端证书
+// 强制 mTLS 时，已签发的证书未过期则必须出示该证书才能续期，只凭 Token 只能在没有有效证书时申请；
+// 旧证书在过期前仍然有效，吊销 Sentinel 后凭 Token 校验拒绝其请求
+func (s *sentinelService) IssueCertificate(ctx context.Context, sentinelID string, req *SentinelCertificateRequest, certVerified bool) (*SentinelCertificate, error) {
+	if s.ca == nil {
+		return nil, ErrMTLSDisabled
+	}
+
+	sentinel, err := s.sentinelRepo.GetBySentinelID(ctx, sentinelID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSentinelNotFound
+		}
+		return nil, fmt.Errorf("failed to get sentinel: %w", err)
+	}
+	if sentinel.RevokedAt != nil {
+		return nil, ErrSentinelRevoked
+	}
+	if s.certRequired && !certVerified &&
+		sentinel.CertExpiresAt != nil && time.Now().Before(*sentinel.CertExpiresAt) {
+		return nil, ErrClientCertRequired
+	}
+
+	cert, err := s.issueCertificate(sentinel.SentinelID, req.CSR)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.sentinelRepo.UpdateFields(ctx, sentinel.ID, map[string]interface{}{
+		"cert_serial":              cert.SerialNumber,
+		"cert_expires_at":          cert.ExpiresAt,
+		"previous_cert_serial":     sentinel.CertSerial,
+		"previous_cert_expires_at": now.Add(defaultCertOverlap),
+		"updated_at":               now,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to save certificate: %w", err)
+	}
+	s.tokens.invalidate(sentinel.SentinelID)
+	return cert, nil
+}
+
+// VerifyCertificate 校验客户端证书是否为 Sentinel 当前的证书或重叠期内的旧证书
+// 重新注册或续期后被替换的证书即使未过期也会被拒绝
+func (s *sentinelService) VerifyCertificate(ctx context.Context, sentinelID, serial string) error {
+	now := time.Now()
+
+	sentinel, err := s.cachedSentinel(ctx, sentinelID, now)
+	if err != nil {
+		return err
+	}
+	if sentinel.RevokedAt != nil {
+		return ErrSentinelRevoked
+	}
+	if !certSerialValid(sentinel, serial, now) {
+		return ErrSentinelUnauthorized
+	}
+	return nil
+}
+
+// certSerialValid 证书序列号是否与记录的当前证书或重叠期内的旧证书一致
+func certSerialValid(sentinel *model.Sentinel, serial string, now time.Time) bool {
+	if serial == "" {
+		return false
+	}
+	if serial == sentinel.CertSerial {
+		return true
+	}
+	return serial == sentinel.PreviousCertSerial &&
+		sentinel.PreviousCertExpiresAt != nil && now.Before(*sentinel.PreviousCertExpiresAt)
+}
+
+// certValidity 解析客户端证书有效期
+func certValidity(d time.Duration) time.Duration {
+	if d <= 0 {
+		return defaultCertValidity
+	}
+	return d
+}
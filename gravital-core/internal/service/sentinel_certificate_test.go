@@ -0,0 +1,33 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/celestial/gravital-core/internal/model"
+)
+
+func TestCertSerialValid(t *testing.T) {
+	now := time.Now()
+	future, past := now.Add(time.Minute), now.Add(-time.Minute)
+
+	tests := []struct {
+		name     string
+		sentinel model.Sentinel
+		serial   string
+		want     bool
+	}{
+		{"current", model.Sentinel{CertSerial: "a1"}, "a1", true},
+		{"replaced", model.Sentinel{CertSerial: "b2"}, "a1", false},
+		{"previous in overlap", model.Sentinel{CertSerial: "b2", PreviousCertSerial: "a1", PreviousCertExpiresAt: &future}, "a1", true},
+		{"previous after overlap", model.Sentinel{CertSerial: "b2", PreviousCertSerial: "a1", PreviousCertExpiresAt: &past}, "a1", false},
+		{"previous without expiry", model.Sentinel{CertSerial: "b2", PreviousCertSerial: "a1"}, "a1", false},
+		{"empty serial", model.Sentinel{}, "", false},
+	}
+
+	for _, tt := range tests {
+		if got := certSerialValid(&tt.sentinel, tt.serial, now); got != tt.want {
+			t.Errorf("%s: certSerialValid = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/celestial/gravital-core/internal/model"
+	"github.com/celestial/gravital-core/internal/pkg/auth"
+)
+
+var (
+	// ErrEnrollmentRejected 注册密钥缺失或无效，注册被拒绝
+	ErrEnrollmentRejected = errors.New("enrollment rejected")
+	// ErrSentinelNotPending Sentinel 不处于待审批状态
+	ErrSentinelNotPending = errors.New("sentinel is not pending approval")
+)
+
+// 注册模式
+const (
+	RegistrationModeKey  = "key"  // 必须使用注册密钥
+	RegistrationModeOpen = "open" // 允许不带密钥注册
+)
+
+// consumeEnrollmentKey 校验注册密钥并占用一次使用次数
+// 开放注册模式下未携带密钥时返回 nil；密钥已知但不可用时同时返回密钥，用于审计记录
+func (s *sentinelService) consumeEnrollmentKey(ctx context.Context, req *RegisterSentinelRequest) (*model.EnrollmentKey, error) {
+	if req.RegistrationKey == "" {
+		if s.registration.Mode == RegistrationModeOpen {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%w: registration key is required", ErrEnrollmentRejected)
+	}
+
+	key, err := s.enrollmentRepo.GetKeyByHash(ctx, auth.HashToken(req.RegistrationKey))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: unknown registration key", ErrEnrollmentRejected)
+		}
+		return nil, fmt.Errorf("failed to get enrollment key: %w", err)
+	}
+
+	now := time.Now()
+	switch {
+	case key.RevokedAt != nil:
+		return key, fmt.Errorf("%w: registration key revoked", ErrEnrollmentRejected)
+	case key.ExpiresAt != nil && !now.Before(*key.ExpiresAt):
+		return key, fmt.Errorf("%w: registration key expired", ErrEnrollmentRejected)
+	case key.MaxUses > 0 && key.UsedCount >= key.MaxUses:
+		return key, fmt.Errorf("%w: registration key exhausted", ErrEnrollmentRejected)
+	case key.Region != "" && req.Region != "" && req.Region != key.Region:
+		return key, fmt.Errorf("%w: region %q is not allowed by registration key", ErrEnrollmentRejected, req.Region)
+	}
+
+	// 并发注册时以条件更新的结果为准
+	ok, err := s.enrollmentRepo.ConsumeKey(ctx, key.ID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume enrollment key: %w", err)
+	}
+	if !ok {
+		return key, fmt.Errorf("%w: registration key is no longer valid", ErrEnrollmentRejected)
+	}
+	return key, nil
+}
+
+// releaseEnrollmentKey 注册失败时归还密钥使用次数
+func (s *sentinelService) releaseEnrollmentKey(ctx context.Context, key *model.EnrollmentKey) {
+	if key == nil {
+		return
+	}
+	_ = s.enrollmentRepo.ReleaseKey(ctx, key.ID)
+}
+
+// applyEnrollmentScope 按注册密钥限定区域和默认标签，请求中的同名标签优先
+func applyEnrollmentScope(req *RegisterSentinelRequest, key *model.EnrollmentKey) {
+	if key == nil {
+		return
+	}
+
+	if req.Region == "" {
+		req.Region = key.Region
+	}
+
+	if len(key.Labels) > 0 {
+		labels := make(map[string]interface{}, len(key.Labels)+len(req.Labels))
+		for k, v := range key.Labels {
+			labels[k] = v
+		}
+		for k, v := range req.Labels {
+			labels[k] = v
+		}
+		req.Labels = labels
+	}
+}
+
+// reEnrollTarget 返回重新注册时证明了身份的已注册 Sentinel，未出示凭证时返回 nil
+// 客户端证书需为当前有效的证书（见 VerifyCertificate）；出示的 Sentinel ID 与 Token 不匹配时拒绝
+func (s *sentinelService) reEnrollTarget(ctx context.Context, req *RegisterSentinelRequest) (*model.Sentinel, error) {
+	sentinelID := req.SentinelID
+	if sentinelID == "" {
+		sentinelID = req.CertSentinelID
+	}
+	if sentinelID == "" {
+		return nil, nil
+	}
+
+	sentinel, err := s.sentinelRepo.GetBySentinelID(ctx, sentinelID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if req.SentinelID != "" {
+				return nil, ErrSentinelUnauthorized
+			}
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get sentinel: %w", err)
+	}
+	if sentinel.RevokedAt != nil {
+		return nil, ErrSentinelRevoked
+	}
+
+	if req.CertSentinelID == sentinel.SentinelID && certSerialValid(sentinel, req.CertSerial, time.Now()) {
+		return sentinel, nil
+	}
+	if req.SentinelID == "" {
+		// 只携带了已被替换的证书，按未证明身份处理
+		return nil, nil
+	}
+	if req.APIToken == "" {
+		return nil, ErrSentinelUnauthorized
+	}
+	if err := s.Authenticate(ctx, sentinel.SentinelID, req.APIToken); err != nil {
+		return nil, err
+	}
+	return sentinel, nil
+}
+
+// requireApproval 新注册的 Sentinel 是否需要审批
+func (s *sentinelService) requireApproval(key *model.EnrollmentKey) bool {
+	return s.registration.RequireApproval || (key != nil && key.RequireApproval)
+}
+
+// recordEnrollment 记录注册审计，审计失败不影响注册结果
+func (s *sentinelService) recordEnrollment(ctx context.Context, req *RegisterSentinelRequest, sentinelID string, key *model.EnrollmentKey, result, reason string) {
+	enrollment := &model.SentinelEnrollment{
+		SentinelID: sentinelID,
+		Hostname:   req.Hostname,
+		IPAddress:  req.SourceIP,
+		Result:     result,
+		Reason:     reason,
+	}
+	if enrollment.IPAddress == "" {
+		enrollment.IPAddress = req.IPAddress
+	}
+	if key != nil {
+		enrollment.EnrollmentKeyID = &key.ID
+		enrollment.KeyName = key.Name
+	}
+	_ = s.enrollmentRepo.CreateEnrollment(ctx, enrollment)
+}
+
+// Approve 审批通过待审批的 Sentinel，之后开始下发任务
+func (s *sentinelService) Approve(ctx context.Context, id uint, userID uint) (*model.Sentinel, error) {
+	sentinel, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sentinel.ApprovalStatus != model.SentinelApprovalPending {
+		return nil, ErrSentinelNotPending
+	}
+
+	now := time.Now()
+	fields := map[string]interface{}{
+		"approval_status": model.SentinelApprovalApproved,
+		"approved_at":     now,
+		"updated_at":      now,
+	}
+	if userID > 0 {
+		fields["approved_by"] = userID
+		sentinel.ApprovedBy = &userID
+	}
+	if err := s.sentinelRepo.UpdateFields(ctx, sentinel.ID, fields); err != nil {
+		return nil, fmt.Errorf("failed to approve sentinel: %w", err)
+	}
+
+	sentinel.ApprovalStatus = model.SentinelApprovalApproved
+	sentinel.ApprovedAt = &now
+	sentinel.UpdatedAt = now
+	return sentinel, nil
+}
+
+// Reject 拒绝待审批的 Sentinel 并吊销其凭证
+func (s *sentinelService) Reject(ctx context.Context, id uint, userID uint) error {
+	sentinel, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if sentinel.ApprovalStatus != model.SentinelApprovalPending {
+		return ErrSentinelNotPending
+	}
+
+	now := time.Now()
+	fields := map[string]interface{}{
+		"approval_status": model.SentinelApprovalRejected,
+		"approved_at":     now,
+		"updated_at":      now,
+	}
+	if userID > 0 {
+		fields["approved_by"] = userID
+	}
+	if err := s.sentinelRepo.UpdateFields(ctx, sentinel.ID, fields); err != nil {
+		return fmt.Errorf("failed to reject sentinel: %w", err)
+	}
+
+	return s.Revoke(ctx, sentinel.ID)
+}
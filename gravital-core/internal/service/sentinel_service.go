@@ -12,17 +12,43 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/celestial/gravital-core/internal/model"
+	"github.com/celestial/gravital-core/internal/pkg/auth"
+	"github.com/celestial/gravital-core/internal/pkg/config"
+	"github.com/celestial/gravital-core/internal/pkg/pki"
 	"github.com/celestial/gravital-core/internal/repository"
 )
 
+// ErrSentinelNotFound Sentinel 不存在
+var ErrSentinelNotFound = errors.New("sentinel not found")
+
+// ErrInvalidSentinelCommand 控制命令无效
+var ErrInvalidSentinelCommand = errors.New("invalid sentinel command")
+
+const (
+	// defaultCommandTimeout 控制命令默认有效期，超时未完成的命令标记为过期
+	defaultCommandTimeout = 10 * time.Minute
+	// maxCommandTimeout 控制命令最长有效期
+	maxCommandTimeout = 24 * time.Hour
+	// maxCommandsPerHeartbeat 单次心跳最多下发的命令数
+	maxCommandsPerHeartbeat = 10
+)
+
 // SentinelService Sentinel 服务接口
 type SentinelService interface {
 	Register(ctx context.Context, req *RegisterSentinelRequest) (*RegisterSentinelResponse, error)
-	Heartbeat(ctx context.Context, sentinelID string, req *HeartbeatRequest) error
+	Heartbeat(ctx context.Context, sentinelID string, req *HeartbeatRequest) (*HeartbeatResponse, error)
 	Get(ctx context.Context, id uint) (*model.Sentinel, error)
 	List(ctx context.Context, req *ListSentinelRequest) ([]*model.Sentinel, int64, error)
 	Delete(ctx context.Context, id uint) error
-	Control(ctx context.Context, sentinelID string, action string) error
+	Control(ctx context.Context, id uint, req *ControlSentinelRequest, userID uint) (*model.SentinelCommand, error)
+	ListCommands(ctx context.Context, id uint, req *ListSentinelCommandRequest) ([]*model.SentinelCommand, int64, error)
+	Authenticate(ctx context.Context, sentinelID, token string) error
+	RotateToken(ctx context.Context, id uint, req *RotateSentinelTokenRequest, userID uint) (*RotateSentinelTokenResponse, error)
+	Revoke(ctx context.Context, id uint) error
+	Approve(ctx context.Context, id uint, userID uint) (*model.Sentinel, error)
+	Reject(ctx context.Context, id uint, userID uint) error
+	IssueCertificate(ctx context.Context, sentinelID string, req *SentinelCertificateRequest, certVerified bool) (*SentinelCertificate, error)
+	VerifyCertificate(ctx context.Context, sentinelID, serial string) error
 }
 
 // RegisterSentinelRequest Sentinel 注册请求
@@ -36,15 +62,23 @@ type RegisterSentinelRequest struct {
 	Arch            string                 `json:"arch" binding:"required"`
 	Region          string                 `json:"region"`
 	Labels          map[string]interface{} `json:"labels"`
-	RegistrationKey string                 `json:"registration_key"` // 注册密钥(可选)
+	RegistrationKey string                 `json:"registration_key"` // 注册密钥(开放注册模式下可选)
+	CSR             string                 `json:"csr"`              // PEM 格式的证书签名请求，启用 mTLS 时签发客户端证书
+	SourceIP        string                 `json:"-"`                // 请求来源地址，用于注册审计
+	SentinelID      string                 `json:"-"`                // 重新注册时出示的 Sentinel ID（X-Sentinel-ID）
+	APIToken        string                 `json:"-"`                // 重新注册时出示的当前 Token（X-API-Token）
+	CertSentinelID  string                 `json:"-"`                // 已校验的客户端证书绑定的 Sentinel ID
+	CertSerial      string                 `json:"-"`                // 已校验的客户端证书序列号
 }
 
 // RegisterSentinelResponse Sentinel 注册响应
 type RegisterSentinelResponse struct {
-	SentinelID string                 `json:"sentinel_id"`
-	APIToken   string                 `json:"api_token"`
-	Config     map[string]interface{} `json:"config"`
-	Message    string                 `json:"message,omitempty"` // 附加消息
+	SentinelID     string                 `json:"sentinel_id"`
+	APIToken       string                 `json:"api_token"`
+	ApprovalStatus string                 `json:"approval_status"` // approved/pending，pending 时审批通过前不下发任务
+	Certificate    *SentinelCertificate   `json:"certificate,omitempty"`
+	Config         map[string]interface{} `json:"config"`
+	Message        string                 `json:"message,omitempty"` // 附加消息
 }
 
 // HeartbeatRequest 心跳请求
@@ -56,129 +90,276 @@ type HeartbeatRequest struct {
 	PluginCount   int     `json:"plugin_count"`
 	UptimeSeconds int64   `json:"uptime_seconds"`
 	Version       string  `json:"version"`
+	// Plugins 已加载插件及版本（名称 -> 版本），插件热加载后随下一次心跳上报；旧版本采集端不上报
+	Plugins map[string]string `json:"plugins"`
+	// AcceptCommands 采集端支持控制命令时为 true，只有这样的心跳才会领取命令
+	AcceptCommands bool                    `json:"accept_commands"`
+	CommandResults []SentinelCommandResult `json:"command_results"`
 }
 
-// ListSentinelRequest Sentinel 列表请求
-type ListSentinelRequest struct {
+// HeartbeatResponse 心跳响应
+type HeartbeatResponse struct {
+	Status        string                     `json:"status"`
+	ConfigVersion int                        `json:"config_version"`
+	Commands      []*SentinelCommandDelivery `json:"commands"`
+}
+
+// SentinelCommandDelivery 随心跳下发的控制命令
+type SentinelCommandDelivery struct {
+	ID        uint                   `json:"id"`
+	Type      string                 `json:"type"`
+	Payload   map[string]interface{} `json:"payload,omitempty"`
+	ExpiresAt time.Time              `json:"expires_at"`
+}
+
+// SentinelCommandResult 采集端回传的命令执行结果
+type SentinelCommandResult struct {
+	ID     uint                   `json:"id"`
+	Status string                 `json:"status"` // succeeded/failed
+	Result map[string]interface{} `json:"result"`
+	Error  string                 `json:"error"`
+}
+
+// ControlSentinelRequest 控制命令请求
+type ControlSentinelRequest struct {
+	Action         string                 `json:"action" binding:"required"`
+	Params         map[string]interface{} `json:"params"`
+	TimeoutSeconds int                    `json:"timeout_seconds"` // 命令有效期，默认 600 秒
+}
+
+// ListSentinelCommandRequest 控制命令列表请求
+type ListSentinelCommandRequest struct {
 	Page     int    `form:"page"`
 	PageSize int    `form:"page_size"`
 	Status   string `form:"status"`
-	Region   string `form:"region"`
+	Type     string `form:"type"`
+}
+
+// ListSentinelRequest Sentinel 列表请求
+type ListSentinelRequest struct {
+	Page           int    `form:"page"`
+	PageSize       int    `form:"page_size"`
+	Status         string `form:"status"`
+	Region         string `form:"region"`
+	ApprovalStatus string `form:"approval_status"`
 }
 
 type sentinelService struct {
-	sentinelRepo repository.SentinelRepository
+	sentinelRepo   repository.SentinelRepository
+	commandRepo    repository.SentinelCommandRepository
+	enrollmentRepo repository.EnrollmentRepository
+	registration   config.RegistrationConfig
+	ca             *pki.CA // 未启用 mTLS 时为 nil
+	certValidity   time.Duration
+	certRequired   bool
+	tokens         *tokenCache
 }
 
 // NewSentinelService 创建 Sentinel 服务
-func NewSentinelService(sentinelRepo repository.SentinelRepository) SentinelService {
+func NewSentinelService(sentinelRepo repository.SentinelRepository, commandRepo repository.SentinelCommandRepository, enrollmentRepo repository.EnrollmentRepository, cfg config.SentinelConfig, ca *pki.CA) SentinelService {
+	registration := cfg.Registration
+	if registration.Mode == "" {
+		registration.Mode = RegistrationModeKey
+	}
 	return &sentinelService{
-		sentinelRepo: sentinelRepo,
+		sentinelRepo:   sentinelRepo,
+		commandRepo:    commandRepo,
+		enrollmentRepo: enrollmentRepo,
+		registration:   registration,
+		ca:             ca,
+		certValidity:   certValidity(cfg.MTLS.CertValidity),
+		certRequired:   ca != nil && cfg.MTLS.Required,
+		tokens:         newTokenCache(tokenCacheTTL),
 	}
 }
 
 func (s *sentinelService) Register(ctx context.Context, req *RegisterSentinelRequest) (*RegisterSentinelResponse, error) {
-	// TODO: 这里可以添加 Registration Key 验证逻辑
-	// if req.RegistrationKey != expectedKey {
-	//     return nil, fmt.Errorf("invalid registration key")
-	// }
-
-	// 1. 检查是否已存在(基于 Hostname 或 MAC 地址)
-	var existing *model.Sentinel
-	var err error
-
-	if req.MACAddress != "" {
-		// 优先使用 MAC 地址查找(更可靠)
-		existing, err = s.sentinelRepo.GetByHostname(ctx, req.Hostname)
-		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("failed to check existing sentinel: %w", err)
+	// 1. 校验注册密钥并占用一次使用次数，注册失败时归还
+	key, err := s.consumeEnrollmentKey(ctx, req)
+	if err != nil {
+		if errors.Is(err, ErrEnrollmentRejected) {
+			s.recordEnrollment(ctx, req, "", key, model.EnrollmentResultRejected, err.Error())
+		}
+		return nil, err
+	}
+	registered := false
+	defer func() {
+		if !registered {
+			s.releaseEnrollmentKey(ctx, key)
 		}
-	} else {
-		// 仅使用 Hostname 查找
-		existing, err = s.sentinelRepo.GetByHostname(ctx, req.Hostname)
+	}()
+	applyEnrollmentScope(req, key)
+
+	var keyID *uint
+	if key != nil {
+		keyID = &key.ID
+	}
+
+	// 2. 已注册的 Sentinel 必须出示当前 Token 或客户端证书才能重新注册
+	existing, err := s.reEnrollTarget(ctx, req)
+	if err != nil {
+		if errors.Is(err, ErrSentinelUnauthorized) || errors.Is(err, ErrSentinelRevoked) {
+			claimed := req.SentinelID
+			if claimed == "" {
+				claimed = req.CertSentinelID
+			}
+			s.recordEnrollment(ctx, req, claimed, key, model.EnrollmentResultRejected, err.Error())
+		}
+		return nil, err
+	}
+
+	// 同一 Hostname 已注册但未证明身份时按新 Sentinel 注册，由管理员审批
+	hostnameTaken := false
+	if existing == nil {
+		sameHost, err := s.sentinelRepo.GetByHostname(ctx, req.Hostname)
 		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("failed to check existing sentinel: %w", err)
 		}
+		if sameHost != nil {
+			if sameHost.RevokedAt != nil {
+				s.recordEnrollment(ctx, req, sameHost.SentinelID, key, model.EnrollmentResultRejected, ErrSentinelRevoked.Error())
+				return nil, ErrSentinelRevoked
+			}
+			hostnameTaken = true
+		}
 	}
 
 	now := time.Now()
 
-	// 2. 如果已存在,更新信息
+	// 3. 重新注册时签发新 Token（原 Token 不保存明文，无法再返回）
+	// 审批状态、区域和标签保持不变
 	if existing != nil {
+		apiToken, err := generateAPIToken()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate api token: %w", err)
+		}
+		cert, err := s.registerCertificate(existing.SentinelID, req.CSR)
+		if err != nil {
+			return nil, err
+		}
 		existing.Name = req.Name
 		existing.IPAddress = req.IPAddress
 		existing.Version = req.Version
 		existing.OS = req.OS
 		existing.Arch = req.Arch
-		existing.Region = req.Region
-		existing.Labels = req.Labels
 		existing.Status = "online"
 		existing.LastHeartbeat = &now
 		existing.UpdatedAt = now
+		// 重新注册后原 Token 立即失效，不保留重叠期
+		existing.PreviousTokenHash = ""
+		existing.PreviousTokenExpiresAt = nil
+		existing.APITokenHash = auth.HashToken(apiToken)
+		existing.PendingTokenHash = ""
+		existing.PendingTokenOverlap = 0
+		existing.TokenRotatedAt = &now
+		if keyID != nil {
+			existing.EnrollmentKeyID = keyID
+		}
+		if cert != nil {
+			existing.CertSerial = cert.SerialNumber
+			existing.CertExpiresAt = &cert.ExpiresAt
+			existing.PreviousCertSerial = ""
+			existing.PreviousCertExpiresAt = nil
+		}
 
 		if err := s.sentinelRepo.Update(ctx, existing); err != nil {
 			return nil, fmt.Errorf("failed to update sentinel: %w", err)
 		}
+		s.tokens.invalidate(existing.SentinelID)
+		registered = true
+		s.recordEnrollment(ctx, req, existing.SentinelID, key, model.EnrollmentResultReEnrolled, "")
 
+		message := "Sentinel re-registered successfully"
+		if existing.ApprovalStatus == model.SentinelApprovalPending {
+			message = "Sentinel re-registered, pending approval"
+		}
 		return &RegisterSentinelResponse{
-			SentinelID: existing.SentinelID,
-			APIToken:   existing.APIToken,
+			SentinelID:     existing.SentinelID,
+			APIToken:       apiToken,
+			ApprovalStatus: existing.ApprovalStatus,
+			Certificate:    cert,
 			Config: map[string]interface{}{
 				"heartbeat_interval":  30,
 				"task_fetch_interval": 60,
 			},
-			Message: "Sentinel re-registered successfully",
+			Message: message,
 		}, nil
 	}
 
-	// 3. 生成新的 Sentinel ID 和 API Token
+	// 4. 生成新的 Sentinel ID 和 API Token
 	sentinelID := generateSentinelID(req.Hostname, req.MACAddress)
 	apiToken, err := generateAPIToken()
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate api token: %w", err)
 	}
+	cert, err := s.registerCertificate(sentinelID, req.CSR)
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. 创建新的 Sentinel，需要审批时进入待审批状态
+	approvalStatus := model.SentinelApprovalApproved
+	message := "Sentinel registered successfully"
+	if s.requireApproval(key) || hostnameTaken {
+		approvalStatus = model.SentinelApprovalPending
+		message = "Sentinel registered, pending approval"
+	}
 
-	// 4. 创建新的 Sentinel
 	sentinel := &model.Sentinel{
-		SentinelID:    sentinelID,
-		Name:          req.Name,
-		Hostname:      req.Hostname,
-		IPAddress:     req.IPAddress,
-		Version:       req.Version,
-		OS:            req.OS,
-		Arch:          req.Arch,
-		Region:        req.Region,
-		Labels:        req.Labels,
-		APIToken:      apiToken,
-		Status:        "online",
-		LastHeartbeat: &now,
-		RegisteredAt:  now,
-		UpdatedAt:     now,
+		SentinelID:      sentinelID,
+		Name:            req.Name,
+		Hostname:        req.Hostname,
+		IPAddress:       req.IPAddress,
+		Version:         req.Version,
+		OS:              req.OS,
+		Arch:            req.Arch,
+		Region:          req.Region,
+		Labels:          req.Labels,
+		APITokenHash:    auth.HashToken(apiToken),
+		ApprovalStatus:  approvalStatus,
+		EnrollmentKeyID: keyID,
+		Status:          "online",
+		LastHeartbeat:   &now,
+		RegisteredAt:    now,
+		UpdatedAt:       now,
+	}
+
+	if cert != nil {
+		sentinel.CertSerial = cert.SerialNumber
+		sentinel.CertExpiresAt = &cert.ExpiresAt
 	}
 
 	if err := s.sentinelRepo.Create(ctx, sentinel); err != nil {
 		return nil, fmt.Errorf("failed to create sentinel: %w", err)
 	}
+	registered = true
+	reason := ""
+	if hostnameTaken {
+		reason = "hostname already registered"
+	}
+	s.recordEnrollment(ctx, req, sentinelID, key, model.EnrollmentResultEnrolled, reason)
 
 	return &RegisterSentinelResponse{
-		SentinelID: sentinelID,
-		APIToken:   apiToken,
+		SentinelID:     sentinelID,
+		APIToken:       apiToken,
+		ApprovalStatus: approvalStatus,
+		Certificate:    cert,
 		Config: map[string]interface{}{
 			"heartbeat_interval":  30,
 			"task_fetch_interval": 60,
 		},
-		Message: "Sentinel registered successfully",
+		Message: message,
 	}, nil
 }
 
-func (s *sentinelService) Heartbeat(ctx context.Context, sentinelID string, req *HeartbeatRequest) error {
+func (s *sentinelService) Heartbeat(ctx context.Context, sentinelID string, req *HeartbeatRequest) (*HeartbeatResponse, error) {
 	// 检查 Sentinel 是否存在
 	sentinel, err := s.sentinelRepo.GetBySentinelID(ctx, sentinelID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return fmt.Errorf("sentinel not found")
+			return nil, ErrSentinelNotFound
 		}
-		return fmt.Errorf("failed to get sentinel: %w", err)
+		return nil, fmt.Errorf("failed to get sentinel: %w", err)
 	}
 
 	// 创建心跳记录
@@ -195,25 +376,149 @@ func (s *sentinelService) Heartbeat(ctx context.Context, sentinelID string, req
 
 	// 更新心跳
 	if err := s.sentinelRepo.UpdateHeartbeat(ctx, sentinelID, heartbeat); err != nil {
-		return fmt.Errorf("failed to update heartbeat: %w", err)
+		return nil, fmt.Errorf("failed to update heartbeat: %w", err)
 	}
 
 	// 如果版本不同，更新版本
 	if req.Version != "" && req.Version != sentinel.Version {
 		sentinel.Version = req.Version
-		if err := s.sentinelRepo.Update(ctx, sentinel); err != nil {
-			return fmt.Errorf("failed to update version: %w", err)
+		if err := s.sentinelRepo.UpdateFields(ctx, sentinel.ID, map[string]interface{}{"version": req.Version}); err != nil {
+			return nil, fmt.Errorf("failed to update version: %w", err)
 		}
 	}
 
+	// 插件版本变化时更新
+	if req.Plugins != nil && !samePluginVersions(sentinel.Plugins, req.Plugins) {
+		plugins := make(model.JSONB, len(req.Plugins))
+		for name, version := range req.Plugins {
+			plugins[name] = version
+		}
+		if err := s.sentinelRepo.UpdateFields(ctx, sentinel.ID, map[string]interface{}{"plugins": plugins}); err != nil {
+			return nil, fmt.Errorf("failed to update plugins: %w", err)
+		}
+		sentinel.Plugins = plugins
+	}
+
+	// 先记录执行结果，再下发新命令
+	if err := s.saveCommandResults(ctx, sentinel, req.CommandResults); err != nil {
+		return nil, err
+	}
+
+	resp := &HeartbeatResponse{
+		Status:        "ok",
+		ConfigVersion: 1,
+	}
+	if req.AcceptCommands {
+		commands, err := s.deliverCommands(ctx, sentinel)
+		if err != nil {
+			return nil, err
+		}
+		resp.Commands = commands
+	}
+
+	return resp, nil
+}
+
+// samePluginVersions 判断已记录的插件版本与心跳上报的是否一致
+func samePluginVersions(stored model.JSONB, reported map[string]string) bool {
+	if len(stored) != len(reported) {
+		return false
+	}
+	for name, version := range reported {
+		if v, ok := stored[name].(string); !ok || v != version {
+			return false
+		}
+	}
+	return true
+}
+
+// saveCommandResults 保存采集端回传的命令执行结果
+func (s *sentinelService) saveCommandResults(ctx context.Context, sentinel *model.Sentinel, results []SentinelCommandResult) error {
+	now := time.Now()
+	for _, r := range results {
+		status := model.SentinelCommandStatusFailed
+		if r.Status == model.SentinelCommandStatusSucceeded {
+			status = model.SentinelCommandStatusSucceeded
+		}
+
+		command, err := s.commandRepo.GetByID(ctx, r.ID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			return fmt.Errorf("failed to get command: %w", err)
+		}
+
+		updated, err := s.commandRepo.Complete(ctx, sentinel.SentinelID, r.ID, status, r.Result, r.Error, now)
+		if err != nil {
+			return fmt.Errorf("failed to save command result: %w", err)
+		}
+
+		// 采集端确认已保存新 Token 后让新 Token 生效（若认证时尚未生效）
+		if updated && command.Type == model.SentinelCommandRotateToken &&
+			status == model.SentinelCommandStatusSucceeded {
+			if err := s.promotePendingToken(ctx, sentinel); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
+// deliverCommands 领取待下发的命令
+func (s *sentinelService) deliverCommands(ctx context.Context, sentinel *model.Sentinel) ([]*SentinelCommandDelivery, error) {
+	now := time.Now()
+	if _, err := s.commandRepo.ExpireStale(ctx, sentinel.SentinelID, now); err != nil {
+		return nil, fmt.Errorf("failed to expire commands: %w", err)
+	}
+
+	commands, err := s.commandRepo.ClaimPending(ctx, sentinel.SentinelID, now, maxCommandsPerHeartbeat)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim commands: %w", err)
+	}
+
+	deliveries := make([]*SentinelCommandDelivery, 0, len(commands))
+	for _, command := range commands {
+		payload := make(map[string]interface{}, len(command.Payload)+1)
+		for k, v := range command.Payload {
+			payload[k] = v
+		}
+
+		// 新 Token 只在下发时生成并随命令发送，命令记录和数据库中都不保存明文
+		// 采集端首次使用新 Token 时生效，下发丢失时原 Token 不受影响
+		if command.Type == model.SentinelCommandRotateToken {
+			token, err := generateAPIToken()
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate api token: %w", err)
+			}
+			overlap, _ := command.Payload["overlap_seconds"].(float64)
+			sentinel.PendingTokenHash = auth.HashToken(token)
+			sentinel.PendingTokenOverlap = int(overlap)
+			if err := s.sentinelRepo.UpdateFields(ctx, sentinel.ID, map[string]interface{}{
+				"pending_token_hash":    sentinel.PendingTokenHash,
+				"pending_token_overlap": sentinel.PendingTokenOverlap,
+			}); err != nil {
+				return nil, fmt.Errorf("failed to save pending api token: %w", err)
+			}
+			s.tokens.invalidate(sentinel.SentinelID)
+			payload["api_token"] = token
+		}
+
+		deliveries = append(deliveries, &SentinelCommandDelivery{
+			ID:        command.ID,
+			Type:      command.Type,
+			Payload:   payload,
+			ExpiresAt: command.ExpiresAt,
+		})
+	}
+	return deliveries, nil
+}
+
 func (s *sentinelService) Get(ctx context.Context, id uint) (*model.Sentinel, error) {
 	sentinel, err := s.sentinelRepo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("sentinel not found")
+			return nil, ErrSentinelNotFound
 		}
 		return nil, fmt.Errorf("failed to get sentinel: %w", err)
 	}
@@ -233,10 +538,11 @@ func (s *sentinelService) List(ctx context.Context, req *ListSentinelRequest) ([
 	}
 
 	filter := &repository.SentinelFilter{
-		Page:     req.Page,
-		PageSize: req.PageSize,
-		Status:   req.Status,
-		Region:   req.Region,
+		Page:           req.Page,
+		PageSize:       req.PageSize,
+		Status:         req.Status,
+		Region:         req.Region,
+		ApprovalStatus: req.ApprovalStatus,
 	}
 
 	return s.sentinelRepo.List(ctx, filter)
@@ -246,10 +552,104 @@ func (s *sentinelService) Delete(ctx context.Context, id uint) error {
 	return s.sentinelRepo.Delete(ctx, id)
 }
 
-func (s *sentinelService) Control(ctx context.Context, sentinelID string, action string) error {
-	// TODO: 实现远程控制逻辑
-	// 这里需要通过某种机制（如 WebSocket、消息队列）向 Sentinel 发送控制命令
-	return fmt.Errorf("control action not implemented: %s", action)
+func (s *sentinelService) Control(ctx context.Context, id uint, req *ControlSentinelRequest, userID uint) (*model.SentinelCommand, error) {
+	sentinel, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sentinel.RevokedAt != nil {
+		return nil, ErrSentinelRevoked
+	}
+
+	if err := validateSentinelCommand(req); err != nil {
+		return nil, err
+	}
+
+	timeout := defaultCommandTimeout
+	if req.TimeoutSeconds > 0 {
+		timeout = time.Duration(req.TimeoutSeconds) * time.Second
+	}
+
+	command := &model.SentinelCommand{
+		SentinelID: sentinel.SentinelID,
+		Type:       req.Action,
+		Payload:    req.Params,
+		Status:     model.SentinelCommandStatusPending,
+		ExpiresAt:  time.Now().Add(timeout),
+	}
+	if userID > 0 {
+		command.CreatedBy = &userID
+	}
+
+	if err := s.commandRepo.Create(ctx, command); err != nil {
+		return nil, fmt.Errorf("failed to create command: %w", err)
+	}
+	return command, nil
+}
+
+func (s *sentinelService) ListCommands(ctx context.Context, id uint, req *ListSentinelCommandRequest) ([]*model.SentinelCommand, int64, error) {
+	sentinel, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+
+	// 查询前先把超时的命令标记为过期，避免一直显示为待执行
+	if _, err := s.commandRepo.ExpireStale(ctx, sentinel.SentinelID, time.Now()); err != nil {
+		return nil, 0, fmt.Errorf("failed to expire commands: %w", err)
+	}
+
+	filter := &repository.SentinelCommandFilter{
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		SentinelID: sentinel.SentinelID,
+		Status:     req.Status,
+		Type:       req.Type,
+	}
+
+	return s.commandRepo.List(ctx, filter)
+}
+
+// validateSentinelCommand 校验控制命令类型和参数
+func validateSentinelCommand(req *ControlSentinelRequest) error {
+	switch req.Action {
+	case model.SentinelCommandRotateToken:
+		if overlap, ok := req.Params["overlap_seconds"]; ok {
+			seconds, isNumber := overlap.(float64)
+			if !isNumber {
+				return fmt.Errorf("%w: params.overlap_seconds must be a number", ErrInvalidSentinelCommand)
+			}
+			if _, err := tokenOverlap(int(seconds)); err != nil {
+				return err
+			}
+		}
+	case model.SentinelCommandReloadConfig,
+		model.SentinelCommandRestartScheduler,
+		model.SentinelCommandRefetchTasks,
+		model.SentinelCommandUploadDiagnostics,
+		model.SentinelCommandShutdown:
+	case model.SentinelCommandRunTask:
+		if taskID, _ := req.Params["task_id"].(string); taskID == "" {
+			return fmt.Errorf("%w: params.task_id is required for %s", ErrInvalidSentinelCommand, req.Action)
+		}
+	default:
+		return fmt.Errorf("%w: unknown action %q", ErrInvalidSentinelCommand, req.Action)
+	}
+
+	if req.TimeoutSeconds < 0 || time.Duration(req.TimeoutSeconds)*time.Second > maxCommandTimeout {
+		return fmt.Errorf("%w: timeout_seconds must be between 0 and %d", ErrInvalidSentinelCommand, int(maxCommandTimeout.Seconds()))
+	}
+	return nil
 }
 
 // generateAPIToken 生成 API Token
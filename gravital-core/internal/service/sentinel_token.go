@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/celestial/gravital-core/internal/model"
+	"github.com/celestial/gravital-core/internal/pkg/auth"
+)
+
+var (
+	// ErrSentinelUnauthorized Sentinel ID 或 API Token 无效
+	ErrSentinelUnauthorized = errors.New("invalid sentinel credentials")
+	// ErrSentinelRevoked Sentinel 已被吊销
+	ErrSentinelRevoked = errors.New("sentinel revoked")
+)
+
+const (
+	// defaultTokenOverlap 轮换后旧 Token 默认继续有效的时间
+	defaultTokenOverlap = 5 * time.Minute
+	// maxTokenOverlap 旧 Token 最长重叠时间
+	maxTokenOverlap = 7 * 24 * time.Hour
+	// tokenCacheTTL 凭证缓存时间，多副本部署时其他副本最多在这段时间后感知吊销和轮换
+	tokenCacheTTL = 10 * time.Second
+)
+
+// RotateSentinelTokenRequest 轮换 Token 请求
+type RotateSentinelTokenRequest struct {
+	OverlapSeconds int  `json:"overlap_seconds"` // 旧 Token 继续有效的秒数，默认 300
+	ReturnToken    bool `json:"return_token"`    // true 时立即生成并返回新 Token，由管理员手动配置到采集端
+}
+
+// RotateSentinelTokenResponse 轮换 Token 响应
+type RotateSentinelTokenResponse struct {
+	APIToken               string                 `json:"api_token,omitempty"`
+	PreviousTokenExpiresAt *time.Time             `json:"previous_token_expires_at,omitempty"`
+	Command                *model.SentinelCommand `json:"command,omitempty"`
+}
+
+// tokenCache Sentinel 凭证缓存，避免每个请求都查询数据库
+type tokenCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]tokenCacheEntry
+}
+
+type tokenCacheEntry struct {
+	sentinel  *model.Sentinel
+	expiresAt time.Time
+}
+
+func newTokenCache(ttl time.Duration) *tokenCache {
+	return &tokenCache{
+		ttl:     ttl,
+		entries: make(map[string]tokenCacheEntry),
+	}
+}
+
+func (c *tokenCache) get(sentinelID string, now time.Time) (*model.Sentinel, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[sentinelID]
+	if !ok || now.After(entry.expiresAt) {
+		delete(c.entries, sentinelID)
+		return nil, false
+	}
+	return entry.sentinel, true
+}
+
+func (c *tokenCache) set(sentinel *model.Sentinel, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[sentinel.SentinelID] = tokenCacheEntry{
+		sentinel:  sentinel,
+		expiresAt: now.Add(c.ttl),
+	}
+}
+
+func (c *tokenCache) invalidate(sentinelID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, sentinelID)
+}
+
+// Authenticate 校验 Sentinel 的 API Token
+// 当前 Token、重叠期内的旧 Token 以及已下发的新 Token 均可通过；首次使用新 Token 时使其生效
+func (s *sentinelService) Authenticate(ctx context.Context, sentinelID, token string) error {
+	now := time.Now()
+
+	sentinel, err := s.cachedSentinel(ctx, sentinelID, now)
+	if err != nil {
+		return err
+	}
+
+	if sentinel.RevokedAt != nil {
+		return ErrSentinelRevoked
+	}
+
+	if auth.CheckTokenHash(token, sentinel.APITokenHash) {
+		return nil
+	}
+	if sentinel.PreviousTokenExpiresAt != nil && now.Before(*sentinel.PreviousTokenExpiresAt) &&
+		auth.CheckTokenHash(token, sentinel.PreviousTokenHash) {
+		return nil
+	}
+	if auth.CheckTokenHash(token, sentinel.PendingTokenHash) {
+		return s.promotePendingToken(ctx, sentinel)
+	}
+
+	return ErrSentinelUnauthorized
+}
+
+// cachedSentinel 优先从凭证缓存获取 Sentinel，不存在时返回 ErrSentinelUnauthorized
+func (s *sentinelService) cachedSentinel(ctx context.Context, sentinelID string, now time.Time) (*model.Sentinel, error) {
+	if sentinel, ok := s.tokens.get(sentinelID, now); ok {
+		return sentinel, nil
+	}
+
+	sentinel, err := s.sentinelRepo.GetBySentinelID(ctx, sentinelID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSentinelUnauthorized
+		}
+		return nil, fmt.Errorf("failed to get sentinel: %w", err)
+	}
+	s.tokens.set(sentinel, now)
+	return sentinel, nil
+}
+
+// promotePendingToken 启用已下发的新 Token
+func (s *sentinelService) promotePendingToken(ctx context.Context, sentinel *model.Sentinel) error {
+	if sentinel.PendingTokenHash == "" {
+		return nil
+	}
+
+	now := time.Now()
+	overlap := time.Duration(sentinel.PendingTokenOverlap) * time.Second
+	if overlap <= 0 {
+		overlap = defaultTokenOverlap
+	}
+
+	if _, err := s.sentinelRepo.PromotePendingToken(ctx, sentinel.ID, sentinel.PendingTokenHash, now.Add(overlap), now); err != nil {
+		return fmt.Errorf("failed to promote api token: %w", err)
+	}
+	s.tokens.invalidate(sentinel.SentinelID)
+	return nil
+}
+
+// RotateToken 轮换 Sentinel 的 API Token
+// 默认生成轮换命令，由采集端在心跳中领取新 Token；ReturnToken 为 true 时立即生效并返回新 Token
+func (s *sentinelService) RotateToken(ctx context.Context, id uint, req *RotateSentinelTokenRequest, userID uint) (*RotateSentinelTokenResponse, error) {
+	overlap, err := tokenOverlap(req.OverlapSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.ReturnToken {
+		command, err := s.Control(ctx, id, &ControlSentinelRequest{
+			Action: model.SentinelCommandRotateToken,
+			Params: map[string]interface{}{"overlap_seconds": overlap.Seconds()},
+		}, userID)
+		if err != nil {
+			return nil, err
+		}
+		return &RotateSentinelTokenResponse{Command: command}, nil
+	}
+
+	sentinel, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := generateAPIToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate api token: %w", err)
+	}
+
+	// 手动轮换同时解除吊销，并清除已签发证书的记录，使采集端可以只凭新 Token 重新申请证书
+	now := time.Now()
+	previousExpiresAt := now.Add(overlap)
+	if err := s.sentinelRepo.UpdateFields(ctx, sentinel.ID, map[string]interface{}{
+		"api_token_hash":            auth.HashToken(token),
+		"previous_token_hash":       sentinel.APITokenHash,
+		"previous_token_expires_at": previousExpiresAt,
+		"pending_token_hash":        "",
+		"pending_token_overlap":     0,
+		"token_rotated_at":          now,
+		"revoked_at":                nil,
+		"cert_serial":               "",
+		"cert_expires_at":           nil,
+		"updated_at":                now,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to rotate api token: %w", err)
+	}
+	s.tokens.invalidate(sentinel.SentinelID)
+
+	return &RotateSentinelTokenResponse{
+		APIToken:               token,
+		PreviousTokenExpiresAt: &previousExpiresAt,
+	}, nil
+}
+
+// Revoke 吊销 Sentinel，所有 Token 立即失效
+func (s *sentinelService) Revoke(ctx context.Context, id uint) error {
+	sentinel, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := s.sentinelRepo.UpdateFields(ctx, sentinel.ID, map[string]interface{}{
+		"api_token_hash":            "",
+		"previous_token_hash":       "",
+		"previous_token_expires_at": nil,
+		"pending_token_hash":        "",
+		"pending_token_overlap":     0,
+		"revoked_at":                now,
+		"status":                    "revoked",
+		"updated_at":                now,
+	}); err != nil {
+		return fmt.Errorf("failed to revoke sentinel: %w", err)
+	}
+	s.tokens.invalidate(sentinel.SentinelID)
+	return nil
+}
+
+// tokenOverlap 解析旧 Token 重叠时间
+func tokenOverlap(seconds int) (time.Duration, error) {
+	if seconds == 0 {
+		return defaultTokenOverlap, nil
+	}
+	overlap := time.Duration(seconds) * time.Second
+	if seconds < 0 || overlap > maxTokenOverlap {
+		return 0, fmt.Errorf("%w: overlap_seconds must be between 0 and %d", ErrInvalidSentinelCommand, int(maxTokenOverlap.Seconds()))
+	}
+	return overlap, nil
+}
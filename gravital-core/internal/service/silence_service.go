@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/celestial/gravital-core/internal/alert/silence"
+	"github.com/celestial/gravital-core/internal/model"
+	"github.com/celestial/gravital-core/internal/repository"
+)
+
+// SilenceService 告警静默服务接口
+type SilenceService interface {
+	CreateSilence(ctx context.Context, req *CreateSilenceRequest, userID uint, username string) (*model.AlertSilence, error)
+	GetSilence(ctx context.Context, id uint) (*model.AlertSilence, error)
+	UpdateSilence(ctx context.Context, id uint, req *UpdateSilenceRequest) error
+	ExpireSilence(ctx context.Context, id uint) error
+	ListSilences(ctx context.Context, req *ListSilenceRequest) ([]*model.AlertSilence, int64, error)
+}
+
+// CreateSilenceRequest 创建静默请求
+type CreateSilenceRequest struct {
+	Matchers []model.SilenceMatcher `json:"matchers" binding:"required,min=1,dive"`
+	StartsAt *time.Time             `json:"starts_at"`
+	EndsAt   time.Time              `json:"ends_at" binding:"required"`
+	Comment  string                 `json:"comment" binding:"required"`
+}
+
+// UpdateSilenceRequest 更新静默请求
+type UpdateSilenceRequest struct {
+	Matchers []model.SilenceMatcher `json:"matchers" binding:"omitempty,dive"`
+	StartsAt *time.Time             `json:"starts_at"`
+	EndsAt   *time.Time             `json:"ends_at"`
+	Comment  string                 `json:"comment"`
+}
+
+// ListSilenceRequest 静默列表请求
+type ListSilenceRequest struct {
+	Page     int    `form:"page"`
+	PageSize int    `form:"page_size"`
+	State    string `form:"state"`
+}
+
+type silenceService struct {
+	silenceRepo repository.SilenceRepository
+}
+
+// NewSilenceService 创建静默服务
+func NewSilenceService(silenceRepo repository.SilenceRepository) SilenceService {
+	return &silenceService{
+		silenceRepo: silenceRepo,
+	}
+}
+
+func (s *silenceService) CreateSilence(ctx context.Context, req *CreateSilenceRequest, userID uint, username string) (*model.AlertSilence, error) {
+	startsAt := time.Now()
+	if req.StartsAt != nil {
+		startsAt = *req.StartsAt
+	}
+
+	item := &model.AlertSilence{
+		Matchers:  req.Matchers,
+		StartsAt:  startsAt,
+		EndsAt:    req.EndsAt,
+		CreatedBy: &userID,
+		Creator:   username,
+		Comment:   req.Comment,
+	}
+	if err := silence.Validate(item); err != nil {
+		return nil, err
+	}
+
+	if err := s.silenceRepo.Create(ctx, item); err != nil {
+		return nil, fmt.Errorf("failed to create silence: %w", err)
+	}
+
+	item.State = item.StateAt(time.Now())
+	return item, nil
+}
+
+func (s *silenceService) GetSilence(ctx context.Context, id uint) (*model.AlertSilence, error) {
+	item, err := s.silenceRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("silence not found")
+		}
+		return nil, fmt.Errorf("failed to get silence: %w", err)
+	}
+
+	item.State = item.StateAt(time.Now())
+	return item, nil
+}
+
+func (s *silenceService) UpdateSilence(ctx context.Context, id uint, req *UpdateSilenceRequest) error {
+	item, err := s.silenceRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("silence not found")
+	}
+
+	if item.StateAt(time.Now()) == model.SilenceStateExpired {
+		return fmt.Errorf("%w: silence already expired", silence.ErrInvalidSilence)
+	}
+
+	// 更新字段
+	if len(req.Matchers) > 0 {
+		item.Matchers = req.Matchers
+	}
+	if req.StartsAt != nil {
+		item.StartsAt = *req.StartsAt
+	}
+	if req.EndsAt != nil {
+		item.EndsAt = *req.EndsAt
+	}
+	if req.Comment != "" {
+		item.Comment = req.Comment
+	}
+
+	if err := silence.Validate(item); err != nil {
+		return err
+	}
+
+	return s.silenceRepo.Update(ctx, item)
+}
+
+// ExpireSilence 立即结束静默，保留记录用于审计
+func (s *silenceService) ExpireSilence(ctx context.Context, id uint) error {
+	item, err := s.silenceRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("silence not found")
+	}
+
+	now := time.Now()
+	if item.StateAt(now) == model.SilenceStateExpired {
+		return nil
+	}
+	if item.StartsAt.After(now) {
+		item.StartsAt = now
+	}
+	item.EndsAt = now
+
+	return s.silenceRepo.Update(ctx, item)
+}
+
+func (s *silenceService) ListSilences(ctx context.Context, req *ListSilenceRequest) ([]*model.AlertSilence, int64, error) {
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+
+	now := time.Now()
+	filter := &repository.SilenceFilter{
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		State:    req.State,
+		Now:      now,
+	}
+
+	items, total, err := s.silenceRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, item := range items {
+		item.State = item.StateAt(now)
+	}
+	return items, total, nil
+}
@@ -192,6 +192,15 @@ func (s *taskService) List(ctx context.Context, req *ListTaskRequest) ([]*model.
 }
 
 func (s *taskService) GetSentinelTasks(ctx context.Context, sentinelID string) ([]*model.CollectionTask, error) {
+	// 未审批通过的 Sentinel 不下发任务
+	sentinel, err := s.sentinelRepo.GetBySentinelID(ctx, sentinelID)
+	if err != nil {
+		return nil, err
+	}
+	if sentinel.ApprovalStatus != "" && sentinel.ApprovalStatus != model.SentinelApprovalApproved {
+		return []*model.CollectionTask{}, nil
+	}
+
 	tasks, err := s.taskRepo.GetBySentinelID(ctx, sentinelID)
 	if err != nil {
 		return nil, err
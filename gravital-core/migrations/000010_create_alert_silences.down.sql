@@ -0,0 +1,2 @@
+-- 删除告警静默表
+DROP TABLE IF EXISTS alert_silences;
@@ -0,0 +1,15 @@
+-- 创建告警静默表
+CREATE TABLE IF NOT EXISTS alert_silences (
+    id BIGSERIAL PRIMARY KEY,
+    matchers JSONB NOT NULL,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    created_by BIGINT,
+    creator VARCHAR(64),
+    comment TEXT,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_alert_silences_starts_at ON alert_silences(starts_at);
+CREATE INDEX idx_alert_silences_ends_at ON alert_silences(ends_at);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_alert_events_fingerprint;
+DROP INDEX IF EXISTS idx_alert_rules_source;
+
+ALTER TABLE alert_events DROP COLUMN IF EXISTS fingerprint;
+ALTER TABLE alert_events DROP COLUMN IF EXISTS source;
+ALTER TABLE alert_rules DROP COLUMN IF EXISTS source;
@@ -0,0 +1,9 @@
+-- 告警来源：engine 为内置引擎，alertmanager 为 webhook 接入
+ALTER TABLE alert_rules ADD COLUMN IF NOT EXISTS source VARCHAR(32) NOT NULL DEFAULT 'engine';
+ALTER TABLE alert_events ADD COLUMN IF NOT EXISTS source VARCHAR(32) NOT NULL DEFAULT 'engine';
+
+-- 外部告警指纹，用于去重
+ALTER TABLE alert_events ADD COLUMN IF NOT EXISTS fingerprint VARCHAR(64);
+
+CREATE INDEX IF NOT EXISTS idx_alert_rules_source ON alert_rules(source);
+CREATE INDEX IF NOT EXISTS idx_alert_events_fingerprint ON alert_events(fingerprint);
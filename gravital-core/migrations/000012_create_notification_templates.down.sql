@@ -0,0 +1,2 @@
+-- 删除通知模板表
+DROP TABLE IF EXISTS notification_templates;
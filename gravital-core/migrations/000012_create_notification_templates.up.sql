@@ -0,0 +1,14 @@
+-- 创建通知模板表
+CREATE TABLE IF NOT EXISTS notification_templates (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(128) NOT NULL,
+    channel VARCHAR(32) NOT NULL DEFAULT '',
+    subject TEXT,
+    content TEXT NOT NULL,
+    description TEXT,
+    created_by BIGINT,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_notification_templates_name_channel ON notification_templates(name, channel);
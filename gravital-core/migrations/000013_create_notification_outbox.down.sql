@@ -0,0 +1,2 @@
+-- 删除通知发送队列表
+DROP TABLE IF EXISTS notification_outbox;
@@ -0,0 +1,27 @@
+-- 创建通知发送队列表
+CREATE TABLE IF NOT EXISTS notification_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    notification_id VARCHAR(255),
+    alert_event_id BIGINT,
+    alert_id VARCHAR(255),
+    alert_rule_id BIGINT,
+    channel VARCHAR(32) NOT NULL,
+    recipient VARCHAR(255),
+    subject TEXT,
+    content TEXT,
+    templated BOOLEAN DEFAULT FALSE,
+    priority VARCHAR(16),
+    metadata JSONB,
+    status VARCHAR(32) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    sent_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_notification_outbox_status_next ON notification_outbox(status, next_attempt_at);
+CREATE INDEX idx_notification_outbox_alert_event_id ON notification_outbox(alert_event_id);
+CREATE INDEX idx_notification_outbox_notification_id ON notification_outbox(notification_id);
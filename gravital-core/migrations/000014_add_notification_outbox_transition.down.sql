@@ -0,0 +1,4 @@
+-- 删除通知发送队列生命周期和会话字段
+DROP INDEX IF EXISTS idx_notification_outbox_event_transition;
+ALTER TABLE notification_outbox DROP COLUMN IF EXISTS thread_id;
+ALTER TABLE notification_outbox DROP COLUMN IF EXISTS transition;
@@ -0,0 +1,5 @@
+-- 通知发送队列增加生命周期和会话字段
+ALTER TABLE notification_outbox ADD COLUMN IF NOT EXISTS transition VARCHAR(32) NOT NULL DEFAULT 'firing';
+ALTER TABLE notification_outbox ADD COLUMN IF NOT EXISTS thread_id VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS idx_notification_outbox_event_transition ON notification_outbox(alert_event_id, transition);
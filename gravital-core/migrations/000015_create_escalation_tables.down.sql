@@ -0,0 +1,7 @@
+-- 删除告警升级相关表
+DROP INDEX IF EXISTS idx_alert_rules_escalation_policy;
+ALTER TABLE alert_rules DROP COLUMN IF EXISTS escalation_policy_id;
+DROP TABLE IF EXISTS alert_escalations;
+DROP TABLE IF EXISTS oncall_overrides;
+DROP TABLE IF EXISTS oncall_schedules;
+DROP TABLE IF EXISTS escalation_policies;
@@ -0,0 +1,58 @@
+-- 创建告警升级策略表
+CREATE TABLE IF NOT EXISTS escalation_policies (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(128) NOT NULL UNIQUE,
+    description TEXT,
+    levels JSONB NOT NULL DEFAULT '[]',
+    repeat_times INT NOT NULL DEFAULT 0,
+    created_by BIGINT,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+-- 创建值班表
+CREATE TABLE IF NOT EXISTS oncall_schedules (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(128) NOT NULL UNIQUE,
+    description TEXT,
+    timezone VARCHAR(64),
+    layers JSONB NOT NULL DEFAULT '[]',
+    created_by BIGINT,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+-- 创建值班覆盖表
+CREATE TABLE IF NOT EXISTS oncall_overrides (
+    id BIGSERIAL PRIMARY KEY,
+    schedule_id BIGINT NOT NULL REFERENCES oncall_schedules(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    comment TEXT,
+    created_by BIGINT,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_oncall_overrides_schedule ON oncall_overrides(schedule_id, starts_at, ends_at);
+
+-- 创建告警升级进度表
+CREATE TABLE IF NOT EXISTS alert_escalations (
+    id BIGSERIAL PRIMARY KEY,
+    alert_event_id BIGINT NOT NULL UNIQUE REFERENCES alert_events(id) ON DELETE CASCADE,
+    policy_id BIGINT NOT NULL,
+    level INT NOT NULL DEFAULT 0,
+    repeats INT NOT NULL DEFAULT 0,
+    status VARCHAR(32) NOT NULL DEFAULT 'active',
+    next_at TIMESTAMP,
+    last_notified_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_alert_escalations_status_next ON alert_escalations(status, next_at);
+
+-- 告警规则关联升级策略
+ALTER TABLE alert_rules ADD COLUMN IF NOT EXISTS escalation_policy_id BIGINT REFERENCES escalation_policies(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_alert_rules_escalation_policy ON alert_rules(escalation_policy_id);
@@ -0,0 +1,3 @@
+-- 删除 Sentinel 控制命令表
+ALTER TABLE sentinels DROP COLUMN IF EXISTS pending_api_token;
+DROP TABLE IF EXISTS sentinel_commands;
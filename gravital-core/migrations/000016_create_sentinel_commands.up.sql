@@ -0,0 +1,24 @@
+-- 创建 Sentinel 控制命令表
+CREATE TABLE IF NOT EXISTS sentinel_commands (
+    id BIGSERIAL PRIMARY KEY,
+    sentinel_id VARCHAR(64) NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    payload JSONB,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    result JSONB,
+    error TEXT,
+    expires_at TIMESTAMP NOT NULL,
+    delivered_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    created_by BIGINT,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_sentinel_commands_sentinel ON sentinel_commands(sentinel_id, created_at);
+CREATE INDEX idx_sentinel_commands_status ON sentinel_commands(status);
+
+COMMENT ON TABLE sentinel_commands IS 'Sentinel 控制命令队列';
+
+-- 轮换中的新 Token
+ALTER TABLE sentinels ADD COLUMN IF NOT EXISTS pending_api_token VARCHAR(255);
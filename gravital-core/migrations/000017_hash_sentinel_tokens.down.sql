@@ -0,0 +1,11 @@
+-- 恢复明文 Token 列（哈希无法还原，回滚后 Sentinel 需要重新注册）
+ALTER TABLE sentinels ADD COLUMN IF NOT EXISTS api_token VARCHAR(255);
+ALTER TABLE sentinels ADD COLUMN IF NOT EXISTS pending_api_token VARCHAR(255);
+
+ALTER TABLE sentinels DROP COLUMN IF EXISTS revoked_at;
+ALTER TABLE sentinels DROP COLUMN IF EXISTS token_rotated_at;
+ALTER TABLE sentinels DROP COLUMN IF EXISTS pending_token_overlap;
+ALTER TABLE sentinels DROP COLUMN IF EXISTS pending_token_hash;
+ALTER TABLE sentinels DROP COLUMN IF EXISTS previous_token_expires_at;
+ALTER TABLE sentinels DROP COLUMN IF EXISTS previous_token_hash;
+ALTER TABLE sentinels DROP COLUMN IF EXISTS api_token_hash;
@@ -0,0 +1,16 @@
+-- Sentinel API Token 改为保存哈希，并支持轮换和吊销
+ALTER TABLE sentinels ADD COLUMN IF NOT EXISTS api_token_hash VARCHAR(64);
+ALTER TABLE sentinels ADD COLUMN IF NOT EXISTS previous_token_hash VARCHAR(64);
+ALTER TABLE sentinels ADD COLUMN IF NOT EXISTS previous_token_expires_at TIMESTAMP;
+ALTER TABLE sentinels ADD COLUMN IF NOT EXISTS pending_token_hash VARCHAR(64);
+ALTER TABLE sentinels ADD COLUMN IF NOT EXISTS pending_token_overlap INT NOT NULL DEFAULT 0;
+ALTER TABLE sentinels ADD COLUMN IF NOT EXISTS token_rotated_at TIMESTAMP;
+ALTER TABLE sentinels ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMP;
+
+-- 已有 Token 转换为 SHA-256 哈希后删除明文列
+UPDATE sentinels
+SET api_token_hash = encode(sha256(convert_to(api_token, 'UTF8')), 'hex')
+WHERE api_token IS NOT NULL AND api_token <> '';
+
+ALTER TABLE sentinels DROP COLUMN IF EXISTS api_token;
+ALTER TABLE sentinels DROP COLUMN IF EXISTS pending_api_token;
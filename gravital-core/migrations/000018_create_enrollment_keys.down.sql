@@ -0,0 +1,9 @@
+-- 删除注册密钥和审批相关字段
+DROP INDEX IF EXISTS idx_sentinels_approval_status;
+ALTER TABLE sentinels DROP COLUMN IF EXISTS enrollment_key_id;
+ALTER TABLE sentinels DROP COLUMN IF EXISTS approved_at;
+ALTER TABLE sentinels DROP COLUMN IF EXISTS approved_by;
+ALTER TABLE sentinels DROP COLUMN IF EXISTS approval_status;
+
+DROP TABLE IF EXISTS sentinel_enrollments;
+DROP TABLE IF EXISTS enrollment_keys;
@@ -0,0 +1,49 @@
+-- 创建注册密钥表
+CREATE TABLE IF NOT EXISTS enrollment_keys (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(128) NOT NULL UNIQUE,
+    description TEXT,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    key_prefix VARCHAR(16),
+    region VARCHAR(64),
+    labels JSONB,
+    require_approval BOOLEAN NOT NULL DEFAULT FALSE,
+    max_uses INT NOT NULL DEFAULT 0,
+    used_count INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    created_by BIGINT,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+COMMENT ON TABLE enrollment_keys IS 'Sentinel 注册密钥';
+
+-- 创建注册审计表
+CREATE TABLE IF NOT EXISTS sentinel_enrollments (
+    id BIGSERIAL PRIMARY KEY,
+    sentinel_id VARCHAR(64),
+    enrollment_key_id BIGINT,
+    key_name VARCHAR(128),
+    hostname VARCHAR(255),
+    ip_address VARCHAR(64),
+    result VARCHAR(16) NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_sentinel_enrollments_sentinel ON sentinel_enrollments(sentinel_id);
+CREATE INDEX idx_sentinel_enrollments_key ON sentinel_enrollments(enrollment_key_id);
+CREATE INDEX idx_sentinel_enrollments_result ON sentinel_enrollments(result);
+CREATE INDEX idx_sentinel_enrollments_created ON sentinel_enrollments(created_at);
+
+COMMENT ON TABLE sentinel_enrollments IS 'Sentinel 注册审计记录';
+
+-- Sentinel 审批状态，已有 Sentinel 视为已审批
+ALTER TABLE sentinels ADD COLUMN IF NOT EXISTS approval_status VARCHAR(16) NOT NULL DEFAULT 'approved';
+ALTER TABLE sentinels ADD COLUMN IF NOT EXISTS approved_by BIGINT;
+ALTER TABLE sentinels ADD COLUMN IF NOT EXISTS approved_at TIMESTAMP;
+ALTER TABLE sentinels ADD COLUMN IF NOT EXISTS enrollment_key_id BIGINT;
+
+CREATE INDEX IF NOT EXISTS idx_sentinels_approval_status ON sentinels(approval_status);
@@ -0,0 +1,5 @@
+-- 删除 Sentinel 客户端证书字段
+ALTER TABLE sentinels DROP COLUMN IF EXISTS previous_cert_expires_at;
+ALTER TABLE sentinels DROP COLUMN IF EXISTS previous_cert_serial;
+ALTER TABLE sentinels DROP COLUMN IF EXISTS cert_expires_at;
+ALTER TABLE sentinels DROP COLUMN IF EXISTS cert_serial;
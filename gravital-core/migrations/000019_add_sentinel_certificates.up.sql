@@ -0,0 +1,5 @@
+-- 记录最近为 Sentinel 签发的客户端证书，续期后的旧证书在重叠期内仍然有效
+ALTER TABLE sentinels ADD COLUMN IF NOT EXISTS cert_serial VARCHAR(64);
+ALTER TABLE sentinels ADD COLUMN IF NOT EXISTS cert_expires_at TIMESTAMP;
+ALTER TABLE sentinels ADD COLUMN IF NOT EXISTS previous_cert_serial VARCHAR(64);
+ALTER TABLE sentinels ADD COLUMN IF NOT EXISTS previous_cert_expires_at TIMESTAMP;
@@ -0,0 +1,2 @@
+-- 删除 Sentinel 插件版本字段
+ALTER TABLE sentinels DROP COLUMN IF EXISTS plugins;
@@ -0,0 +1,2 @@
+-- 记录 Sentinel 已加载的插件及版本（名称 -> 版本）
+ALTER TABLE sentinels ADD COLUMN IF NOT EXISTS plugins JSONB;
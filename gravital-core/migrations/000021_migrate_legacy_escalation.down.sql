@@ -0,0 +1,3 @@
+-- 删除由旧版升级配置生成的策略，规则的 escalation_policy_id 随外键置空
+-- 规则 notification_config 中的旧字段未被修改，无需恢复
+DELETE FROM escalation_policies WHERE name LIKE 'legacy-rule-%';
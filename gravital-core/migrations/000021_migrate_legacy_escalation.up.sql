@@ -0,0 +1,41 @@
+-- 旧版规则内联升级配置（notification_config 中的 escalation_enabled/escalation_after/escalation_channels）转换为升级策略
+-- 每条启用升级且未关联策略的规则生成一个单级策略：告警触发 escalation_after 秒（默认 1800）后通知 escalation_channels，
+-- 接收人取规则通知配置中同一渠道的接收人
+INSERT INTO escalation_policies (name, description, levels, repeat_times)
+SELECT
+    'legacy-rule-' || r.id,
+    '由规则「' || r.rule_name || '」的旧版升级配置迁移',
+    jsonb_build_array(jsonb_build_object(
+        'delay_minutes', CASE
+            WHEN jsonb_typeof(r.notification_config->'escalation_after') = 'number'
+                THEN CEIL((r.notification_config->>'escalation_after')::numeric / 60)::int
+            ELSE 30
+        END,
+        'targets', (
+            SELECT COALESCE(jsonb_agg(jsonb_build_object(
+                'type', 'channel',
+                'channel', ch.value #>> '{}',
+                'recipients', COALESCE((
+                    SELECT c->'recipients'
+                    FROM jsonb_array_elements(
+                        CASE WHEN jsonb_typeof(r.notification_config->'channels') = 'array'
+                            THEN r.notification_config->'channels' ELSE '[]'::jsonb END) c
+                    WHERE c->>'channel' = ch.value #>> '{}'
+                    LIMIT 1), '[]'::jsonb)
+            )), '[]'::jsonb)
+            FROM jsonb_array_elements(r.notification_config->'escalation_channels') ch
+        )
+    )),
+    0
+FROM alert_rules r
+WHERE r.escalation_policy_id IS NULL
+  AND r.notification_config->'escalation_enabled' = 'true'::jsonb
+  AND jsonb_typeof(r.notification_config->'escalation_channels') = 'array'
+  AND jsonb_array_length(r.notification_config->'escalation_channels') > 0
+ON CONFLICT (name) DO NOTHING;
+
+UPDATE alert_rules r
+SET escalation_policy_id = p.id
+FROM escalation_policies p
+WHERE p.name = 'legacy-rule-' || r.id
+  AND r.escalation_policy_id IS NULL;
@@ -12,6 +12,12 @@ CREATE TABLE IF NOT EXISTS metrics.data (
     device_id String,
     sentinel_id String,
     labels Map(String, String),
+    metric_sum Float64,
+    metric_count UInt64,
+    bucket_bounds Array(Float64),
+    bucket_counts Array(UInt64),
+    quantiles Array(Float64),
+    quantile_values Array(Float64),
     date Date DEFAULT toDate(timestamp)
 ) ENGINE = MergeTree()
 PARTITION BY toYYYYMM(date)
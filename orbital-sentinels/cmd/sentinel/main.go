@@ -77,10 +77,11 @@ func runStart(cmd *cobra.Command, args []string) {
 
 	// 创建并启动 Agent
 	ag := agent.NewAgent(cfg)
+	ag.SetConfigPath(configFile)
 	if err := ag.Start(); err != nil {
 		logger.Fatal("Failed to start agent", zap.Error(err))
 	}
 
-	// 阻塞等待
-	select {}
+	// 阻塞等待 Agent 停止（收到信号或远程停止命令）
+	<-ag.Done()
 }
@@ -7,13 +7,16 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/celestial/orbital-sentinels/internal/buffer"
+	"github.com/celestial/orbital-sentinels/internal/certs"
 	"github.com/celestial/orbital-sentinels/internal/client"
 	"github.com/celestial/orbital-sentinels/internal/credentials"
 	"github.com/celestial/orbital-sentinels/internal/heartbeat"
+	"github.com/celestial/orbital-sentinels/internal/listener"
 	"github.com/celestial/orbital-sentinels/internal/pkg/config"
 	"github.com/celestial/orbital-sentinels/internal/pkg/logger"
 	"github.com/celestial/orbital-sentinels/internal/plugin"
@@ -22,6 +25,10 @@ import (
 	"github.com/celestial/orbital-sentinels/internal/sender"
 	ping "github.com/celestial/orbital-sentinels/plugins/ping"
 	lldp "github.com/celestial/orbital-sentinels/plugins/lldp"
+	snmp "github.com/celestial/orbital-sentinels/plugins/snmp"
+	httpprobe "github.com/celestial/orbital-sentinels/plugins/http"
+	execplugin "github.com/celestial/orbital-sentinels/plugins/exec"
+	promscrape "github.com/celestial/orbital-sentinels/plugins/promscrape"
 	"go.uber.org/zap"
 )
 
@@ -40,30 +47,59 @@ const (
 // Agent 主控制器
 type Agent struct {
 	config       *config.Config
+	configPath   string
 	state        State
 	pluginMgr    *plugin.Manager
 	scheduler    *scheduler.Scheduler
 	buffer       buffer.Buffer
 	sender       *sender.Sender
 	heartbeatMgr *heartbeat.Manager
+	taskClient   *client.TaskClient
+	credsMgr     *credentials.Manager
+	listeners    *listener.Manager // 设备事件接收，未启用时为 nil
+	certMgr      *certs.Manager  // 启用 mTLS 时管理客户端证书
+	transport    *http.Transport // 连接中心端的 Transport
+	tokenMu      sync.RWMutex    // 保护 config.Core.APIToken
+	localTaskIDs []string // 从配置文件加载的任务，重新加载配置时替换
+	startTime    time.Time
 	ctx          context.Context
 	cancel       context.CancelFunc
+	stopOnce     sync.Once
+	done         chan struct{}
 }
 
+// defaultPluginReloadInterval 未配置 plugins.reload_interval 时的插件目录检查间隔
+const defaultPluginReloadInterval = 5 * time.Minute
+
 // NewAgent 创建 Agent
 func NewAgent(cfg *config.Config) *Agent {
 	return &Agent{
 		config: cfg,
 		state:  StateInitializing,
+		done:   make(chan struct{}),
 	}
 }
 
+// SetConfigPath 设置配置文件路径（用于远程重新加载配置）
+func (a *Agent) SetConfigPath(path string) {
+	a.configPath = path
+}
+
+// Done 返回 Agent 停止后关闭的通道
+func (a *Agent) Done() <-chan struct{} {
+	return a.done
+}
+
 // Start 启动 Agent
 func (a *Agent) Start() error {
 	a.ctx, a.cancel = context.WithCancel(context.Background())
+	a.startTime = time.Now()
 
 	// 1. 处理注册和凭证
 	a.setState(StateRegistering)
+	if err := a.setupTLS(); err != nil {
+		return fmt.Errorf("failed to setup tls: %w", err)
+	}
 	if err := a.handleRegistration(); err != nil {
 		logger.Warn("Failed to handle registration, will try to continue", zap.Error(err))
 	}
@@ -84,16 +120,25 @@ func (a *Agent) Start() error {
 	return nil
 }
 
-// Stop 停止 Agent
+// Stop 停止 Agent，可以重复调用
 func (a *Agent) Stop() error {
+	a.stopOnce.Do(a.stop)
+	return nil
+}
+
+// stop 依次停止各组件
+func (a *Agent) stop() {
 	a.setState(StateStopping)
 
 	logger.Info("Stopping agent...")
 
-	// 1. 停止心跳
+	// 1. 停止心跳和事件接收
 	if a.heartbeatMgr != nil {
 		a.heartbeatMgr.Stop()
 	}
+	if a.listeners != nil {
+		a.listeners.Stop()
+	}
 
 	// 2. 停止调度器
 	if a.scheduler != nil {
@@ -123,7 +168,7 @@ func (a *Agent) Stop() error {
 	a.setState(StateStopped)
 	logger.Info("Agent stopped")
 
-	return nil
+	close(a.done)
 }
 
 // initialize 初始化
@@ -144,8 +189,17 @@ func (a *Agent) initialize() error {
 	case "memory":
 		a.buffer = buffer.NewMemoryBuffer(a.config.Buffer.Size)
 	case "disk":
-		// TODO: 实现磁盘缓冲
-		return fmt.Errorf("disk buffer not implemented yet")
+		diskBuffer, err := buffer.NewDiskBuffer(buffer.DiskConfig{
+			Path:         a.config.Buffer.DiskPath,
+			MaxBytes:     a.config.Buffer.MaxBytes,
+			SegmentBytes: a.config.Buffer.SegmentBytes,
+			SyncMode:     a.config.Buffer.SyncMode,
+			SyncInterval: a.config.Buffer.SyncInterval,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create disk buffer: %w", err)
+		}
+		a.buffer = diskBuffer
 	default:
 		a.buffer = buffer.NewMemoryBuffer(a.config.Buffer.Size)
 	}
@@ -184,6 +238,7 @@ func (a *Agent) initialize() error {
 			a.config.Core.APIToken,
 			a.config.Sender.Timeout,
 		)
+		coreSender.SetTransport(a.transport)
 		a.sender.SetCoreSender(coreSender)
 	}
 
@@ -276,13 +331,14 @@ func (a *Agent) initialize() error {
 
 	// 如果配置了中心端 URL 和 Token，创建任务客户端用于从中心端获取任务
 	if a.config.Core.URL != "" && a.config.Core.APIToken != "" {
-		taskClient := client.NewTaskClient(
+		a.taskClient = client.NewTaskClient(
 			a.config.Core.URL,
 			a.config.Core.APIToken,
 			a.config.Sentinel.ID,
 			a.config.Sender.Timeout,
 		)
-		a.scheduler.SetTaskClient(taskClient)
+		a.taskClient.SetTransport(a.transport)
+		a.scheduler.SetTaskClient(a.taskClient)
 		logger.Info("Task client configured for fetching tasks from core",
 			zap.String("core_url", a.config.Core.URL),
 			zap.Duration("fetch_interval", a.config.Collector.TaskFetchInterval))
@@ -304,6 +360,14 @@ func (a *Agent) initialize() error {
 		a.config.Heartbeat.Timeout,
 		a.config.Heartbeat.RetryTimes,
 	)
+	a.heartbeatMgr.SetTransport(a.transport)
+	a.heartbeatMgr.SetCommandHandler(a.handleCommand)
+	// 插件热加载后立即上报新版本
+	a.heartbeatMgr.SetPluginVersionsProvider(a.pluginMgr.Versions)
+	a.pluginMgr.SetChangeHandler(a.heartbeatMgr.Trigger)
+
+	// 6. 创建设备事件接收管理器
+	a.setupListeners()
 
 	logger.Info("Agent initialized",
 		zap.String("sentinel_id", sentinelID),
@@ -329,6 +393,23 @@ func (a *Agent) startComponents() {
 	// 启动心跳
 	a.heartbeatMgr.Start(a.ctx)
 
+	// 启动设备事件接收
+	a.startListeners()
+
+	// 插件热加载
+	if a.config.Plugins.AutoReload {
+		interval := a.config.Plugins.ReloadInterval
+		if interval <= 0 {
+			interval = defaultPluginReloadInterval
+		}
+		go a.pluginMgr.Watch(a.ctx, interval)
+	}
+
+	// 启用 mTLS 时定期续期客户端证书
+	if a.certMgr != nil && a.config.Core.URL != "" {
+		go a.certRenewLoop()
+	}
+
 	logger.Info("All components started")
 }
 
@@ -357,6 +438,54 @@ func (a *Agent) registerBuiltinPlugins() {
 			logger.Info("Registered builtin plugin", zap.String("name", "lldp"))
 		}
 	}
+
+	// 注册 SNMP 插件
+	snmpPlugin := snmp.NewPlugin()
+	if err := snmpPlugin.Init(nil); err != nil {
+		logger.Error("Failed to initialize snmp plugin", zap.Error(err))
+	} else {
+		if err := a.pluginMgr.RegisterPlugin(snmpPlugin); err != nil {
+			logger.Error("Failed to register snmp plugin", zap.Error(err))
+		} else {
+			logger.Info("Registered builtin plugin", zap.String("name", "snmp"))
+		}
+	}
+
+	// 注册 HTTP 拨测插件
+	httpPlugin := httpprobe.NewPlugin()
+	if err := httpPlugin.Init(nil); err != nil {
+		logger.Error("Failed to initialize http plugin", zap.Error(err))
+	} else {
+		if err := a.pluginMgr.RegisterPlugin(httpPlugin); err != nil {
+			logger.Error("Failed to register http plugin", zap.Error(err))
+		} else {
+			logger.Info("Registered builtin plugin", zap.String("name", "http"))
+		}
+	}
+
+	// 注册脚本执行插件
+	execPlugin := execplugin.NewPlugin()
+	if err := execPlugin.Init(a.config.Plugins.Settings["exec"]); err != nil {
+		logger.Error("Failed to initialize exec plugin", zap.Error(err))
+	} else {
+		if err := a.pluginMgr.RegisterPlugin(execPlugin); err != nil {
+			logger.Error("Failed to register exec plugin", zap.Error(err))
+		} else {
+			logger.Info("Registered builtin plugin", zap.String("name", "exec"))
+		}
+	}
+
+	// 注册 Prometheus Exporter 采集插件
+	scrapePlugin := promscrape.NewPlugin()
+	if err := scrapePlugin.Init(nil); err != nil {
+		logger.Error("Failed to initialize prometheus_scrape plugin", zap.Error(err))
+	} else {
+		if err := a.pluginMgr.RegisterPlugin(scrapePlugin); err != nil {
+			logger.Error("Failed to register prometheus_scrape plugin", zap.Error(err))
+		} else {
+			logger.Info("Registered builtin plugin", zap.String("name", "prometheus_scrape"))
+		}
+	}
 }
 
 // loadLocalTasks 加载本地任务配置
@@ -405,6 +534,7 @@ func (a *Agent) loadLocalTasks() {
 
 		// 添加到调度器
 		a.scheduler.AddTask(task, interval)
+		a.localTaskIDs = append(a.localTaskIDs, taskCfg.ID)
 
 		logger.Info("Loaded local task",
 			zap.String("task_id", taskCfg.ID),
@@ -453,7 +583,10 @@ func generateSentinelID() string {
 // handleRegistration 处理注册和凭证
 func (a *Agent) handleRegistration() error {
 	// 1. 初始化凭证管理器
-	credsMgr := credentials.NewManager(a.config.CredentialsPath)
+	if a.credsMgr == nil {
+		a.credsMgr = credentials.NewManager(a.config.CredentialsPath)
+	}
+	credsMgr := a.credsMgr
 
 	// 2. 尝试加载本地凭证
 	creds, err := credsMgr.Load()
@@ -476,6 +609,10 @@ func (a *Agent) handleRegistration() error {
 			a.config.Core.RegistrationKey,
 			credsMgr,
 		)
+		registerMgr.SetTransport(a.transport)
+		if a.certMgr != nil {
+			registerMgr.SetCertManager(a.certMgr)
+		}
 
 		registerConfig := &register.Config{
 			Name:    a.config.Sentinel.Name,
@@ -501,6 +638,13 @@ func (a *Agent) handleRegistration() error {
 			zap.String("sentinel_id", creds.SentinelID),
 			zap.Time("registered_at", creds.RegisteredAt))
 
+		// 证书即将过期时先续期，否则要求客户端证书的中心端会拒绝验证请求
+		if a.config.Core.URL != "" {
+			if err := a.ensureCertificate(a.ctx, creds.CoreURL, creds.SentinelID, creds.APIToken); err != nil {
+				logger.Warn("Failed to renew client certificate", zap.Error(err))
+			}
+		}
+
 		// 验证凭证有效性
 		if a.config.Core.URL != "" && !a.validateCredentials(creds) {
 			logger.Warn("Credentials validation failed, attempting to re-register...")
@@ -515,7 +659,9 @@ func (a *Agent) handleRegistration() error {
 	// 4. 使用凭证更新配置
 	if creds != nil && creds.IsValid() {
 		a.config.Sentinel.ID = creds.SentinelID
+		a.tokenMu.Lock()
 		a.config.Core.APIToken = creds.APIToken
+		a.tokenMu.Unlock()
 		if creds.CoreURL != "" {
 			a.config.Core.URL = creds.CoreURL
 		}
@@ -529,7 +675,7 @@ func (a *Agent) validateCredentials(creds *credentials.Credentials) bool {
 	logger.Debug("Validating credentials", zap.String("sentinel_id", creds.SentinelID))
 
 	// 发送一次心跳测试
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := &http.Client{Timeout: 10 * time.Second, Transport: a.transport}
 
 	req, err := http.NewRequest("POST",
 		creds.CoreURL+"/api/v1/sentinels/heartbeat",
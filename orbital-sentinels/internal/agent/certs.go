@@ -0,0 +1,88 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/celestial/orbital-sentinels/internal/certs"
+	"github.com/celestial/orbital-sentinels/internal/credentials"
+	"github.com/celestial/orbital-sentinels/internal/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// certCheckInterval 检查客户端证书是否需要续期的间隔
+const certCheckInterval = time.Hour
+
+// setupTLS 创建连接中心端的 Transport，启用 mTLS 时加载本地客户端证书
+// 证书与 credentials.yaml 保存在同一目录
+func (a *Agent) setupTLS() error {
+	if a.credsMgr == nil {
+		a.credsMgr = credentials.NewManager(a.config.CredentialsPath)
+	}
+
+	if a.config.Core.MTLS {
+		a.certMgr = certs.NewManager(filepath.Dir(a.credsMgr.GetPath()))
+		if err := a.certMgr.Load(); err != nil {
+			// 证书损坏时重新申请
+			logger.Warn("Failed to load client certificate, a new one will be requested", zap.Error(err))
+		}
+	}
+
+	transport, err := certs.NewTransport(certs.TLSOptions{
+		CAFile:             a.config.Core.CAFile,
+		InsecureSkipVerify: a.config.Core.InsecureSkipVerify,
+	}, a.certMgr)
+	if err != nil {
+		return err
+	}
+	a.transport = transport
+
+	return nil
+}
+
+// ensureCertificate 证书不存在或即将过期时向中心端申请新证书
+func (a *Agent) ensureCertificate(ctx context.Context, coreURL, sentinelID, apiToken string) error {
+	if a.certMgr == nil || !a.certMgr.NeedsRenewal(time.Now()) {
+		return nil
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second, Transport: a.transport}
+	if err := a.certMgr.Request(ctx, client, coreURL, sentinelID, apiToken); err != nil {
+		return fmt.Errorf("failed to request certificate: %w", err)
+	}
+
+	// 关闭空闲连接，后续请求使用新证书重新握手
+	a.transport.CloseIdleConnections()
+
+	logger.Info("Client certificate renewed",
+		zap.Time("expires_at", a.certMgr.Leaf().NotAfter),
+		zap.String("path", a.certMgr.Dir()))
+	return nil
+}
+
+// certRenewLoop 定期检查并续期客户端证书
+func (a *Agent) certRenewLoop() {
+	ticker := time.NewTicker(certCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := a.ensureCertificate(a.ctx, a.config.Core.URL, a.config.Sentinel.ID, a.apiToken()); err != nil {
+				logger.Warn("Failed to renew client certificate", zap.Error(err))
+			}
+		}
+	}
+}
+
+// apiToken 返回当前 API Token，Token 轮换时会被更新
+func (a *Agent) apiToken() string {
+	a.tokenMu.RLock()
+	defer a.tokenMu.RUnlock()
+	return a.config.Core.APIToken
+}
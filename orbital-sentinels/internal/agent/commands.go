@@ -0,0 +1,191 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/celestial/orbital-sentinels/internal/credentials"
+	"github.com/celestial/orbital-sentinels/internal/heartbeat"
+	"github.com/celestial/orbital-sentinels/internal/pkg/config"
+	"github.com/celestial/orbital-sentinels/internal/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// maxDiagnosticTasks 诊断信息中最多包含的任务数
+const maxDiagnosticTasks = 200
+
+// handleCommand 执行中心端下发的控制命令
+func (a *Agent) handleCommand(ctx context.Context, cmd *heartbeat.Command) (map[string]interface{}, func(), error) {
+	switch cmd.Type {
+	case heartbeat.CommandReloadConfig:
+		result, err := a.reloadConfig()
+		return result, nil, err
+
+	case heartbeat.CommandRestartScheduler:
+		a.scheduler.Restart(a.ctx)
+		return map[string]interface{}{"tasks": len(a.scheduler.GetAllTasks())}, nil, nil
+
+	case heartbeat.CommandRefetchTasks:
+		fetchCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+		count, err := a.scheduler.FetchTasks(fetchCtx)
+		if err != nil {
+			return nil, nil, err
+		}
+		return map[string]interface{}{"tasks": count}, nil, nil
+
+	case heartbeat.CommandRunTask:
+		taskID, _ := cmd.Payload["task_id"].(string)
+		if taskID == "" {
+			return nil, nil, fmt.Errorf("payload.task_id is required")
+		}
+		start := time.Now()
+		if err := a.scheduler.RunTask(taskID); err != nil {
+			return nil, nil, err
+		}
+		return map[string]interface{}{
+			"task_id":     taskID,
+			"duration_ms": time.Since(start).Milliseconds(),
+		}, nil, nil
+
+	case heartbeat.CommandRotateToken:
+		token, _ := cmd.Payload["api_token"].(string)
+		if token == "" {
+			return nil, nil, fmt.Errorf("payload.api_token is required")
+		}
+		if err := a.rotateToken(token); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, nil
+
+	case heartbeat.CommandUploadDiagnostics:
+		return a.diagnostics(), nil, nil
+
+	case heartbeat.CommandShutdown:
+		// 结果上报后再停止
+		return nil, func() { a.Stop() }, nil
+
+	default:
+		return nil, nil, fmt.Errorf("unknown command type: %s", cmd.Type)
+	}
+}
+
+// reloadConfig 重新加载配置文件中的本地任务
+func (a *Agent) reloadConfig() (map[string]interface{}, error) {
+	if a.configPath == "" {
+		return nil, fmt.Errorf("config path not set")
+	}
+
+	cfg, err := config.Load(a.configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, taskID := range a.localTaskIDs {
+		a.scheduler.RemoveTask(taskID)
+	}
+	a.localTaskIDs = nil
+
+	a.config.Tasks = cfg.Tasks
+	a.loadLocalTasks()
+
+	logger.Info("Config reloaded", zap.String("path", a.configPath))
+
+	return map[string]interface{}{"tasks": len(a.localTaskIDs)}, nil
+}
+
+// rotateToken 保存新的 API Token 并在各组件中生效
+func (a *Agent) rotateToken(token string) error {
+	if a.credsMgr == nil {
+		a.credsMgr = credentials.NewManager(a.config.CredentialsPath)
+	}
+
+	// 使用配置文件中的 Token 运行时，轮换后改用凭证文件保存
+	base := &credentials.Credentials{
+		SentinelID:   a.config.Sentinel.ID,
+		CoreURL:      a.config.Core.URL,
+		RegisteredAt: time.Now(),
+		Region:       a.config.Sentinel.Region,
+		Labels:       a.config.Sentinel.Labels,
+	}
+	if err := a.credsMgr.UpdateAPIToken(token, base); err != nil {
+		return fmt.Errorf("failed to save credentials: %w", err)
+	}
+
+	a.tokenMu.Lock()
+	a.config.Core.APIToken = token
+	a.tokenMu.Unlock()
+	a.heartbeatMgr.SetAPIToken(token)
+	if a.taskClient != nil {
+		a.taskClient.SetAPIToken(token)
+	}
+	if coreSender := a.sender.GetCoreSender(); coreSender != nil {
+		coreSender.SetToken(token)
+	}
+	if a.listeners != nil {
+		a.listeners.Forwarder().SetToken(token)
+	}
+
+	logger.Info("API token rotated", zap.String("credentials", a.credsMgr.GetPath()))
+	return nil
+}
+
+// diagnostics 收集诊断信息
+func (a *Agent) diagnostics() map[string]interface{} {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	hostname, _ := os.Hostname()
+	success, failed := a.sender.GetStats()
+
+	plugins := make([]string, 0)
+	for _, meta := range a.pluginMgr.ListPlugins() {
+		plugins = append(plugins, meta.Name)
+	}
+	sort.Strings(plugins)
+
+	allTasks := a.scheduler.GetAllTasks()
+	sort.Slice(allTasks, func(i, j int) bool {
+		return allTasks[i].Task.TaskID < allTasks[j].Task.TaskID
+	})
+	tasks := make([]map[string]interface{}, 0, len(allTasks))
+	for i, st := range allTasks {
+		if i >= maxDiagnosticTasks {
+			break
+		}
+		tasks = append(tasks, st.Snapshot())
+	}
+
+	return map[string]interface{}{
+		"sentinel_id":    a.config.Sentinel.ID,
+		"hostname":       hostname,
+		"state":          int(a.GetState()),
+		"uptime_seconds": int64(time.Since(a.startTime).Seconds()),
+		"go_version":     runtime.Version(),
+		"os":             runtime.GOOS,
+		"arch":           runtime.GOARCH,
+		"goroutines":     runtime.NumGoroutine(),
+		"memory": map[string]interface{}{
+			"alloc_bytes":  mem.Alloc,
+			"sys_bytes":    mem.Sys,
+			"heap_objects": mem.HeapObjects,
+			"num_gc":       mem.NumGC,
+		},
+		"buffer": map[string]interface{}{
+			"type": a.config.Buffer.Type,
+			"size": a.buffer.Size(),
+		},
+		"sender": map[string]interface{}{
+			"mode":    a.config.Sender.Mode,
+			"success": success,
+			"failed":  failed,
+		},
+		"plugins":    plugins,
+		"task_count": len(allTasks),
+		"tasks":      tasks,
+	}
+}
@@ -0,0 +1,79 @@
+package agent
+
+import (
+	"github.com/celestial/orbital-sentinels/internal/listener"
+	"github.com/celestial/orbital-sentinels/internal/pkg/logger"
+	"github.com/celestial/orbital-sentinels/internal/plugin"
+	"go.uber.org/zap"
+)
+
+// setupListeners 创建设备事件接收管理器，未启用任何接收器或没有中心端时跳过
+func (a *Agent) setupListeners() {
+	cfg := a.config.Listeners
+	if !cfg.Trap.Enabled && !cfg.Syslog.Enabled {
+		return
+	}
+	if a.config.Core.URL == "" {
+		logger.Warn("Event listeners require core connection, skipping")
+		return
+	}
+
+	var trapCfg *listener.TrapConfig
+	if cfg.Trap.Enabled {
+		trapCfg = &listener.TrapConfig{
+			Address:     cfg.Trap.Address,
+			Communities: cfg.Trap.Communities,
+		}
+		for _, u := range cfg.Trap.Users {
+			trapCfg.Users = append(trapCfg.Users, listener.TrapUser{
+				Username:      u.Username,
+				SecurityLevel: u.SecurityLevel,
+				AuthProtocol:  u.AuthProtocol,
+				AuthPassword:  u.AuthPassword,
+				PrivProtocol:  u.PrivProtocol,
+				PrivPassword:  u.PrivPassword,
+			})
+		}
+	}
+
+	var syslogCfg *listener.SyslogConfig
+	if cfg.Syslog.Enabled {
+		syslogCfg = &listener.SyslogConfig{
+			UDPAddress: cfg.Syslog.UDPAddress,
+			TCPAddress: cfg.Syslog.TCPAddress,
+		}
+	}
+
+	forwarder := listener.NewForwarder(
+		a.config.Core.URL,
+		a.config.Sentinel.ID,
+		a.apiToken(),
+		cfg.QueueSize,
+		cfg.BatchSize,
+		cfg.FlushInterval,
+		a.config.Sender.Timeout,
+	)
+	forwarder.SetTransport(a.transport)
+
+	resolver := listener.NewDeviceResolver(func() []*plugin.CollectionTask {
+		scheduled := a.scheduler.GetAllTasks()
+		tasks := make([]*plugin.CollectionTask, 0, len(scheduled))
+		for _, st := range scheduled {
+			tasks = append(tasks, st.Task)
+		}
+		return tasks
+	})
+
+	a.listeners = listener.NewManager(trapCfg, syslogCfg, resolver, forwarder)
+}
+
+// startListeners 启动设备事件接收，端口被占用或权限不足时只记录错误，不影响采集
+func (a *Agent) startListeners() {
+	if a.listeners == nil {
+		return
+	}
+	if err := a.listeners.Start(a.ctx); err != nil {
+		logger.Error("Failed to start event listeners", zap.Error(err))
+		a.listeners = nil
+	}
+}
@@ -1,18 +1,35 @@
 package buffer
 
 import (
+	"errors"
+
 	"github.com/celestial/orbital-sentinels/internal/plugin"
 )
 
+// ErrUnknownConsumer 消费者未注册
+var ErrUnknownConsumer = errors.New("unknown consumer")
+
 // Buffer 缓冲队列接口
+// 每个消费者（发送目标）独立维护读取位置：Peek 读取尚未确认的数据，
+// 发送成功后 Commit 确认，发送失败后 Nack 退回，下次 Peek 重新读取同一批数据。
+// 数据在所有消费者确认后才从缓冲区移除。
 type Buffer interface {
 	// Push 推入数据
 	Push(metrics []*plugin.Metric) error
 
-	// Pop 弹出数据
-	Pop(count int) ([]*plugin.Metric, error)
+	// SetConsumers 设置消费者，新的消费者从最旧的数据开始读取，不在列表中的消费者被移除
+	SetConsumers(consumers []string) error
+
+	// Peek 读取消费者尚未读取的数据，最多 count 条，没有数据时返回空
+	Peek(consumer string, count int) ([]*plugin.Metric, error)
+
+	// Commit 确认消费者最早读取的 count 条数据
+	Commit(consumer string, count int) error
+
+	// Nack 退回消费者已读取但未确认的数据
+	Nack(consumer string) error
 
-	// Size 获取当前大小
+	// Size 获取当前大小（最慢的消费者尚未确认的数据量）
 	Size() int
 
 	// Close 关闭缓冲区
@@ -0,0 +1,882 @@
+package buffer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/celestial/orbital-sentinels/internal/pkg/logger"
+	"github.com/celestial/orbital-sentinels/internal/plugin"
+	"go.uber.org/zap"
+)
+
+// 刷盘策略
+const (
+	SyncModeBatch    = "batch"    // 每次写入后 fsync
+	SyncModeInterval = "interval" // 按固定间隔 fsync
+)
+
+const (
+	segmentExt    = ".seg"
+	cursorFile    = "cursor"
+	recordHeader  = 8 // 4 字节长度 + 4 字节 CRC32
+	maxRecordSize = 16 << 20
+
+	defaultMaxBytes     = 1 << 30  // 1GB
+	defaultSegmentBytes = 16 << 20 // 16MB
+	defaultSyncInterval = time.Second
+)
+
+var errCorruptRecord = errors.New("corrupt record")
+
+// DiskConfig 磁盘缓冲配置
+type DiskConfig struct {
+	Path         string        // 数据目录
+	MaxBytes     int64         // 最大占用字节数，超出后丢弃最旧的段
+	SegmentBytes int64         // 单个段文件大小
+	SyncMode     string        // batch 或 interval
+	SyncInterval time.Duration // interval 模式下的 fsync 间隔
+}
+
+// segment 段文件
+type segment struct {
+	id       uint64
+	firstSeq uint64 // 段内第一条记录的序号
+	size     int64
+	count    int
+}
+
+// diskPosition 段文件中的位置
+type diskPosition struct {
+	Segment uint64 `json:"segment"`
+	Offset  int64  `json:"offset"`
+	seq     uint64
+}
+
+// diskConsumer 消费者的确认位置和读取位置
+type diskConsumer struct {
+	committed diskPosition
+	read      diskPosition
+	dropped   uint64 // 已读取未确认、但因溢出被删除的数据量
+}
+
+// diskCursors cursor 文件内容
+type diskCursors struct {
+	Consumers map[string]diskPosition `json:"consumers"`
+}
+
+// DiskBuffer 基于段文件的磁盘缓冲（预写日志）
+// 每个指标作为一条记录追加到当前段文件，段写满后切换到新段；
+// 各消费者的确认位置保存在 cursor 文件中，所有消费者都确认的段文件被删除，
+// 重启后各消费者从确认位置继续读取。
+type DiskBuffer struct {
+	config    DiskConfig
+	segments  []*segment // 按 id 递增，最后一个为写入段
+	writer    *os.File
+	consumers map[string]*diskConsumer
+	saved     map[string]diskPosition // 启动时读取的确认位置，设置消费者后清空
+	nextSeq   uint64
+	bytes     int64 // 段文件总大小
+	dirty     bool
+	mu        sync.Mutex
+	closed    bool
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewDiskBuffer 创建磁盘缓冲，并恢复目录中未发送的数据
+func NewDiskBuffer(config DiskConfig) (*DiskBuffer, error) {
+	if config.Path == "" {
+		return nil, fmt.Errorf("disk buffer path is required")
+	}
+	if config.MaxBytes <= 0 {
+		config.MaxBytes = defaultMaxBytes
+	}
+	if config.SegmentBytes <= 0 {
+		config.SegmentBytes = defaultSegmentBytes
+	}
+	// 至少保留两个段，丢弃最旧的段时不会影响正在写入的段
+	if config.SegmentBytes > config.MaxBytes/2 {
+		config.SegmentBytes = config.MaxBytes / 2
+	}
+	switch config.SyncMode {
+	case "":
+		config.SyncMode = SyncModeBatch
+	case SyncModeBatch, SyncModeInterval:
+	default:
+		return nil, fmt.Errorf("unknown sync mode: %s", config.SyncMode)
+	}
+	if config.SyncInterval <= 0 {
+		config.SyncInterval = defaultSyncInterval
+	}
+
+	if err := os.MkdirAll(config.Path, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create buffer directory: %w", err)
+	}
+
+	db := &DiskBuffer{
+		config:    config,
+		consumers: make(map[string]*diskConsumer),
+		stopCh:    make(chan struct{}),
+	}
+
+	if err := db.recover(); err != nil {
+		if db.writer != nil {
+			db.writer.Close()
+		}
+		return nil, err
+	}
+
+	if config.SyncMode == SyncModeInterval {
+		db.wg.Add(1)
+		go db.syncLoop()
+	}
+
+	if size := db.sizeLocked(); size > 0 {
+		logger.Info("Disk buffer replaying unsent metrics",
+			zap.String("path", config.Path),
+			zap.Int("metrics", size),
+			zap.Int("segments", len(db.segments)))
+	}
+
+	return db, nil
+}
+
+// recover 扫描段文件和确认位置，截断未写完的尾部记录
+func (db *DiskBuffer) recover() error {
+	ids, err := db.listSegments()
+	if err != nil {
+		return err
+	}
+
+	saved, err := db.loadCursors()
+	if err != nil {
+		logger.Warn("Failed to load disk buffer cursor, replaying from oldest segment", zap.Error(err))
+	}
+	db.saved = saved
+
+	// 所有消费者都已确认的段可以删除
+	var minSegment, maxSegment uint64
+	for _, pos := range saved {
+		if minSegment == 0 || pos.Segment < minSegment {
+			minSegment = pos.Segment
+		}
+		if pos.Segment > maxSegment {
+			maxSegment = pos.Segment
+		}
+	}
+
+	for _, id := range ids {
+		if id < minSegment {
+			if err := os.Remove(db.segmentPath(id)); err != nil {
+				return fmt.Errorf("failed to remove segment: %w", err)
+			}
+			continue
+		}
+
+		seg, err := db.scanSegment(id)
+		if err != nil {
+			return err
+		}
+		seg.firstSeq = db.nextSeq
+		db.segments = append(db.segments, seg)
+		db.bytes += seg.size
+		db.nextSeq += uint64(seg.count)
+	}
+
+	if len(db.segments) == 0 {
+		seg, err := db.createSegment(maxSegment + 1)
+		if err != nil {
+			return err
+		}
+		db.segments = append(db.segments, seg)
+	}
+
+	last := db.segments[len(db.segments)-1]
+	writer, err := os.OpenFile(db.segmentPath(last.id), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open segment: %w", err)
+	}
+	db.writer = writer
+
+	return nil
+}
+
+// scanSegment 扫描段文件，统计记录数并截断损坏的尾部
+func (db *DiskBuffer) scanSegment(id uint64) (*segment, error) {
+	path := db.segmentPath(id)
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open segment: %w", err)
+	}
+	defer f.Close()
+
+	seg := &segment{id: id}
+	for {
+		n, err := skipRecord(f)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// 进程异常退出时最后一条记录可能只写了一半
+			logger.Warn("Truncating corrupt disk buffer segment",
+				zap.String("segment", path),
+				zap.Int64("offset", seg.size),
+				zap.Error(err))
+			if err := os.Truncate(path, seg.size); err != nil {
+				return nil, fmt.Errorf("failed to truncate segment: %w", err)
+			}
+			break
+		}
+		seg.size += n
+		seg.count++
+	}
+
+	return seg, nil
+}
+
+// Push 推入数据
+func (db *DiskBuffer) Push(metrics []*plugin.Metric) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.closed {
+		return fmt.Errorf("buffer is closed")
+	}
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for _, m := range metrics {
+		if err := encodeRecord(&buf, m); err != nil {
+			return err
+		}
+	}
+
+	last := db.segments[len(db.segments)-1]
+	if last.size > 0 && last.size+int64(buf.Len()) > db.config.SegmentBytes {
+		if err := db.rotate(); err != nil {
+			return err
+		}
+		last = db.segments[len(db.segments)-1]
+	}
+
+	n, err := db.writer.Write(buf.Bytes())
+	if err != nil {
+		// 去掉部分写入的记录，截断失败时由重启恢复处理
+		if n > 0 {
+			db.writer.Truncate(last.size)
+		}
+		return fmt.Errorf("failed to write segment: %w", err)
+	}
+	last.size += int64(n)
+	last.count += len(metrics)
+	db.bytes += int64(n)
+	db.nextSeq += uint64(len(metrics))
+
+	if db.config.SyncMode == SyncModeBatch {
+		if err := db.writer.Sync(); err != nil {
+			return fmt.Errorf("failed to sync segment: %w", err)
+		}
+	} else {
+		db.dirty = true
+	}
+
+	db.enforceLimit()
+
+	return nil
+}
+
+// SetConsumers 设置消费者
+// 启动时从 cursor 文件恢复已有消费者的确认位置，新的消费者从最旧的段开始读取
+func (db *DiskBuffer) SetConsumers(consumers []string) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.closed {
+		return fmt.Errorf("buffer is closed")
+	}
+
+	keep := make(map[string]bool, len(consumers))
+	for _, name := range consumers {
+		keep[name] = true
+		if _, ok := db.consumers[name]; ok {
+			continue
+		}
+
+		pos := db.startPosition()
+		if saved, ok := db.saved[name]; ok {
+			if located, ok := db.locate(saved); ok {
+				pos = located
+			} else {
+				logger.Warn("Disk buffer cursor is invalid, replaying from oldest segment",
+					zap.String("consumer", name),
+					zap.Uint64("segment", saved.Segment),
+					zap.Int64("offset", saved.Offset))
+			}
+		}
+		db.consumers[name] = &diskConsumer{committed: pos, read: pos}
+	}
+	for name := range db.consumers {
+		if !keep[name] {
+			delete(db.consumers, name)
+		}
+	}
+	db.saved = nil
+
+	db.trim()
+	return db.saveCursors(true)
+}
+
+// Peek 读取消费者尚未读取的数据
+func (db *DiskBuffer) Peek(consumer string, count int) ([]*plugin.Metric, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	// 关闭后未确认的数据保留在磁盘上，下次启动时重放
+	if db.closed {
+		return nil, fmt.Errorf("buffer is closed")
+	}
+
+	c, ok := db.consumers[consumer]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownConsumer, consumer)
+	}
+
+	metrics, pos, err := db.scan(c.read, count, true)
+	c.read = pos
+	return metrics, err
+}
+
+// Commit 确认消费者最早读取的 count 条数据
+func (db *DiskBuffer) Commit(consumer string, count int) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.closed {
+		return fmt.Errorf("buffer is closed")
+	}
+
+	c, ok := db.consumers[consumer]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownConsumer, consumer)
+	}
+
+	if count < 0 {
+		return fmt.Errorf("invalid commit count %d", count)
+	}
+	// 溢出删除的在途数据已不在段文件中，只确认仍在缓冲区中的部分
+	skip := min(uint64(count), c.dropped)
+	inflight := c.read.seq - c.committed.seq
+	if uint64(count)-skip > inflight {
+		return fmt.Errorf("commit %d exceeds %d in-flight metrics", count, inflight+c.dropped)
+	}
+	c.dropped -= skip
+	count -= int(skip)
+
+	if uint64(count) == inflight {
+		c.committed = c.read
+	} else if count > 0 {
+		_, pos, err := db.scan(c.committed, count, false)
+		if err != nil {
+			return err
+		}
+		c.committed = pos
+	}
+
+	db.trim()
+	return db.saveCursors(db.config.SyncMode == SyncModeBatch)
+}
+
+// Nack 退回消费者已读取但未确认的数据
+func (db *DiskBuffer) Nack(consumer string) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	c, ok := db.consumers[consumer]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownConsumer, consumer)
+	}
+	c.read = c.committed
+	c.dropped = 0
+
+	return nil
+}
+
+// Size 获取当前大小
+func (db *DiskBuffer) Size() int {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return db.sizeLocked()
+}
+
+// sizeLocked 最慢的消费者尚未确认的记录数，没有消费者时为全部记录数
+func (db *DiskBuffer) sizeLocked() int {
+	min := db.segments[0].firstSeq
+	if len(db.consumers) > 0 {
+		min = db.nextSeq
+		for _, c := range db.consumers {
+			if c.committed.seq < min {
+				min = c.committed.seq
+			}
+		}
+	}
+	return int(db.nextSeq - min)
+}
+
+// Bytes 获取段文件占用的字节数
+func (db *DiskBuffer) Bytes() int64 {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return db.bytes
+}
+
+// Close 关闭缓冲区，未确认的数据保留在磁盘上
+func (db *DiskBuffer) Close() error {
+	db.mu.Lock()
+	if db.closed {
+		db.mu.Unlock()
+		return nil
+	}
+	db.closed = true
+	db.mu.Unlock()
+
+	close(db.stopCh)
+	db.wg.Wait()
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	var errs []error
+	if err := db.writer.Sync(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := db.saveCursors(true); err != nil {
+		errs = append(errs, err)
+	}
+	if err := db.writer.Close(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// syncLoop interval 模式下定期 fsync
+func (db *DiskBuffer) syncLoop() {
+	defer db.wg.Done()
+
+	ticker := time.NewTicker(db.config.SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			db.mu.Lock()
+			if db.dirty {
+				if err := db.writer.Sync(); err != nil {
+					logger.Error("Failed to sync disk buffer", zap.Error(err))
+				} else {
+					db.dirty = false
+				}
+			}
+			if err := db.saveCursors(true); err != nil {
+				logger.Error("Failed to save disk buffer cursor", zap.Error(err))
+			}
+			db.mu.Unlock()
+		case <-db.stopCh:
+			return
+		}
+	}
+}
+
+// scan 从指定位置读取最多 count 条记录，返回读取后的位置
+// decode 为 false 时只校验并跳过记录
+func (db *DiskBuffer) scan(pos diskPosition, count int, decode bool) ([]*plugin.Metric, diskPosition, error) {
+	metrics := make([]*plugin.Metric, 0)
+	var f *os.File
+	defer func() {
+		if f != nil {
+			f.Close()
+		}
+	}()
+
+	for read := 0; read < count; {
+		i := db.segmentIndex(pos.Segment)
+		if i < 0 {
+			// 所在的段已被丢弃，从最旧的段继续
+			pos = db.startPosition()
+			continue
+		}
+		seg := db.segments[i]
+
+		if pos.seq >= seg.firstSeq+uint64(seg.count) {
+			if i == len(db.segments)-1 {
+				break
+			}
+			next := db.segments[i+1]
+			pos = diskPosition{Segment: next.id, seq: next.firstSeq}
+			if f != nil {
+				f.Close()
+				f = nil
+			}
+			continue
+		}
+
+		if f == nil {
+			var err error
+			f, err = os.Open(db.segmentPath(seg.id))
+			if err != nil {
+				return metrics, pos, fmt.Errorf("failed to open segment: %w", err)
+			}
+			if _, err := f.Seek(pos.Offset, io.SeekStart); err != nil {
+				return metrics, pos, fmt.Errorf("failed to seek segment: %w", err)
+			}
+		}
+
+		var m *plugin.Metric
+		var n int64
+		var err error
+		if decode {
+			m, n, err = readRecord(f)
+		} else {
+			n, err = skipRecord(f)
+		}
+		if err != nil {
+			// 段中间的记录损坏，跳过该段剩余的数据
+			lost := seg.firstSeq + uint64(seg.count) - pos.seq
+			logger.Error("Skipping corrupt disk buffer segment",
+				zap.Uint64("segment", seg.id),
+				zap.Int64("offset", pos.Offset),
+				zap.Uint64("lost", lost),
+				zap.Error(err))
+			pos = diskPosition{Segment: seg.id, Offset: seg.size, seq: seg.firstSeq + uint64(seg.count)}
+			f.Close()
+			f = nil
+			continue
+		}
+
+		if decode {
+			metrics = append(metrics, m)
+		}
+		pos.Offset += n
+		pos.seq++
+		read++
+	}
+
+	return metrics, pos, nil
+}
+
+// locate 校验确认位置并计算序号
+func (db *DiskBuffer) locate(pos diskPosition) (diskPosition, bool) {
+	i := db.segmentIndex(pos.Segment)
+	if i < 0 {
+		return pos, false
+	}
+	seg := db.segments[i]
+
+	f, err := os.Open(db.segmentPath(seg.id))
+	if err != nil {
+		return pos, false
+	}
+	defer f.Close()
+
+	var offset int64
+	index := 0
+	for offset < pos.Offset {
+		n, err := skipRecord(f)
+		if err != nil {
+			return pos, false
+		}
+		offset += n
+		index++
+	}
+	if offset != pos.Offset {
+		return pos, false
+	}
+
+	pos.seq = seg.firstSeq + uint64(index)
+	return pos, true
+}
+
+// startPosition 最旧的段的起始位置
+func (db *DiskBuffer) startPosition() diskPosition {
+	first := db.segments[0]
+	return diskPosition{Segment: first.id, seq: first.firstSeq}
+}
+
+// normalize 已读到段末尾的位置移动到下一个段的起始位置
+func (db *DiskBuffer) normalize(pos diskPosition) diskPosition {
+	i := db.segmentIndex(pos.Segment)
+	if i < 0 {
+		return db.startPosition()
+	}
+	seg := db.segments[i]
+	if pos.seq >= seg.firstSeq+uint64(seg.count) && i < len(db.segments)-1 {
+		next := db.segments[i+1]
+		return diskPosition{Segment: next.id, seq: next.firstSeq}
+	}
+	return pos
+}
+
+// trim 删除所有消费者都已确认的段，没有消费者时保留全部数据
+func (db *DiskBuffer) trim() {
+	if len(db.consumers) == 0 {
+		return
+	}
+
+	var min uint64
+	first := true
+	for _, c := range db.consumers {
+		c.committed = db.normalize(c.committed)
+		if c.read.seq == c.committed.seq {
+			c.read = c.committed
+		}
+		if first || c.committed.Segment < min {
+			min = c.committed.Segment
+			first = false
+		}
+	}
+
+	for len(db.segments) > 1 && db.segments[0].id < min {
+		if err := db.dropOldest(); err != nil {
+			logger.Error("Failed to remove disk buffer segment", zap.Error(err))
+			return
+		}
+	}
+}
+
+// rotate 切换到新的写入段
+func (db *DiskBuffer) rotate() error {
+	last := db.segments[len(db.segments)-1]
+	if err := db.writer.Sync(); err != nil {
+		return fmt.Errorf("failed to sync segment: %w", err)
+	}
+	db.writer.Close()
+
+	seg, err := db.createSegment(last.id + 1)
+	if err != nil {
+		return err
+	}
+	seg.firstSeq = db.nextSeq
+	writer, err := os.OpenFile(db.segmentPath(seg.id), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open segment: %w", err)
+	}
+	db.writer = writer
+	db.segments = append(db.segments, seg)
+	return nil
+}
+
+// enforceLimit 超出最大字节数时丢弃最旧的段
+func (db *DiskBuffer) enforceLimit() {
+	for db.bytes > db.config.MaxBytes && len(db.segments) > 1 {
+		seg := db.segments[0]
+		logger.Warn("Disk buffer full, dropping oldest segment",
+			zap.Uint64("segment", seg.id),
+			zap.Int("metrics", seg.count),
+			zap.Int64("max_bytes", db.config.MaxBytes))
+		if err := db.dropOldest(); err != nil {
+			logger.Error("Failed to drop disk buffer segment", zap.Error(err))
+			return
+		}
+	}
+}
+
+// dropOldest 删除最旧的段，位置落在其中的消费者跳到下一个段
+func (db *DiskBuffer) dropOldest() error {
+	seg := db.segments[0]
+	if err := os.Remove(db.segmentPath(seg.id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove segment: %w", err)
+	}
+	db.segments = db.segments[1:]
+	db.bytes -= seg.size
+
+	start := db.startPosition()
+	for _, c := range db.consumers {
+		if c.committed.Segment <= seg.id {
+			c.dropped += min(c.read.seq, start.seq) - c.committed.seq
+			c.committed = start
+		}
+		if c.read.Segment <= seg.id {
+			c.read = start
+		}
+	}
+	return nil
+}
+
+// createSegment 创建空的段文件
+func (db *DiskBuffer) createSegment(id uint64) (*segment, error) {
+	f, err := os.OpenFile(db.segmentPath(id), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create segment: %w", err)
+	}
+	f.Close()
+	return &segment{id: id, firstSeq: db.nextSeq}, nil
+}
+
+func (db *DiskBuffer) segmentIndex(id uint64) int {
+	for i, seg := range db.segments {
+		if seg.id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// listSegments 列出目录中的段文件 id
+func (db *DiskBuffer) listSegments() ([]uint64, error) {
+	entries, err := os.ReadDir(db.config.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read buffer directory: %w", err)
+	}
+
+	var ids []uint64
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, segmentExt) {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimSuffix(name, segmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+// loadCursors 读取各消费者的确认位置
+func (db *DiskBuffer) loadCursors() (map[string]diskPosition, error) {
+	data, err := os.ReadFile(filepath.Join(db.config.Path, cursorFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var c diskCursors
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return c.Consumers, nil
+}
+
+// saveCursors 保存各消费者的确认位置（写临时文件后重命名）
+func (db *DiskBuffer) saveCursors(sync bool) error {
+	c := diskCursors{Consumers: make(map[string]diskPosition, len(db.consumers))}
+	if len(db.consumers) == 0 && db.saved != nil {
+		// 尚未设置消费者，保留启动时读取的位置
+		c.Consumers = db.saved
+	}
+	for name, consumer := range db.consumers {
+		c.Consumers[name] = consumer.committed
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(db.config.Path, cursorFile)
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to save cursor: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to save cursor: %w", err)
+	}
+	if sync {
+		if err := f.Sync(); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to save cursor: %w", err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to save cursor: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+func (db *DiskBuffer) segmentPath(id uint64) string {
+	return filepath.Join(db.config.Path, fmt.Sprintf("%020d%s", id, segmentExt))
+}
+
+// encodeRecord 编码一条记录：长度 + CRC32 + JSON
+func encodeRecord(w *bytes.Buffer, m *plugin.Metric) error {
+	payload, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("failed to encode metric: %w", err)
+	}
+
+	var header [recordHeader]byte
+	binary.BigEndian.PutUint32(header[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(header[4:8], crc32.ChecksumIEEE(payload))
+	w.Write(header[:])
+	w.Write(payload)
+	return nil
+}
+
+// readRecord 读取一条记录，返回指标和记录长度
+func readRecord(r io.Reader) (*plugin.Metric, int64, error) {
+	payload, err := readPayload(r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var m plugin.Metric
+	if err := json.Unmarshal(payload, &m); err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", errCorruptRecord, err)
+	}
+	return &m, int64(recordHeader + len(payload)), nil
+}
+
+// skipRecord 校验并跳过一条记录，返回记录长度
+func skipRecord(r io.Reader) (int64, error) {
+	payload, err := readPayload(r)
+	if err != nil {
+		return 0, err
+	}
+	return int64(recordHeader + len(payload)), nil
+}
+
+func readPayload(r io.Reader) ([]byte, error) {
+	var header [recordHeader]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("%w: %v", errCorruptRecord, err)
+	}
+
+	length := binary.BigEndian.Uint32(header[0:4])
+	if length > maxRecordSize {
+		return nil, fmt.Errorf("%w: record too large (%d bytes)", errCorruptRecord, length)
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", errCorruptRecord, err)
+	}
+	if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:8]) {
+		return nil, fmt.Errorf("%w: checksum mismatch", errCorruptRecord)
+	}
+	return payload, nil
+}
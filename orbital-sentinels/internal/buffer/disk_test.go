@@ -0,0 +1,368 @@
+package buffer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/celestial/orbital-sentinels/internal/plugin"
+)
+
+func newTestMetrics(start, n int) []*plugin.Metric {
+	metrics := make([]*plugin.Metric, 0, n)
+	for i := start; i < start+n; i++ {
+		metrics = append(metrics, &plugin.Metric{
+			Name:   "test",
+			Value:  float64(i),
+			Labels: map[string]string{"host": "h1"},
+			Type:   plugin.MetricTypeGauge,
+		})
+	}
+	return metrics
+}
+
+// popDisk 读取并确认数据
+func popDisk(t *testing.T, buf *DiskBuffer, consumer string, count int) []*plugin.Metric {
+	t.Helper()
+
+	metrics, err := buf.Peek(consumer, count)
+	if err != nil {
+		t.Fatalf("Peek failed: %v", err)
+	}
+	if err := buf.Commit(consumer, len(metrics)); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	return metrics
+}
+
+func TestDiskBuffer_PushPeek(t *testing.T) {
+	buf, err := NewDiskBuffer(DiskConfig{Path: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewDiskBuffer failed: %v", err)
+	}
+	defer buf.Close()
+	buf.SetConsumers([]string{"core"})
+
+	if err := buf.Push(newTestMetrics(0, 3)); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+
+	if buf.Size() != 3 {
+		t.Errorf("Expected size 3, got %d", buf.Size())
+	}
+
+	popped := popDisk(t, buf, "core", 2)
+
+	if len(popped) != 2 {
+		t.Fatalf("Expected 2 metrics, got %d", len(popped))
+	}
+
+	if popped[0].Value != 0 || popped[1].Value != 1 {
+		t.Errorf("Expected values 0,1, got %f,%f", popped[0].Value, popped[1].Value)
+	}
+
+	if popped[0].Labels["host"] != "h1" || popped[0].Type != plugin.MetricTypeGauge {
+		t.Errorf("Metric fields not preserved: %+v", popped[0])
+	}
+
+	if buf.Size() != 1 {
+		t.Errorf("Expected size 1, got %d", buf.Size())
+	}
+}
+
+func TestDiskBuffer_Replay(t *testing.T) {
+	dir := t.TempDir()
+
+	buf, err := NewDiskBuffer(DiskConfig{Path: dir})
+	if err != nil {
+		t.Fatalf("NewDiskBuffer failed: %v", err)
+	}
+	buf.SetConsumers([]string{"core"})
+	if err := buf.Push(newTestMetrics(0, 5)); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	popDisk(t, buf, "core", 2)
+	if err := buf.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// 重启后从上次读取位置继续
+	buf, err = NewDiskBuffer(DiskConfig{Path: dir})
+	if err != nil {
+		t.Fatalf("NewDiskBuffer failed: %v", err)
+	}
+	defer buf.Close()
+	buf.SetConsumers([]string{"core"})
+
+	if buf.Size() != 3 {
+		t.Fatalf("Expected size 3 after replay, got %d", buf.Size())
+	}
+
+	popped := popDisk(t, buf, "core", 10)
+	if len(popped) != 3 || popped[0].Value != 2 || popped[2].Value != 4 {
+		t.Errorf("Unexpected replayed metrics: %d", len(popped))
+	}
+}
+
+func TestDiskBuffer_TruncateTornRecord(t *testing.T) {
+	dir := t.TempDir()
+
+	buf, err := NewDiskBuffer(DiskConfig{Path: dir})
+	if err != nil {
+		t.Fatalf("NewDiskBuffer failed: %v", err)
+	}
+	if err := buf.Push(newTestMetrics(0, 2)); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	if err := buf.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// 模拟写入一半时进程退出
+	segments, _ := filepath.Glob(filepath.Join(dir, "*"+segmentExt))
+	if len(segments) != 1 {
+		t.Fatalf("Expected 1 segment, got %d", len(segments))
+	}
+	f, err := os.OpenFile(segments[0], os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatalf("Open segment failed: %v", err)
+	}
+	f.Write([]byte{0, 0, 0, 100, 1, 2})
+	f.Close()
+
+	buf, err = NewDiskBuffer(DiskConfig{Path: dir})
+	if err != nil {
+		t.Fatalf("NewDiskBuffer failed: %v", err)
+	}
+	defer buf.Close()
+	buf.SetConsumers([]string{"core"})
+
+	if buf.Size() != 2 {
+		t.Fatalf("Expected size 2 after truncation, got %d", buf.Size())
+	}
+
+	// 截断后继续写入的数据可以正常读取
+	if err := buf.Push(newTestMetrics(2, 1)); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	popped := popDisk(t, buf, "core", 10)
+	if len(popped) != 3 || popped[2].Value != 2 {
+		t.Errorf("Unexpected metrics after truncation: %d", len(popped))
+	}
+}
+
+func TestDiskBuffer_SegmentRotation(t *testing.T) {
+	dir := t.TempDir()
+
+	buf, err := NewDiskBuffer(DiskConfig{Path: dir, SegmentBytes: 256, MaxBytes: 1 << 20})
+	if err != nil {
+		t.Fatalf("NewDiskBuffer failed: %v", err)
+	}
+	defer buf.Close()
+	buf.SetConsumers([]string{"core"})
+
+	for i := 0; i < 10; i++ {
+		if err := buf.Push(newTestMetrics(i*2, 2)); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+	}
+
+	segments, _ := filepath.Glob(filepath.Join(dir, "*"+segmentExt))
+	if len(segments) < 2 {
+		t.Fatalf("Expected multiple segments, got %d", len(segments))
+	}
+
+	popped := popDisk(t, buf, "core", 20)
+	if len(popped) != 20 {
+		t.Fatalf("Expected 20 metrics, got %d", len(popped))
+	}
+	for i, m := range popped {
+		if m.Value != float64(i) {
+			t.Fatalf("Expected value %d at %d, got %f", i, i, m.Value)
+		}
+	}
+
+	// 读完的段被删除，只保留写入段
+	segments, _ = filepath.Glob(filepath.Join(dir, "*"+segmentExt))
+	if len(segments) != 1 {
+		t.Errorf("Expected 1 segment after consuming, got %d", len(segments))
+	}
+}
+
+func TestDiskBuffer_MaxBytes(t *testing.T) {
+	buf, err := NewDiskBuffer(DiskConfig{Path: t.TempDir(), SegmentBytes: 256, MaxBytes: 1024})
+	if err != nil {
+		t.Fatalf("NewDiskBuffer failed: %v", err)
+	}
+	defer buf.Close()
+	buf.SetConsumers([]string{"core"})
+
+	for i := 0; i < 50; i++ {
+		if err := buf.Push(newTestMetrics(i, 1)); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+	}
+
+	if buf.Bytes() > 1024 {
+		t.Errorf("Expected at most 1024 bytes, got %d", buf.Bytes())
+	}
+
+	// 超出容量时丢弃最旧的数据，保留最新的
+	size := buf.Size()
+	if size == 0 || size >= 50 {
+		t.Fatalf("Expected oldest metrics dropped, size %d", size)
+	}
+	popped := popDisk(t, buf, "core", size)
+	if popped[len(popped)-1].Value != 49 {
+		t.Errorf("Expected last value 49, got %f", popped[len(popped)-1].Value)
+	}
+	if popped[0].Value != float64(50-size) {
+		t.Errorf("Expected first value %d, got %f", 50-size, popped[0].Value)
+	}
+}
+
+func TestDiskBuffer_MaxBytesInFlight(t *testing.T) {
+	buf, err := NewDiskBuffer(DiskConfig{Path: t.TempDir(), SegmentBytes: 256, MaxBytes: 1024})
+	if err != nil {
+		t.Fatalf("NewDiskBuffer failed: %v", err)
+	}
+	defer buf.Close()
+	buf.SetConsumers([]string{"core"})
+
+	if err := buf.Push(newTestMetrics(0, 10)); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	inflight, err := buf.Peek("core", 10)
+	if err != nil || len(inflight) != 10 {
+		t.Fatalf("Peek failed: %v", err)
+	}
+
+	// 发送期间超出容量，在途批次被删除
+	for i := 10; i < 50; i++ {
+		if err := buf.Push(newTestMetrics(i, 1)); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+	}
+
+	// 发送成功后确认整批数据
+	if err := buf.Commit("core", len(inflight)); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	size := buf.Size()
+	if size == 0 || size > 40 {
+		t.Fatalf("Expected oldest metrics dropped, size %d", size)
+	}
+	popped := popDisk(t, buf, "core", size)
+	if len(popped) != size {
+		t.Fatalf("Expected %d metrics, got %d", size, len(popped))
+	}
+	if popped[0].Value != float64(50-size) || popped[len(popped)-1].Value != 49 {
+		t.Errorf("Expected values %d..49, got %f..%f", 50-size, popped[0].Value, popped[len(popped)-1].Value)
+	}
+}
+
+func TestDiskBuffer_IntervalSync(t *testing.T) {
+	dir := t.TempDir()
+
+	buf, err := NewDiskBuffer(DiskConfig{Path: dir, SyncMode: SyncModeInterval})
+	if err != nil {
+		t.Fatalf("NewDiskBuffer failed: %v", err)
+	}
+	if err := buf.Push(newTestMetrics(0, 2)); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	if err := buf.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	buf, err = NewDiskBuffer(DiskConfig{Path: dir, SyncMode: SyncModeInterval})
+	if err != nil {
+		t.Fatalf("NewDiskBuffer failed: %v", err)
+	}
+	defer buf.Close()
+
+	if buf.Size() != 2 {
+		t.Errorf("Expected size 2, got %d", buf.Size())
+	}
+}
+
+func TestDiskBuffer_Close(t *testing.T) {
+	buf, err := NewDiskBuffer(DiskConfig{Path: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewDiskBuffer failed: %v", err)
+	}
+
+	if err := buf.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// 尝试推入数据应该失败
+	if err := buf.Push(newTestMetrics(0, 1)); err == nil {
+		t.Error("Expected error when pushing to closed buffer")
+	}
+}
+
+func TestNewDiskBuffer_InvalidSyncMode(t *testing.T) {
+	if _, err := NewDiskBuffer(DiskConfig{Path: t.TempDir(), SyncMode: "never"}); err == nil {
+		t.Error("Expected error for unknown sync mode")
+	}
+}
+
+func TestDiskBuffer_ConsumerCursors(t *testing.T) {
+	dir := t.TempDir()
+
+	buf, err := NewDiskBuffer(DiskConfig{Path: dir, SegmentBytes: 256, MaxBytes: 1 << 20})
+	if err != nil {
+		t.Fatalf("NewDiskBuffer failed: %v", err)
+	}
+	buf.SetConsumers([]string{"core", "prometheus"})
+
+	for i := 0; i < 10; i++ {
+		if err := buf.Push(newTestMetrics(i*2, 2)); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+	}
+
+	// core 全部确认，prometheus 只确认一部分，另一批发送失败
+	popDisk(t, buf, "core", 20)
+	popDisk(t, buf, "prometheus", 5)
+	failed, err := buf.Peek("prometheus", 5)
+	if err != nil || len(failed) != 5 {
+		t.Fatalf("Peek failed: %v", err)
+	}
+	if err := buf.Nack("prometheus"); err != nil {
+		t.Fatalf("Nack failed: %v", err)
+	}
+
+	// 数据保留到最慢的消费者确认
+	if buf.Size() != 15 {
+		t.Errorf("Expected size 15, got %d", buf.Size())
+	}
+	if err := buf.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// 重启后各消费者从自己的确认位置继续
+	buf, err = NewDiskBuffer(DiskConfig{Path: dir, SegmentBytes: 256, MaxBytes: 1 << 20})
+	if err != nil {
+		t.Fatalf("NewDiskBuffer failed: %v", err)
+	}
+	defer buf.Close()
+	buf.SetConsumers([]string{"core", "prometheus"})
+
+	if core, _ := buf.Peek("core", 20); len(core) != 0 {
+		t.Errorf("Expected no metrics for core after replay, got %d", len(core))
+	}
+
+	prom := popDisk(t, buf, "prometheus", 20)
+	if len(prom) != 15 || prom[0].Value != 5 || prom[14].Value != 19 {
+		t.Fatalf("Unexpected metrics for prometheus after replay: %d", len(prom))
+	}
+
+	// 所有消费者确认后只保留写入段
+	segments, _ := filepath.Glob(filepath.Join(dir, "*"+segmentExt))
+	if len(segments) != 1 {
+		t.Errorf("Expected 1 segment after all commits, got %d", len(segments))
+	}
+}
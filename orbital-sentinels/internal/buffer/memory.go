@@ -7,23 +7,30 @@ import (
 	"github.com/celestial/orbital-sentinels/internal/plugin"
 )
 
+// memoryCursor 消费者读取位置（全局序号）
+type memoryCursor struct {
+	committed uint64
+	read      uint64
+	dropped   uint64 // 已读取未确认、但因溢出被丢弃的数据量
+}
+
 // MemoryBuffer 内存缓冲实现
 type MemoryBuffer struct {
-	queue    []*plugin.Metric
-	maxSize  int
-	mu       sync.Mutex
-	notEmpty *sync.Cond
-	closed   bool
+	queue     []*plugin.Metric
+	head      uint64 // queue[0] 的序号
+	maxSize   int
+	consumers map[string]*memoryCursor
+	mu        sync.Mutex
+	closed    bool
 }
 
 // NewMemoryBuffer 创建内存缓冲
 func NewMemoryBuffer(maxSize int) *MemoryBuffer {
-	mb := &MemoryBuffer{
-		queue:   make([]*plugin.Metric, 0, maxSize),
-		maxSize: maxSize,
+	return &MemoryBuffer{
+		queue:     make([]*plugin.Metric, 0, maxSize),
+		maxSize:   maxSize,
+		consumers: make(map[string]*memoryCursor),
 	}
-	mb.notEmpty = sync.NewCond(&mb.mu)
-	return mb
 }
 
 // Push 推入数据
@@ -42,49 +49,110 @@ func (mb *MemoryBuffer) Push(metrics []*plugin.Metric) error {
 		if overflow > 0 {
 			if overflow >= len(mb.queue) {
 				// 如果溢出量大于等于当前队列长度，清空队列
-				mb.queue = mb.queue[:0]
-			} else {
-				// 否则删除最旧的数据
-				mb.queue = mb.queue[overflow:]
+				overflow = len(mb.queue)
 			}
+			mb.discard(overflow)
 		}
 	}
 
 	mb.queue = append(mb.queue, metrics...)
-	mb.notEmpty.Signal()
 
 	return nil
 }
 
-// Pop 弹出数据
-func (mb *MemoryBuffer) Pop(count int) ([]*plugin.Metric, error) {
+// SetConsumers 设置消费者
+func (mb *MemoryBuffer) SetConsumers(consumers []string) error {
 	mb.mu.Lock()
 	defer mb.mu.Unlock()
 
-	// 如果没有数据且未关闭，等待
-	for len(mb.queue) == 0 && !mb.closed {
-		mb.notEmpty.Wait()
+	keep := make(map[string]bool, len(consumers))
+	for _, name := range consumers {
+		keep[name] = true
+		if _, ok := mb.consumers[name]; !ok {
+			mb.consumers[name] = &memoryCursor{committed: mb.head, read: mb.head}
+		}
 	}
+	for name := range mb.consumers {
+		if !keep[name] {
+			delete(mb.consumers, name)
+		}
+	}
+
+	mb.trim()
+	return nil
+}
+
+// Peek 读取消费者尚未读取的数据
+func (mb *MemoryBuffer) Peek(consumer string, count int) ([]*plugin.Metric, error) {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
 
-	if mb.closed && len(mb.queue) == 0 {
+	if mb.closed {
 		return nil, fmt.Errorf("buffer is closed")
 	}
 
-	if count > len(mb.queue) {
-		count = len(mb.queue)
+	c, ok := mb.consumers[consumer]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownConsumer, consumer)
 	}
 
-	if count == 0 {
+	start := int(c.read - mb.head)
+	available := len(mb.queue) - start
+	if count > available {
+		count = available
+	}
+
+	if count <= 0 {
 		return []*plugin.Metric{}, nil
 	}
 
 	metrics := make([]*plugin.Metric, count)
-	copy(metrics, mb.queue[:count])
-	mb.queue = mb.queue[count:]
+	copy(metrics, mb.queue[start:start+count])
+	c.read += uint64(count)
 
 	return metrics, nil
 }
 
+// Commit 确认消费者最早读取的 count 条数据
+func (mb *MemoryBuffer) Commit(consumer string, count int) error {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+
+	c, ok := mb.consumers[consumer]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownConsumer, consumer)
+	}
+
+	if count < 0 {
+		return fmt.Errorf("invalid commit count %d", count)
+	}
+	// 溢出丢弃的在途数据已不在队列中，只确认仍在队列中的部分
+	skip := min(uint64(count), c.dropped)
+	if c.committed+uint64(count)-skip > c.read {
+		return fmt.Errorf("commit %d exceeds %d in-flight metrics", count, c.read-c.committed+c.dropped)
+	}
+	c.dropped -= skip
+	c.committed += uint64(count) - skip
+
+	mb.trim()
+	return nil
+}
+
+// Nack 退回消费者已读取但未确认的数据
+func (mb *MemoryBuffer) Nack(consumer string) error {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+
+	c, ok := mb.consumers[consumer]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownConsumer, consumer)
+	}
+	c.read = c.committed
+	c.dropped = 0
+
+	return nil
+}
+
 // Size 获取当前大小
 func (mb *MemoryBuffer) Size() int {
 	mb.mu.Lock()
@@ -99,7 +167,45 @@ func (mb *MemoryBuffer) Close() error {
 	defer mb.mu.Unlock()
 
 	mb.closed = true
-	mb.notEmpty.Broadcast()
 
 	return nil
 }
+
+// trim 移除所有消费者都已确认的数据，没有消费者时保留全部数据
+func (mb *MemoryBuffer) trim() {
+	if len(mb.consumers) == 0 {
+		return
+	}
+
+	min := mb.head + uint64(len(mb.queue))
+	for _, c := range mb.consumers {
+		if c.committed < min {
+			min = c.committed
+		}
+	}
+	mb.discard(int(min - mb.head))
+}
+
+// discard 移除最旧的 n 条数据，读取位置落在其中的消费者跳到新的队首
+func (mb *MemoryBuffer) discard(n int) {
+	if n <= 0 {
+		return
+	}
+
+	// 释放引用，避免底层数组持有已移除的指标
+	for i := 0; i < n; i++ {
+		mb.queue[i] = nil
+	}
+	mb.queue = mb.queue[n:]
+	mb.head += uint64(n)
+
+	for _, c := range mb.consumers {
+		if c.committed < mb.head {
+			c.dropped += min(c.read, mb.head) - c.committed
+			c.committed = mb.head
+		}
+		if c.read < mb.head {
+			c.read = mb.head
+		}
+	}
+}
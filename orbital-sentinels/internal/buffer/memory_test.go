@@ -6,8 +6,9 @@ import (
 	"github.com/celestial/orbital-sentinels/internal/plugin"
 )
 
-func TestMemoryBuffer_PushPop(t *testing.T) {
+func TestMemoryBuffer_PushPeek(t *testing.T) {
 	buf := NewMemoryBuffer(10)
+	buf.SetConsumers([]string{"core"})
 
 	// 测试推入数据
 	metrics := []*plugin.Metric{
@@ -25,10 +26,10 @@ func TestMemoryBuffer_PushPop(t *testing.T) {
 		t.Errorf("Expected size 2, got %d", buf.Size())
 	}
 
-	// 测试弹出数据
-	popped, err := buf.Pop(1)
+	// 测试读取数据
+	popped, err := buf.Peek("core", 1)
 	if err != nil {
-		t.Fatalf("Pop failed: %v", err)
+		t.Fatalf("Peek failed: %v", err)
 	}
 
 	if len(popped) != 1 {
@@ -39,6 +40,15 @@ func TestMemoryBuffer_PushPop(t *testing.T) {
 		t.Errorf("Expected test1, got %s", popped[0].Name)
 	}
 
+	// 确认前数据保留在缓冲区
+	if buf.Size() != 2 {
+		t.Errorf("Expected size 2 before commit, got %d", buf.Size())
+	}
+
+	if err := buf.Commit("core", 1); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
 	// 验证剩余大小
 	if buf.Size() != 1 {
 		t.Errorf("Expected size 1, got %d", buf.Size())
@@ -47,6 +57,7 @@ func TestMemoryBuffer_PushPop(t *testing.T) {
 
 func TestMemoryBuffer_Overflow(t *testing.T) {
 	buf := NewMemoryBuffer(5)
+	buf.SetConsumers([]string{"core"})
 
 	// 先推入 3 个数据
 	metrics1 := []*plugin.Metric{
@@ -77,10 +88,10 @@ func TestMemoryBuffer_Overflow(t *testing.T) {
 		t.Errorf("Expected size 5, got %d", buf.Size())
 	}
 
-	// 弹出所有数据，验证是最新的 5 个
-	popped, err := buf.Pop(5)
+	// 读取所有数据，验证是最新的 5 个
+	popped, err := buf.Peek("core", 5)
 	if err != nil {
-		t.Fatalf("Pop failed: %v", err)
+		t.Fatalf("Peek failed: %v", err)
 	}
 
 	if len(popped) != 5 {
@@ -98,6 +109,47 @@ func TestMemoryBuffer_Overflow(t *testing.T) {
 	}
 }
 
+func TestMemoryBuffer_OverflowInFlight(t *testing.T) {
+	buf := NewMemoryBuffer(5)
+	buf.SetConsumers([]string{"core"})
+
+	buf.Push([]*plugin.Metric{
+		{Name: "test", Value: 0.0},
+		{Name: "test", Value: 1.0},
+		{Name: "test", Value: 2.0},
+		{Name: "test", Value: 3.0},
+	})
+
+	inflight, _ := buf.Peek("core", 4)
+	if len(inflight) != 4 {
+		t.Fatalf("Expected 4 metrics, got %d", len(inflight))
+	}
+
+	// 发送期间溢出，丢弃在途批次中最旧的 3 个
+	buf.Push([]*plugin.Metric{
+		{Name: "test", Value: 4.0},
+		{Name: "test", Value: 5.0},
+		{Name: "test", Value: 6.0},
+		{Name: "test", Value: 7.0},
+	})
+
+	// 发送成功后确认整批数据
+	if err := buf.Commit("core", len(inflight)); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	rest, _ := buf.Peek("core", 10)
+	if len(rest) != 4 || rest[0].Value != 4.0 || rest[3].Value != 7.0 {
+		t.Errorf("Expected remaining [4.0 .. 7.0], got %v", rest)
+	}
+	if err := buf.Commit("core", len(rest)); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if buf.Size() != 0 {
+		t.Errorf("Expected size 0 after commit, got %d", buf.Size())
+	}
+}
+
 func TestMemoryBuffer_Close(t *testing.T) {
 	buf := NewMemoryBuffer(10)
 
@@ -114,3 +166,80 @@ func TestMemoryBuffer_Close(t *testing.T) {
 		t.Error("Expected error when pushing to closed buffer")
 	}
 }
+
+func TestMemoryBuffer_Nack(t *testing.T) {
+	buf := NewMemoryBuffer(10)
+	buf.SetConsumers([]string{"core"})
+
+	buf.Push([]*plugin.Metric{
+		{Name: "test", Value: 1.0},
+		{Name: "test", Value: 2.0},
+		{Name: "test", Value: 3.0},
+	})
+
+	first, _ := buf.Peek("core", 2)
+	if len(first) != 2 {
+		t.Fatalf("Expected 2 metrics, got %d", len(first))
+	}
+
+	// 未确认前继续读取下一批
+	next, _ := buf.Peek("core", 2)
+	if len(next) != 1 || next[0].Value != 3.0 {
+		t.Fatalf("Expected next batch [3.0], got %v", next)
+	}
+
+	// 退回后重新读取同一批数据，顺序不变
+	if err := buf.Nack("core"); err != nil {
+		t.Fatalf("Nack failed: %v", err)
+	}
+	retry, _ := buf.Peek("core", 2)
+	if len(retry) != 2 || retry[0].Value != 1.0 || retry[1].Value != 2.0 {
+		t.Errorf("Expected retried batch [1.0 2.0], got %v", retry)
+	}
+
+	// 确认数量不能超过已读取的数量
+	if err := buf.Commit("core", 3); err == nil {
+		t.Error("Expected error when committing more than in-flight")
+	}
+}
+
+func TestMemoryBuffer_MultipleConsumers(t *testing.T) {
+	buf := NewMemoryBuffer(10)
+	buf.SetConsumers([]string{"core", "prometheus"})
+
+	buf.Push([]*plugin.Metric{
+		{Name: "test", Value: 1.0},
+		{Name: "test", Value: 2.0},
+	})
+
+	// core 发送成功
+	core, _ := buf.Peek("core", 10)
+	buf.Commit("core", len(core))
+
+	// prometheus 发送失败，core 不会再收到同一批数据
+	prom, _ := buf.Peek("prometheus", 10)
+	buf.Nack("prometheus")
+
+	if again, _ := buf.Peek("core", 10); len(again) != 0 {
+		t.Errorf("Expected no duplicates for core, got %d", len(again))
+	}
+
+	// 数据保留到所有消费者确认
+	if buf.Size() != 2 {
+		t.Errorf("Expected size 2 until all consumers commit, got %d", buf.Size())
+	}
+
+	prom, _ = buf.Peek("prometheus", 10)
+	if len(prom) != 2 {
+		t.Fatalf("Expected 2 metrics for prometheus, got %d", len(prom))
+	}
+	buf.Commit("prometheus", len(prom))
+
+	if buf.Size() != 0 {
+		t.Errorf("Expected size 0 after all commits, got %d", buf.Size())
+	}
+
+	if _, err := buf.Peek("clickhouse", 1); err == nil {
+		t.Error("Expected error for unknown consumer")
+	}
+}
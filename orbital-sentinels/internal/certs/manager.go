@@ -0,0 +1,186 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// 证书文件名，与 credentials.yaml 保存在同一目录
+const (
+	CertFileName = "sentinel.crt"
+	KeyFileName  = "sentinel.key"
+	CAFileName   = "ca.crt"
+)
+
+// Manager 客户端证书管理器
+// 证书由中心端在注册或续期时签发，私钥只保存在本地
+type Manager struct {
+	dir  string
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+// NewManager 创建证书管理器，dir 为证书保存目录
+func NewManager(dir string) *Manager {
+	return &Manager{dir: dir}
+}
+
+// Load 加载本地证书，证书不存在时返回 nil
+func (m *Manager) Load() error {
+	certPEM, err := os.ReadFile(m.path(CertFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read certificate: %w", err)
+	}
+	keyPEM, err := os.ReadFile(m.path(KeyFileName))
+	if err != nil {
+		return fmt.Errorf("failed to read private key: %w", err)
+	}
+
+	cert, err := parseKeyPair(certPEM, keyPEM)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.cert = cert
+	m.mu.Unlock()
+	return nil
+}
+
+// NewCSR 生成新的私钥和证书签名请求
+// 中心端会以 Sentinel ID 作为证书主题，commonName 仅用于识别
+func (m *Manager) NewCSR(commonName string) (string, *ecdsa.PrivateKey, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate private key: %w", err)
+	}
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: commonName},
+	}, key)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create csr: %w", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})), key, nil
+}
+
+// Store 保存中心端签发的证书和对应的私钥，并立即用于后续连接
+func (m *Manager) Store(certPEM, caPEM string, key *ecdsa.PrivateKey) error {
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("failed to marshal private key: %w", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	// 写入前校验证书与私钥匹配
+	cert, err := parseKeyPair([]byte(certPEM), keyPEM)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	// 先写私钥再写证书，任一步中断时下次启动会因证书与私钥不匹配而重新申请
+	if err := writeFileAtomic(m.path(KeyFileName), keyPEM, 0600); err != nil {
+		return fmt.Errorf("failed to write private key: %w", err)
+	}
+	if err := writeFileAtomic(m.path(CertFileName), []byte(certPEM), 0644); err != nil {
+		return fmt.Errorf("failed to write certificate: %w", err)
+	}
+	if caPEM != "" {
+		if err := writeFileAtomic(m.path(CAFileName), []byte(caPEM), 0644); err != nil {
+			return fmt.Errorf("failed to write ca certificate: %w", err)
+		}
+	}
+
+	m.mu.Lock()
+	m.cert = cert
+	m.mu.Unlock()
+	return nil
+}
+
+// Leaf 返回当前证书，没有证书时返回 nil
+func (m *Manager) Leaf() *x509.Certificate {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.cert == nil {
+		return nil
+	}
+	return m.cert.Leaf
+}
+
+// NeedsRenewal 没有证书或已超过有效期的三分之二时需要续期
+func (m *Manager) NeedsRenewal(now time.Time) bool {
+	leaf := m.Leaf()
+	if leaf == nil {
+		return true
+	}
+	lifetime := leaf.NotAfter.Sub(leaf.NotBefore)
+	return now.After(leaf.NotBefore.Add(lifetime * 2 / 3))
+}
+
+// GetClientCertificate 用于 tls.Config，TLS 握手时返回当前证书
+// 没有证书或证书已过期时返回空证书，即不出示客户端证书，以便重新申请
+func (m *Manager) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.cert == nil || time.Now().After(m.cert.Leaf.NotAfter) {
+		return &tls.Certificate{}, nil
+	}
+	return m.cert, nil
+}
+
+// Dir 返回证书保存目录
+func (m *Manager) Dir() string {
+	return m.dir
+}
+
+func (m *Manager) path(name string) string {
+	return filepath.Join(m.dir, name)
+}
+
+// parseKeyPair 解析证书和私钥并校验二者匹配
+func parseKeyPair(certPEM, keyPEM []byte) (*tls.Certificate, error) {
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate: %w", err)
+	}
+	if cert.Leaf == nil {
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate: %w", err)
+		}
+		cert.Leaf = leaf
+	}
+	return &cert, nil
+}
+
+// writeFileAtomic 先写临时文件再重命名，避免写入中断导致文件损坏
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, perm); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
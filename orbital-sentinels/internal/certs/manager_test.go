@@ -0,0 +1,210 @@
+package certs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testCA 模拟中心端 CA
+type testCA struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM string
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate failed: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+
+	return &testCA{
+		cert:    cert,
+		key:     key,
+		certPEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+	}
+}
+
+// sign 按中心端的方式签发证书
+func (ca *testCA) sign(t *testing.T, csrPEM, sentinelID string, notBefore, notAfter time.Time) string {
+	t.Helper()
+
+	block, _ := pem.Decode([]byte(csrPEM))
+	if block == nil {
+		t.Fatal("Invalid csr pem")
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		t.Fatalf("ParseCertificateRequest failed: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: sentinelID, OrganizationalUnit: []string{"sentinel"}},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, csr.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("CreateCertificate failed: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestManager_StoreAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	m := NewManager(dir)
+
+	// 没有证书时加载成功，需要申请
+	if err := m.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !m.NeedsRenewal(time.Now()) {
+		t.Error("Expected renewal without certificate")
+	}
+
+	csr, key, err := m.NewCSR("host-1")
+	if err != nil {
+		t.Fatalf("NewCSR failed: %v", err)
+	}
+	now := time.Now()
+	certPEM := ca.sign(t, csr, "sentinel-1", now.Add(-time.Minute), now.Add(time.Hour))
+	if err := m.Store(certPEM, ca.certPEM, key); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	// 私钥只允许所有者读写
+	info, err := os.Stat(filepath.Join(dir, KeyFileName))
+	if err != nil {
+		t.Fatalf("Stat key failed: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected key mode 0600, got %o", info.Mode().Perm())
+	}
+	if _, err := os.Stat(filepath.Join(dir, CAFileName)); err != nil {
+		t.Errorf("Expected ca certificate saved: %v", err)
+	}
+
+	// 重启后从文件加载
+	loaded := NewManager(dir)
+	if err := loaded.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if leaf := loaded.Leaf(); leaf == nil || leaf.Subject.CommonName != "sentinel-1" {
+		t.Fatalf("Unexpected certificate after load: %v", leaf)
+	}
+	if loaded.NeedsRenewal(now) {
+		t.Error("Expected no renewal for fresh certificate")
+	}
+	cert, err := loaded.GetClientCertificate(nil)
+	if err != nil || len(cert.Certificate) == 0 {
+		t.Errorf("Expected client certificate, got %v", err)
+	}
+}
+
+func TestManager_StoreMismatchedKey(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	m := NewManager(dir)
+
+	csr, _, err := m.NewCSR("host-1")
+	if err != nil {
+		t.Fatalf("NewCSR failed: %v", err)
+	}
+	_, otherKey, _ := m.NewCSR("host-1")
+	now := time.Now()
+	certPEM := ca.sign(t, csr, "sentinel-1", now, now.Add(time.Hour))
+
+	// 证书与私钥不匹配时不写入文件
+	if err := m.Store(certPEM, ca.certPEM, otherKey); err == nil {
+		t.Fatal("Expected error for mismatched key")
+	}
+	if _, err := os.Stat(filepath.Join(dir, CertFileName)); !os.IsNotExist(err) {
+		t.Errorf("Expected no certificate written, got %v", err)
+	}
+}
+
+func TestManager_NeedsRenewal(t *testing.T) {
+	ca := newTestCA(t)
+	m := NewManager(t.TempDir())
+
+	csr, key, _ := m.NewCSR("host-1")
+	start := time.Now().Add(-time.Minute)
+	certPEM := ca.sign(t, csr, "sentinel-1", start, start.Add(3*time.Hour))
+	if err := m.Store(certPEM, "", key); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	// 超过有效期的三分之二后续期
+	if m.NeedsRenewal(start.Add(119 * time.Minute)) {
+		t.Error("Expected no renewal before two thirds of lifetime")
+	}
+	if !m.NeedsRenewal(start.Add(121 * time.Minute)) {
+		t.Error("Expected renewal after two thirds of lifetime")
+	}
+}
+
+func TestManager_Request(t *testing.T) {
+	ca := newTestCA(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/sentinels/certificate" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("X-Sentinel-ID") != "sentinel-1" || r.Header.Get("X-API-Token") != "token" {
+			t.Errorf("Missing sentinel auth headers")
+		}
+		var req struct {
+			CSR string `json:"csr"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		now := time.Now()
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code": 0,
+			"data": map[string]interface{}{
+				"certificate":    ca.sign(t, req.CSR, "sentinel-1", now.Add(-time.Minute), now.Add(time.Hour)),
+				"ca_certificate": ca.certPEM,
+			},
+		})
+	}))
+	defer server.Close()
+
+	m := NewManager(t.TempDir())
+	if err := m.Request(context.Background(), server.Client(), server.URL, "sentinel-1", "token"); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if leaf := m.Leaf(); leaf == nil || leaf.Subject.CommonName != "sentinel-1" {
+		t.Fatalf("Unexpected certificate: %v", leaf)
+	}
+}
@@ -0,0 +1,104 @@
+package certs
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// TLSOptions 连接中心端的 TLS 配置
+type TLSOptions struct {
+	CAFile             string // 校验中心端证书的 CA 文件，为空时使用系统证书
+	InsecureSkipVerify bool
+}
+
+// NewTransport 创建连接中心端的 HTTP Transport
+// m 不为空时在 TLS 握手中出示 Sentinel 客户端证书，证书续期后新连接自动使用新证书
+func NewTransport(opts TLSOptions, m *Manager) (*http.Transport, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: opts.InsecureSkipVerify,
+	}
+
+	if opts.CAFile != "" {
+		caPEM, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificate found in ca file: %s", opts.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if m != nil {
+		tlsConfig.GetClientCertificate = m.GetClientCertificate
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// certificateResponse 中心端签发证书的响应
+type certificateResponse struct {
+	Certificate   string `json:"certificate"`
+	CACertificate string `json:"ca_certificate"`
+}
+
+// Request 向中心端申请新证书并保存
+// 已有证书时通过当前连接出示，中心端据此确认续期请求来自证书持有者
+func (m *Manager) Request(ctx context.Context, client *http.Client, coreURL, sentinelID, apiToken string) error {
+	csr, key, err := m.NewCSR(sentinelID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(map[string]string{"csr": csr})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", coreURL+"/api/v1/sentinels/certificate", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentinel-ID", sentinelID)
+	req.Header.Set("X-API-Token", apiToken)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("certificate request failed: status=%d, body=%s", resp.StatusCode, string(body))
+	}
+
+	var apiResp struct {
+		Code int                 `json:"code"`
+		Data certificateResponse `json:"data"`
+		Msg  string              `json:"message"`
+	}
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if apiResp.Code != 0 {
+		return fmt.Errorf("certificate request failed: code=%d, message=%s", apiResp.Code, apiResp.Msg)
+	}
+
+	return m.Store(apiResp.Data.Certificate, apiResp.Data.CACertificate, key)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/celestial/orbital-sentinels/internal/pkg/logger"
@@ -19,6 +20,7 @@ type TaskClient struct {
 	coreURL    string
 	apiToken   string
 	sentinelID string
+	mu         sync.RWMutex
 }
 
 // NewTaskClient 创建任务客户端
@@ -33,6 +35,18 @@ func NewTaskClient(coreURL, apiToken, sentinelID string, timeout time.Duration)
 	}
 }
 
+// SetTransport 设置连接中心端使用的 Transport（TLS 配置）
+func (c *TaskClient) SetTransport(rt http.RoundTripper) {
+	c.client.Transport = rt
+}
+
+// SetAPIToken 更新 API Token（Token 轮换后调用）
+func (c *TaskClient) SetAPIToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.apiToken = token
+}
+
 // CoreTask 中心端返回的任务格式
 type CoreTask struct {
 	ID              uint                   `json:"id"`
@@ -71,7 +85,9 @@ func (c *TaskClient) GetTasks(ctx context.Context) ([]TaskWithInterval, error) {
 	}
 
 	req.Header.Set("X-Sentinel-ID", c.sentinelID)
+	c.mu.RLock()
 	req.Header.Set("X-API-Token", c.apiToken)
+	c.mu.RUnlock()
 
 	resp, err := c.client.Do(req)
 	if err != nil {
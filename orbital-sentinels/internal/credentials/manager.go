@@ -58,8 +58,13 @@ func (m *Manager) Save(creds *Credentials) error {
 		return fmt.Errorf("failed to marshal credentials: %w", err)
 	}
 
-	// 写入文件 (0600权限,仅所有者可读写)
-	if err := os.WriteFile(m.credentialsPath, data, 0600); err != nil {
+	// 先写临时文件再重命名,避免写入中断导致凭证丢失 (0600权限,仅所有者可读写)
+	tmpPath := m.credentialsPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write credentials: %w", err)
+	}
+	if err := os.Rename(tmpPath, m.credentialsPath); err != nil {
+		os.Remove(tmpPath)
 		return fmt.Errorf("failed to write credentials: %w", err)
 	}
 
@@ -67,6 +72,22 @@ func (m *Manager) Save(creds *Credentials) error {
 	return nil
 }
 
+// UpdateAPIToken 保存轮换后的 API Token
+// 尚未使用凭证文件时(Token 来自配置文件),以 base 为基础创建凭证文件
+func (m *Manager) UpdateAPIToken(token string, base *Credentials) error {
+	current := m.credentials
+	if current == nil {
+		current = base
+	}
+	if current == nil {
+		return fmt.Errorf("no credentials to update")
+	}
+
+	updated := *current
+	updated.APIToken = token
+	return m.Save(&updated)
+}
+
 // Delete 删除凭证
 func (m *Manager) Delete() error {
 	if err := os.Remove(m.credentialsPath); err != nil && !os.IsNotExist(err) {
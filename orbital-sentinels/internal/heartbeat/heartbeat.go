@@ -8,12 +8,41 @@ import (
 	"io"
 	"net/http"
 	"runtime"
+	"sync"
 	"time"
 
 	"github.com/celestial/orbital-sentinels/internal/pkg/logger"
 	"go.uber.org/zap"
 )
 
+// 控制命令类型
+const (
+	CommandReloadConfig      = "reload_config"
+	CommandRestartScheduler  = "restart_scheduler"
+	CommandRefetchTasks      = "refetch_tasks"
+	CommandRunTask           = "run_task"
+	CommandRotateToken       = "rotate_token"
+	CommandUploadDiagnostics = "upload_diagnostics"
+	CommandShutdown          = "shutdown"
+)
+
+// 命令执行结果状态
+const (
+	CommandStatusSucceeded = "succeeded"
+	CommandStatusFailed    = "failed"
+)
+
+const (
+	// commandQueueSize 待执行命令队列长度
+	commandQueueSize = 32
+	// maxPendingResults 待上报结果的最大数量，超出时丢弃最旧的结果
+	maxPendingResults = 100
+)
+
+// CommandHandler 命令处理函数
+// 返回执行结果；after 不为空时在结果上报给中心端之后执行（如停止采集端）
+type CommandHandler func(ctx context.Context, cmd *Command) (result map[string]interface{}, after func(), err error)
+
 // Manager 心跳管理器
 type Manager struct {
 	client         *http.Client
@@ -27,25 +56,52 @@ type Manager struct {
 	ctx            context.Context
 	cancel         context.CancelFunc
 	onConfigUpdate func(version int)
+	onCommand      CommandHandler
+	pluginVersions func() map[string]string
+
+	mu       sync.Mutex
+	sendMu   sync.Mutex // 保证同一时间只有一个心跳在发送，避免重复上报结果
+	results  []CommandResult
+	commands chan *Command
+	trigger  chan struct{}
 }
 
 // HeartbeatRequest 心跳请求
 type HeartbeatRequest struct {
-	SentinelID    string  `json:"sentinel_id"`
-	CPUUsage      float64 `json:"cpu_usage"`
-	MemoryUsage   float64 `json:"memory_usage"`
-	DiskUsage     float64 `json:"disk_usage"`
-	TaskCount     int     `json:"task_count"`
-	PluginCount   int     `json:"plugin_count"`
-	UptimeSeconds int64   `json:"uptime_seconds"`
-	Version       string  `json:"version"`
+	SentinelID     string            `json:"sentinel_id"`
+	CPUUsage       float64           `json:"cpu_usage"`
+	MemoryUsage    float64           `json:"memory_usage"`
+	DiskUsage      float64           `json:"disk_usage"`
+	TaskCount      int               `json:"task_count"`
+	PluginCount    int               `json:"plugin_count"`
+	UptimeSeconds  int64             `json:"uptime_seconds"`
+	Version        string            `json:"version"`
+	Plugins        map[string]string `json:"plugins,omitempty"` // 已加载插件及版本
+	AcceptCommands bool              `json:"accept_commands"`
+	CommandResults []CommandResult   `json:"command_results,omitempty"`
 }
 
 // HeartbeatResponse 心跳响应
 type HeartbeatResponse struct {
-	Status        string   `json:"status"`
-	ConfigVersion int      `json:"config_version"`
-	Commands      []string `json:"commands"`
+	Status        string     `json:"status"`
+	ConfigVersion int        `json:"config_version"`
+	Commands      []*Command `json:"commands"`
+}
+
+// Command 中心端下发的控制命令
+type Command struct {
+	ID        uint                   `json:"id"`
+	Type      string                 `json:"type"`
+	Payload   map[string]interface{} `json:"payload"`
+	ExpiresAt time.Time              `json:"expires_at"`
+}
+
+// CommandResult 命令执行结果，随下一次心跳上报
+type CommandResult struct {
+	ID     uint                   `json:"id"`
+	Status string                 `json:"status"`
+	Result map[string]interface{} `json:"result,omitempty"`
+	Error  string                 `json:"error,omitempty"`
 }
 
 // NewManager 创建心跳管理器
@@ -65,6 +121,8 @@ func NewManager(
 		timeout:    timeout,
 		retryTimes: retryTimes,
 		metrics:    NewSystemMetrics(),
+		commands:   make(chan *Command, commandQueueSize),
+		trigger:    make(chan struct{}, 1),
 	}
 }
 
@@ -73,11 +131,44 @@ func (m *Manager) SetConfigUpdateHandler(handler func(version int)) {
 	m.onConfigUpdate = handler
 }
 
+// SetCommandHandler 设置命令处理器，设置后心跳会领取中心端下发的控制命令
+func (m *Manager) SetCommandHandler(handler CommandHandler) {
+	m.onCommand = handler
+}
+
+// SetPluginVersionsProvider 设置插件版本来源，心跳上报已加载插件及版本
+func (m *Manager) SetPluginVersionsProvider(provider func() map[string]string) {
+	m.pluginVersions = provider
+}
+
+// Trigger 立即发送一次心跳（如插件热加载后上报新版本）
+func (m *Manager) Trigger() {
+	select {
+	case m.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// SetTransport 设置连接中心端使用的 Transport（TLS 配置），需在 Start 之前调用
+func (m *Manager) SetTransport(rt http.RoundTripper) {
+	m.client.Transport = rt
+}
+
+// SetAPIToken 更新 API Token（Token 轮换后调用）
+func (m *Manager) SetAPIToken(token string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.apiToken = token
+}
+
 // Start 启动心跳
 func (m *Manager) Start(ctx context.Context) {
 	m.ctx, m.cancel = context.WithCancel(ctx)
 
 	go m.heartbeatLoop()
+	if m.onCommand != nil {
+		go m.commandLoop()
+	}
 
 	logger.Info("Heartbeat started", zap.Duration("interval", m.interval))
 }
@@ -103,14 +194,114 @@ func (m *Manager) heartbeatLoop() {
 		select {
 		case <-ticker.C:
 			m.sendHeartbeat()
+		case <-m.trigger:
+			// 命令执行完成后立即上报结果
+			m.sendHeartbeat()
 		case <-m.ctx.Done():
 			return
 		}
 	}
 }
 
+// commandLoop 按下发顺序依次执行命令
+func (m *Manager) commandLoop() {
+	for {
+		select {
+		case cmd := <-m.commands:
+			after := m.execute(cmd)
+			if after != nil {
+				// 先同步上报结果，再执行后续动作
+				m.sendHeartbeat()
+				after()
+				continue
+			}
+			select {
+			case m.trigger <- struct{}{}:
+			default:
+			}
+		case <-m.ctx.Done():
+			return
+		}
+	}
+}
+
+// execute 执行单个命令并记录结果
+func (m *Manager) execute(cmd *Command) (after func()) {
+	logger.Info("Executing command",
+		zap.Uint("command_id", cmd.ID),
+		zap.String("type", cmd.Type))
+
+	result := CommandResult{ID: cmd.ID, Status: CommandStatusSucceeded}
+	if !cmd.ExpiresAt.IsZero() && time.Now().After(cmd.ExpiresAt) {
+		result.Status = CommandStatusFailed
+		result.Error = "command expired before execution"
+		m.addResult(result)
+		return nil
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				result.Status = CommandStatusFailed
+				result.Error = fmt.Sprintf("command panic: %v", r)
+				after = nil
+			}
+		}()
+
+		var err error
+		result.Result, after, err = m.onCommand(m.ctx, cmd)
+		if err != nil {
+			result.Status = CommandStatusFailed
+			result.Error = err.Error()
+		}
+	}()
+
+	if result.Status == CommandStatusFailed {
+		logger.Warn("Command failed",
+			zap.Uint("command_id", cmd.ID),
+			zap.String("type", cmd.Type),
+			zap.String("error", result.Error))
+	} else {
+		logger.Info("Command succeeded",
+			zap.Uint("command_id", cmd.ID),
+			zap.String("type", cmd.Type))
+	}
+
+	m.addResult(result)
+	return after
+}
+
+// addResult 记录待上报的结果
+func (m *Manager) addResult(result CommandResult) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.results = append(m.results, result)
+	if len(m.results) > maxPendingResults {
+		m.results = m.results[len(m.results)-maxPendingResults:]
+	}
+}
+
+// enqueueCommands 将下发的命令放入执行队列，队列已满时直接记为失败
+func (m *Manager) enqueueCommands(commands []*Command) {
+	for _, cmd := range commands {
+		select {
+		case m.commands <- cmd:
+		default:
+			m.addResult(CommandResult{
+				ID:     cmd.ID,
+				Status: CommandStatusFailed,
+				Error:  "command queue is full",
+			})
+		}
+	}
+}
+
 // sendHeartbeat 发送心跳
 func (m *Manager) sendHeartbeat() {
+	m.sendMu.Lock()
+	defer m.sendMu.Unlock()
+
 	ctx, cancel := context.WithTimeout(m.ctx, m.timeout)
 	defer cancel()
 
@@ -128,15 +319,34 @@ func (m *Manager) sendHeartbeat() {
 		UptimeSeconds: metrics.UptimeSeconds,
 		Version:       "1.0.0", // TODO: 从配置或编译时注入
 	}
+	if m.pluginVersions != nil {
+		req.Plugins = m.pluginVersions()
+		req.PluginCount = len(req.Plugins)
+	}
+
+	// 附带待上报的命令结果
+	m.mu.Lock()
+	req.AcceptCommands = m.onCommand != nil
+	req.CommandResults = append([]CommandResult(nil), m.results...)
+	m.mu.Unlock()
 
 	// 发送请求
 	resp, err := m.sendRequest(ctx, req)
 	if err != nil {
-		// 中心端不可用时只记录警告，不影响采集端运行
+		// 中心端不可用时只记录警告，不影响采集端运行，结果留到下次心跳再上报
 		logger.Warn("Failed to send heartbeat (core may be unavailable)", zap.Error(err))
 		return
 	}
 
+	// 上报成功后移除已上报的结果（发送期间新增的结果保留）
+	if len(req.CommandResults) > 0 {
+		m.removeResults(req.CommandResults)
+	}
+
+	if len(resp.Commands) > 0 && m.onCommand != nil {
+		m.enqueueCommands(resp.Commands)
+	}
+
 	// 处理响应
 	if m.onConfigUpdate != nil && resp.ConfigVersion > 0 {
 		m.onConfigUpdate(resp.ConfigVersion)
@@ -145,6 +355,24 @@ func (m *Manager) sendHeartbeat() {
 	logger.Debug("Heartbeat sent successfully")
 }
 
+// removeResults 移除已上报的结果
+func (m *Manager) removeResults(sent []CommandResult) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	reported := make(map[uint]bool, len(sent))
+	for _, r := range sent {
+		reported[r.ID] = true
+	}
+	remaining := m.results[:0]
+	for _, r := range m.results {
+		if !reported[r.ID] {
+			remaining = append(remaining, r)
+		}
+	}
+	m.results = remaining
+}
+
 // sendRequest 发送请求
 func (m *Manager) sendRequest(ctx context.Context, req *HeartbeatRequest) (*HeartbeatResponse, error) {
 	data, err := json.Marshal(req)
@@ -164,7 +392,9 @@ func (m *Manager) sendRequest(ctx context.Context, req *HeartbeatRequest) (*Hear
 
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("X-Sentinel-ID", m.sentinelID)
+	m.mu.Lock()
 	httpReq.Header.Set("X-API-Token", m.apiToken)
+	m.mu.Unlock()
 
 	httpResp, err := m.client.Do(httpReq)
 	if err != nil {
@@ -0,0 +1,215 @@
+package heartbeat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeCore 模拟中心端心跳接口：第一次心跳下发命令，记录之后上报的结果
+type fakeCore struct {
+	mu       sync.Mutex
+	commands []*Command
+	requests []HeartbeatRequest
+	tokens   []string
+	results  chan CommandResult
+}
+
+func newFakeCore(commands ...*Command) *fakeCore {
+	return &fakeCore{
+		commands: commands,
+		results:  make(chan CommandResult, 10),
+	}
+}
+
+func (f *fakeCore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req HeartbeatRequest
+	json.NewDecoder(r.Body).Decode(&req)
+
+	f.mu.Lock()
+	f.requests = append(f.requests, req)
+	f.tokens = append(f.tokens, r.Header.Get("X-API-Token"))
+	var commands []*Command
+	if req.AcceptCommands {
+		commands, f.commands = f.commands, nil
+	}
+	f.mu.Unlock()
+
+	for _, result := range req.CommandResults {
+		f.results <- result
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"code": 0,
+		"data": HeartbeatResponse{Status: "ok", ConfigVersion: 1, Commands: commands},
+	})
+}
+
+func (f *fakeCore) waitResult(t *testing.T) CommandResult {
+	t.Helper()
+
+	select {
+	case result := <-f.results:
+		return result
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for command result")
+		return CommandResult{}
+	}
+}
+
+func TestManager_CommandResultsReported(t *testing.T) {
+	core := newFakeCore(
+		&Command{ID: 1, Type: CommandRunTask, Payload: map[string]interface{}{"task_id": "t1"}},
+		&Command{ID: 2, Type: CommandRefetchTasks},
+	)
+	server := httptest.NewServer(core)
+	defer server.Close()
+
+	m := NewManager(server.URL, "token", "s1", time.Hour, time.Second, 0)
+	m.SetCommandHandler(func(ctx context.Context, cmd *Command) (map[string]interface{}, func(), error) {
+		if cmd.Type == CommandRefetchTasks {
+			return nil, nil, errors.New("core unavailable")
+		}
+		return map[string]interface{}{"task_id": cmd.Payload["task_id"]}, nil, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m.Start(ctx)
+	defer m.Stop()
+
+	// 命令按下发顺序执行，结果在之后的心跳中上报
+	first := core.waitResult(t)
+	if first.ID != 1 || first.Status != CommandStatusSucceeded || first.Result["task_id"] != "t1" {
+		t.Errorf("Unexpected result for command 1: %+v", first)
+	}
+
+	second := core.waitResult(t)
+	if second.ID != 2 || second.Status != CommandStatusFailed || second.Error != "core unavailable" {
+		t.Errorf("Unexpected result for command 2: %+v", second)
+	}
+
+	core.mu.Lock()
+	defer core.mu.Unlock()
+	if !core.requests[0].AcceptCommands {
+		t.Error("Expected heartbeat to accept commands")
+	}
+}
+
+func TestManager_AfterRunsAfterReport(t *testing.T) {
+	core := newFakeCore(&Command{ID: 7, Type: CommandShutdown})
+	server := httptest.NewServer(core)
+	defer server.Close()
+
+	m := NewManager(server.URL, "token", "s1", time.Hour, time.Second, 0)
+	stopped := make(chan int, 1)
+	m.SetCommandHandler(func(ctx context.Context, cmd *Command) (map[string]interface{}, func(), error) {
+		return nil, func() {
+			// 后续动作执行时结果已经上报
+			stopped <- len(core.results)
+		}, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m.Start(ctx)
+	defer m.Stop()
+
+	select {
+	case reported := <-stopped:
+		if reported != 1 {
+			t.Errorf("Expected result reported before after(), got %d", reported)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for after()")
+	}
+}
+
+func TestManager_ExpiredCommand(t *testing.T) {
+	m := NewManager("", "token", "s1", time.Hour, time.Second, 0)
+	called := false
+	m.SetCommandHandler(func(ctx context.Context, cmd *Command) (map[string]interface{}, func(), error) {
+		called = true
+		return nil, nil, nil
+	})
+	m.ctx = context.Background()
+
+	m.execute(&Command{ID: 3, Type: CommandReloadConfig, ExpiresAt: time.Now().Add(-time.Minute)})
+
+	if called {
+		t.Error("Expected expired command not to be executed")
+	}
+	if len(m.results) != 1 || m.results[0].Status != CommandStatusFailed {
+		t.Errorf("Expected failed result, got %+v", m.results)
+	}
+}
+
+func TestManager_SetAPIToken(t *testing.T) {
+	core := newFakeCore()
+	server := httptest.NewServer(core)
+	defer server.Close()
+
+	m := NewManager(server.URL, "old", "s1", time.Hour, time.Second, 0)
+	m.ctx = context.Background()
+
+	m.sendHeartbeat()
+	m.SetAPIToken("new")
+	m.sendHeartbeat()
+
+	core.mu.Lock()
+	defer core.mu.Unlock()
+	if len(core.tokens) != 2 || core.tokens[0] != "old" || core.tokens[1] != "new" {
+		t.Errorf("Unexpected tokens: %v", core.tokens)
+	}
+}
+
+func TestManager_PluginVersionsReported(t *testing.T) {
+	core := newFakeCore()
+	server := httptest.NewServer(core)
+	defer server.Close()
+
+	var mu sync.Mutex
+	versions := map[string]string{"ping": "1.1.0"}
+	m := NewManager(server.URL, "token", "s1", time.Hour, time.Second, 0)
+	m.SetPluginVersionsProvider(func() map[string]string {
+		mu.Lock()
+		defer mu.Unlock()
+		return versions
+	})
+	m.Start(context.Background())
+	defer m.Stop()
+
+	// 热加载后立即上报新版本
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	versions = map[string]string{"ping": "1.1.0", "vendor-x": "2.0.0"}
+	mu.Unlock()
+	m.Trigger()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		core.mu.Lock()
+		n := len(core.requests)
+		var last HeartbeatRequest
+		if n > 0 {
+			last = core.requests[n-1]
+		}
+		core.mu.Unlock()
+
+		if n >= 2 {
+			if last.PluginCount != 2 || last.Plugins["vendor-x"] != "2.0.0" {
+				t.Errorf("Unexpected plugins: count=%d plugins=%v", last.PluginCount, last.Plugins)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected 2 heartbeats, got %d", n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,45 @@
+package listener
+
+import (
+	"strings"
+	"time"
+)
+
+// 事件类型
+const (
+	EventTypeTrap   = "trap"
+	EventTypeSyslog = "syslog"
+)
+
+// Event 归一化的设备事件，字段名只包含字母、数字和下划线，可直接用于中心端事件规则匹配
+type Event struct {
+	Type      string            `json:"type"`
+	DeviceID  string            `json:"device_id,omitempty"`
+	SourceIP  string            `json:"source_ip"`
+	Timestamp time.Time         `json:"timestamp"`
+	Severity  string            `json:"severity,omitempty"`
+	Message   string            `json:"message"`
+	Fields    map[string]string `json:"fields,omitempty"`
+}
+
+// Handler 事件处理函数
+type Handler func(ev *Event)
+
+// fieldName 将任意字符串转换为合法的字段名
+func fieldName(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,218 @@
+package listener
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/celestial/orbital-sentinels/internal/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Forwarder 将设备事件批量转发到中心端
+// 事件先进入有界队列，队列满或中心端长时间不可用时丢弃新事件，避免事件风暴占满内存。
+type Forwarder struct {
+	client        *http.Client
+	url           string
+	sentinelID    string
+	token         string
+	tokenMu       sync.RWMutex
+	batchSize     int
+	flushInterval time.Duration
+
+	queue   chan *Event
+	pending []*Event  // 发送失败等待重试的事件
+	retryAt time.Time // 发送失败后在此之前不再请求中心端
+	dropped atomic.Int64
+	sent    atomic.Int64
+	wg      sync.WaitGroup
+}
+
+// NewForwarder 创建事件转发器
+func NewForwarder(url, sentinelID, token string, queueSize, batchSize int, flushInterval, timeout time.Duration) *Forwarder {
+	if queueSize <= 0 {
+		queueSize = 10000
+	}
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	if flushInterval <= 0 {
+		flushInterval = time.Second
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &Forwarder{
+		client:        &http.Client{Timeout: timeout},
+		url:           url,
+		sentinelID:    sentinelID,
+		token:         token,
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		queue:         make(chan *Event, queueSize),
+	}
+}
+
+// SetTransport 设置连接中心端使用的 Transport（TLS 配置）
+func (f *Forwarder) SetTransport(rt http.RoundTripper) {
+	f.client.Transport = rt
+}
+
+// SetToken 更新 API Token（Token 轮换后调用）
+func (f *Forwarder) SetToken(token string) {
+	f.tokenMu.Lock()
+	defer f.tokenMu.Unlock()
+	f.token = token
+}
+
+// Enqueue 加入待发送队列，队列已满时丢弃
+func (f *Forwarder) Enqueue(ev *Event) {
+	select {
+	case f.queue <- ev:
+	default:
+		if f.dropped.Add(1)%1000 == 1 {
+			logger.Warn("Event queue is full, dropping events", zap.Int64("dropped", f.dropped.Load()))
+		}
+	}
+}
+
+// Stats 返回已发送和丢弃的事件数
+func (f *Forwarder) Stats() (sent, dropped int64) {
+	return f.sent.Load(), f.dropped.Load()
+}
+
+// Start 启动发送循环，ctx 取消后尽力发送剩余事件
+func (f *Forwarder) Start(ctx context.Context) {
+	f.wg.Add(1)
+	go f.run(ctx)
+}
+
+// Wait 等待发送循环退出
+func (f *Forwarder) Wait() {
+	f.wg.Wait()
+}
+
+// run 发送循环
+func (f *Forwarder) run(ctx context.Context) {
+	defer f.wg.Done()
+
+	ticker := time.NewTicker(f.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Event, 0, f.batchSize)
+	for {
+		select {
+		case ev := <-f.queue:
+			batch = append(batch, ev)
+			if len(batch) >= f.batchSize {
+				batch = f.flush(ctx, batch)
+			}
+		case <-ticker.C:
+			batch = f.flush(ctx, batch)
+		case <-ctx.Done():
+			for {
+				select {
+				case ev := <-f.queue:
+					batch = append(batch, ev)
+					continue
+				default:
+				}
+				break
+			}
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			f.flush(shutdownCtx, batch)
+			cancel()
+			return
+		}
+	}
+}
+
+// retryInterval 发送失败后的重试间隔
+const retryInterval = 10 * time.Second
+
+// flush 发送待重试事件和当前批次，返回清空后的批次
+func (f *Forwarder) flush(ctx context.Context, batch []*Event) []*Event {
+	events := append(f.pending, batch...)
+	f.pending = nil
+	if len(events) == 0 {
+		return batch[:0]
+	}
+	if time.Now().Before(f.retryAt) {
+		f.retain(events)
+		return batch[:0]
+	}
+
+	for len(events) > 0 {
+		n := len(events)
+		if n > f.batchSize {
+			n = f.batchSize
+		}
+		if err := f.send(ctx, events[:n]); err != nil {
+			logger.Warn("Failed to forward events to core", zap.Int("events", len(events)), zap.Error(err))
+			f.retain(events)
+			f.retryAt = time.Now().Add(retryInterval)
+			break
+		}
+		events = events[n:]
+	}
+
+	return batch[:0]
+}
+
+// retain 保留发送失败的事件，超过队列容量的最旧事件被丢弃
+func (f *Forwarder) retain(events []*Event) {
+	limit := cap(f.queue)
+	if len(events) > limit {
+		f.dropped.Add(int64(len(events) - limit))
+		events = events[len(events)-limit:]
+	}
+	f.pending = append([]*Event(nil), events...)
+}
+
+// send 发送一批事件
+func (f *Forwarder) send(ctx context.Context, events []*Event) error {
+	data, err := json.Marshal(map[string]interface{}{
+		"events": events,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal events: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", f.url+"/api/v1/data/events", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentinel-ID", f.sentinelID)
+	f.tokenMu.RLock()
+	req.Header.Set("X-API-Token", f.token)
+	f.tokenMu.RUnlock()
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		// 请求本身无效时重试没有意义，直接丢弃
+		if resp.StatusCode == http.StatusBadRequest {
+			f.dropped.Add(int64(len(events)))
+			logger.Warn("Core rejected events", zap.Int("events", len(events)), zap.String("body", string(body)))
+			return nil
+		}
+		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	f.sent.Add(int64(len(events)))
+	logger.Debug("Forwarded events to core", zap.Int("events", len(events)))
+	return nil
+}
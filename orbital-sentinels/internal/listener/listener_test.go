@@ -0,0 +1,304 @@
+package listener
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/celestial/orbital-sentinels/internal/plugin"
+	"github.com/gosnmp/gosnmp"
+)
+
+func TestParseSyslogRFC3164(t *testing.T) {
+	received := time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)
+	ev, err := parseSyslog([]byte("<187>Mar  1 10:02:03 core-sw1 sshd[812]: Failed password for root\n"), received)
+	if err != nil {
+		t.Fatalf("parseSyslog failed: %v", err)
+	}
+
+	if ev.Type != EventTypeSyslog || ev.Severity != "err" {
+		t.Errorf("type=%s severity=%s, want syslog err", ev.Type, ev.Severity)
+	}
+	want := map[string]string{
+		"facility": "local7",
+		"severity": "err",
+		"format":   "rfc3164",
+		"hostname": "core-sw1",
+		"app_name": "sshd",
+		"proc_id":  "812",
+	}
+	for k, v := range want {
+		if ev.Fields[k] != v {
+			t.Errorf("field %s = %q, want %q", k, ev.Fields[k], v)
+		}
+	}
+	if ev.Message != "Failed password for root" {
+		t.Errorf("message = %q", ev.Message)
+	}
+	if ev.Timestamp.Month() != time.March || ev.Timestamp.Hour() != 10 {
+		t.Errorf("timestamp = %v", ev.Timestamp)
+	}
+}
+
+func TestParseSyslogRFC5424(t *testing.T) {
+	msg := `<165>1 2026-03-01T10:02:03.5Z fw01 firewalld 77 ID47 [origin ip="10.0.0.1" software="fw\"os"] ` + "\ufeff" + `Rule updated`
+	ev, err := parseSyslog([]byte(msg), time.Now())
+	if err != nil {
+		t.Fatalf("parseSyslog failed: %v", err)
+	}
+
+	want := map[string]string{
+		"facility":           "local4",
+		"severity":           "notice",
+		"format":             "rfc5424",
+		"hostname":           "fw01",
+		"app_name":           "firewalld",
+		"proc_id":            "77",
+		"msg_id":             "ID47",
+		"sd_origin_ip":       "10.0.0.1",
+		"sd_origin_software": `fw"os`,
+	}
+	for k, v := range want {
+		if ev.Fields[k] != v {
+			t.Errorf("field %s = %q, want %q", k, ev.Fields[k], v)
+		}
+	}
+	if ev.Message != "Rule updated" {
+		t.Errorf("message = %q", ev.Message)
+	}
+	if !ev.Timestamp.Equal(time.Date(2026, 3, 1, 10, 2, 3, 5e8, time.UTC)) {
+		t.Errorf("timestamp = %v", ev.Timestamp)
+	}
+}
+
+func TestParseSyslogMnemonic(t *testing.T) {
+	ev, err := parseSyslog([]byte("<189>123: *Mar  1 10:02:03: %LINEPROTO-5-UPDOWN: Line protocol on Interface Gi0/1, changed state to down"), time.Now())
+	if err != nil {
+		t.Fatalf("parseSyslog failed: %v", err)
+	}
+
+	if ev.Fields["mnemonic"] != "LINEPROTO-5-UPDOWN" || ev.Fields["mnemonic_name"] != "UPDOWN" || ev.Fields["mnemonic_facility"] != "LINEPROTO" {
+		t.Errorf("mnemonic fields = %v", ev.Fields)
+	}
+}
+
+func TestParseSyslogInvalid(t *testing.T) {
+	for _, msg := range []string{"no priority", "<>x", "<999>x", "<abc>x"} {
+		if _, err := parseSyslog([]byte(msg), time.Now()); err == nil {
+			t.Errorf("parseSyslog(%q) should fail", msg)
+		}
+	}
+}
+
+func TestReadFrame(t *testing.T) {
+	input := "9 <13>hello<13>line one\n<13>line two\r\n"
+	reader := bufio.NewReader(strings.NewReader(input))
+
+	want := []string{"<13>hello", "<13>line one", "<13>line two"}
+	for _, w := range want {
+		frame, err := readFrame(reader)
+		if err != nil {
+			t.Fatalf("readFrame failed: %v", err)
+		}
+		if string(frame) != w {
+			t.Errorf("frame = %q, want %q", frame, w)
+		}
+	}
+	if _, err := readFrame(reader); err == nil {
+		t.Error("expected EOF after last frame")
+	}
+}
+
+func TestReadFrameTooLarge(t *testing.T) {
+	reader := bufio.NewReader(strings.NewReader("9999999 <13>x"))
+	if _, err := readFrame(reader); err == nil {
+		t.Error("expected error for oversized frame")
+	}
+}
+
+func TestTrapEventV2c(t *testing.T) {
+	pkt := &gosnmp.SnmpPacket{
+		Version:   gosnmp.Version2c,
+		Community: "public",
+		Variables: []gosnmp.SnmpPDU{
+			{Name: ".1.3.6.1.2.1.1.3.0", Type: gosnmp.TimeTicks, Value: uint32(12345)},
+			{Name: ".1.3.6.1.6.3.1.1.4.1.0", Type: gosnmp.ObjectIdentifier, Value: ".1.3.6.1.6.3.1.1.5.3"},
+			{Name: ".1.3.6.1.2.1.2.2.1.1.7", Type: gosnmp.Integer, Value: 7},
+			{Name: ".1.3.6.1.2.1.2.2.1.8.7", Type: gosnmp.Integer, Value: 2},
+			{Name: ".1.3.6.1.2.1.31.1.1.1.1.7", Type: gosnmp.OctetString, Value: []byte("Gi0/7")},
+			{Name: ".1.3.6.1.4.1.9.9.999.1", Type: gosnmp.OctetString, Value: []byte{0x00, 0xff}},
+		},
+	}
+
+	ev := trapEvent(pkt, net.ParseIP("10.0.0.5"), time.Now())
+
+	want := map[string]string{
+		"version":                   "2c",
+		"trap_oid":                  "1.3.6.1.6.3.1.1.5.3",
+		"trap_name":                 "linkDown",
+		"sys_uptime":                "12345",
+		"ifIndex":                   "7",
+		"ifOperStatus":              "2",
+		"ifName":                    "Gi0/7",
+		"oid_1_3_6_1_4_1_9_9_999_1": "00ff",
+	}
+	for k, v := range want {
+		if ev.Fields[k] != v {
+			t.Errorf("field %s = %q, want %q", k, ev.Fields[k], v)
+		}
+	}
+	if ev.Severity != "warning" || ev.SourceIP != "10.0.0.5" {
+		t.Errorf("severity=%s source=%s", ev.Severity, ev.SourceIP)
+	}
+	if ev.Message != "linkDown ifIndex=7 ifName=Gi0/7 ifOperStatus=2" {
+		t.Errorf("message = %q", ev.Message)
+	}
+}
+
+func TestTrapEventV1(t *testing.T) {
+	generic := &gosnmp.SnmpPacket{
+		Version: gosnmp.Version1,
+		SnmpTrap: gosnmp.SnmpTrap{
+			Enterprise:   ".1.3.6.1.4.1.9",
+			AgentAddress: "192.168.1.1",
+			GenericTrap:  0,
+			Timestamp:    100,
+		},
+	}
+	ev := trapEvent(generic, net.ParseIP("10.0.0.9"), time.Now())
+	if ev.Fields["trap_oid"] != "1.3.6.1.6.3.1.1.5.1" || ev.Fields["trap_name"] != "coldStart" {
+		t.Errorf("generic trap fields = %v", ev.Fields)
+	}
+	if ev.Fields["agent_address"] != "192.168.1.1" || ev.Fields["sys_uptime"] != "100" {
+		t.Errorf("v1 header fields = %v", ev.Fields)
+	}
+
+	specific := &gosnmp.SnmpPacket{
+		Version: gosnmp.Version1,
+		SnmpTrap: gosnmp.SnmpTrap{
+			Enterprise:   ".1.3.6.1.4.1.9",
+			GenericTrap:  6,
+			SpecificTrap: 42,
+		},
+	}
+	ev = trapEvent(specific, net.ParseIP("10.0.0.9"), time.Now())
+	if ev.Fields["trap_oid"] != "1.3.6.1.4.1.9.0.42" || ev.Fields["trap_name"] != "" {
+		t.Errorf("specific trap fields = %v", ev.Fields)
+	}
+	if ev.Severity != "info" || ev.Message != "1.3.6.1.4.1.9.0.42" {
+		t.Errorf("severity=%s message=%q", ev.Severity, ev.Message)
+	}
+}
+
+func TestDeviceResolver(t *testing.T) {
+	tasks := []*plugin.CollectionTask{
+		{DeviceID: "dev-1", DeviceConfig: map[string]interface{}{"host": "10.0.0.1", "port": 161}},
+		{DeviceID: "dev-2", DeviceConfig: map[string]interface{}{"url": "x", "target": "https://Web01.example.com:8443/health"}},
+		{DeviceID: "dev-3", DeviceConfig: map[string]interface{}{"ip": "10.0.0.1"}},
+		{DeviceConfig: map[string]interface{}{"host": "10.0.0.9"}},
+	}
+	r := NewDeviceResolver(func() []*plugin.CollectionTask { return tasks })
+
+	cases := []struct {
+		ip, hostname, want string
+	}{
+		{"10.0.0.1", "", "dev-1"},
+		{"::ffff:10.0.0.1", "", "dev-1"},
+		{"10.0.0.2", "web01.example.com", "dev-2"},
+		{"10.0.0.9", "", ""},
+		{"10.0.0.3", "unknown", ""},
+	}
+	for _, c := range cases {
+		if got := r.Resolve(c.ip, c.hostname); got != c.want {
+			t.Errorf("Resolve(%q, %q) = %q, want %q", c.ip, c.hostname, got, c.want)
+		}
+	}
+}
+
+func TestForwarder(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []Event
+		calls    int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+
+		if r.URL.Path != "/api/v1/data/events" || r.Header.Get("X-Sentinel-ID") != "sentinel-1" || r.Header.Get("X-API-Token") != "token" {
+			t.Errorf("unexpected request %s, headers %v", r.URL.Path, r.Header)
+		}
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var body struct {
+			Events []Event `json:"events"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode failed: %v", err)
+		}
+		received = append(received, body.Events...)
+	}))
+	defer server.Close()
+
+	f := NewForwarder(server.URL, "sentinel-1", "token", 10, 2, time.Hour, time.Second)
+
+	// 第一次发送失败，事件保留到下次发送
+	batch := f.flush(context.Background(), []*Event{{Type: EventTypeTrap, SourceIP: "10.0.0.1"}})
+	if len(batch) != 0 || len(f.pending) != 1 {
+		t.Fatalf("pending = %d after failure, want 1", len(f.pending))
+	}
+
+	// 重试间隔内不请求中心端
+	f.flush(context.Background(), []*Event{{Type: EventTypeSyslog, SourceIP: "10.0.0.2"}})
+	if calls != 1 || len(f.pending) != 2 {
+		t.Fatalf("calls = %d, pending = %d during backoff", calls, len(f.pending))
+	}
+
+	f.retryAt = time.Time{}
+	f.flush(context.Background(), []*Event{{Type: EventTypeSyslog, SourceIP: "10.0.0.3"}})
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 3 || received[0].SourceIP != "10.0.0.1" || received[2].SourceIP != "10.0.0.3" {
+		t.Errorf("received = %+v", received)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3 (failure + two batches)", calls)
+	}
+	if sent, dropped := f.Stats(); sent != 3 || dropped != 0 {
+		t.Errorf("stats = %d sent, %d dropped", sent, dropped)
+	}
+}
+
+func TestForwarderDropsWhenFull(t *testing.T) {
+	f := NewForwarder("http://127.0.0.1:1", "s", "t", 2, 10, time.Hour, time.Second)
+	for i := 0; i < 5; i++ {
+		f.Enqueue(&Event{Type: EventTypeTrap})
+	}
+	if _, dropped := f.Stats(); dropped != 3 {
+		t.Errorf("dropped = %d, want 3", dropped)
+	}
+}
+
+func TestFieldName(t *testing.T) {
+	cases := map[string]string{
+		"1.3.6.1":  "_1_3_6_1",
+		"app-name": "app_name",
+		"ok_1":     "ok_1",
+	}
+	for in, want := range cases {
+		if got := fieldName(in); got != want {
+			t.Errorf("fieldName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package listener
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/celestial/orbital-sentinels/internal/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Manager 设备事件接收管理器，负责启动接收器、识别设备并转发事件
+type Manager struct {
+	trapCfg   *TrapConfig
+	syslogCfg *SyslogConfig
+	resolver  *DeviceResolver
+	forwarder *Forwarder
+
+	trap   *TrapReceiver
+	syslog *SyslogReceiver
+	cancel context.CancelFunc
+}
+
+// NewManager 创建接收管理器，trapCfg 或 syslogCfg 为空时不启用对应接收器
+func NewManager(trapCfg *TrapConfig, syslogCfg *SyslogConfig, resolver *DeviceResolver, forwarder *Forwarder) *Manager {
+	return &Manager{
+		trapCfg:   trapCfg,
+		syslogCfg: syslogCfg,
+		resolver:  resolver,
+		forwarder: forwarder,
+	}
+}
+
+// Start 启动转发器和接收器，任一接收器启动失败时停止已启动的组件
+func (m *Manager) Start(ctx context.Context) error {
+	if m.trapCfg != nil {
+		trap, err := NewTrapReceiver(*m.trapCfg, m.handle)
+		if err != nil {
+			return fmt.Errorf("failed to create trap receiver: %w", err)
+		}
+		if err := trap.Start(); err != nil {
+			return fmt.Errorf("failed to start trap receiver: %w", err)
+		}
+		m.trap = trap
+	}
+
+	if m.syslogCfg != nil {
+		syslog := NewSyslogReceiver(*m.syslogCfg, m.handle)
+		if err := syslog.Start(); err != nil {
+			m.Stop()
+			return fmt.Errorf("failed to start syslog receiver: %w", err)
+		}
+		m.syslog = syslog
+	}
+
+	fctx, cancel := context.WithCancel(ctx)
+	m.cancel = cancel
+	m.forwarder.Start(fctx)
+	return nil
+}
+
+// Stop 停止接收器，并等待转发器发送剩余事件
+func (m *Manager) Stop() {
+	if m.trap != nil {
+		m.trap.Stop()
+	}
+	if m.syslog != nil {
+		m.syslog.Stop()
+	}
+	if m.cancel != nil {
+		m.cancel()
+		m.forwarder.Wait()
+	}
+
+	sent, dropped := m.forwarder.Stats()
+	logger.Info("Event listeners stopped", zap.Int64("sent", sent), zap.Int64("dropped", dropped))
+}
+
+// Forwarder 返回事件转发器
+func (m *Manager) Forwarder() *Forwarder {
+	return m.forwarder
+}
+
+// handle 识别事件所属设备后加入转发队列
+func (m *Manager) handle(ev *Event) {
+	hostname := ev.Fields["hostname"]
+	if ev.Fields["agent_address"] != "" {
+		// v1 Trap 经过转发时来源地址不是设备本身
+		if id := m.resolver.Resolve(ev.Fields["agent_address"], ""); id != "" {
+			ev.DeviceID = id
+		}
+	}
+	if ev.DeviceID == "" {
+		ev.DeviceID = m.resolver.Resolve(ev.SourceIP, hostname)
+	}
+
+	logger.Debug("Received device event",
+		zap.String("type", ev.Type),
+		zap.String("source_ip", ev.SourceIP),
+		zap.String("device_id", ev.DeviceID))
+
+	m.forwarder.Enqueue(ev)
+}
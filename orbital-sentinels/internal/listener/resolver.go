@@ -0,0 +1,86 @@
+package listener
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/celestial/orbital-sentinels/internal/plugin"
+)
+
+// resolverTTL 设备索引的重建间隔，任务变化后最多延迟这么久生效
+const resolverTTL = 30 * time.Second
+
+// deviceAddressKeys 任务设备配置中表示设备地址的字段
+var deviceAddressKeys = []string{"host", "ip", "address", "target"}
+
+// DeviceResolver 根据事件来源地址匹配设备
+// 索引由采集任务的设备配置构建，中心端下发任务时已合并设备的连接配置。
+type DeviceResolver struct {
+	tasks func() []*plugin.CollectionTask
+
+	mu      sync.Mutex
+	index   map[string]string // 地址 -> device_id
+	builtAt time.Time
+}
+
+// NewDeviceResolver 创建设备解析器，tasks 返回当前的采集任务
+func NewDeviceResolver(tasks func() []*plugin.CollectionTask) *DeviceResolver {
+	return &DeviceResolver{tasks: tasks}
+}
+
+// Resolve 返回来源 IP 对应的设备 ID，找不到时尝试事件中的主机名
+func (r *DeviceResolver) Resolve(sourceIP, hostname string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.index == nil || time.Since(r.builtAt) > resolverTTL {
+		r.rebuild()
+	}
+
+	if id, ok := r.index[normalizeAddress(sourceIP)]; ok {
+		return id
+	}
+	if hostname != "" {
+		return r.index[normalizeAddress(hostname)]
+	}
+	return ""
+}
+
+// rebuild 重建地址索引（调用方需持有 mu）
+func (r *DeviceResolver) rebuild() {
+	index := make(map[string]string)
+	for _, task := range r.tasks() {
+		if task.DeviceID == "" {
+			continue
+		}
+		for _, key := range deviceAddressKeys {
+			if addr, ok := task.DeviceConfig[key].(string); ok && addr != "" {
+				// 同一地址有多个任务时以第一个为准
+				if _, exists := index[normalizeAddress(addr)]; !exists {
+					index[normalizeAddress(addr)] = task.DeviceID
+				}
+			}
+		}
+	}
+	r.index = index
+	r.builtAt = time.Now()
+}
+
+// normalizeAddress 去掉端口和 URL 前缀，IP 统一格式（如 IPv4 映射的 IPv6 地址），主机名转小写
+func normalizeAddress(addr string) string {
+	if i := strings.Index(addr, "://"); i >= 0 {
+		addr = addr[i+3:]
+		if j := strings.IndexByte(addr, '/'); j >= 0 {
+			addr = addr[:j]
+		}
+	}
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	if ip := net.ParseIP(addr); ip != nil {
+		return ip.String()
+	}
+	return strings.ToLower(addr)
+}
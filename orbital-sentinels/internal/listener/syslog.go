@@ -0,0 +1,447 @@
+package listener
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/celestial/orbital-sentinels/internal/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// maxSyslogMessageSize 单条 Syslog 消息的最大长度
+const maxSyslogMessageSize = 64 * 1024
+
+// SyslogConfig Syslog 接收配置，UDP 和 TCP 地址为空时不监听对应协议
+type SyslogConfig struct {
+	UDPAddress string
+	TCPAddress string
+}
+
+var syslogSeverities = []string{"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug"}
+
+var syslogFacilities = []string{
+	"kern", "user", "mail", "daemon", "auth", "syslog", "lpr", "news",
+	"uucp", "cron", "authpriv", "ftp", "ntp", "security", "console", "solaris_cron",
+	"local0", "local1", "local2", "local3", "local4", "local5", "local6", "local7",
+}
+
+// mnemonicPattern 网络设备日志中的 %FACILITY-SEVERITY-MNEMONIC 标识（Cisco、H3C 等）
+var mnemonicPattern = regexp.MustCompile(`%([A-Z0-9_]+)-(\d)-([A-Z0-9_]+)`)
+
+// SyslogReceiver Syslog 接收器，支持 RFC 3164 和 RFC 5424 格式
+type SyslogReceiver struct {
+	cfg     SyslogConfig
+	handler Handler
+	udpConn net.PacketConn
+	tcpLn   net.Listener
+	mu      sync.Mutex
+	conns   map[net.Conn]struct{}
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+// NewSyslogReceiver 创建 Syslog 接收器
+func NewSyslogReceiver(cfg SyslogConfig, handler Handler) *SyslogReceiver {
+	return &SyslogReceiver{
+		cfg:     cfg,
+		handler: handler,
+		conns:   make(map[net.Conn]struct{}),
+	}
+}
+
+// Start 开始监听，端口绑定失败时返回错误
+func (r *SyslogReceiver) Start() error {
+	if r.cfg.UDPAddress == "" && r.cfg.TCPAddress == "" {
+		return fmt.Errorf("syslog requires udp_address or tcp_address")
+	}
+
+	if r.cfg.UDPAddress != "" {
+		conn, err := net.ListenPacket("udp", r.cfg.UDPAddress)
+		if err != nil {
+			return fmt.Errorf("failed to listen on udp %s: %w", r.cfg.UDPAddress, err)
+		}
+		r.udpConn = conn
+		r.wg.Add(1)
+		go r.serveUDP()
+		logger.Info("Syslog UDP receiver started", zap.String("address", conn.LocalAddr().String()))
+	}
+
+	if r.cfg.TCPAddress != "" {
+		ln, err := net.Listen("tcp", r.cfg.TCPAddress)
+		if err != nil {
+			r.Stop()
+			return fmt.Errorf("failed to listen on tcp %s: %w", r.cfg.TCPAddress, err)
+		}
+		r.tcpLn = ln
+		r.wg.Add(1)
+		go r.serveTCP()
+		logger.Info("Syslog TCP receiver started", zap.String("address", ln.Addr().String()))
+	}
+
+	return nil
+}
+
+// Stop 停止监听并关闭所有连接
+func (r *SyslogReceiver) Stop() {
+	r.mu.Lock()
+	r.closed = true
+	if r.udpConn != nil {
+		r.udpConn.Close()
+	}
+	if r.tcpLn != nil {
+		r.tcpLn.Close()
+	}
+	for conn := range r.conns {
+		conn.Close()
+	}
+	r.mu.Unlock()
+
+	r.wg.Wait()
+}
+
+// serveUDP 每个 UDP 报文是一条消息
+func (r *SyslogReceiver) serveUDP() {
+	defer r.wg.Done()
+
+	buf := make([]byte, maxSyslogMessageSize)
+	for {
+		n, addr, err := r.udpConn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			logger.Debug("Syslog UDP read failed", zap.Error(err))
+			continue
+		}
+		r.handle(buf[:n], addrIP(addr))
+	}
+}
+
+// serveTCP 接受 TCP 连接
+func (r *SyslogReceiver) serveTCP() {
+	defer r.wg.Done()
+
+	for {
+		conn, err := r.tcpLn.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			logger.Debug("Syslog TCP accept failed", zap.Error(err))
+			continue
+		}
+
+		r.mu.Lock()
+		if r.closed {
+			r.mu.Unlock()
+			conn.Close()
+			return
+		}
+		r.conns[conn] = struct{}{}
+		r.wg.Add(1)
+		r.mu.Unlock()
+
+		go r.serveConn(conn)
+	}
+}
+
+// serveConn 读取 TCP 连接中的消息，支持 RFC 6587 的长度前缀和换行分帧
+func (r *SyslogReceiver) serveConn(conn net.Conn) {
+	defer r.wg.Done()
+	defer func() {
+		r.mu.Lock()
+		delete(r.conns, conn)
+		r.mu.Unlock()
+		conn.Close()
+	}()
+
+	source := addrIP(conn.RemoteAddr())
+	reader := bufio.NewReaderSize(conn, 4096)
+	for {
+		frame, err := readFrame(reader)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				logger.Debug("Syslog TCP read failed", zap.String("source", source), zap.Error(err))
+			}
+			return
+		}
+		if len(frame) > 0 {
+			r.handle(frame, source)
+		}
+	}
+}
+
+// readFrame 读取一帧：以数字开头时为 "长度 消息"，否则读到换行
+func readFrame(reader *bufio.Reader) ([]byte, error) {
+	first, err := reader.Peek(1)
+	if err != nil {
+		return nil, err
+	}
+
+	if first[0] >= '1' && first[0] <= '9' {
+		prefix, err := reader.ReadString(' ')
+		if err != nil {
+			return nil, err
+		}
+		length, err := strconv.Atoi(strings.TrimSpace(prefix))
+		if err != nil || length > maxSyslogMessageSize {
+			return nil, fmt.Errorf("invalid frame length %q", prefix)
+		}
+		frame := make([]byte, length)
+		if _, err := io.ReadFull(reader, frame); err != nil {
+			return nil, err
+		}
+		return frame, nil
+	}
+
+	var line []byte
+	for {
+		chunk, isPrefix, err := reader.ReadLine()
+		if err != nil {
+			return nil, err
+		}
+		line = append(line, chunk...)
+		if len(line) > maxSyslogMessageSize {
+			return nil, fmt.Errorf("message exceeds %d bytes", maxSyslogMessageSize)
+		}
+		if !isPrefix {
+			return line, nil
+		}
+	}
+}
+
+// handle 解析消息并交给事件处理函数
+func (r *SyslogReceiver) handle(data []byte, source string) {
+	ev, err := parseSyslog(data, time.Now())
+	if err != nil {
+		logger.Debug("Dropping invalid syslog message", zap.String("source", source), zap.Error(err))
+		return
+	}
+	ev.SourceIP = source
+	r.handler(ev)
+}
+
+// addrIP 返回地址中的 IP
+func addrIP(addr net.Addr) string {
+	switch a := addr.(type) {
+	case *net.UDPAddr:
+		return a.IP.String()
+	case *net.TCPAddr:
+		return a.IP.String()
+	}
+	host, _, _ := net.SplitHostPort(addr.String())
+	return host
+}
+
+// parseSyslog 解析 Syslog 消息，<PRI> 之后为 "1 " 时按 RFC 5424，否则按 RFC 3164 宽松解析
+func parseSyslog(data []byte, received time.Time) (*Event, error) {
+	msg := strings.TrimRight(string(data), "\r\n\x00")
+	if !strings.HasPrefix(msg, "<") {
+		return nil, fmt.Errorf("missing priority")
+	}
+	end := strings.IndexByte(msg, '>')
+	if end < 2 || end > 4 {
+		return nil, fmt.Errorf("invalid priority")
+	}
+	pri, err := strconv.Atoi(msg[1:end])
+	if err != nil || pri > 191 {
+		return nil, fmt.Errorf("invalid priority %q", msg[1:end])
+	}
+	msg = msg[end+1:]
+
+	severity := syslogSeverities[pri%8]
+	ev := &Event{
+		Type:      EventTypeSyslog,
+		Timestamp: received,
+		Severity:  severity,
+		Fields: map[string]string{
+			"facility": syslogFacilities[pri/8],
+			"severity": severity,
+		},
+	}
+
+	if strings.HasPrefix(msg, "1 ") {
+		parseRFC5424(ev, msg[2:])
+	} else {
+		parseRFC3164(ev, msg, received)
+	}
+
+	if m := mnemonicPattern.FindStringSubmatch(ev.Message); m != nil {
+		ev.Fields["mnemonic"] = m[1] + "-" + m[2] + "-" + m[3]
+		ev.Fields["mnemonic_facility"] = m[1]
+		ev.Fields["mnemonic_name"] = m[3]
+	}
+	return ev, nil
+}
+
+// parseRFC5424 解析 RFC 5424 头部和结构化数据
+// TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG，"-" 表示空值
+func parseRFC5424(ev *Event, msg string) {
+	ev.Fields["format"] = "rfc5424"
+
+	headers := []string{"timestamp", "hostname", "app_name", "proc_id", "msg_id"}
+	for _, name := range headers {
+		var token string
+		token, msg = nextToken(msg)
+		if token == "-" || token == "" {
+			continue
+		}
+		if name == "timestamp" {
+			if ts, err := time.Parse(time.RFC3339Nano, token); err == nil {
+				ev.Timestamp = ts
+			}
+			continue
+		}
+		ev.Fields[name] = token
+	}
+
+	if strings.HasPrefix(msg, "-") {
+		msg = strings.TrimPrefix(msg[1:], " ")
+	} else if strings.HasPrefix(msg, "[") {
+		msg = parseStructuredData(ev.Fields, msg)
+	}
+
+	// 消息可能带 UTF-8 BOM
+	ev.Message = strings.TrimPrefix(msg, "\ufeff")
+}
+
+// parseStructuredData 解析 [id key="value" ...] 形式的结构化数据，字段名为 sd_<id>_<key>
+// 返回结构化数据之后的消息
+func parseStructuredData(fields map[string]string, msg string) string {
+	for strings.HasPrefix(msg, "[") {
+		end := -1
+		escaped := false
+		inQuote := false
+		for i := 1; i < len(msg); i++ {
+			c := msg[i]
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inQuote = !inQuote
+			case c == ']' && !inQuote:
+				end = i
+			}
+			if end >= 0 {
+				break
+			}
+		}
+		if end < 0 {
+			return msg
+		}
+
+		element := msg[1:end]
+		msg = msg[end+1:]
+
+		id, params := nextToken(element)
+		prefix := "sd_" + fieldName(id) + "_"
+		for params != "" {
+			eq := strings.Index(params, `="`)
+			if eq < 0 {
+				break
+			}
+			key := strings.TrimSpace(params[:eq])
+			value, rest := quotedValue(params[eq+2:])
+			fields[prefix+fieldName(key)] = value
+			params = strings.TrimLeft(rest, " ")
+		}
+	}
+	return strings.TrimPrefix(msg, " ")
+}
+
+// quotedValue 读取以 " 结束的参数值，处理 \" \\ \] 转义
+func quotedValue(s string) (value, rest string) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '\\' && i+1 < len(s) && strings.IndexByte(`"\]`, s[i+1]) >= 0 {
+			b.WriteByte(s[i+1])
+			i++
+			continue
+		}
+		if c == '"' {
+			return b.String(), s[i+1:]
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), ""
+}
+
+// rfc3164Timestamp RFC 3164 时间戳格式，没有年份
+const rfc3164Timestamp = "Jan _2 15:04:05"
+
+// parseRFC3164 宽松解析 RFC 3164 消息：[TIMESTAMP HOSTNAME] [TAG[PID]:] MSG
+// 网络设备常省略主机名或在时间戳前加序号，无法识别的部分保留在消息中。
+func parseRFC3164(ev *Event, msg string, received time.Time) {
+	ev.Fields["format"] = "rfc3164"
+
+	if len(msg) >= len(rfc3164Timestamp) {
+		if ts, err := time.ParseInLocation(rfc3164Timestamp, msg[:len(rfc3164Timestamp)], received.Location()); err == nil {
+			ts = ts.AddDate(received.Year(), 0, 0)
+			// 跨年时收到上一年末的消息
+			if ts.After(received.Add(24 * time.Hour)) {
+				ts = ts.AddDate(-1, 0, 0)
+			}
+			ev.Timestamp = ts
+			msg = strings.TrimLeft(msg[len(rfc3164Timestamp):], " ")
+
+			// 时间戳之后的第一个词是主机名，除非它本身就是 TAG
+			if token, rest := nextToken(msg); token != "" && !strings.HasSuffix(token, ":") && !strings.Contains(token, "[") {
+				ev.Fields["hostname"] = token
+				msg = rest
+			}
+		}
+	}
+
+	if tag, pid, rest, ok := parseTag(msg); ok {
+		ev.Fields["app_name"] = tag
+		if pid != "" {
+			ev.Fields["proc_id"] = pid
+		}
+		msg = rest
+	}
+
+	ev.Message = msg
+}
+
+// parseTag 解析 TAG[PID]: 前缀，TAG 以字母开头，只能包含字母、数字和 -_./
+func parseTag(msg string) (tag, pid, rest string, ok bool) {
+	colon := strings.Index(msg, ": ")
+	if colon <= 0 || colon > 48 || !isLetter(msg[0]) {
+		return "", "", msg, false
+	}
+	tag = msg[:colon]
+	if open := strings.IndexByte(tag, '['); open > 0 && strings.HasSuffix(tag, "]") {
+		pid = tag[open+1 : len(tag)-1]
+		tag = tag[:open]
+	}
+	for _, c := range tag {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.ContainsRune("-_./", c)) {
+			return "", "", msg, false
+		}
+	}
+	return tag, pid, msg[colon+2:], true
+}
+
+// isLetter 判断是否为 ASCII 字母
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// nextToken 返回第一个空格分隔的词和剩余部分
+func nextToken(s string) (token, rest string) {
+	if i := strings.IndexByte(s, ' '); i >= 0 {
+		return s[:i], s[i+1:]
+	}
+	return s, ""
+}
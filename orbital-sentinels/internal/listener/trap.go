@@ -0,0 +1,327 @@
+package listener
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/celestial/orbital-sentinels/internal/pkg/logger"
+	"github.com/gosnmp/gosnmp"
+	"go.uber.org/zap"
+)
+
+// TrapUser SNMPv3 Trap 的 USM 用户
+type TrapUser struct {
+	Username      string
+	SecurityLevel string // noAuthNoPriv, authNoPriv, authPriv
+	AuthProtocol  string // MD5, SHA, SHA224, SHA256, SHA384, SHA512
+	AuthPassword  string
+	PrivProtocol  string // DES, AES, AES192, AES256
+	PrivPassword  string
+}
+
+// TrapConfig SNMP Trap 接收配置
+type TrapConfig struct {
+	Address     string     // 监听地址，默认 :162
+	Communities []string   // v1/v2c 允许的团体名，为空时不校验
+	Users       []TrapUser // v3 用户，为空时不接收 v3 Trap
+}
+
+const (
+	oidSysUpTime          = "1.3.6.1.2.1.1.3.0"
+	oidSnmpTrapOID        = "1.3.6.1.6.3.1.1.4.1.0"
+	oidSnmpTrapEnterprise = "1.3.6.1.6.3.1.1.4.3.0"
+	oidStandardTraps      = "1.3.6.1.6.3.1.1.5"
+)
+
+// trapNames 标准 Trap 名称（RFC 3418、RFC 2863）
+var trapNames = map[string]string{
+	oidStandardTraps + ".1": "coldStart",
+	oidStandardTraps + ".2": "warmStart",
+	oidStandardTraps + ".3": "linkDown",
+	oidStandardTraps + ".4": "linkUp",
+	oidStandardTraps + ".5": "authenticationFailure",
+	oidStandardTraps + ".6": "egpNeighborLoss",
+}
+
+// trapSeverity 标准 Trap 的事件级别，其余 Trap 为 info
+var trapSeverity = map[string]string{
+	"coldStart":             "warning",
+	"linkDown":              "warning",
+	"authenticationFailure": "warning",
+	"egpNeighborLoss":       "warning",
+}
+
+// varbindNames 常见绑定变量的字段名，表列对象的实例部分（如 ifIndex）被去掉
+var varbindNames = map[string]string{
+	"1.3.6.1.2.1.1.5.0":       "sysName",
+	"1.3.6.1.2.1.2.2.1.1":     "ifIndex",
+	"1.3.6.1.2.1.2.2.1.2":     "ifDescr",
+	"1.3.6.1.2.1.2.2.1.7":     "ifAdminStatus",
+	"1.3.6.1.2.1.2.2.1.8":     "ifOperStatus",
+	"1.3.6.1.2.1.31.1.1.1.1":  "ifName",
+	"1.3.6.1.2.1.31.1.1.1.18": "ifAlias",
+}
+
+// TrapReceiver SNMP Trap 接收器
+type TrapReceiver struct {
+	cfg         TrapConfig
+	communities map[string]bool
+	listener    *gosnmp.TrapListener
+	handler     Handler
+	errCh       chan error
+}
+
+// NewTrapReceiver 创建 SNMP Trap 接收器
+func NewTrapReceiver(cfg TrapConfig, handler Handler) (*TrapReceiver, error) {
+	if cfg.Address == "" {
+		cfg.Address = ":162"
+	}
+
+	// 报文版本以收到的 Trap 为准，Version3 仅用于启用 USM 认证校验
+	params := &gosnmp.GoSNMP{Version: gosnmp.Version2c}
+	if len(cfg.Users) > 0 {
+		params.Version = gosnmp.Version3
+		table := gosnmp.NewSnmpV3SecurityParametersTable(params.Logger)
+		for _, user := range cfg.Users {
+			if user.Username == "" {
+				return nil, fmt.Errorf("snmpv3 user requires username")
+			}
+			if err := table.Add(user.Username, usmParameters(user)); err != nil {
+				return nil, fmt.Errorf("invalid snmpv3 user %s: %w", user.Username, err)
+			}
+		}
+		params.TrapSecurityParametersTable = table
+	}
+
+	r := &TrapReceiver{
+		cfg:         cfg,
+		communities: make(map[string]bool, len(cfg.Communities)),
+		handler:     handler,
+		errCh:       make(chan error, 1),
+	}
+	for _, c := range cfg.Communities {
+		r.communities[c] = true
+	}
+
+	r.listener = gosnmp.NewTrapListener()
+	r.listener.Params = params
+	r.listener.OnNewTrap = r.onTrap
+	return r, nil
+}
+
+// Start 开始监听，端口绑定失败时返回错误
+func (r *TrapReceiver) Start() error {
+	go func() {
+		r.errCh <- r.listener.Listen(r.cfg.Address)
+	}()
+
+	select {
+	case <-r.listener.Listening():
+		logger.Info("SNMP trap receiver started", zap.String("address", r.cfg.Address))
+		return nil
+	case err := <-r.errCh:
+		return fmt.Errorf("failed to listen on %s: %w", r.cfg.Address, err)
+	}
+}
+
+// Stop 停止监听
+func (r *TrapReceiver) Stop() {
+	r.listener.Close()
+}
+
+// onTrap 处理收到的 Trap
+func (r *TrapReceiver) onTrap(pkt *gosnmp.SnmpPacket, addr *net.UDPAddr) {
+	if pkt.Version != gosnmp.Version3 && len(r.communities) > 0 && !r.communities[pkt.Community] {
+		logger.Debug("Dropping trap with unknown community", zap.String("source", addr.IP.String()))
+		return
+	}
+	if pkt.Version == gosnmp.Version3 && len(r.cfg.Users) == 0 {
+		logger.Debug("Dropping snmpv3 trap without configured users", zap.String("source", addr.IP.String()))
+		return
+	}
+
+	r.handler(trapEvent(pkt, addr.IP, time.Now()))
+}
+
+// trapEvent 将 Trap 报文转换为事件
+// v1 Trap 按 RFC 3584 转换为 snmpTrapOID，使不同版本的同一 Trap 字段一致。
+func trapEvent(pkt *gosnmp.SnmpPacket, source net.IP, received time.Time) *Event {
+	fields := map[string]string{"version": versionName(pkt.Version)}
+
+	if pkt.Version == gosnmp.Version1 {
+		enterprise := strings.TrimPrefix(pkt.Enterprise, ".")
+		fields["enterprise"] = enterprise
+		fields["generic_trap"] = strconv.Itoa(pkt.GenericTrap)
+		fields["specific_trap"] = strconv.Itoa(pkt.SpecificTrap)
+		fields["sys_uptime"] = strconv.FormatUint(uint64(pkt.Timestamp), 10)
+		if pkt.AgentAddress != "" {
+			fields["agent_address"] = pkt.AgentAddress
+		}
+		if pkt.GenericTrap == 6 {
+			fields["trap_oid"] = fmt.Sprintf("%s.0.%d", enterprise, pkt.SpecificTrap)
+		} else {
+			fields["trap_oid"] = fmt.Sprintf("%s.%d", oidStandardTraps, pkt.GenericTrap+1)
+		}
+	}
+
+	for _, v := range pkt.Variables {
+		oid := strings.TrimPrefix(v.Name, ".")
+		value := varbindValue(v)
+
+		switch oid {
+		case oidSysUpTime:
+			fields["sys_uptime"] = value
+			continue
+		case oidSnmpTrapOID:
+			fields["trap_oid"] = strings.TrimPrefix(value, ".")
+			continue
+		case oidSnmpTrapEnterprise:
+			fields["enterprise"] = strings.TrimPrefix(value, ".")
+			continue
+		}
+
+		name, instance := varbindName(oid)
+		fields[name] = value
+		if instance != "" && fields["ifIndex"] == "" && strings.HasPrefix(name, "if") {
+			fields["ifIndex"] = instance
+		}
+	}
+
+	name := trapNames[fields["trap_oid"]]
+	if name != "" {
+		fields["trap_name"] = name
+	} else {
+		name = fields["trap_oid"]
+	}
+	severity := trapSeverity[name]
+	if severity == "" {
+		severity = "info"
+	}
+
+	return &Event{
+		Type:      EventTypeTrap,
+		SourceIP:  source.String(),
+		Timestamp: received,
+		Severity:  severity,
+		Message:   trapMessage(name, fields),
+		Fields:    fields,
+	}
+}
+
+// varbindName 返回绑定变量的字段名和表实例，未知对象使用 oid_ 前缀的字段名
+func varbindName(oid string) (name, instance string) {
+	if name, ok := varbindNames[oid]; ok {
+		return name, ""
+	}
+	for prefix, name := range varbindNames {
+		if strings.HasPrefix(oid, prefix+".") {
+			return name, oid[len(prefix)+1:]
+		}
+	}
+	return "oid_" + strings.ReplaceAll(oid, ".", "_"), ""
+}
+
+// trapMessage 生成事件消息：Trap 名称和接口相关字段
+func trapMessage(name string, fields map[string]string) string {
+	var parts []string
+	for k, v := range fields {
+		if strings.HasPrefix(k, "if") || k == "sysName" {
+			parts = append(parts, k+"="+v)
+		}
+	}
+	sort.Strings(parts)
+	if len(parts) == 0 {
+		return name
+	}
+	return name + " " + strings.Join(parts, " ")
+}
+
+// varbindValue 将绑定变量的值格式化为字符串
+func varbindValue(v gosnmp.SnmpPDU) string {
+	switch v.Type {
+	case gosnmp.OctetString:
+		b, _ := v.Value.([]byte)
+		if utf8.Valid(b) && isPrintable(b) {
+			return string(b)
+		}
+		return hex.EncodeToString(b)
+	case gosnmp.ObjectIdentifier, gosnmp.IPAddress:
+		s, _ := v.Value.(string)
+		return strings.TrimPrefix(s, ".")
+	case gosnmp.Null, gosnmp.NoSuchObject, gosnmp.NoSuchInstance, gosnmp.EndOfMibView:
+		return ""
+	default:
+		return gosnmp.ToBigInt(v.Value).String()
+	}
+}
+
+// isPrintable 判断字节串是否为可打印文本
+func isPrintable(b []byte) bool {
+	for _, c := range string(b) {
+		if c < 0x20 && c != '\t' && c != '\r' && c != '\n' {
+			return false
+		}
+	}
+	return true
+}
+
+// versionName 返回 SNMP 版本名称
+func versionName(v gosnmp.SnmpVersion) string {
+	switch v {
+	case gosnmp.Version1:
+		return "1"
+	case gosnmp.Version3:
+		return "3"
+	default:
+		return "2c"
+	}
+}
+
+// usmParameters 构建 SNMPv3 USM 参数，协议名称与 SNMP 采集插件一致
+func usmParameters(user TrapUser) *gosnmp.UsmSecurityParameters {
+	params := &gosnmp.UsmSecurityParameters{
+		UserName:                 user.Username,
+		AuthenticationProtocol:   gosnmp.NoAuth,
+		PrivacyProtocol:          gosnmp.NoPriv,
+		AuthenticationPassphrase: user.AuthPassword,
+		PrivacyPassphrase:        user.PrivPassword,
+	}
+
+	if user.SecurityLevel == "authNoPriv" || user.SecurityLevel == "authPriv" {
+		switch user.AuthProtocol {
+		case "SHA":
+			params.AuthenticationProtocol = gosnmp.SHA
+		case "SHA224":
+			params.AuthenticationProtocol = gosnmp.SHA224
+		case "SHA256":
+			params.AuthenticationProtocol = gosnmp.SHA256
+		case "SHA384":
+			params.AuthenticationProtocol = gosnmp.SHA384
+		case "SHA512":
+			params.AuthenticationProtocol = gosnmp.SHA512
+		default:
+			params.AuthenticationProtocol = gosnmp.MD5
+		}
+	}
+
+	if user.SecurityLevel == "authPriv" {
+		switch user.PrivProtocol {
+		case "AES":
+			params.PrivacyProtocol = gosnmp.AES
+		case "AES192":
+			params.PrivacyProtocol = gosnmp.AES192
+		case "AES256":
+			params.PrivacyProtocol = gosnmp.AES256
+		default:
+			params.PrivacyProtocol = gosnmp.DES
+		}
+	}
+
+	return params
+}
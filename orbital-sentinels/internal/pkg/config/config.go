@@ -15,6 +15,7 @@ type Config struct {
 	Buffer          BufferConfig    `mapstructure:"buffer"`
 	Sender          SenderConfig    `mapstructure:"sender"`
 	Plugins         PluginsConfig   `mapstructure:"plugins"`
+	Listeners       ListenersConfig `mapstructure:"listeners"`
 	Logging         LoggingConfig   `mapstructure:"logging"`
 	Tasks           []TaskConfig    `mapstructure:"tasks"`            // 本地任务配置
 	CredentialsPath string          `mapstructure:"credentials_path"` // 凭证文件路径
@@ -34,6 +35,8 @@ type CoreConfig struct {
 	APIToken           string `mapstructure:"api_token"`
 	RegistrationKey    string `mapstructure:"registration_key"`    // 注册密钥(可选)
 	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"`
+	CAFile             string `mapstructure:"ca_file"` // 校验中心端证书的 CA 文件(可选)
+	MTLS               bool   `mapstructure:"mtls"`    // 向中心端申请并使用客户端证书
 }
 
 // HeartbeatConfig 心跳配置
@@ -56,6 +59,10 @@ type BufferConfig struct {
 	Size          int           `mapstructure:"size"`
 	FlushInterval time.Duration `mapstructure:"flush_interval"`
 	DiskPath      string        `mapstructure:"disk_path"`
+	MaxBytes      int64         `mapstructure:"max_bytes"`     // disk 模式下的最大占用字节数
+	SegmentBytes  int64         `mapstructure:"segment_bytes"` // disk 模式下的段文件大小
+	SyncMode      string        `mapstructure:"sync_mode"`     // disk 模式下的刷盘策略：batch, interval
+	SyncInterval  time.Duration `mapstructure:"sync_interval"` // interval 刷盘间隔
 }
 
 // SenderConfig 发送器配置
@@ -90,9 +97,44 @@ type DatabaseConfig struct {
 
 // PluginsConfig 插件配置
 type PluginsConfig struct {
-	Directory      string        `mapstructure:"directory"`
-	AutoReload     bool          `mapstructure:"auto_reload"`
-	ReloadInterval time.Duration `mapstructure:"reload_interval"`
+	Directory      string                            `mapstructure:"directory"`
+	AutoReload     bool                              `mapstructure:"auto_reload"`
+	ReloadInterval time.Duration                     `mapstructure:"reload_interval"`
+	Settings       map[string]map[string]interface{} `mapstructure:"settings"` // 内置插件的插件级配置，按插件名索引
+}
+
+// ListenersConfig 设备事件接收配置（SNMP Trap、Syslog），事件转发到中心端
+type ListenersConfig struct {
+	Trap          TrapListenerConfig   `mapstructure:"trap"`
+	Syslog        SyslogListenerConfig `mapstructure:"syslog"`
+	QueueSize     int                  `mapstructure:"queue_size"`     // 待转发事件队列长度
+	BatchSize     int                  `mapstructure:"batch_size"`     // 单次转发的最大事件数
+	FlushInterval time.Duration        `mapstructure:"flush_interval"` // 转发间隔
+}
+
+// TrapListenerConfig SNMP Trap 接收配置
+type TrapListenerConfig struct {
+	Enabled     bool             `mapstructure:"enabled"`
+	Address     string           `mapstructure:"address"`     // 默认 :162
+	Communities []string         `mapstructure:"communities"` // v1/v2c 允许的团体名，为空时不校验
+	Users       []TrapUserConfig `mapstructure:"users"`       // v3 USM 用户
+}
+
+// TrapUserConfig SNMPv3 Trap 用户
+type TrapUserConfig struct {
+	Username      string `mapstructure:"username"`
+	SecurityLevel string `mapstructure:"security_level"` // noAuthNoPriv, authNoPriv, authPriv
+	AuthProtocol  string `mapstructure:"auth_protocol"`
+	AuthPassword  string `mapstructure:"auth_password"`
+	PrivProtocol  string `mapstructure:"priv_protocol"`
+	PrivPassword  string `mapstructure:"priv_password"`
+}
+
+// SyslogListenerConfig Syslog 接收配置
+type SyslogListenerConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	UDPAddress string `mapstructure:"udp_address"` // 为空时不监听 UDP
+	TCPAddress string `mapstructure:"tcp_address"` // 为空时不监听 TCP
 }
 
 // LoggingConfig 日志配置
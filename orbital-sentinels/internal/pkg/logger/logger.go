@@ -67,6 +67,8 @@ func Init(level string, format string, output string, filePath string) error {
 	switch output {
 	case "stdout":
 		writeSyncer = zapcore.AddSync(os.Stdout)
+	case "stderr":
+		writeSyncer = zapcore.AddSync(os.Stderr)
 	case "file":
 		file, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 		if err != nil {
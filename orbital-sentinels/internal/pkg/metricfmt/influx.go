@@ -0,0 +1,145 @@
+package metricfmt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/celestial/orbital-sentinels/internal/plugin"
+)
+
+// ParseInflux 解析 InfluxDB 行协议
+// 每个字段转换为一个指标，名称为 measurement_field，标签为 tag。
+// 字符串字段忽略，布尔值转换为 1/0，时间戳按纳秒精度解析。
+func ParseInflux(data []byte) ([]*plugin.Metric, error) {
+	var metrics []*plugin.Metric
+	for n, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parsed, err := parseInfluxLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n+1, err)
+		}
+		metrics = append(metrics, parsed...)
+	}
+	return metrics, nil
+}
+
+// parseInfluxLine 解析一行：measurement[,tag=value...] field=value[,field=value...] [timestamp]
+func parseInfluxLine(line string) ([]*plugin.Metric, error) {
+	sections := splitEscaped(line, ' ', true)
+	if len(sections) < 2 || len(sections) > 3 {
+		return nil, fmt.Errorf("invalid line %q", line)
+	}
+
+	keys := splitEscaped(sections[0], ',', false)
+	measurement := unescapeInflux(keys[0])
+	if measurement == "" {
+		return nil, fmt.Errorf("missing measurement in %q", line)
+	}
+	tags := make(map[string]string, len(keys)-1)
+	for _, kv := range keys[1:] {
+		parts := splitEscaped(kv, '=', false)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid tag %q", kv)
+		}
+		tags[unescapeInflux(parts[0])] = unescapeInflux(parts[1])
+	}
+
+	var timestamp int64
+	if len(sections) == 3 {
+		ns, err := strconv.ParseInt(sections[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp %q", sections[2])
+		}
+		timestamp = ns / 1e9
+	}
+
+	var metrics []*plugin.Metric
+	for _, field := range splitEscaped(sections[1], ',', true) {
+		parts := splitEscaped(field, '=', true)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid field %q", field)
+		}
+		value, ok, err := influxFieldValue(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", field, err)
+		}
+		if !ok {
+			continue
+		}
+
+		labels := make(map[string]string, len(tags))
+		for k, v := range tags {
+			labels[k] = v
+		}
+		metrics = append(metrics, &plugin.Metric{
+			Name:      SanitizeName(measurement + "_" + unescapeInflux(parts[0])),
+			Value:     value,
+			Timestamp: timestamp,
+			Labels:    labels,
+			Type:      plugin.MetricTypeGauge,
+		})
+	}
+	if len(metrics) == 0 && len(sections[1]) == 0 {
+		return nil, fmt.Errorf("missing fields in %q", line)
+	}
+	return metrics, nil
+}
+
+// influxFieldValue 解析字段值，字符串字段返回 ok=false
+func influxFieldValue(s string) (float64, bool, error) {
+	if strings.HasPrefix(s, `"`) {
+		return 0, false, nil
+	}
+	switch s {
+	case "t", "T", "true", "True", "TRUE":
+		return 1, true, nil
+	case "f", "F", "false", "False", "FALSE":
+		return 0, true, nil
+	}
+	if strings.HasSuffix(s, "i") || strings.HasSuffix(s, "u") {
+		s = s[:len(s)-1]
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid value %q", s)
+	}
+	return v, true, nil
+}
+
+// splitEscaped 按分隔符切分，跳过反斜杠转义的字符；quotes 为 true 时双引号内的分隔符不切分
+func splitEscaped(s string, sep byte, quotes bool) []string {
+	var parts []string
+	start := 0
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\':
+			i++
+		case quotes && c == '"':
+			quoted = !quoted
+		case c == sep && !quoted:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// unescapeInflux 去掉名称和标签中的转义
+func unescapeInflux(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
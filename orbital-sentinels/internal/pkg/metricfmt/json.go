@@ -0,0 +1,124 @@
+package metricfmt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/celestial/orbital-sentinels/internal/plugin"
+)
+
+// jsonMetric 显式声明的指标
+type jsonMetric struct {
+	Name      string            `json:"name"`
+	Value     *float64          `json:"value"`
+	Labels    map[string]string `json:"labels"`
+	Type      string            `json:"type"`
+	Timestamp int64             `json:"timestamp"`
+}
+
+// ParseJSON 解析 JSON 输出，支持两种形式：
+//   - 指标列表：[{"name": "...", "value": 1, "labels": {...}, "type": "gauge"}]，或放在 {"metrics": [...]} 中
+//   - 任意对象：数值和布尔叶子节点展开为指标，名称为以下划线连接的路径，如 {"disk": {"used": 1}} 得到 disk_used
+func ParseJSON(data []byte) ([]*plugin.Metric, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var doc interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid json: %w", err)
+	}
+
+	switch v := doc.(type) {
+	case []interface{}:
+		return parseJSONList(data)
+	case map[string]interface{}:
+		if _, ok := v["metrics"].([]interface{}); ok {
+			var wrapped struct {
+				Metrics json.RawMessage `json:"metrics"`
+			}
+			if err := json.Unmarshal(data, &wrapped); err != nil {
+				return nil, fmt.Errorf("invalid json: %w", err)
+			}
+			return parseJSONList(wrapped.Metrics)
+		}
+		var metrics []*plugin.Metric
+		flattenJSON("", v, &metrics)
+		return metrics, nil
+	}
+	return nil, fmt.Errorf("expected json object or array, got %T", doc)
+}
+
+// parseJSONList 解析指标列表
+func parseJSONList(data []byte) ([]*plugin.Metric, error) {
+	var items []jsonMetric
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("invalid metric list: %w", err)
+	}
+
+	metrics := make([]*plugin.Metric, 0, len(items))
+	for i, item := range items {
+		if item.Name == "" || item.Value == nil {
+			return nil, fmt.Errorf("metric %d: name and value are required", i)
+		}
+		metricType := plugin.MetricType(item.Type)
+		if metricType != plugin.MetricTypeCounter {
+			metricType = plugin.MetricTypeGauge
+		}
+		labels := item.Labels
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		metrics = append(metrics, &plugin.Metric{
+			Name:      item.Name,
+			Value:     *item.Value,
+			Timestamp: item.Timestamp,
+			Labels:    labels,
+			Type:      metricType,
+		})
+	}
+	return metrics, nil
+}
+
+// flattenJSON 按键名顺序展开数值和布尔叶子节点，忽略字符串、null 和数组
+func flattenJSON(prefix string, value interface{}, metrics *[]*plugin.Metric) {
+	add := func(v float64) {
+		*metrics = append(*metrics, &plugin.Metric{
+			Name:   SanitizeName(prefix),
+			Value:  v,
+			Labels: map[string]string{},
+			Type:   plugin.MetricTypeGauge,
+		})
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			name := k
+			if prefix != "" {
+				name = prefix + "_" + k
+			}
+			flattenJSON(name, v[k], metrics)
+		}
+	case json.Number:
+		if f, err := v.Float64(); err == nil {
+			add(f)
+		}
+	case bool:
+		if v {
+			add(1)
+		} else {
+			add(0)
+		}
+	}
+}
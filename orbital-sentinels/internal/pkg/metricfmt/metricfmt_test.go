@@ -0,0 +1,285 @@
+package metricfmt
+
+import (
+	"testing"
+
+	"github.com/celestial/orbital-sentinels/internal/plugin"
+)
+
+// findMetric 返回名称和标签匹配的指标
+func findMetric(metrics []*plugin.Metric, name string, labels map[string]string) *plugin.Metric {
+	for _, m := range metrics {
+		if m.Name != name {
+			continue
+		}
+		match := true
+		for k, v := range labels {
+			if m.Labels[k] != v {
+				match = false
+				break
+			}
+		}
+		if match {
+			return m
+		}
+	}
+	return nil
+}
+
+func TestParsePrometheus(t *testing.T) {
+	input := `# HELP http_requests_total Total requests.
+# TYPE http_requests_total counter
+http_requests_total{method="get",code="200"} 1027 1700000000000
+http_requests_total{method="post",code="500"} 3
+# TYPE temperature gauge
+temperature 21.5
+# TYPE latency_seconds histogram
+latency_seconds_bucket{le="0.1"} 5
+latency_seconds_bucket{le="+Inf"} 8
+latency_seconds_sum 1.2
+latency_seconds_count 8
+untyped_value 7`
+
+	metrics, err := ParsePrometheus([]byte(input), "")
+	if err != nil {
+		t.Fatalf("ParsePrometheus failed: %v", err)
+	}
+	if len(metrics) != 5 {
+		t.Fatalf("expected 5 metrics, got %d", len(metrics))
+	}
+
+	m := findMetric(metrics, "http_requests_total", map[string]string{"method": "get"})
+	if m == nil || m.Value != 1027 || m.Type != plugin.MetricTypeCounter || m.Timestamp != 1700000000 {
+		t.Errorf("unexpected counter: %+v", m)
+	}
+	if _, ok := m.Labels["__name__"]; ok {
+		t.Error("__name__ should not be a label")
+	}
+	if m := findMetric(metrics, "temperature", nil); m == nil || m.Type != plugin.MetricTypeGauge || m.Timestamp != 0 {
+		t.Errorf("unexpected gauge: %+v", m)
+	}
+	if m := findMetric(metrics, "latency_seconds", nil); m == nil || m.Value != 8 || m.Type != plugin.MetricTypeHistogram ||
+		m.Histogram == nil || m.Histogram.Count != 8 || m.Histogram.Sum != 1.2 || len(m.Histogram.Buckets) != 1 {
+		t.Errorf("unexpected histogram: %+v", m)
+	}
+	if m := findMetric(metrics, "untyped_value", nil); m == nil || m.Type != plugin.MetricTypeGauge {
+		t.Errorf("unexpected untyped: %+v", m)
+	}
+}
+
+func TestParsePrometheus_Summary(t *testing.T) {
+	input := `# TYPE rpc_seconds summary
+rpc_seconds{service="a",quantile="0.99"} 0.3
+rpc_seconds{service="a",quantile="0.5"} 0.02
+rpc_seconds_sum{service="a"} 8.1
+rpc_seconds_count{service="a"} 200
+rpc_seconds{service="b",quantile="0.5"} NaN
+rpc_seconds_sum{service="b"} 0
+rpc_seconds_count{service="b"} 0`
+
+	metrics, err := ParsePrometheus([]byte(input), "")
+	if err != nil {
+		t.Fatalf("ParsePrometheus failed: %v", err)
+	}
+	if len(metrics) != 2 {
+		t.Fatalf("expected 2 metrics, got %d", len(metrics))
+	}
+
+	m := findMetric(metrics, "rpc_seconds", map[string]string{"service": "a"})
+	if m == nil || m.Type != plugin.MetricTypeSummary || m.Summary == nil || m.Value != 200 {
+		t.Fatalf("unexpected summary: %+v", m)
+	}
+	if _, ok := m.Labels["quantile"]; ok {
+		t.Error("quantile should not be a label")
+	}
+	want := []plugin.Quantile{{Quantile: 0.5, Value: 0.02}, {Quantile: 0.99, Value: 0.3}}
+	if len(m.Summary.Quantiles) != 2 || m.Summary.Quantiles[0] != want[0] || m.Summary.Quantiles[1] != want[1] {
+		t.Errorf("unexpected quantiles: %+v", m.Summary.Quantiles)
+	}
+	if m := findMetric(metrics, "rpc_seconds", map[string]string{"service": "b"}); m == nil || len(m.Summary.Quantiles) != 0 {
+		t.Errorf("NaN quantile should be skipped: %+v", m)
+	}
+}
+
+func TestParsePrometheus_HistogramWithoutCount(t *testing.T) {
+	input := `# TYPE size_bytes histogram
+size_bytes_bucket{le="1024"} 3
+size_bytes_bucket{le="+Inf"} 4
+size_bytes_bucket{le="256"} 1`
+
+	metrics, err := ParsePrometheus([]byte(input), "")
+	if err != nil {
+		t.Fatalf("ParsePrometheus failed: %v", err)
+	}
+	if len(metrics) != 1 || metrics[0].Histogram == nil {
+		t.Fatalf("unexpected metrics: %+v", metrics)
+	}
+	h := metrics[0].Histogram
+	if h.Count != 4 || len(h.Buckets) != 2 || h.Buckets[0].UpperBound != 256 || h.Buckets[1].UpperBound != 1024 {
+		t.Errorf("unexpected histogram: %+v", h)
+	}
+}
+
+func TestParsePrometheus_OpenMetrics(t *testing.T) {
+	input := "# TYPE jobs counter\njobs_total 4\n# EOF\n"
+	metrics, err := ParsePrometheus([]byte(input), ContentTypeOpenMetrics)
+	if err != nil {
+		t.Fatalf("ParsePrometheus failed: %v", err)
+	}
+	if m := findMetric(metrics, "jobs_total", nil); m == nil || m.Value != 4 || m.Type != plugin.MetricTypeCounter {
+		t.Errorf("unexpected metric: %+v", m)
+	}
+}
+
+func TestParsePrometheus_Invalid(t *testing.T) {
+	if _, err := ParsePrometheus([]byte("metric{ 1\n"), ""); err == nil {
+		t.Error("expected error for invalid input")
+	}
+}
+
+func TestParseNagios(t *testing.T) {
+	input := `DISK OK - free space: / 3326 MB (56%) | /=2643MB;5948;5958;0;5968 'inode usage'=12%;80;90
+/ 15272 MB (77%);
+/boot 68 MB (69%);
+/home 69357 MB (27%);
+/var/log 819 MB (84%); | /boot=68MB;88;93;0;98
+/home=69357MB;253404;253409;0;253414
+errors=5c;;;; load=U`
+
+	out, err := ParseNagios([]byte(input))
+	if err != nil {
+		t.Fatalf("ParseNagios failed: %v", err)
+	}
+	if out.Status != "DISK OK - free space: / 3326 MB (56%)" {
+		t.Errorf("unexpected status %q", out.Status)
+	}
+	if len(out.Metrics) != 5 {
+		t.Fatalf("expected 5 metrics, got %d", len(out.Metrics))
+	}
+
+	if m := findMetric(out.Metrics, "value", map[string]string{"unit": "MB"}); m == nil || m.Value != 2643 {
+		t.Errorf("unexpected root metric: %+v", m)
+	}
+	if m := findMetric(out.Metrics, "inode_usage", nil); m == nil || m.Value != 12 || m.Labels["unit"] != "%" {
+		t.Errorf("unexpected quoted metric: %+v", m)
+	}
+	if m := findMetric(out.Metrics, "boot", nil); m == nil || m.Value != 68 {
+		t.Errorf("unexpected long text metric: %+v", m)
+	}
+	if m := findMetric(out.Metrics, "errors", nil); m == nil || m.Type != plugin.MetricTypeCounter {
+		t.Errorf("unexpected counter: %+v", m)
+	}
+	if m := findMetric(out.Metrics, "load", nil); m != nil {
+		t.Errorf("U value should be skipped: %+v", m)
+	}
+}
+
+func TestParseNagios_Invalid(t *testing.T) {
+	for _, input := range []string{"OK | novalue", "OK | 'open=1", "OK | a=abc"} {
+		if _, err := ParseNagios([]byte(input)); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
+
+func TestParseInflux(t *testing.T) {
+	input := `# comment
+cpu,host=server\ 01,region=us-west usage_idle=98.5,usage_user=1i,online=true,note="a b" 1700000000000000000
+disk\,io,dev=sda reads=10u`
+
+	metrics, err := ParseInflux([]byte(input))
+	if err != nil {
+		t.Fatalf("ParseInflux failed: %v", err)
+	}
+	if len(metrics) != 4 {
+		t.Fatalf("expected 4 metrics, got %d", len(metrics))
+	}
+
+	m := findMetric(metrics, "cpu_usage_idle", map[string]string{"host": "server 01", "region": "us-west"})
+	if m == nil || m.Value != 98.5 || m.Timestamp != 1700000000 {
+		t.Errorf("unexpected metric: %+v", m)
+	}
+	if m := findMetric(metrics, "cpu_usage_user", nil); m == nil || m.Value != 1 {
+		t.Errorf("unexpected integer field: %+v", m)
+	}
+	if m := findMetric(metrics, "cpu_online", nil); m == nil || m.Value != 1 {
+		t.Errorf("unexpected bool field: %+v", m)
+	}
+	if m := findMetric(metrics, "disk_io_reads", map[string]string{"dev": "sda"}); m == nil || m.Value != 10 {
+		t.Errorf("unexpected escaped measurement: %+v", m)
+	}
+}
+
+func TestParseInflux_Invalid(t *testing.T) {
+	for _, input := range []string{"cpu", "cpu value=abc", "cpu,host value=1", "cpu value=1 notatime"} {
+		if _, err := ParseInflux([]byte(input)); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	t.Run("list", func(t *testing.T) {
+		input := `[{"name":"queue_depth","value":12,"labels":{"queue":"mail"}},{"name":"sent_total","value":3,"type":"counter"}]`
+		metrics, err := ParseJSON([]byte(input))
+		if err != nil {
+			t.Fatalf("ParseJSON failed: %v", err)
+		}
+		if m := findMetric(metrics, "queue_depth", map[string]string{"queue": "mail"}); m == nil || m.Value != 12 || m.Type != plugin.MetricTypeGauge {
+			t.Errorf("unexpected metric: %+v", m)
+		}
+		if m := findMetric(metrics, "sent_total", nil); m == nil || m.Type != plugin.MetricTypeCounter {
+			t.Errorf("unexpected counter: %+v", m)
+		}
+	})
+
+	t.Run("wrapped", func(t *testing.T) {
+		metrics, err := ParseJSON([]byte(`{"metrics":[{"name":"up","value":1}]}`))
+		if err != nil {
+			t.Fatalf("ParseJSON failed: %v", err)
+		}
+		if len(metrics) != 1 || metrics[0].Name != "up" {
+			t.Errorf("unexpected metrics: %+v", metrics)
+		}
+	})
+
+	t.Run("object", func(t *testing.T) {
+		input := `{"status":"ok","healthy":true,"disk":{"used":40.5,"free":59.5},"items":[1,2],"empty":null}`
+		metrics, err := ParseJSON([]byte(input))
+		if err != nil {
+			t.Fatalf("ParseJSON failed: %v", err)
+		}
+		if len(metrics) != 3 {
+			t.Fatalf("expected 3 metrics, got %d", len(metrics))
+		}
+		if m := findMetric(metrics, "disk_used", nil); m == nil || m.Value != 40.5 {
+			t.Errorf("unexpected nested metric: %+v", m)
+		}
+		if m := findMetric(metrics, "healthy", nil); m == nil || m.Value != 1 {
+			t.Errorf("unexpected bool metric: %+v", m)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, input := range []string{`{"a":`, `"text"`, `[{"value":1}]`} {
+			if _, err := ParseJSON([]byte(input)); err == nil {
+				t.Errorf("expected error for %q", input)
+			}
+		}
+	})
+}
+
+func TestSanitizeName(t *testing.T) {
+	cases := map[string]string{
+		"/":            "value",
+		"inode usage":  "inode_usage",
+		"/var/log":     "var_log",
+		"5xx-errors":   "_5xx_errors",
+		"CPU.Load(1m)": "cpu_load_1m",
+	}
+	for in, want := range cases {
+		if got := SanitizeName(in); got != want {
+			t.Errorf("SanitizeName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
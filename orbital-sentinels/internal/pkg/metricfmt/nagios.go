@@ -0,0 +1,177 @@
+package metricfmt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/celestial/orbital-sentinels/internal/plugin"
+)
+
+// Nagios 插件退出码
+const (
+	NagiosOK       = 0
+	NagiosWarning  = 1
+	NagiosCritical = 2
+	NagiosUnknown  = 3
+)
+
+// NagiosOutput Nagios 插件输出
+type NagiosOutput struct {
+	Status  string           // 第一行 | 之前的状态文本
+	Metrics []*plugin.Metric // 性能数据
+}
+
+// ParseNagios 解析 Nagios 插件输出
+// 格式为 "状态文本 | 性能数据" 加可选的多行长文本，长文本中 | 之后的内容也是性能数据。
+// 性能数据 'label'=value[UOM];[warn];[crit];[min];[max] 转换为以标签名命名的指标，
+// 单位记录在 unit 标签中，单位为 c 时为 counter。阈值不转换为指标。
+func ParseNagios(data []byte) (*NagiosOutput, error) {
+	text := strings.TrimSpace(string(data))
+	output := &NagiosOutput{}
+	if text == "" {
+		return output, nil
+	}
+
+	lines := strings.Split(text, "\n")
+	var perfdata []string
+
+	first := lines[0]
+	if i := strings.Index(first, "|"); i >= 0 {
+		perfdata = append(perfdata, first[i+1:])
+		first = first[:i]
+	}
+	output.Status = strings.TrimSpace(first)
+
+	// 长文本中第一个 | 之后的所有行都是性能数据
+	inPerfdata := false
+	for _, line := range lines[1:] {
+		if inPerfdata {
+			perfdata = append(perfdata, line)
+			continue
+		}
+		if i := strings.Index(line, "|"); i >= 0 {
+			perfdata = append(perfdata, line[i+1:])
+			inPerfdata = true
+		}
+	}
+
+	for _, chunk := range perfdata {
+		items, err := splitPerfdata(chunk)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			metric, err := parsePerfItem(item)
+			if err != nil {
+				return nil, err
+			}
+			if metric != nil {
+				output.Metrics = append(output.Metrics, metric)
+			}
+		}
+	}
+	return output, nil
+}
+
+// splitPerfdata 按空白切分性能数据，单引号内的空白不切分
+func splitPerfdata(s string) ([]string, error) {
+	var items []string
+	var current strings.Builder
+	quoted := false
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\'':
+			// 引号内的 '' 表示一个单引号
+			if quoted && i+1 < len(s) && s[i+1] == '\'' {
+				current.WriteByte('\'')
+				i++
+				continue
+			}
+			quoted = !quoted
+			current.WriteByte(c)
+		case !quoted && (c == ' ' || c == '\t' || c == '\r'):
+			if current.Len() > 0 {
+				items = append(items, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteByte(c)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in perfdata %q", s)
+	}
+	if current.Len() > 0 {
+		items = append(items, current.String())
+	}
+	return items, nil
+}
+
+// parsePerfItem 解析单个性能数据，值为 U（无法确定）时返回 nil
+func parsePerfItem(item string) (*plugin.Metric, error) {
+	eq := strings.LastIndex(item, "=")
+	if eq <= 0 {
+		return nil, fmt.Errorf("invalid perfdata %q", item)
+	}
+	label := strings.Trim(item[:eq], "'")
+	value := item[eq+1:]
+	if i := strings.Index(value, ";"); i >= 0 {
+		value = value[:i]
+	}
+	if value == "U" {
+		return nil, nil
+	}
+
+	// 数值后面是单位
+	end := len(value)
+	for end > 0 && !unicode.IsDigit(rune(value[end-1])) && value[end-1] != '.' {
+		end--
+	}
+	number, unit := value[:end], value[end:]
+	v, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid perfdata value %q", item)
+	}
+
+	metric := &plugin.Metric{
+		Name:   SanitizeName(label),
+		Value:  v,
+		Labels: map[string]string{},
+		Type:   plugin.MetricTypeGauge,
+	}
+	switch unit {
+	case "":
+	case "c":
+		metric.Type = plugin.MetricTypeCounter
+	default:
+		metric.Labels["unit"] = unit
+	}
+	return metric, nil
+}
+
+// SanitizeName 将任意文本转换为合法的指标名：非字母数字替换为下划线，不以数字开头
+func SanitizeName(s string) string {
+	var b strings.Builder
+	lastUnderscore := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			lastUnderscore = false
+		} else if !lastUnderscore {
+			b.WriteByte('_')
+			lastUnderscore = true
+		}
+	}
+	name := strings.Trim(b.String(), "_")
+	if name == "" {
+		return "value"
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
@@ -0,0 +1,259 @@
+// Package metricfmt 将常见的指标文本格式解析为插件指标
+// 支持 Prometheus 文本格式（含 OpenMetrics）、Nagios 插件输出、InfluxDB 行协议和 JSON。
+// 解析结果的时间戳为输入中携带的时间（秒），未携带时为 0，由调用方填充。
+package metricfmt
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/celestial/orbital-sentinels/internal/plugin"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/textparse"
+)
+
+// Prometheus 文本格式的 Content-Type
+const (
+	ContentTypePrometheus  = "text/plain; version=0.0.4"
+	ContentTypeOpenMetrics = "application/openmetrics-text"
+)
+
+// ParsePrometheus 解析 Prometheus 文本格式，contentType 为空或无法识别时按 Prometheus 文本格式解析
+// 计数器和仪表的每个序列转换为一个指标；直方图、摘要的 _bucket、_sum、_count 和分位数序列按指标族和标签
+// 合并为一个指标，分布数据在 Histogram、Summary 中。类型取所属指标族的 # TYPE 声明，OpenMetrics 的 _created 序列忽略。
+func ParsePrometheus(data []byte, contentType string) ([]*plugin.Metric, error) {
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		data = append(data[:len(data):len(data)], '\n')
+	}
+
+	parser, err := textparse.New(data, contentType, labels.NewSymbolTable(), textparse.ParserOptions{
+		FallbackContentType:     "text/plain",
+		OpenMetricsSkipCTSeries: true,
+	})
+	if parser == nil {
+		return nil, fmt.Errorf("unsupported content type %q: %w", contentType, err)
+	}
+
+	types := make(map[string]model.MetricType)
+	distributions := make(map[string]*distribution)
+	var metrics []*plugin.Metric
+	for {
+		entry, err := parser.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch entry {
+		case textparse.EntryType:
+			name, typ := parser.Type()
+			types[string(name)] = typ
+		case textparse.EntrySeries:
+			_, ts, value := parser.Series()
+			var lset labels.Labels
+			parser.Labels(&lset)
+
+			name := lset.Get(labels.MetricName)
+			family, typ := prometheusFamily(name, types)
+			metricType := toMetricType(typ)
+
+			if metricType == plugin.MetricTypeHistogram || metricType == plugin.MetricTypeSummary {
+				key := family + labels.NewBuilder(lset).Del(labels.MetricName, model.BucketLabel, model.QuantileLabel).Labels().String()
+				d, ok := distributions[key]
+				if !ok {
+					d = newDistribution(family, metricType, lset)
+				}
+				if d.add(name, lset, value) {
+					if !ok {
+						distributions[key] = d
+						metrics = append(metrics, d.metric)
+					}
+					if ts != nil && d.metric.Timestamp == 0 {
+						d.metric.Timestamp = *ts / 1000
+					}
+					continue
+				}
+				// 不属于 _bucket、_sum、_count 和分位数的序列按仪表处理
+				metricType = plugin.MetricTypeGauge
+			}
+
+			metric := &plugin.Metric{
+				Name:   name,
+				Value:  value,
+				Labels: make(map[string]string, lset.Len()),
+				Type:   metricType,
+			}
+			lset.Range(func(l labels.Label) {
+				if l.Name != labels.MetricName {
+					metric.Labels[l.Name] = l.Value
+				}
+			})
+			if ts != nil {
+				metric.Timestamp = *ts / 1000
+			}
+			metrics = append(metrics, metric)
+		}
+	}
+
+	for _, d := range distributions {
+		d.finish()
+	}
+	return metrics, nil
+}
+
+// distribution 合并中的直方图或摘要
+type distribution struct {
+	metric   *plugin.Metric
+	count    float64
+	hasCount bool
+	inf      float64
+	hasInf   bool
+}
+
+// newDistribution 创建直方图或摘要，标签去掉 le、quantile
+func newDistribution(family string, metricType plugin.MetricType, lset labels.Labels) *distribution {
+	m := &plugin.Metric{
+		Name:   family,
+		Labels: make(map[string]string, lset.Len()),
+		Type:   metricType,
+	}
+	lset.Range(func(l labels.Label) {
+		switch l.Name {
+		case labels.MetricName, model.BucketLabel, model.QuantileLabel:
+		default:
+			m.Labels[l.Name] = l.Value
+		}
+	})
+	if metricType == plugin.MetricTypeHistogram {
+		m.Histogram = &plugin.Histogram{}
+	} else {
+		m.Summary = &plugin.Summary{}
+	}
+	return &distribution{metric: m}
+}
+
+// add 合并一个序列，序列不属于该指标族的分布数据时返回 false
+// 非有限值无法编码为 JSON，对应的桶、分位数和总和忽略。
+func (d *distribution) add(name string, lset labels.Labels, value float64) bool {
+	family := d.metric.Name
+	finite := !math.IsNaN(value) && !math.IsInf(value, 0)
+
+	switch name {
+	case family + "_sum", family + "_gsum":
+		if finite {
+			d.setSum(value)
+		}
+		return true
+	case family + "_count", family + "_gcount":
+		d.count, d.hasCount = value, true
+		return true
+	}
+
+	if h := d.metric.Histogram; h != nil && name == family+"_bucket" && lset.Has(model.BucketLabel) {
+		le, err := strconv.ParseFloat(lset.Get(model.BucketLabel), 64)
+		if err != nil {
+			return false
+		}
+		switch {
+		case math.IsInf(le, +1):
+			d.inf, d.hasInf = value, true
+		case finite && !math.IsNaN(le):
+			h.Buckets = append(h.Buckets, plugin.Bucket{UpperBound: le, Count: toCount(value)})
+		}
+		return true
+	}
+
+	if s := d.metric.Summary; s != nil && name == family && lset.Has(model.QuantileLabel) {
+		q, err := strconv.ParseFloat(lset.Get(model.QuantileLabel), 64)
+		if err != nil {
+			return false
+		}
+		if finite && !math.IsNaN(q) {
+			s.Quantiles = append(s.Quantiles, plugin.Quantile{Quantile: q, Value: value})
+		}
+		return true
+	}
+	return false
+}
+
+// setSum 设置总和
+func (d *distribution) setSum(sum float64) {
+	if h := d.metric.Histogram; h != nil {
+		h.Sum = sum
+	} else {
+		d.metric.Summary.Sum = sum
+	}
+}
+
+// finish 排序桶和分位数并确定观测总数
+// 缺少 _count 序列时直方图取 +Inf 桶的计数。
+func (d *distribution) finish() {
+	count := d.count
+	if !d.hasCount && d.hasInf {
+		count = d.inf
+	}
+
+	if h := d.metric.Histogram; h != nil {
+		sort.Slice(h.Buckets, func(i, j int) bool { return h.Buckets[i].UpperBound < h.Buckets[j].UpperBound })
+		if !d.hasCount && !d.hasInf && len(h.Buckets) > 0 {
+			count = float64(h.Buckets[len(h.Buckets)-1].Count)
+		}
+		h.Count = toCount(count)
+		d.metric.Value = float64(h.Count)
+		return
+	}
+
+	s := d.metric.Summary
+	sort.Slice(s.Quantiles, func(i, j int) bool { return s.Quantiles[i].Quantile < s.Quantiles[j].Quantile })
+	s.Count = toCount(count)
+	d.metric.Value = float64(s.Count)
+}
+
+// toCount 将计数样本转换为整数，负数和非有限值按 0 处理
+func toCount(value float64) uint64 {
+	if math.IsNaN(value) || value <= 0 {
+		return 0
+	}
+	if value >= math.MaxUint64 {
+		return math.MaxUint64
+	}
+	return uint64(value)
+}
+
+// prometheusFamily 根据 # TYPE 声明确定序列所属的指标族和类型
+// 直方图和摘要的 _bucket、_sum、_count 序列按所属指标族返回，未声明类型的为 unknown。
+func prometheusFamily(name string, types map[string]model.MetricType) (string, model.MetricType) {
+	if typ, ok := types[name]; ok {
+		return name, typ
+	}
+	for _, suffix := range []string{"_total", "_bucket", "_sum", "_count", "_gsum", "_gcount", "_created"} {
+		if family := strings.TrimSuffix(name, suffix); family != name {
+			if typ, ok := types[family]; ok {
+				return family, typ
+			}
+		}
+	}
+	return name, model.MetricTypeUnknown
+}
+
+// toMetricType 将 Prometheus 指标类型转换为插件指标类型，未声明类型的为 gauge
+func toMetricType(typ model.MetricType) plugin.MetricType {
+	switch typ {
+	case model.MetricTypeCounter:
+		return plugin.MetricTypeCounter
+	case model.MetricTypeHistogram, model.MetricTypeGaugeHistogram:
+		return plugin.MetricTypeHistogram
+	case model.MetricTypeSummary:
+		return plugin.MetricTypeSummary
+	default:
+		return plugin.MetricTypeGauge
+	}
+}
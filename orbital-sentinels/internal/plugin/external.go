@@ -0,0 +1,474 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/celestial/orbital-sentinels/internal/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// RuntimeConfig 外部插件运行配置，在 plugin.yaml 的 runtime 段声明
+type RuntimeConfig struct {
+	Command        string                 `yaml:"command"` // 可执行文件，相对路径基于插件目录
+	Args           []string               `yaml:"args"`
+	Env            map[string]string      `yaml:"env"`
+	Config         map[string]interface{} `yaml:"config"`          // 传给 Init 的插件级配置
+	StartTimeout   time.Duration          `yaml:"start_timeout"`   // 握手超时
+	CallTimeout    time.Duration          `yaml:"call_timeout"`    // 非采集调用的超时
+	HealthInterval time.Duration          `yaml:"health_interval"` // 健康检查间隔
+	Limits         ResourceLimits         `yaml:"limits"`
+}
+
+// ResourceLimits 插件进程资源限制，0 表示不限制
+type ResourceLimits struct {
+	MaxConcurrency int `yaml:"max_concurrency"` // 同时进行的采集数
+	MemoryMB       int `yaml:"memory_mb"`       // 常驻内存上限，健康检查时超过则重启，仅 Linux
+	OpenFiles      int `yaml:"open_files"`      // 文件描述符上限，仅 Linux
+	Nice           int `yaml:"nice"`            // 进程优先级（-20~19），仅 Linux
+}
+
+// 外部插件默认参数
+const (
+	defaultStartTimeout   = 10 * time.Second
+	defaultCallTimeout    = 30 * time.Second
+	defaultHealthInterval = 30 * time.Second
+	maxHealthFailures     = 3
+	stopTimeout           = 5 * time.Second
+	drainTimeout          = 2 * time.Minute // 热加载时等待旧实例上进行中的调用
+)
+
+// 崩溃重启退避，进程稳定运行超过 restartResetAfter 后退避重置
+var (
+	restartBackoffMin = time.Second
+	restartBackoffMax = time.Minute
+	restartResetAfter = time.Minute
+)
+
+func (rt RuntimeConfig) command(dir string) string {
+	if filepath.IsAbs(rt.Command) {
+		return rt.Command
+	}
+	return filepath.Join(dir, rt.Command)
+}
+
+func (rt RuntimeConfig) startTimeout() time.Duration {
+	if rt.StartTimeout > 0 {
+		return rt.StartTimeout
+	}
+	return defaultStartTimeout
+}
+
+func (rt RuntimeConfig) callTimeout() time.Duration {
+	if rt.CallTimeout > 0 {
+		return rt.CallTimeout
+	}
+	return defaultCallTimeout
+}
+
+func (rt RuntimeConfig) healthInterval() time.Duration {
+	if rt.HealthInterval > 0 {
+		return rt.HealthInterval
+	}
+	return defaultHealthInterval
+}
+
+// ExternalPlugin 以独立进程运行的插件
+// 实现 Plugin 接口，调用通过标准输入输出转发给插件进程。
+// 进程崩溃或健康检查连续失败时按指数退避重启，重启后重新执行 Init。
+// 热加载时旧实例被新实例取代，之后的调用转给新实例，进行中的调用完成后关闭。
+type ExternalPlugin struct {
+	dir      string
+	runtime  RuntimeConfig
+	sem      chan struct{}  // 采集并发限制
+	inflight sync.WaitGroup // 进行中的调用
+
+	mu        sync.RWMutex
+	schema    PluginSchema
+	config    map[string]interface{}
+	proc      *process
+	restarts  int
+	closed    bool
+	retired   bool
+	successor *ExternalPlugin // 热加载后取代本实例的新实例，卸载时为 nil
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewExternalPlugin 创建外部插件，schema 为 plugin.yaml 中的声明，启动后以插件返回的为准
+func NewExternalPlugin(dir string, schema PluginSchema, runtime RuntimeConfig) *ExternalPlugin {
+	p := &ExternalPlugin{
+		dir:     dir,
+		runtime: runtime,
+		schema:  schema,
+		config:  runtime.Config,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if runtime.Limits.MaxConcurrency > 0 {
+		p.sem = make(chan struct{}, runtime.Limits.MaxConcurrency)
+	}
+	return p
+}
+
+// Start 启动插件进程并开始守护，首次启动失败时返回错误
+func (p *ExternalPlugin) Start() error {
+	proc, err := p.launch()
+	if err != nil {
+		close(p.done)
+		return err
+	}
+
+	p.mu.Lock()
+	p.proc = proc
+	p.mu.Unlock()
+
+	go p.supervise(proc)
+	return nil
+}
+
+// launch 启动进程、获取 Schema 并执行 Init
+func (p *ExternalPlugin) launch() (*process, error) {
+	name := p.name()
+	proc, err := startProcess(name, p.dir, p.runtime)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.runtime.callTimeout())
+	defer cancel()
+
+	var schema wireSchema
+	if err := proc.call(ctx, MethodSchema, nil, &schema); err != nil {
+		proc.kill()
+		return nil, fmt.Errorf("failed to get plugin schema: %w", err)
+	}
+	if schema.Meta.Name != name {
+		proc.kill()
+		return nil, fmt.Errorf("plugin reports name %q, expected %q", schema.Meta.Name, name)
+	}
+
+	p.mu.RLock()
+	config := p.config
+	p.mu.RUnlock()
+	if err := proc.call(ctx, MethodInit, configParams{Config: config}, nil); err != nil {
+		proc.kill()
+		return nil, fmt.Errorf("failed to init plugin: %w", err)
+	}
+
+	p.mu.Lock()
+	p.schema = fromWireSchema(schema)
+	p.mu.Unlock()
+	return proc, nil
+}
+
+// supervise 健康检查，进程退出后按退避重启，直到 Close
+func (p *ExternalPlugin) supervise(proc *process) {
+	defer close(p.done)
+
+	backoff := restartBackoffMin
+	for {
+		p.watch(proc)
+
+		select {
+		case <-p.stop:
+			return
+		default:
+		}
+
+		if time.Since(proc.started) > restartResetAfter {
+			backoff = restartBackoffMin
+		}
+		logger.Warn("Plugin process exited, restarting",
+			zap.String("plugin", p.name()),
+			zap.Error(proc.exitErr()),
+			zap.Duration("backoff", backoff))
+
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-time.After(backoff):
+			}
+			backoff = minDuration(backoff*2, restartBackoffMax)
+
+			next, err := p.launch()
+			if err != nil {
+				logger.Error("Failed to restart plugin", zap.String("plugin", p.name()), zap.Error(err))
+				continue
+			}
+
+			p.mu.Lock()
+			if p.closed {
+				p.mu.Unlock()
+				next.stop(stopTimeout)
+				return
+			}
+			p.proc = next
+			p.restarts++
+			p.mu.Unlock()
+
+			logger.Info("Plugin restarted", zap.String("plugin", p.name()), zap.Int("restarts", p.Restarts()))
+			proc = next
+			break
+		}
+	}
+}
+
+// watch 定期健康检查，直到进程退出或插件关闭
+// 连续多次检查失败或内存超限时结束进程。
+func (p *ExternalPlugin) watch(proc *process) {
+	ticker := time.NewTicker(p.runtime.healthInterval())
+	defer ticker.Stop()
+
+	failures := 0
+	for {
+		select {
+		case <-proc.exited:
+			return
+		case <-p.stop:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), p.runtime.callTimeout())
+		err := proc.call(ctx, MethodPing, nil, nil)
+		cancel()
+		if err != nil {
+			failures++
+			logger.Warn("Plugin health check failed",
+				zap.String("plugin", p.name()),
+				zap.Int("failures", failures),
+				zap.Error(err))
+			if failures >= maxHealthFailures {
+				proc.kill()
+				return
+			}
+			continue
+		}
+		failures = 0
+
+		if limit := p.runtime.Limits.MemoryMB; limit > 0 {
+			rss, err := residentMemory(proc.cmd.Process.Pid)
+			if err == nil && rss > uint64(limit)<<20 {
+				logger.Warn("Plugin exceeded memory limit, restarting",
+					zap.String("plugin", p.name()),
+					zap.Uint64("rss_bytes", rss),
+					zap.Int("limit_mb", limit))
+				proc.kill()
+				return
+			}
+		}
+	}
+}
+
+// Restarts 返回进程重启次数
+func (p *ExternalPlugin) Restarts() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.restarts
+}
+
+// Meta 返回插件元信息
+func (p *ExternalPlugin) Meta() PluginMeta {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.schema.Meta
+}
+
+// Schema 返回配置 Schema
+func (p *ExternalPlugin) Schema() PluginSchema {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.schema
+}
+
+// Init 初始化插件，配置会在进程重启后重新下发
+func (p *ExternalPlugin) Init(config map[string]interface{}) error {
+	next, err := p.enter()
+	if err != nil {
+		return err
+	}
+	if next != nil {
+		return next.Init(config)
+	}
+	defer p.inflight.Done()
+
+	p.mu.Lock()
+	p.config = config
+	p.mu.Unlock()
+	return p.callWithTimeout(MethodInit, configParams{Config: config}, nil)
+}
+
+// ValidateConfig 验证设备配置
+func (p *ExternalPlugin) ValidateConfig(deviceConfig map[string]interface{}) error {
+	next, err := p.enter()
+	if err != nil {
+		return err
+	}
+	if next != nil {
+		return next.ValidateConfig(deviceConfig)
+	}
+	defer p.inflight.Done()
+
+	return p.callWithTimeout(MethodValidateConfig, configParams{Config: deviceConfig}, nil)
+}
+
+// TestConnection 测试连接
+func (p *ExternalPlugin) TestConnection(deviceConfig map[string]interface{}) error {
+	next, err := p.enter()
+	if err != nil {
+		return err
+	}
+	if next != nil {
+		return next.TestConnection(deviceConfig)
+	}
+	defer p.inflight.Done()
+
+	return p.callWithTimeout(MethodTestConnection, configParams{Config: deviceConfig}, nil)
+}
+
+// Collect 采集数据，超过并发限制时排队等待
+func (p *ExternalPlugin) Collect(ctx context.Context, task *CollectionTask) ([]*Metric, error) {
+	next, err := p.enter()
+	if err != nil {
+		return nil, err
+	}
+	if next != nil {
+		return next.Collect(ctx, task)
+	}
+	defer p.inflight.Done()
+
+	if p.sem != nil {
+		select {
+		case p.sem <- struct{}{}:
+			defer func() { <-p.sem }()
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	proc, err := p.current()
+	if err != nil {
+		return nil, err
+	}
+
+	var result collectResult
+	if err := proc.call(ctx, MethodCollect, toWireTask(task), &result); err != nil {
+		return nil, err
+	}
+	return fromWireMetrics(result.Metrics), nil
+}
+
+// Close 停止守护并关闭插件进程
+func (p *ExternalPlugin) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	proc := p.proc
+	p.proc = nil
+	p.mu.Unlock()
+
+	close(p.stop)
+	if proc != nil {
+		proc.stop(stopTimeout)
+	}
+	<-p.done
+	return nil
+}
+
+// enter 登记一次调用；实例已被取代时返回新实例，已卸载时返回错误
+func (p *ExternalPlugin) enter() (*ExternalPlugin, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.retired {
+		if p.successor != nil {
+			return p.successor, nil
+		}
+		return nil, fmt.Errorf("plugin %s has been unloaded", p.schema.Meta.Name)
+	}
+	p.inflight.Add(1)
+	return nil, nil
+}
+
+// retire 由 successor 取代本实例（为 nil 表示卸载），之后的调用转给 successor 或返回错误
+func (p *ExternalPlugin) retire(successor *ExternalPlugin) {
+	p.mu.Lock()
+	p.retired = true
+	p.successor = successor
+	p.mu.Unlock()
+}
+
+// drain 等待进行中的调用完成后关闭，超时或 stop 关闭时不再等待
+func (p *ExternalPlugin) drain(timeout time.Duration, stop <-chan struct{}) {
+	drained := make(chan struct{})
+	go func() {
+		p.inflight.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+	case <-time.After(timeout):
+		logger.Warn("Timed out draining plugin calls, closing anyway",
+			zap.String("plugin", p.name()),
+			zap.Duration("timeout", timeout))
+	case <-stop:
+	}
+
+	p.Close()
+}
+
+// callWithTimeout 以默认超时调用插件方法
+func (p *ExternalPlugin) callWithTimeout(method string, params, result interface{}) error {
+	proc, err := p.current()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), p.runtime.callTimeout())
+	defer cancel()
+	return proc.call(ctx, method, params, result)
+}
+
+// current 返回当前进程，进程不可用时返回错误
+func (p *ExternalPlugin) current() (*process, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return nil, errors.New("plugin is closed")
+	}
+	if p.proc == nil {
+		return nil, fmt.Errorf("plugin %s is not running", p.schema.Meta.Name)
+	}
+	select {
+	case <-p.proc.exited:
+		return nil, fmt.Errorf("plugin %s is restarting", p.schema.Meta.Name)
+	default:
+	}
+	return p.proc, nil
+}
+
+func (p *ExternalPlugin) name() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.schema.Meta.Name
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// 确保实现了接口
+var _ Plugin = (*ExternalPlugin)(nil)
@@ -0,0 +1,280 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// envTestHelper 测试二进制以外部插件方式运行
+const envTestHelper = "CELESTIAL_PLUGIN_TEST_HELPER"
+
+// envTestVersion 测试插件上报的版本
+const envTestVersion = "CELESTIAL_PLUGIN_TEST_VERSION"
+
+func TestMain(m *testing.M) {
+	switch os.Getenv(envTestHelper) {
+	case "serve":
+		if err := Serve(&helperPlugin{}); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	case "bad-version":
+		fmt.Println(`{"protocol":"celestial-plugin","version":99}`)
+		time.Sleep(time.Minute)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// helperPlugin 在子进程中运行的测试插件
+type helperPlugin struct {
+	config map[string]interface{}
+}
+
+func (h *helperPlugin) Meta() PluginMeta {
+	version := os.Getenv(envTestVersion)
+	if version == "" {
+		version = "1.0.0"
+	}
+	return PluginMeta{Name: "helper", Version: version}
+}
+
+func (h *helperPlugin) Schema() PluginSchema {
+	return PluginSchema{DeviceFields: []DeviceField{{Name: "host", Type: "string", Required: true}}}
+}
+
+func (h *helperPlugin) Init(config map[string]interface{}) error {
+	h.config = config
+	return nil
+}
+
+func (h *helperPlugin) ValidateConfig(deviceConfig map[string]interface{}) error {
+	if _, ok := deviceConfig["host"]; !ok {
+		return errors.New("host is required")
+	}
+	return nil
+}
+
+func (h *helperPlugin) TestConnection(deviceConfig map[string]interface{}) error {
+	return nil
+}
+
+func (h *helperPlugin) Collect(ctx context.Context, task *CollectionTask) ([]*Metric, error) {
+	switch task.DeviceConfig["mode"] {
+	case "crash":
+		os.Exit(2)
+	case "block":
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	site, _ := h.config["site"].(string)
+	return []*Metric{{
+		Name:      "helper_up",
+		Value:     1,
+		Timestamp: time.Now().Unix(),
+		Labels:    map[string]string{"device_id": task.DeviceID, "site": site},
+	}}, nil
+}
+
+func (h *helperPlugin) Close() error {
+	return nil
+}
+
+// helperRuntime 以测试二进制作为插件可执行文件
+func helperRuntime(t *testing.T, mode string) RuntimeConfig {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatalf("os.Executable: %v", err)
+	}
+	return RuntimeConfig{
+		Command:      exe,
+		Env:          map[string]string{envTestHelper: mode},
+		Config:       map[string]interface{}{"site": "dc1"},
+		StartTimeout: 5 * time.Second,
+		CallTimeout:  5 * time.Second,
+	}
+}
+
+func startHelper(t *testing.T, rt RuntimeConfig) *ExternalPlugin {
+	p := NewExternalPlugin(t.TempDir(), PluginSchema{Meta: PluginMeta{Name: "helper"}}, rt)
+	if err := p.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	t.Cleanup(func() { p.Close() })
+	return p
+}
+
+func collectHelper(p *ExternalPlugin, ctx context.Context, mode string) ([]*Metric, error) {
+	return p.Collect(ctx, &CollectionTask{
+		DeviceID:     "dev-1",
+		DeviceConfig: map[string]interface{}{"host": "10.0.0.1", "mode": mode},
+	})
+}
+
+func TestExternalPlugin_Calls(t *testing.T) {
+	p := startHelper(t, helperRuntime(t, "serve"))
+
+	// Schema 以插件进程返回的为准
+	if meta := p.Meta(); meta.Name != "helper" || meta.Version != "1.0.0" {
+		t.Errorf("meta = %+v", meta)
+	}
+	if fields := p.Schema().DeviceFields; len(fields) != 1 || fields[0].Name != "host" || !fields[0].Required {
+		t.Errorf("device fields = %+v", fields)
+	}
+
+	metrics, err := collectHelper(p, context.Background(), "")
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if len(metrics) != 1 || metrics[0].Name != "helper_up" || metrics[0].Type != MetricTypeGauge {
+		t.Fatalf("metrics = %+v", metrics)
+	}
+	if metrics[0].Labels["device_id"] != "dev-1" || metrics[0].Labels["site"] != "dc1" {
+		t.Errorf("labels = %v", metrics[0].Labels)
+	}
+
+	if err := p.ValidateConfig(map[string]interface{}{}); err == nil || err.Error() != "host is required" {
+		t.Errorf("ValidateConfig error = %v", err)
+	}
+	if err := p.TestConnection(map[string]interface{}{"host": "10.0.0.1"}); err != nil {
+		t.Errorf("TestConnection failed: %v", err)
+	}
+
+	if err := p.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+	if _, err := collectHelper(p, context.Background(), ""); err == nil {
+		t.Error("Collect after Close should fail")
+	}
+}
+
+func TestExternalPlugin_CancelCollect(t *testing.T) {
+	p := startHelper(t, helperRuntime(t, "serve"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := collectHelper(p, ctx, "block"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Collect error = %v, want deadline exceeded", err)
+	}
+
+	// 取消后进程仍可用
+	if _, err := collectHelper(p, context.Background(), ""); err != nil {
+		t.Errorf("Collect after cancel failed: %v", err)
+	}
+}
+
+func TestExternalPlugin_RestartAfterCrash(t *testing.T) {
+	backoff := restartBackoffMin
+	restartBackoffMin = 10 * time.Millisecond
+	defer func() { restartBackoffMin = backoff }()
+
+	p := startHelper(t, helperRuntime(t, "serve"))
+
+	if _, err := collectHelper(p, context.Background(), "crash"); !errors.Is(err, errProcessExited) {
+		t.Fatalf("Collect error = %v, want process exited", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for p.Restarts() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("plugin was not restarted")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// 重启后重新下发 Init 配置
+	metrics, err := collectHelper(p, context.Background(), "")
+	if err != nil {
+		t.Fatalf("Collect after restart failed: %v", err)
+	}
+	if metrics[0].Labels["site"] != "dc1" {
+		t.Errorf("labels = %v", metrics[0].Labels)
+	}
+}
+
+func TestExternalPlugin_MaxConcurrency(t *testing.T) {
+	rt := helperRuntime(t, "serve")
+	rt.Limits.MaxConcurrency = 1
+	p := startHelper(t, rt)
+
+	blockCtx, release := context.WithCancel(context.Background())
+	blocked := make(chan struct{})
+	go func() {
+		defer close(blocked)
+		collectHelper(p, blockCtx, "block")
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// 唯一的并发名额被占用，排队直到超时
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := collectHelper(p, ctx, ""); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Collect error = %v, want deadline exceeded", err)
+	}
+
+	release()
+	<-blocked
+	if _, err := collectHelper(p, context.Background(), ""); err != nil {
+		t.Errorf("Collect after release failed: %v", err)
+	}
+}
+
+func TestExternalPlugin_HandshakeVersionMismatch(t *testing.T) {
+	p := NewExternalPlugin(t.TempDir(), PluginSchema{Meta: PluginMeta{Name: "helper"}}, helperRuntime(t, "bad-version"))
+	err := p.Start()
+	if err == nil {
+		p.Close()
+		t.Fatal("Start should fail on protocol version mismatch")
+	}
+	if want := "unsupported protocol version 99"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %v, want %q", err, want)
+	}
+}
+
+func TestManager_LoadExternalPlugin(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatalf("os.Executable: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "helper"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := fmt.Sprintf(`meta:
+  name: helper
+  version: "0.0.1"
+runtime:
+  command: %q
+  env:
+    %s: serve
+  start_timeout: 5s
+`, exe, envTestHelper)
+	if err := os.WriteFile(filepath.Join(dir, "helper", "plugin.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mgr := NewManager(dir)
+	if err := mgr.LoadAll(); err != nil {
+		t.Fatalf("LoadAll failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	p, ok := mgr.GetPlugin("helper")
+	if !ok {
+		t.Fatal("external plugin not registered")
+	}
+	if _, err := p.Collect(context.Background(), &CollectionTask{DeviceID: "dev-1", DeviceConfig: map[string]interface{}{}}); err != nil {
+		t.Errorf("Collect failed: %v", err)
+	}
+	if schema, _ := mgr.GetSchema("helper"); schema.Meta.Version != "1.0.0" {
+		t.Errorf("schema version = %q, want version reported by plugin", schema.Meta.Version)
+	}
+}
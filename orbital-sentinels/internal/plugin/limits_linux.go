@@ -0,0 +1,50 @@
+package plugin
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// applyLimits 设置插件进程的文件描述符上限和优先级
+func applyLimits(pid int, limits ResourceLimits) error {
+	if limits.OpenFiles > 0 {
+		rlimit := unix.Rlimit{Cur: uint64(limits.OpenFiles), Max: uint64(limits.OpenFiles)}
+		if err := unix.Prlimit(pid, unix.RLIMIT_NOFILE, &rlimit, nil); err != nil {
+			return fmt.Errorf("set open files limit: %w", err)
+		}
+	}
+	if limits.Nice != 0 {
+		if err := unix.Setpriority(unix.PRIO_PROCESS, pid, limits.Nice); err != nil {
+			return fmt.Errorf("set nice: %w", err)
+		}
+	}
+	return nil
+}
+
+// residentMemory 读取进程常驻内存（字节）
+func residentMemory(pid int) (uint64, error) {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// VmRSS:     12345 kB
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "VmRSS:" {
+			kb, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return 0, err
+			}
+			return kb << 10, nil
+		}
+	}
+	return 0, fmt.Errorf("VmRSS not found for pid %d", pid)
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+
+package plugin
+
+import "errors"
+
+// applyLimits 非 Linux 平台只支持并发限制
+func applyLimits(pid int, limits ResourceLimits) error {
+	if limits.OpenFiles > 0 || limits.Nice != 0 {
+		return errors.New("open_files and nice limits are only supported on linux")
+	}
+	return nil
+}
+
+// residentMemory 非 Linux 平台不检查内存
+func residentMemory(pid int) (uint64, error) {
+	return 0, errors.New("memory limit is only supported on linux")
+}
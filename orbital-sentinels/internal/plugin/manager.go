@@ -1,6 +1,8 @@
 package plugin
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,12 +13,33 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+// pluginManifest plugin.yaml 文件内容
+type pluginManifest struct {
+	PluginSchema `yaml:",inline"`
+	Runtime      RuntimeConfig `yaml:"runtime"` // 外部插件运行配置
+}
+
 // Manager 插件管理器
 type Manager struct {
 	plugins   map[string]Plugin
 	schemas   map[string]PluginSchema
 	directory string
 	mu        sync.RWMutex
+
+	loadMu   sync.Mutex              // 串行化加载和热加载
+	sources  map[string]pluginSource // 插件目录名 -> 加载记录
+	onChange func()                  // 热加载后插件或版本有变化时调用
+	stopped  bool
+
+	retiring sync.WaitGroup // 后台排空中的旧实例
+	stopping chan struct{}  // StopAll 时关闭，旧实例不再等待排空
+}
+
+// pluginSource 插件目录的加载记录
+type pluginSource struct {
+	name        string
+	fingerprint string // plugin.yaml 和可执行文件的指纹，变化时重新加载
+	external    bool
 }
 
 // NewManager 创建插件管理器
@@ -25,11 +48,16 @@ func NewManager(directory string) *Manager {
 		plugins:   make(map[string]Plugin),
 		schemas:   make(map[string]PluginSchema),
 		directory: directory,
+		sources:   make(map[string]pluginSource),
+		stopping:  make(chan struct{}),
 	}
 }
 
 // LoadAll 加载所有插件
 func (m *Manager) LoadAll() error {
+	m.loadMu.Lock()
+	defer m.loadMu.Unlock()
+
 	entries, err := os.ReadDir(m.directory)
 	if err != nil {
 		return fmt.Errorf("failed to read plugin directory: %w", err)
@@ -53,35 +81,106 @@ func (m *Manager) LoadAll() error {
 	return nil
 }
 
-// loadPlugin 加载单个插件
+// loadPlugin 加载单个插件，目录已加载过时替换为新版本
 func (m *Manager) loadPlugin(pluginPath string) error {
-	// 读取插件配置文件
-	schemaPath := filepath.Join(pluginPath, "plugin.yaml")
-	data, err := os.ReadFile(schemaPath)
+	manifest, fingerprint, err := readManifest(pluginPath)
 	if err != nil {
-		return fmt.Errorf("failed to read plugin schema: %w", err)
+		return err
 	}
+	schema := manifest.PluginSchema
+	key := filepath.Base(pluginPath)
+	previous, reloading := m.sources[key]
 
-	var schema PluginSchema
-	if err := yaml.Unmarshal(data, &schema); err != nil {
-		return fmt.Errorf("failed to parse plugin schema: %w", err)
+	// 内置插件编译在 Sentinel 中，只有声明了 runtime.command 的外部插件需要启动进程
+	if manifest.Runtime.Command == "" {
+		if reloading && (previous.external || previous.name != schema.Meta.Name) {
+			m.unload(previous)
+		}
+
+		m.mu.Lock()
+		m.schemas[schema.Meta.Name] = schema
+		m.mu.Unlock()
+		m.sources[key] = pluginSource{name: schema.Meta.Name, fingerprint: fingerprint}
+
+		logger.Info("Loaded plugin schema",
+			zap.String("name", schema.Meta.Name),
+			zap.String("version", schema.Meta.Version))
+		return nil
 	}
 
-	// 注意：这里需要根据实际的插件加载方式来实现
-	// 由于 Go Plugin 在某些平台上有限制，这里先存储 schema
-	// 实际的插件实例化需要在具体实现中完成
+	external := NewExternalPlugin(pluginPath, schema, manifest.Runtime)
+	if err := external.Start(); err != nil {
+		return fmt.Errorf("failed to start external plugin: %w", err)
+	}
 
+	// 同一目录的旧实例原子替换，其他同名插件（如内置插件）视为冲突
+	var old *ExternalPlugin
 	m.mu.Lock()
-	m.schemas[schema.Meta.Name] = schema
+	if current, exists := m.plugins[schema.Meta.Name]; exists {
+		old, _ = current.(*ExternalPlugin)
+		if !reloading || previous.name != schema.Meta.Name || old == nil {
+			m.mu.Unlock()
+			external.Close()
+			return fmt.Errorf("plugin %s already registered", schema.Meta.Name)
+		}
+	}
+	m.plugins[schema.Meta.Name] = external
+	// 以插件进程返回的 Schema 为准
+	m.schemas[schema.Meta.Name] = external.Schema()
 	m.mu.Unlock()
+	m.sources[key] = pluginSource{name: schema.Meta.Name, fingerprint: fingerprint, external: true}
 
-	logger.Info("Loaded plugin schema",
-		zap.String("name", schema.Meta.Name),
-		zap.String("version", schema.Meta.Version))
+	meta := external.Meta()
+	if old == nil {
+		logger.Info("Registered plugin",
+			zap.String("name", meta.Name),
+			zap.String("version", meta.Version))
+	} else {
+		logger.Info("Reloaded plugin",
+			zap.String("name", meta.Name),
+			zap.String("old_version", old.Meta().Version),
+			zap.String("version", meta.Version))
+		m.retire(old, external)
+	}
 
+	// 插件改名时卸载旧名称
+	if reloading && previous.name != schema.Meta.Name {
+		m.unload(previous)
+	}
 	return nil
 }
 
+// readManifest 读取 plugin.yaml，返回内容和目录指纹
+func readManifest(pluginPath string) (*pluginManifest, string, error) {
+	// 读取插件配置文件
+	schemaPath := filepath.Join(pluginPath, "plugin.yaml")
+	data, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read plugin schema: %w", err)
+	}
+
+	var manifest pluginManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, "", fmt.Errorf("failed to parse plugin schema: %w", err)
+	}
+	if manifest.Meta.Name == "" {
+		return nil, "", fmt.Errorf("plugin name is required")
+	}
+
+	// 可执行文件替换后即使 plugin.yaml 不变也重新加载
+	h := sha256.New()
+	h.Write(data)
+	if manifest.Runtime.Command != "" {
+		info, err := os.Stat(manifest.Runtime.command(pluginPath))
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to stat plugin executable: %w", err)
+		}
+		fmt.Fprintf(h, "\x00%d\x00%d", info.Size(), info.ModTime().UnixNano())
+	}
+
+	return &manifest, hex.EncodeToString(h.Sum(nil)), nil
+}
+
 // RegisterPlugin 注册插件实例
 func (m *Manager) RegisterPlugin(plugin Plugin) error {
 	meta := plugin.Meta()
@@ -132,11 +231,17 @@ func (m *Manager) ListPlugins() []PluginMeta {
 	return metas
 }
 
-// StopAll 停止所有插件
+// StopAll 停止所有插件，之后不再热加载
+// 热加载替换下来、仍在排空的旧实例立即关闭，返回前等待其关闭完成。
 func (m *Manager) StopAll() {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+	m.loadMu.Lock()
+	defer m.loadMu.Unlock()
+	if !m.stopped {
+		m.stopped = true
+		close(m.stopping)
+	}
 
+	m.mu.Lock()
 	for name, plugin := range m.plugins {
 		if err := plugin.Close(); err != nil {
 			logger.Error("Failed to close plugin",
@@ -144,6 +249,8 @@ func (m *Manager) StopAll() {
 				zap.Error(err))
 		}
 	}
+	m.mu.Unlock()
 
+	m.retiring.Wait()
 	logger.Info("Stopped all plugins")
 }
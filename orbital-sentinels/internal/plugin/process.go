@@ -0,0 +1,276 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/celestial/orbital-sentinels/internal/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// errProcessExited 插件进程已退出
+var errProcessExited = errors.New("plugin process exited")
+
+// process 一个运行中的插件进程
+type process struct {
+	name  string
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+
+	encMu sync.Mutex
+	enc   *json.Encoder
+
+	mu      sync.Mutex
+	nextID  uint64
+	pending map[uint64]chan *rpcResponse
+	err     error
+
+	exited  chan struct{}
+	started time.Time
+}
+
+// startProcess 启动插件进程并完成握手
+func startProcess(name, dir string, rt RuntimeConfig) (*process, error) {
+	cmd := exec.Command(rt.command(dir), rt.Args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		EnvMagicCookie+"="+MagicCookieValue,
+		EnvProtocolVersion+"="+strconv.Itoa(ProtocolVersion),
+	)
+	for k, v := range rt.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start plugin: %w", err)
+	}
+
+	p := &process{
+		name:    name,
+		cmd:     cmd,
+		stdin:   stdin,
+		enc:     json.NewEncoder(stdin),
+		pending: make(map[uint64]chan *rpcResponse),
+		exited:  make(chan struct{}),
+		started: time.Now(),
+	}
+	go p.logStderr(stderr)
+
+	// 读循环启动前失败时直接结束进程
+	abort := func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}
+	if err := applyLimits(cmd.Process.Pid, rt.Limits); err != nil {
+		abort()
+		return nil, fmt.Errorf("failed to apply resource limits: %w", err)
+	}
+
+	// 握手：第一条消息声明协议和版本
+	dec := json.NewDecoder(stdout)
+	handshakeDone := make(chan error, 1)
+	go func() {
+		var hs handshake
+		if err := dec.Decode(&hs); err != nil {
+			handshakeDone <- fmt.Errorf("failed to read handshake: %w", err)
+			return
+		}
+		if hs.Protocol != ProtocolName {
+			handshakeDone <- fmt.Errorf("unexpected handshake protocol %q", hs.Protocol)
+			return
+		}
+		if hs.Version != ProtocolVersion {
+			handshakeDone <- fmt.Errorf("unsupported protocol version %d, sentinel speaks %d", hs.Version, ProtocolVersion)
+			return
+		}
+		handshakeDone <- nil
+	}()
+
+	select {
+	case err = <-handshakeDone:
+	case <-time.After(rt.startTimeout()):
+		err = errors.New("handshake timed out")
+	}
+	if err != nil {
+		abort()
+		return nil, err
+	}
+
+	go p.readLoop(dec)
+	return p, nil
+}
+
+// readLoop 读取响应并交给等待的调用，进程退出时让所有调用失败
+func (p *process) readLoop(dec *json.Decoder) {
+	var readErr error
+	for {
+		var resp rpcResponse
+		if err := dec.Decode(&resp); err != nil {
+			readErr = err
+			break
+		}
+
+		p.mu.Lock()
+		ch, ok := p.pending[resp.ID]
+		delete(p.pending, resp.ID)
+		p.mu.Unlock()
+		if ok {
+			ch <- &resp
+		}
+	}
+
+	// 协议错误时结束进程，由守护协程重启
+	if !errors.Is(readErr, io.EOF) {
+		logger.Warn("Invalid message from plugin", zap.String("plugin", p.name), zap.Error(readErr))
+		p.cmd.Process.Kill()
+	}
+	waitErr := p.cmd.Wait()
+
+	p.mu.Lock()
+	p.err = errProcessExited
+	if waitErr != nil {
+		p.err = fmt.Errorf("%w: %v", errProcessExited, waitErr)
+	}
+	for id, ch := range p.pending {
+		close(ch)
+		delete(p.pending, id)
+	}
+	p.mu.Unlock()
+	close(p.exited)
+}
+
+// logStderr 插件的标准错误输出写入 Sentinel 日志
+func (p *process) logStderr(r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		logger.Info(scanner.Text(), zap.String("plugin", p.name))
+	}
+}
+
+// call 发送请求并等待响应，ctx 结束时通知插件取消
+func (p *process) call(ctx context.Context, method string, params, result interface{}) error {
+	var raw json.RawMessage
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("failed to encode params: %w", err)
+		}
+		raw = data
+	}
+
+	ch := make(chan *rpcResponse, 1)
+	p.mu.Lock()
+	if p.err != nil {
+		err := p.err
+		p.mu.Unlock()
+		return err
+	}
+	p.nextID++
+	id := p.nextID
+	p.pending[id] = ch
+	p.mu.Unlock()
+
+	if err := p.send(rpcRequest{ID: id, Method: method, Params: raw}); err != nil {
+		p.forget(id)
+		return err
+	}
+
+	select {
+	case resp, ok := <-ch:
+		if !ok {
+			return p.exitErr()
+		}
+		if resp.Error != "" {
+			return errors.New(resp.Error)
+		}
+		if result != nil && len(resp.Result) > 0 {
+			if err := json.Unmarshal(resp.Result, result); err != nil {
+				return fmt.Errorf("failed to decode result: %w", err)
+			}
+		}
+		return nil
+	case <-ctx.Done():
+		p.forget(id)
+		p.send(rpcRequest{Method: MethodCancel, Params: mustMarshal(cancelParams{ID: id})})
+		return ctx.Err()
+	}
+}
+
+// send 写入一条消息
+func (p *process) send(req rpcRequest) error {
+	p.encMu.Lock()
+	defer p.encMu.Unlock()
+	if err := p.enc.Encode(req); err != nil {
+		return fmt.Errorf("failed to write request: %w", err)
+	}
+	return nil
+}
+
+// forget 取消等待
+func (p *process) forget(id uint64) {
+	p.mu.Lock()
+	delete(p.pending, id)
+	p.mu.Unlock()
+}
+
+// exitErr 返回进程退出原因
+func (p *process) exitErr() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return p.err
+	}
+	return errProcessExited
+}
+
+// stop 请求插件关闭，超时后强制结束
+func (p *process) stop(timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := p.call(ctx, MethodClose, nil, nil); err != nil && !errors.Is(err, errProcessExited) {
+		logger.Warn("Plugin did not close cleanly", zap.String("plugin", p.name), zap.Error(err))
+	}
+	p.stdin.Close()
+
+	select {
+	case <-p.exited:
+	case <-time.After(timeout):
+		p.kill()
+	}
+}
+
+// kill 强制结束进程并等待读循环退出
+func (p *process) kill() {
+	p.cmd.Process.Kill()
+	select {
+	case <-p.exited:
+	case <-time.After(5 * time.Second):
+	}
+}
+
+func mustMarshal(v interface{}) json.RawMessage {
+	data, _ := json.Marshal(v)
+	return data
+}
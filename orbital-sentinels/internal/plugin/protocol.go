@@ -0,0 +1,216 @@
+package plugin
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 外部插件协议
+// Sentinel 以子进程方式启动插件可执行文件，通过标准输入输出交换 JSON 消息（每行一条），
+// 标准错误输出作为插件日志。插件启动后先输出握手消息，再逐条处理请求，
+// 请求可以并发，响应按 ID 对应，顺序不限。
+
+// ProtocolVersion 当前协议版本，握手时版本不一致的插件拒绝加载
+const ProtocolVersion = 1
+
+// 握手使用的环境变量
+const (
+	// ProtocolName 握手消息中的协议名
+	ProtocolName = "celestial-plugin"
+	// EnvProtocolVersion 宿主支持的协议版本
+	EnvProtocolVersion = "CELESTIAL_PLUGIN_PROTOCOL_VERSION"
+	// EnvMagicCookie 由 Sentinel 启动的标记，避免插件被直接执行时等待标准输入
+	EnvMagicCookie = "CELESTIAL_PLUGIN_MAGIC_COOKIE"
+	// MagicCookieValue 标记的取值
+	MagicCookieValue = "d2f1c3b0-orbital-sentinels"
+)
+
+// 协议方法，与 Plugin 接口一一对应，另有健康检查和取消
+const (
+	MethodSchema         = "schema"
+	MethodInit           = "init"
+	MethodValidateConfig = "validate_config"
+	MethodTestConnection = "test_connection"
+	MethodCollect        = "collect"
+	MethodClose          = "close"
+	MethodPing           = "ping"
+	MethodCancel         = "cancel" // 通知，不需要响应
+)
+
+// handshake 插件启动后输出的第一条消息
+type handshake struct {
+	Protocol string `json:"protocol"`
+	Version  int    `json:"version"`
+}
+
+// rpcRequest 请求消息
+type rpcRequest struct {
+	ID     uint64          `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// rpcResponse 响应消息，Error 非空表示调用失败
+type rpcResponse struct {
+	ID     uint64          `json:"id"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// cancelParams 取消进行中的请求
+type cancelParams struct {
+	ID uint64 `json:"id"`
+}
+
+// configParams Init、ValidateConfig、TestConnection 的参数
+type configParams struct {
+	Config map[string]interface{} `json:"config"`
+}
+
+// wireMeta 插件元信息
+type wireMeta struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description,omitempty"`
+	Author      string   `json:"author,omitempty"`
+	DeviceTypes []string `json:"device_types,omitempty"`
+}
+
+// wireField 字段定义
+type wireField struct {
+	Name        string      `json:"name"`
+	Type        string      `json:"type"`
+	Required    bool        `json:"required,omitempty"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Validation  string      `json:"validation,omitempty"`
+	Min         int         `json:"min,omitempty"`
+	Max         int         `json:"max,omitempty"`
+	Options     []string    `json:"options,omitempty"`
+}
+
+// wireSchema 配置 Schema
+type wireSchema struct {
+	Meta         wireMeta    `json:"meta"`
+	DeviceFields []wireField `json:"device_fields,omitempty"`
+	ConfigFields []wireField `json:"config_fields,omitempty"`
+}
+
+// wireTask 采集任务，超时单位为毫秒
+type wireTask struct {
+	TaskID       string                 `json:"task_id"`
+	DeviceID     string                 `json:"device_id"`
+	PluginName   string                 `json:"plugin_name"`
+	DeviceConfig map[string]interface{} `json:"device_config"`
+	PluginConfig map[string]interface{} `json:"plugin_config,omitempty"`
+	TimeoutMs    int64                  `json:"timeout_ms,omitempty"`
+}
+
+// wireMetric 指标
+type wireMetric struct {
+	Name      string            `json:"name"`
+	Value     float64           `json:"value"`
+	Timestamp int64             `json:"timestamp"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Type      string            `json:"type,omitempty"`
+	Histogram *Histogram        `json:"histogram,omitempty"`
+	Summary   *Summary          `json:"summary,omitempty"`
+}
+
+// collectResult Collect 的返回值
+type collectResult struct {
+	Metrics []wireMetric `json:"metrics"`
+}
+
+func toWireSchema(s PluginSchema) wireSchema {
+	return wireSchema{
+		Meta:         wireMeta(s.Meta),
+		DeviceFields: toWireFields(s.DeviceFields),
+		ConfigFields: toWireFields(s.ConfigFields),
+	}
+}
+
+func fromWireSchema(s wireSchema) PluginSchema {
+	return PluginSchema{
+		Meta:         PluginMeta(s.Meta),
+		DeviceFields: fromWireFields(s.DeviceFields),
+		ConfigFields: fromWireFields(s.ConfigFields),
+	}
+}
+
+func toWireFields(fields []DeviceField) []wireField {
+	out := make([]wireField, 0, len(fields))
+	for _, f := range fields {
+		out = append(out, wireField(f))
+	}
+	return out
+}
+
+func fromWireFields(fields []wireField) []DeviceField {
+	out := make([]DeviceField, 0, len(fields))
+	for _, f := range fields {
+		out = append(out, DeviceField(f))
+	}
+	return out
+}
+
+func toWireTask(t *CollectionTask) wireTask {
+	return wireTask{
+		TaskID:       t.TaskID,
+		DeviceID:     t.DeviceID,
+		PluginName:   t.PluginName,
+		DeviceConfig: t.DeviceConfig,
+		PluginConfig: t.PluginConfig,
+		TimeoutMs:    t.Timeout.Milliseconds(),
+	}
+}
+
+func fromWireTask(t wireTask) *CollectionTask {
+	return &CollectionTask{
+		TaskID:       t.TaskID,
+		DeviceID:     t.DeviceID,
+		PluginName:   t.PluginName,
+		DeviceConfig: t.DeviceConfig,
+		PluginConfig: t.PluginConfig,
+		Timeout:      time.Duration(t.TimeoutMs) * time.Millisecond,
+	}
+}
+
+func toWireMetrics(metrics []*Metric) []wireMetric {
+	out := make([]wireMetric, 0, len(metrics))
+	for _, m := range metrics {
+		if m == nil {
+			continue
+		}
+		out = append(out, wireMetric{
+			Name:      m.Name,
+			Value:     m.Value,
+			Timestamp: m.Timestamp,
+			Labels:    m.Labels,
+			Type:      string(m.Type),
+			Histogram: m.Histogram,
+			Summary:   m.Summary,
+		})
+	}
+	return out
+}
+
+func fromWireMetrics(metrics []wireMetric) []*Metric {
+	out := make([]*Metric, 0, len(metrics))
+	for _, m := range metrics {
+		metricType := MetricType(m.Type)
+		if metricType == "" {
+			metricType = MetricTypeGauge
+		}
+		out = append(out, &Metric{
+			Name:      m.Name,
+			Value:     m.Value,
+			Timestamp: m.Timestamp,
+			Labels:    m.Labels,
+			Type:      metricType,
+			Histogram: m.Histogram,
+			Summary:   m.Summary,
+		})
+	}
+	return out
+}
@@ -0,0 +1,146 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/celestial/orbital-sentinels/internal/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// SetChangeHandler 设置插件变化处理器，热加载后插件增删或版本变化时调用
+func (m *Manager) SetChangeHandler(handler func()) {
+	m.loadMu.Lock()
+	defer m.loadMu.Unlock()
+	m.onChange = handler
+}
+
+// Watch 按间隔检查插件目录并热加载，直到 ctx 结束
+func (m *Manager) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	logger.Info("Plugin auto reload started",
+		zap.String("directory", m.directory),
+		zap.Duration("interval", interval))
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := m.Reload(); err != nil {
+				logger.Error("Failed to reload plugins", zap.Error(err))
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Reload 重新扫描插件目录：加载新增的插件，替换 plugin.yaml 或可执行文件有变化的插件，卸载已删除的插件
+// 外部插件替换时先原子切换到新实例，旧实例在后台等待进行中的采集完成后再关闭，调度中的任务不受影响。
+// 新版本加载失败时保留旧版本，下次检查时重试。
+func (m *Manager) Reload() error {
+	m.loadMu.Lock()
+	defer m.loadMu.Unlock()
+	if m.stopped {
+		return nil
+	}
+
+	entries, err := os.ReadDir(m.directory)
+	if err != nil {
+		return fmt.Errorf("failed to read plugin directory: %w", err)
+	}
+
+	before := m.Versions()
+	seen := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		seen[entry.Name()] = true
+
+		pluginPath := filepath.Join(m.directory, entry.Name())
+		_, fingerprint, err := readManifest(pluginPath)
+		if err != nil {
+			logger.Error("Failed to read plugin", zap.String("plugin", entry.Name()), zap.Error(err))
+			continue
+		}
+		if source, ok := m.sources[entry.Name()]; ok && source.fingerprint == fingerprint {
+			continue
+		}
+
+		if err := m.loadPlugin(pluginPath); err != nil {
+			logger.Error("Failed to reload plugin", zap.String("plugin", entry.Name()), zap.Error(err))
+		}
+	}
+
+	for key, source := range m.sources {
+		if !seen[key] {
+			m.unload(source)
+			delete(m.sources, key)
+		}
+	}
+
+	if m.onChange != nil && !equalVersions(before, m.Versions()) {
+		m.onChange()
+	}
+	return nil
+}
+
+// unload 卸载插件目录对应的插件，外部插件在后台等待进行中的调用完成后关闭
+func (m *Manager) unload(source pluginSource) {
+	var old *ExternalPlugin
+	m.mu.Lock()
+	delete(m.schemas, source.name)
+	if source.external {
+		if p, ok := m.plugins[source.name].(*ExternalPlugin); ok {
+			old = p
+			delete(m.plugins, source.name)
+		}
+	}
+	m.mu.Unlock()
+
+	if old != nil {
+		logger.Info("Unloaded plugin",
+			zap.String("name", source.name),
+			zap.String("version", old.Meta().Version))
+		m.retire(old, nil)
+	}
+}
+
+// retire 立即让旧实例停止接收调用，在后台等待进行中的调用完成后关闭，不占用 loadMu
+func (m *Manager) retire(old, successor *ExternalPlugin) {
+	old.retire(successor)
+	m.retiring.Add(1)
+	go func() {
+		defer m.retiring.Done()
+		old.drain(drainTimeout, m.stopping)
+	}()
+}
+
+// Versions 返回已注册插件的版本（名称 -> 版本）
+func (m *Manager) Versions() map[string]string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	versions := make(map[string]string, len(m.plugins))
+	for name, plugin := range m.plugins {
+		versions[name] = plugin.Meta().Version
+	}
+	return versions
+}
+
+func equalVersions(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, version := range a {
+		if v, ok := b[name]; !ok || v != version {
+			return false
+		}
+	}
+	return true
+}
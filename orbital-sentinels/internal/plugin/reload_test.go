@@ -0,0 +1,201 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// writeHelperManifest 在插件目录下写入测试插件的 plugin.yaml
+func writeHelperManifest(t *testing.T, dir, version string) {
+	t.Helper()
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatalf("os.Executable: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "helper"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := fmt.Sprintf(`meta:
+  name: helper
+runtime:
+  command: %q
+  env:
+    %s: serve
+    %s: %q
+`, exe, envTestHelper, envTestVersion, version)
+	if err := os.WriteFile(filepath.Join(dir, "helper", "plugin.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestManager_ReloadReplacesPlugin(t *testing.T) {
+	dir := t.TempDir()
+	writeHelperManifest(t, dir, "1.0.0")
+
+	mgr := NewManager(dir)
+	var changes int32
+	mgr.SetChangeHandler(func() { atomic.AddInt32(&changes, 1) })
+	if err := mgr.LoadAll(); err != nil {
+		t.Fatalf("LoadAll failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	old, _ := mgr.GetPlugin("helper")
+	task := &CollectionTask{DeviceID: "dev-1", DeviceConfig: map[string]interface{}{"mode": "block"}}
+
+	// 旧实例上有进行中的采集
+	blockCtx, release := context.WithCancel(context.Background())
+	blocked := make(chan error, 1)
+	go func() {
+		_, err := old.Collect(blockCtx, task)
+		blocked <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	writeHelperManifest(t, dir, "2.0.0")
+	reloaded := make(chan error, 1)
+	go func() { reloaded <- mgr.Reload() }()
+
+	// 切换不等待旧实例排空
+	deadline := time.Now().Add(5 * time.Second)
+	for mgr.Versions()["helper"] != "2.0.0" {
+		if time.Now().After(deadline) {
+			t.Fatalf("plugin not swapped, versions = %v", mgr.Versions())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// 仍持有旧实例的调用方被转给新实例
+	if _, err := old.Collect(context.Background(), &CollectionTask{DeviceID: "dev-1", DeviceConfig: map[string]interface{}{}}); err != nil {
+		t.Errorf("Collect on replaced plugin failed: %v", err)
+	}
+
+	// Reload 不等待旧实例排空，旧实例上的采集继续进行
+	select {
+	case err := <-reloaded:
+		if err != nil {
+			t.Fatalf("Reload failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Reload blocked on in-flight collect")
+	}
+	select {
+	case err := <-blocked:
+		t.Fatalf("in-flight collect finished early: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release()
+	if err := <-blocked; err != context.Canceled {
+		t.Errorf("in-flight collect error = %v", err)
+	}
+	if atomic.LoadInt32(&changes) != 1 {
+		t.Errorf("change handler called %d times, want 1", changes)
+	}
+
+	// 没有变化时不重新加载
+	if err := mgr.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if atomic.LoadInt32(&changes) != 1 {
+		t.Errorf("unchanged reload called change handler")
+	}
+}
+
+func TestManager_StopAllClosesRetiringPlugin(t *testing.T) {
+	dir := t.TempDir()
+	writeHelperManifest(t, dir, "1.0.0")
+
+	mgr := NewManager(dir)
+	if err := mgr.LoadAll(); err != nil {
+		t.Fatalf("LoadAll failed: %v", err)
+	}
+
+	old, _ := mgr.GetPlugin("helper")
+	task := &CollectionTask{DeviceID: "dev-1", DeviceConfig: map[string]interface{}{"mode": "block"}}
+	blockCtx, release := context.WithCancel(context.Background())
+	defer release()
+	blocked := make(chan error, 1)
+	go func() {
+		_, err := old.Collect(blockCtx, task)
+		blocked <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	writeHelperManifest(t, dir, "2.0.0")
+	if err := mgr.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+
+	// 旧实例仍在排空，StopAll 不等待 drainTimeout
+	stopped := make(chan struct{})
+	go func() {
+		mgr.StopAll()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(drainTimeout / 2):
+		t.Fatal("StopAll waited for retiring plugin to drain")
+	}
+	select {
+	case err := <-blocked:
+		if err == nil {
+			t.Error("expected in-flight collect to fail after StopAll")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("in-flight collect not interrupted by StopAll")
+	}
+}
+
+func TestManager_ReloadAddsAndRemovesPlugins(t *testing.T) {
+	dir := t.TempDir()
+
+	// 只有 Schema 的插件目录不启动进程
+	if err := os.MkdirAll(filepath.Join(dir, "builtin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "builtin", "plugin.yaml"), []byte("meta:\n  name: builtin\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mgr := NewManager(dir)
+	if err := mgr.LoadAll(); err != nil {
+		t.Fatalf("LoadAll failed: %v", err)
+	}
+	defer mgr.StopAll()
+	if len(mgr.Versions()) != 0 {
+		t.Fatalf("versions = %v", mgr.Versions())
+	}
+
+	writeHelperManifest(t, dir, "1.0.0")
+	if err := mgr.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	p, ok := mgr.GetPlugin("helper")
+	if !ok {
+		t.Fatal("added plugin not registered")
+	}
+
+	if err := os.RemoveAll(filepath.Join(dir, "helper")); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if _, ok := mgr.GetPlugin("helper"); ok {
+		t.Error("removed plugin still registered")
+	}
+	if _, ok := mgr.GetSchema("builtin"); !ok {
+		t.Error("schema of unchanged plugin was dropped")
+	}
+	if _, err := p.Collect(context.Background(), &CollectionTask{DeviceConfig: map[string]interface{}{}}); err == nil || !strings.Contains(err.Error(), "unloaded") {
+		t.Errorf("Collect on unloaded plugin error = %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// ErrNotLaunchedBySentinel 插件可执行文件未由 Sentinel 启动
+var ErrNotLaunchedBySentinel = errors.New("this binary is a celestial sentinel plugin and must be launched by the sentinel")
+
+// Serve 在插件可执行文件中运行协议服务，直到 Sentinel 关闭标准输入或调用 Close
+func Serve(p Plugin) error {
+	if os.Getenv(EnvMagicCookie) != MagicCookieValue {
+		return ErrNotLaunchedBySentinel
+	}
+	if v := os.Getenv(EnvProtocolVersion); v != strconv.Itoa(ProtocolVersion) {
+		return fmt.Errorf("unsupported protocol version %q, plugin speaks %d", v, ProtocolVersion)
+	}
+	return serve(p, os.Stdin, os.Stdout)
+}
+
+// server 插件端协议服务
+type server struct {
+	plugin Plugin
+
+	encMu sync.Mutex
+	enc   *json.Encoder
+
+	mu      sync.Mutex
+	cancels map[uint64]context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// serve 输出握手消息后处理请求，每个请求在独立的协程中执行
+func serve(p Plugin, r io.Reader, w io.Writer) error {
+	s := &server{
+		plugin:  p,
+		enc:     json.NewEncoder(w),
+		cancels: make(map[uint64]context.CancelFunc),
+	}
+	if err := s.enc.Encode(handshake{Protocol: ProtocolName, Version: ProtocolVersion}); err != nil {
+		return fmt.Errorf("failed to write handshake: %w", err)
+	}
+
+	dec := json.NewDecoder(r)
+	for {
+		var req rpcRequest
+		if err := dec.Decode(&req); err != nil {
+			s.wg.Wait()
+			if errors.Is(err, io.EOF) {
+				// Sentinel 退出时关闭标准输入
+				return p.Close()
+			}
+			return fmt.Errorf("failed to read request: %w", err)
+		}
+
+		switch req.Method {
+		case MethodCancel:
+			var params cancelParams
+			if json.Unmarshal(req.Params, &params) == nil {
+				s.cancel(params.ID)
+			}
+		case MethodClose:
+			s.wg.Wait()
+			err := p.Close()
+			s.reply(req.ID, nil, err)
+			return err
+		default:
+			ctx, cancel := context.WithCancel(context.Background())
+			s.mu.Lock()
+			s.cancels[req.ID] = cancel
+			s.mu.Unlock()
+
+			s.wg.Add(1)
+			go func(req rpcRequest) {
+				defer s.wg.Done()
+				defer s.cancel(req.ID)
+				result, err := s.handle(ctx, req)
+				s.reply(req.ID, result, err)
+			}(req)
+		}
+	}
+}
+
+// handle 调用插件方法
+func (s *server) handle(ctx context.Context, req rpcRequest) (interface{}, error) {
+	switch req.Method {
+	case MethodPing:
+		return nil, nil
+	case MethodSchema:
+		schema := s.plugin.Schema()
+		schema.Meta = s.plugin.Meta()
+		return toWireSchema(schema), nil
+	case MethodInit, MethodValidateConfig, MethodTestConnection:
+		var params configParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, fmt.Errorf("invalid params: %w", err)
+		}
+		switch req.Method {
+		case MethodInit:
+			return nil, s.plugin.Init(params.Config)
+		case MethodValidateConfig:
+			return nil, s.plugin.ValidateConfig(params.Config)
+		default:
+			return nil, s.plugin.TestConnection(params.Config)
+		}
+	case MethodCollect:
+		var task wireTask
+		if err := json.Unmarshal(req.Params, &task); err != nil {
+			return nil, fmt.Errorf("invalid params: %w", err)
+		}
+		metrics, err := s.plugin.Collect(ctx, fromWireTask(task))
+		if err != nil {
+			return nil, err
+		}
+		return collectResult{Metrics: toWireMetrics(metrics)}, nil
+	}
+	return nil, fmt.Errorf("unknown method %q", req.Method)
+}
+
+// reply 写入响应
+func (s *server) reply(id uint64, result interface{}, err error) {
+	resp := rpcResponse{ID: id}
+	if err != nil {
+		resp.Error = err.Error()
+	} else if result != nil {
+		data, marshalErr := json.Marshal(result)
+		if marshalErr != nil {
+			resp.Error = fmt.Sprintf("failed to encode result: %v", marshalErr)
+		} else {
+			resp.Result = data
+		}
+	}
+
+	s.encMu.Lock()
+	defer s.encMu.Unlock()
+	// 写失败说明 Sentinel 已退出，由读循环结束服务
+	_ = s.enc.Encode(resp)
+}
+
+// cancel 取消进行中的请求
+func (s *server) cancel(id uint64) {
+	s.mu.Lock()
+	cancel, ok := s.cancels[id]
+	delete(s.cancels, id)
+	s.mu.Unlock()
+	if ok {
+		cancel()
+	}
+}
@@ -17,13 +17,14 @@ type PluginMeta struct {
 // DeviceField 设备字段定义
 type DeviceField struct {
 	Name        string      `yaml:"name"`
-	Type        string      `yaml:"type"` // string, int, bool, password
+	Type        string      `yaml:"type"` // string, int, bool, password, list, map
 	Required    bool        `yaml:"required"`
 	Default     interface{} `yaml:"default"`
 	Description string      `yaml:"description"`
 	Validation  string      `yaml:"validation"` // 正则表达式
 	Min         int         `yaml:"min"`
 	Max         int         `yaml:"max"`
+	Options     []string    `yaml:"options"` // 可选值，为空时不限制
 }
 
 // PluginSchema 插件配置 Schema
@@ -68,12 +69,42 @@ type CollectionTask struct {
 }
 
 // Metric 指标数据
+// Type 为 histogram、summary 时分布数据在 Histogram、Summary 中，Value 为观测总数（与 Count 相同）。
 type Metric struct {
 	Name      string
 	Value     float64
 	Timestamp int64
 	Labels    map[string]string
-	Type      MetricType // gauge, counter, histogram
+	Type      MetricType // gauge, counter, histogram, summary
+	Histogram *Histogram `json:"histogram,omitempty"`
+	Summary   *Summary   `json:"summary,omitempty"`
+}
+
+// Histogram 直方图，与 Prometheus 经典直方图相同，桶计数为累积值
+// Buckets 按上界升序排列，不包含 +Inf 桶（+Inf 桶的计数即 Count）。
+type Histogram struct {
+	Buckets []Bucket `json:"buckets"`
+	Sum     float64  `json:"sum"`
+	Count   uint64   `json:"count"`
+}
+
+// Bucket 直方图桶
+type Bucket struct {
+	UpperBound float64 `json:"upper_bound"`
+	Count      uint64  `json:"count"` // 小于等于上界的观测数
+}
+
+// Summary 摘要，Quantiles 按分位数升序排列
+type Summary struct {
+	Quantiles []Quantile `json:"quantiles"`
+	Sum       float64    `json:"sum"`
+	Count     uint64     `json:"count"`
+}
+
+// Quantile 分位数
+type Quantile struct {
+	Quantile float64 `json:"quantile"`
+	Value    float64 `json:"value"`
 }
 
 // MetricType 指标类型
@@ -83,4 +114,5 @@ const (
 	MetricTypeGauge     MetricType = "gauge"
 	MetricTypeCounter   MetricType = "counter"
 	MetricTypeHistogram MetricType = "histogram"
+	MetricTypeSummary   MetricType = "summary"
 )
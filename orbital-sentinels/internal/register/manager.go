@@ -3,6 +3,7 @@ package register
 import (
 	"bytes"
 	"context"
+	"crypto/ecdsa"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,6 +12,7 @@ import (
 	"runtime"
 	"time"
 
+	"github.com/celestial/orbital-sentinels/internal/certs"
 	"github.com/celestial/orbital-sentinels/internal/credentials"
 	"github.com/celestial/orbital-sentinels/internal/pkg/logger"
 	"go.uber.org/zap"
@@ -22,6 +24,7 @@ type Manager struct {
 	coreURL         string
 	registrationKey string
 	credsMgr        *credentials.Manager
+	certMgr         *certs.Manager
 }
 
 // NewManager 创建注册管理器
@@ -36,6 +39,16 @@ func NewManager(coreURL, registrationKey string, credsMgr *credentials.Manager)
 	}
 }
 
+// SetTransport 设置连接中心端使用的 Transport（TLS 配置）
+func (m *Manager) SetTransport(rt http.RoundTripper) {
+	m.client.Transport = rt
+}
+
+// SetCertManager 设置证书管理器，设置后注册时提交 CSR 申请客户端证书
+func (m *Manager) SetCertManager(certMgr *certs.Manager) {
+	m.certMgr = certMgr
+}
+
 // Register 注册到中心端
 func (m *Manager) Register(ctx context.Context, config *Config) (*RegisterResponse, error) {
 	// 1. 构建注册请求
@@ -44,6 +57,15 @@ func (m *Manager) Register(ctx context.Context, config *Config) (*RegisterRespon
 		return nil, fmt.Errorf("failed to build register request: %w", err)
 	}
 
+	// 启用 mTLS 时随注册请求提交 CSR，私钥只保存在本地
+	var certKey *ecdsa.PrivateKey
+	if m.certMgr != nil {
+		req.CSR, certKey, err = m.certMgr.NewCSR(req.Hostname)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create csr: %w", err)
+		}
+	}
+
 	logger.Info("Registering to core",
 		zap.String("hostname", req.Hostname),
 		zap.String("ip", req.IPAddress),
@@ -73,6 +95,25 @@ func (m *Manager) Register(ctx context.Context, config *Config) (*RegisterRespon
 		zap.String("sentinel_id", resp.SentinelID),
 		zap.String("credentials_path", m.credsMgr.GetPath()))
 
+	if m.certMgr != nil {
+		if resp.Certificate == nil {
+			logger.Warn("Core did not issue a client certificate, check that mTLS is enabled on core")
+		} else if err := m.certMgr.Store(resp.Certificate.Certificate, resp.Certificate.CACertificate, certKey); err != nil {
+			return nil, fmt.Errorf("failed to save certificate: %w", err)
+		} else {
+			logger.Info("Client certificate saved",
+				zap.String("serial_number", resp.Certificate.SerialNumber),
+				zap.Time("expires_at", resp.Certificate.ExpiresAt),
+				zap.String("path", m.certMgr.Dir()))
+		}
+	}
+
+	// 审批通过前中心端不下发任务，采集端照常心跳，审批后自动拉取任务
+	if resp.ApprovalStatus == "pending" {
+		logger.Warn("Sentinel is pending approval, tasks will be assigned after an administrator approves it",
+			zap.String("sentinel_id", resp.SentinelID))
+	}
+
 	return resp, nil
 }
 
@@ -1,5 +1,7 @@
 package register
 
+import "time"
+
 // Config 注册配置
 type Config struct {
 	Name    string            // 显示名称
@@ -20,13 +22,24 @@ type RegisterRequest struct {
 	Region          string            `json:"region,omitempty"`
 	Labels          map[string]string `json:"labels,omitempty"`
 	RegistrationKey string            `json:"registration_key,omitempty"`
+	CSR             string            `json:"csr,omitempty"` // PEM 格式的证书签名请求(启用 mTLS 时)
 }
 
 // RegisterResponse 注册响应
 type RegisterResponse struct {
-	SentinelID string                 `json:"sentinel_id"`
-	APIToken   string                 `json:"api_token"`
-	Config     map[string]interface{} `json:"config"`
-	Message    string                 `json:"message,omitempty"`
+	SentinelID     string                 `json:"sentinel_id"`
+	APIToken       string                 `json:"api_token"`
+	ApprovalStatus string                 `json:"approval_status,omitempty"` // pending 表示等待中心端审批
+	Config         map[string]interface{} `json:"config"`
+	Message        string                 `json:"message,omitempty"`
+	Certificate    *Certificate           `json:"certificate,omitempty"` // 中心端签发的客户端证书
+}
+
+// Certificate 中心端签发的客户端证书
+type Certificate struct {
+	Certificate   string    `json:"certificate"`
+	CACertificate string    `json:"ca_certificate"`
+	SerialNumber  string    `json:"serial_number"`
+	ExpiresAt     time.Time `json:"expires_at"`
 }
 
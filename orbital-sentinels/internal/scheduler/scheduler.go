@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -22,6 +23,13 @@ const (
 	TaskStatusFailed  TaskStatus = "failed"
 )
 
+var (
+	// ErrTaskNotFound 任务不存在
+	ErrTaskNotFound = errors.New("task not found")
+	// ErrTaskRunning 任务正在执行
+	ErrTaskRunning = errors.New("task is already running")
+)
+
 // ScheduledTask 调度任务
 type ScheduledTask struct {
 	Task         *plugin.CollectionTask
@@ -33,6 +41,26 @@ type ScheduledTask struct {
 	mu           sync.Mutex
 }
 
+// Snapshot 返回任务当前状态，用于诊断信息
+func (st *ScheduledTask) Snapshot() map[string]interface{} {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	snapshot := map[string]interface{}{
+		"task_id":    st.Task.TaskID,
+		"device_id":  st.Task.DeviceID,
+		"plugin":     st.Task.PluginName,
+		"interval":   st.Interval.String(),
+		"status":     string(st.LastStatus),
+		"executions": st.ExecutionCnt,
+		"next_run":   st.NextRun,
+	}
+	if st.LastError != nil {
+		snapshot["error"] = st.LastError.Error()
+	}
+	return snapshot
+}
+
 // Scheduler 任务调度器
 type Scheduler struct {
 	pluginMgr     *plugin.Manager
@@ -45,6 +73,7 @@ type Scheduler struct {
 	mu            sync.RWMutex
 	ctx           context.Context
 	cancel        context.CancelFunc
+	wg            sync.WaitGroup
 }
 
 // NewScheduler 创建调度器
@@ -82,12 +111,14 @@ func (s *Scheduler) Start(ctx context.Context) {
 
 	// 启动任务获取循环（如果有任务客户端）
 	if s.taskClient != nil {
+		s.wg.Add(1)
 		go s.fetchTasksLoop()
 		logger.Info("Task fetch loop started",
 			zap.Duration("interval", s.fetchInterval))
 	}
 
 	// 启动调度循环
+	s.wg.Add(1)
 	go s.scheduleLoop()
 
 	logger.Info("Scheduler started")
@@ -98,6 +129,7 @@ func (s *Scheduler) Stop() {
 	if s.cancel != nil {
 		s.cancel()
 	}
+	s.wg.Wait()
 
 	// 等待所有任务完成（带超时）
 	s.workerPool.Stop(30 * time.Second)
@@ -105,6 +137,37 @@ func (s *Scheduler) Stop() {
 	logger.Info("Scheduler stopped")
 }
 
+// Restart 重启调度器，保留已有任务
+func (s *Scheduler) Restart(ctx context.Context) {
+	logger.Info("Restarting scheduler")
+
+	s.Stop()
+	s.workerPool = NewWorkerPool(s.workerPool.Size())
+	s.Start(ctx)
+}
+
+// RunTask 立即执行一次指定任务，执行完成后返回采集错误
+func (s *Scheduler) RunTask(taskID string) error {
+	st, ok := s.GetTaskStatus(taskID)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrTaskNotFound, taskID)
+	}
+
+	st.mu.Lock()
+	if st.LastStatus == TaskStatusRunning {
+		st.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrTaskRunning, taskID)
+	}
+	st.LastStatus = TaskStatusRunning
+	st.mu.Unlock()
+
+	s.runTask(st)
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.LastError
+}
+
 // AddTask 添加任务
 func (s *Scheduler) AddTask(task *plugin.CollectionTask, interval time.Duration) {
 	s.mu.Lock()
@@ -227,6 +290,8 @@ func (s *Scheduler) UpdateTasksWithIntervals(tasksWithIntervals []client.TaskWit
 
 // scheduleLoop 调度循环
 func (s *Scheduler) scheduleLoop() {
+	defer s.wg.Done()
+
 	ticker := time.NewTicker(1 * time.Second)
 	defer ticker.Stop()
 
@@ -298,22 +363,25 @@ func (s *Scheduler) runTask(st *ScheduledTask) {
 
 	metrics, err := p.Collect(taskCtx, st.Task)
 
-	// 生成设备状态指标（用于时序库和 PostgreSQL）
-	statusMetric := s.createDeviceStatusMetric(st.Task, err)
 	if metrics == nil {
 		metrics = make([]*plugin.Metric, 0)
 	}
 
-	// 添加调试日志
-	logger.Info("Generated device_status metric",
-		zap.String("device_id", st.Task.DeviceID),
-		zap.Float64("value", statusMetric.Value),
-		zap.Int("metrics_before", len(metrics)))
+	// 生成设备状态指标（用于时序库和 PostgreSQL），插件已按自身规则上报时不再生成
+	if err != nil || !hasDeviceStatus(metrics) {
+		statusMetric := s.createDeviceStatusMetric(st.Task, err)
 
-	metrics = append(metrics, statusMetric)
+		// 添加调试日志
+		logger.Info("Generated device_status metric",
+			zap.String("device_id", st.Task.DeviceID),
+			zap.Float64("value", statusMetric.Value),
+			zap.Int("metrics_before", len(metrics)))
+
+		metrics = append(metrics, statusMetric)
 
-	logger.Info("After appending device_status",
-		zap.Int("metrics_after", len(metrics)))
+		logger.Info("After appending device_status",
+			zap.Int("metrics_after", len(metrics)))
+	}
 
 	st.mu.Lock()
 	if err != nil {
@@ -377,6 +445,16 @@ func (s *Scheduler) createDeviceStatusMetric(task *plugin.CollectionTask, collec
 	}
 }
 
+// hasDeviceStatus 判断插件是否已上报 device_status 指标
+func hasDeviceStatus(metrics []*plugin.Metric) bool {
+	for _, m := range metrics {
+		if m.Name == "device_status" {
+			return true
+		}
+	}
+	return false
+}
+
 // GetTaskStatus 获取任务状态
 func (s *Scheduler) GetTaskStatus(taskID string) (*ScheduledTask, bool) {
 	s.mu.RLock()
@@ -401,6 +479,8 @@ func (s *Scheduler) GetAllTasks() []*ScheduledTask {
 
 // fetchTasksLoop 任务获取循环
 func (s *Scheduler) fetchTasksLoop() {
+	defer s.wg.Done()
+
 	// 立即获取一次任务
 	s.fetchTasks()
 
@@ -420,25 +500,32 @@ func (s *Scheduler) fetchTasksLoop() {
 
 // fetchTasks 从中心端获取任务
 func (s *Scheduler) fetchTasks() {
-	if s.taskClient == nil {
-		return
-	}
-
 	ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
 	defer cancel()
 
-	tasksWithIntervals, err := s.taskClient.GetTasks(ctx)
-	if err != nil {
+	if _, err := s.FetchTasks(ctx); err != nil {
 		logger.Warn("Failed to fetch tasks from core",
 			zap.Error(err))
-		return
+	}
+}
+
+// FetchTasks 立即从中心端获取任务并更新任务列表，返回获取到的任务数
+func (s *Scheduler) FetchTasks(ctx context.Context) (int, error) {
+	if s.taskClient == nil {
+		return 0, fmt.Errorf("task client not configured")
+	}
+
+	tasksWithIntervals, err := s.taskClient.GetTasks(ctx)
+	if err != nil {
+		return 0, err
 	}
 
 	if len(tasksWithIntervals) == 0 {
 		logger.Debug("No tasks fetched from core")
-		return
+		return 0, nil
 	}
 
 	// 更新任务列表（每个任务使用自己的间隔）
 	s.UpdateTasksWithIntervals(tasksWithIntervals)
+	return len(tasksWithIntervals), nil
 }
@@ -60,7 +60,22 @@ func NewClickHouseWriter(config *ClickHouseConfig) (*ClickHouseWriter, error) {
 	return writer, nil
 }
 
-// ensureTable 确保表存在
+// distributionColumnsSQL 直方图和摘要的分布数据列，其他类型的指标为零值和空数组
+const distributionColumnsSQL = `metric_sum Float64,
+			metric_count UInt64,
+			bucket_bounds Array(Float64),
+			bucket_counts Array(UInt64),
+			quantiles Array(Float64),
+			quantile_values Array(Float64)`
+
+// insertColumnsSQL 写入的列
+const insertColumnsSQL = `timestamp, metric_name, metric_value, metric_type, device_id, labels,
+			metric_sum, metric_count, bucket_bounds, bucket_counts, quantiles, quantile_values`
+
+// insertValuesSQL 每行的占位符
+const insertValuesSQL = "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+
+// ensureTable 确保表存在，旧版本创建的表补充分布数据列
 func (cw *ClickHouseWriter) ensureTable() error {
 	// 创建表的 SQL（如果不存在）
 	createTableSQL := fmt.Sprintf(`
@@ -71,22 +86,78 @@ func (cw *ClickHouseWriter) ensureTable() error {
 			metric_type String,
 			device_id String,
 			labels Map(String, String),
+			%s,
 			date Date DEFAULT toDate(timestamp)
 		) ENGINE = MergeTree()
 		PARTITION BY toYYYYMM(date)
 		ORDER BY (metric_name, device_id, timestamp)
 		TTL date + INTERVAL 90 DAY
 		SETTINGS index_granularity = 8192
-	`, cw.tableName)
+	`, cw.tableName, distributionColumnsSQL)
 
 	_, err := cw.db.Exec(createTableSQL)
 	if err != nil {
 		return fmt.Errorf("failed to create table: %w", err)
 	}
 
+	// 补充分布数据列
+	columns := strings.Split(distributionColumnsSQL, ",\n")
+	for i, column := range columns {
+		columns[i] = "ADD COLUMN IF NOT EXISTS " + strings.TrimSpace(column)
+	}
+	alterTableSQL := fmt.Sprintf("ALTER TABLE %s %s", cw.tableName, strings.Join(columns, ", "))
+	if _, err := cw.db.Exec(alterTableSQL); err != nil {
+		return fmt.Errorf("failed to add distribution columns: %w", err)
+	}
+
 	return nil
 }
 
+// rowValues 返回指标一行的列值，顺序与 insertColumnsSQL 一致
+func rowValues(metric *plugin.Metric) []interface{} {
+	// 获取 device_id
+	deviceID := metric.Labels["device_id"]
+	if deviceID == "" {
+		deviceID = "unknown"
+	}
+
+	var sum float64
+	var count uint64
+	bucketBounds := []float64{}
+	bucketCounts := []uint64{}
+	quantiles := []float64{}
+	quantileValues := []float64{}
+	switch {
+	case metric.Histogram != nil:
+		sum, count = metric.Histogram.Sum, metric.Histogram.Count
+		for _, b := range metric.Histogram.Buckets {
+			bucketBounds = append(bucketBounds, b.UpperBound)
+			bucketCounts = append(bucketCounts, b.Count)
+		}
+	case metric.Summary != nil:
+		sum, count = metric.Summary.Sum, metric.Summary.Count
+		for _, q := range metric.Summary.Quantiles {
+			quantiles = append(quantiles, q.Quantile)
+			quantileValues = append(quantileValues, q.Value)
+		}
+	}
+
+	return []interface{}{
+		time.Unix(metric.Timestamp, 0),
+		metric.Name,
+		metric.Value,
+		string(metric.Type),
+		deviceID,
+		metric.Labels,
+		sum,
+		count,
+		bucketBounds,
+		bucketCounts,
+		quantiles,
+		quantileValues,
+	}
+}
+
 // Write 写入数据
 func (cw *ClickHouseWriter) Write(ctx context.Context, metrics []*plugin.Metric) error {
 	if len(metrics) == 0 {
@@ -102,9 +173,9 @@ func (cw *ClickHouseWriter) Write(ctx context.Context, metrics []*plugin.Metric)
 
 	// 准备批量插入语句
 	insertSQL := fmt.Sprintf(`
-		INSERT INTO %s (timestamp, metric_name, metric_value, metric_type, device_id, labels)
-		VALUES (?, ?, ?, ?, ?, ?)
-	`, cw.tableName)
+		INSERT INTO %s (%s)
+		VALUES %s
+	`, cw.tableName, insertColumnsSQL, insertValuesSQL)
 
 	stmt, err := tx.PrepareContext(ctx, insertSQL)
 	if err != nil {
@@ -114,24 +185,8 @@ func (cw *ClickHouseWriter) Write(ctx context.Context, metrics []*plugin.Metric)
 
 	// 批量插入
 	for _, metric := range metrics {
-		// 转换时间戳
-		timestamp := time.Unix(metric.Timestamp, 0)
-
-		// 获取 device_id
-		deviceID := metric.Labels["device_id"]
-		if deviceID == "" {
-			deviceID = "unknown"
-		}
-
 		// 执行插入
-		_, err := stmt.ExecContext(ctx,
-			timestamp,
-			metric.Name,
-			metric.Value,
-			string(metric.Type),
-			deviceID,
-			metric.Labels,
-		)
+		_, err := stmt.ExecContext(ctx, rowValues(metric)...)
 		if err != nil {
 			return fmt.Errorf("failed to insert metric: %w", err)
 		}
@@ -160,28 +215,15 @@ func (cw *ClickHouseWriter) WriteBatch(ctx context.Context, metrics []*plugin.Me
 	var valueArgs []interface{}
 
 	for _, metric := range metrics {
-		timestamp := time.Unix(metric.Timestamp, 0)
-		deviceID := metric.Labels["device_id"]
-		if deviceID == "" {
-			deviceID = "unknown"
-		}
-
-		valueStrings = append(valueStrings, "(?, ?, ?, ?, ?, ?)")
-		valueArgs = append(valueArgs,
-			timestamp,
-			metric.Name,
-			metric.Value,
-			string(metric.Type),
-			deviceID,
-			metric.Labels,
-		)
+		valueStrings = append(valueStrings, insertValuesSQL)
+		valueArgs = append(valueArgs, rowValues(metric)...)
 	}
 
 	// 执行批量插入
 	insertSQL := fmt.Sprintf(`
-		INSERT INTO %s (timestamp, metric_name, metric_value, metric_type, device_id, labels)
+		INSERT INTO %s (%s)
 		VALUES %s
-	`, cw.tableName, strings.Join(valueStrings, ","))
+	`, cw.tableName, insertColumnsSQL, strings.Join(valueStrings, ","))
 
 	_, err := cw.db.ExecContext(ctx, insertSQL, valueArgs...)
 	if err != nil {
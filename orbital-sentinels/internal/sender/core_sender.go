@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/celestial/orbital-sentinels/internal/pkg/logger"
@@ -21,6 +22,7 @@ type CoreSender struct {
 	url        string
 	sentinelID string
 	token      string
+	tokenMu    sync.RWMutex
 	breaker    *CircuitBreaker
 }
 
@@ -37,6 +39,18 @@ func NewCoreSender(url, sentinelID, token string, timeout time.Duration) *CoreSe
 	}
 }
 
+// SetTransport 设置连接中心端使用的 Transport（TLS 配置）
+func (cs *CoreSender) SetTransport(rt http.RoundTripper) {
+	cs.client.Transport = rt
+}
+
+// SetToken 更新 API Token（Token 轮换后调用）
+func (cs *CoreSender) SetToken(token string) {
+	cs.tokenMu.Lock()
+	defer cs.tokenMu.Unlock()
+	cs.token = token
+}
+
 // Send 发送数据
 func (cs *CoreSender) Send(ctx context.Context, metrics []*plugin.Metric) error {
 	// 熔断检查
@@ -71,7 +85,9 @@ func (cs *CoreSender) Send(ctx context.Context, metrics []*plugin.Metric) error
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Content-Encoding", "gzip")
 	req.Header.Set("X-Sentinel-ID", cs.sentinelID)
+	cs.tokenMu.RLock()
 	req.Header.Set("X-API-Token", cs.token)
+	cs.tokenMu.RUnlock()
 
 	// 发送请求
 	resp, err := cs.client.Do(req)
@@ -37,6 +37,32 @@ func (ds *DirectSender) SetClickHouseWriter(writer *ClickHouseWriter) {
 	ds.clickhouse = writer
 }
 
+// metricWriter 指标写入接口
+type metricWriter interface {
+	Write(ctx context.Context, metrics []*plugin.Metric) error
+}
+
+// directTarget 直连写入目标
+type directTarget struct {
+	name   string
+	writer metricWriter
+}
+
+// targets 获取已配置的写入目标，发送器为每个目标维护独立的读取位置
+func (ds *DirectSender) targets() []directTarget {
+	var targets []directTarget
+	if ds.prometheus != nil {
+		targets = append(targets, directTarget{name: "prometheus", writer: ds.prometheus})
+	}
+	if ds.victoria != nil {
+		targets = append(targets, directTarget{name: "victoriametrics", writer: ds.victoria})
+	}
+	if ds.clickhouse != nil {
+		targets = append(targets, directTarget{name: "clickhouse", writer: ds.clickhouse})
+	}
+	return targets
+}
+
 // Send 发送数据
 func (ds *DirectSender) Send(ctx context.Context, metrics []*plugin.Metric) error {
 	if len(metrics) == 0 {
@@ -2,6 +2,8 @@ package sender
 
 import (
 	"context"
+	"math"
+	"strings"
 	"testing"
 	"time"
 
@@ -108,6 +110,108 @@ func TestVictoriaMetricsWriter_ConvertFormat(t *testing.T) {
 	}
 }
 
+// TestToTimeSeries_Distribution 与 gravital-core/internal/forwarder 使用相同的测试数据
+func TestToTimeSeries_Distribution(t *testing.T) {
+	metrics := []*plugin.Metric{
+		{
+			Name:      "request_seconds",
+			Value:     6,
+			Type:      plugin.MetricTypeHistogram,
+			Timestamp: 1700000000,
+			Labels:    map[string]string{"host": "server1"},
+			Histogram: &plugin.Histogram{
+				// 显式给出的 +Inf 桶不能产生重复序列
+				Buckets: []plugin.Bucket{{UpperBound: 0.1, Count: 2}, {UpperBound: 1, Count: 5}, {UpperBound: math.Inf(1), Count: 6}},
+				Sum:     3.2,
+				Count:   6,
+			},
+		},
+		{
+			Name:      "rpc_seconds",
+			Value:     10,
+			Type:      plugin.MetricTypeSummary,
+			Timestamp: 1700000000,
+			Summary: &plugin.Summary{
+				Quantiles: []plugin.Quantile{{Quantile: 0.5, Value: 0.02}},
+				Sum:       0.9,
+				Count:     10,
+			},
+		},
+	}
+
+	writer := NewPrometheusWriter("http://localhost:9090/api/v1/write", 30*time.Second)
+	writeRequest := writer.convertToPrometheusFormat(metrics)
+
+	// 序列名 + le/quantile 标签 → 值
+	got := make(map[string]float64)
+	for _, ts := range writeRequest.Timeseries {
+		var name, extra string
+		for _, label := range ts.Labels {
+			switch label.Name {
+			case "__name__":
+				name = label.Value
+			case "le", "quantile":
+				extra = label.Value
+			}
+		}
+		if ts.Samples[0].Timestamp != 1700000000000 {
+			t.Errorf("%s: unexpected timestamp %d", name, ts.Samples[0].Timestamp)
+		}
+		got[name+"{"+extra+"}"] = ts.Samples[0].Value
+	}
+
+	want := map[string]float64{
+		"request_seconds_bucket{0.1}":  2,
+		"request_seconds_bucket{1}":    5,
+		"request_seconds_bucket{+Inf}": 6,
+		"request_seconds_sum{}":        3.2,
+		"request_seconds_count{}":      6,
+		"rpc_seconds{0.5}":             0.02,
+		"rpc_seconds_sum{}":            0.9,
+		"rpc_seconds_count{}":          10,
+	}
+	if len(writeRequest.Timeseries) != len(want) || len(got) != len(want) {
+		t.Errorf("Expected %d timeseries, got %d: %v", len(want), len(writeRequest.Timeseries), got)
+	}
+	for key, value := range want {
+		if v, ok := got[key]; !ok || v != value {
+			t.Errorf("%s: expected %v, got %v (found=%v)", key, value, v, ok)
+		}
+	}
+}
+
+func TestClickHouseRowValues(t *testing.T) {
+	metric := &plugin.Metric{
+		Name:      "request_seconds",
+		Value:     6,
+		Type:      plugin.MetricTypeHistogram,
+		Timestamp: 1700000000,
+		Labels:    map[string]string{"device_id": "dev-1"},
+		Histogram: &plugin.Histogram{
+			Buckets: []plugin.Bucket{{UpperBound: 0.1, Count: 2}, {UpperBound: 1, Count: 5}},
+			Sum:     3.2,
+			Count:   6,
+		},
+	}
+
+	values := rowValues(metric)
+	if len(values) != strings.Count(insertValuesSQL, "?") {
+		t.Fatalf("Expected %d values, got %d", strings.Count(insertValuesSQL, "?"), len(values))
+	}
+	if values[4] != "dev-1" || values[6] != 3.2 || values[7] != uint64(6) {
+		t.Errorf("Unexpected values: %v", values)
+	}
+	if bounds := values[8].([]float64); len(bounds) != 2 || bounds[1] != 1 {
+		t.Errorf("Unexpected bucket bounds: %v", bounds)
+	}
+	if counts := values[9].([]uint64); len(counts) != 2 || counts[1] != 5 {
+		t.Errorf("Unexpected bucket counts: %v", counts)
+	}
+	if quantiles := values[10].([]float64); quantiles == nil || len(quantiles) != 0 {
+		t.Errorf("Expected empty quantiles, got %v", quantiles)
+	}
+}
+
 func TestDirectSender_Send(t *testing.T) {
 	// 创建直连发送器
 	directSender := NewDirectSender()
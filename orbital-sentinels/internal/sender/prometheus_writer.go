@@ -112,30 +112,7 @@ func (pw *PrometheusWriter) convertToPrometheusFormat(metrics []*plugin.Metric)
 	timeseries := make([]prompb.TimeSeries, 0, len(metrics))
 
 	for _, metric := range metrics {
-		// 构建标签
-		labels := make([]prompb.Label, 0, len(metric.Labels)+1)
-		labels = append(labels, prompb.Label{
-			Name:  "__name__",
-			Value: metric.Name,
-		})
-
-		for key, value := range metric.Labels {
-			labels = append(labels, prompb.Label{
-				Name:  key,
-				Value: value,
-			})
-		}
-
-		// 构建样本
-		sample := prompb.Sample{
-			Value:     metric.Value,
-			Timestamp: metric.Timestamp * 1000, // 转换为毫秒
-		}
-
-		timeseries = append(timeseries, prompb.TimeSeries{
-			Labels:  labels,
-			Samples: []prompb.Sample{sample},
-		})
+		timeseries = append(timeseries, toTimeSeries(metric)...)
 	}
 
 	return &prompb.WriteRequest{
@@ -0,0 +1,71 @@
+package sender
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/celestial/orbital-sentinels/internal/plugin"
+	"github.com/prometheus/prometheus/prompb"
+)
+
+// toTimeSeries 将指标转换为 Remote Write 时间序列
+// 直方图展开为 _bucket（含 le="+Inf"）、_sum、_count 序列，摘要展开为带 quantile 标签的序列和 _sum、_count 序列。
+func toTimeSeries(metric *plugin.Metric) []prompb.TimeSeries {
+	timestamp := metric.Timestamp * 1000 // 转换为毫秒
+	series := func(name string, value float64, extra ...prompb.Label) prompb.TimeSeries {
+		labels := make([]prompb.Label, 0, len(metric.Labels)+1+len(extra))
+		labels = append(labels, prompb.Label{
+			Name:  "__name__",
+			Value: name,
+		})
+		for key, value := range metric.Labels {
+			labels = append(labels, prompb.Label{
+				Name:  key,
+				Value: value,
+			})
+		}
+		labels = append(labels, extra...)
+
+		return prompb.TimeSeries{
+			Labels:  labels,
+			Samples: []prompb.Sample{{Value: value, Timestamp: timestamp}},
+		}
+	}
+
+	switch {
+	case metric.Histogram != nil:
+		h := metric.Histogram
+		timeseries := make([]prompb.TimeSeries, 0, len(h.Buckets)+3)
+		for _, b := range h.Buckets {
+			// +Inf 桶统一按 Count 输出，跳过输入中显式给出的 +Inf 桶，避免重复序列
+			if math.IsInf(b.UpperBound, 1) {
+				continue
+			}
+			timeseries = append(timeseries, series(metric.Name+"_bucket", float64(b.Count),
+				prompb.Label{Name: "le", Value: formatFloat(b.UpperBound)}))
+		}
+		return append(timeseries,
+			series(metric.Name+"_bucket", float64(h.Count), prompb.Label{Name: "le", Value: "+Inf"}),
+			series(metric.Name+"_sum", h.Sum),
+			series(metric.Name+"_count", float64(h.Count)),
+		)
+	case metric.Summary != nil:
+		s := metric.Summary
+		timeseries := make([]prompb.TimeSeries, 0, len(s.Quantiles)+2)
+		for _, q := range s.Quantiles {
+			timeseries = append(timeseries, series(metric.Name, q.Value,
+				prompb.Label{Name: "quantile", Value: formatFloat(q.Quantile)}))
+		}
+		return append(timeseries,
+			series(metric.Name+"_sum", s.Sum),
+			series(metric.Name+"_count", float64(s.Count)),
+		)
+	default:
+		return []prompb.TimeSeries{series(metric.Name, metric.Value)}
+	}
+}
+
+// formatFloat 按 Prometheus 文本格式输出 le、quantile 标签值
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
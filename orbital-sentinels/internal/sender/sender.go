@@ -2,6 +2,7 @@ package sender
 
 import (
 	"context"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -30,6 +31,24 @@ type Config struct {
 	RetryInterval time.Duration
 }
 
+const (
+	defaultSendTimeout   = 30 * time.Second
+	defaultRetryInterval = time.Second
+	// maxRetryBackoff 重试间隔上限
+	maxRetryBackoff = 5 * time.Minute
+	// maxBatchesPerFlush 单次刷新每个目标最多发送的批次数，积压时分多次追赶
+	maxBatchesPerFlush = 100
+)
+
+// destination 发送目标
+// 每个目标在缓冲区中有独立的读取位置，一个目标失败不影响其他目标，也不会导致其他目标重复发送
+type destination struct {
+	name     string
+	send     func(ctx context.Context, metrics []*plugin.Metric) error
+	failures int       // 连续失败次数
+	nextTry  time.Time // 退避结束时间
+}
+
 // Sender 数据发送器
 type Sender struct {
 	config       *Config
@@ -37,10 +56,13 @@ type Sender struct {
 	buffer       buffer.Buffer
 	coreSender   *CoreSender
 	directSender *DirectSender
+	destinations []*destination
 	successCount atomic.Int64
 	failedCount  atomic.Int64
+	flushMu      sync.Mutex
 	ctx          context.Context
 	cancel       context.CancelFunc
+	wg           sync.WaitGroup
 }
 
 // NewSender 创建发送器
@@ -71,9 +93,24 @@ func (s *Sender) SetDirectSender(directSender *DirectSender) {
 func (s *Sender) Start(ctx context.Context) {
 	s.ctx, s.cancel = context.WithCancel(ctx)
 
+	if len(s.destinations) == 0 {
+		s.destinations = s.buildDestinations()
+	}
+
+	names := make([]string, 0, len(s.destinations))
+	for _, d := range s.destinations {
+		names = append(names, d.name)
+	}
+	if err := s.buffer.SetConsumers(names); err != nil {
+		logger.Error("Failed to register buffer consumers", zap.Error(err))
+	}
+
+	s.wg.Add(1)
 	go s.flushLoop()
 
-	logger.Info("Sender started", zap.String("mode", string(s.mode)))
+	logger.Info("Sender started",
+		zap.String("mode", string(s.mode)),
+		zap.Strings("destinations", names))
 }
 
 // Stop 停止发送器
@@ -81,17 +118,50 @@ func (s *Sender) Stop() {
 	if s.cancel != nil {
 		s.cancel()
 	}
+	s.wg.Wait()
 
-	// 最后一次刷新
-	s.flush()
+	// 最后一次刷新，未发送成功的数据保留在缓冲区
+	ctx, cancel := context.WithTimeout(context.Background(), s.sendTimeout())
+	defer cancel()
+	s.flush(ctx)
 
 	logger.Info("Sender stopped",
 		zap.Int64("success_count", s.successCount.Load()),
 		zap.Int64("failed_count", s.failedCount.Load()))
 }
 
+// buildDestinations 根据发送模式创建发送目标
+func (s *Sender) buildDestinations() []*destination {
+	var destinations []*destination
+
+	if s.mode == SendModeCore || s.mode == SendModeHybrid {
+		if s.coreSender != nil {
+			destinations = append(destinations, &destination{name: "core", send: s.coreSender.Send})
+		} else {
+			logger.Error("Core sender not configured")
+		}
+	}
+
+	if s.mode == SendModeDirect || s.mode == SendModeHybrid {
+		var targets []directTarget
+		if s.directSender != nil {
+			targets = s.directSender.targets()
+		}
+		if len(targets) == 0 {
+			logger.Error("Direct sender not configured")
+		}
+		for _, t := range targets {
+			destinations = append(destinations, &destination{name: t.name, send: t.writer.Write})
+		}
+	}
+
+	return destinations
+}
+
 // flushLoop 刷新循环
 func (s *Sender) flushLoop() {
+	defer s.wg.Done()
+
 	// 确保 FlushInterval 有效（至少 1 秒）
 	flushInterval := s.config.FlushInterval
 	if flushInterval <= 0 {
@@ -106,85 +176,149 @@ func (s *Sender) flushLoop() {
 	for {
 		select {
 		case <-ticker.C:
-			s.flush()
+			s.flush(s.ctx)
 		case <-s.ctx.Done():
 			return
 		}
 	}
 }
 
-// flush 刷新缓冲区
-func (s *Sender) flush() {
-	metrics, err := s.buffer.Pop(s.config.BatchSize)
-	if err != nil || len(metrics) == 0 {
+// flush 将缓冲区数据并发发送到各个目标
+func (s *Sender) flush(ctx context.Context) {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, d := range s.destinations {
+		wg.Add(1)
+		go func(d *destination) {
+			defer wg.Done()
+			s.deliver(ctx, d)
+		}(d)
+	}
+	wg.Wait()
+}
+
+// deliver 发送目标尚未确认的数据，失败时退回缓冲区并进入退避
+func (s *Sender) deliver(ctx context.Context, d *destination) {
+	if time.Now().Before(d.nextTry) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(s.ctx, s.config.Timeout)
-	defer cancel()
+	for i := 0; i < maxBatchesPerFlush; i++ {
+		metrics, err := s.buffer.Peek(d.name, s.config.BatchSize)
+		if err != nil {
+			logger.Error("Failed to read buffer",
+				zap.String("destination", d.name),
+				zap.Error(err))
+			return
+		}
+		if len(metrics) == 0 {
+			return
+		}
 
-	switch s.mode {
-	case SendModeCore:
-		s.sendToCore(ctx, metrics)
-	case SendModeDirect:
-		s.sendDirect(ctx, metrics)
-	case SendModeHybrid:
-		s.sendHybrid(ctx, metrics)
+		if err := s.sendWithRetry(ctx, d, metrics); err != nil {
+			// 退回后下次从同一位置重新发送，保持数据顺序
+			if err := s.buffer.Nack(d.name); err != nil {
+				logger.Error("Failed to nack buffer",
+					zap.String("destination", d.name),
+					zap.Error(err))
+			}
+			s.failedCount.Add(int64(len(metrics)))
+			d.failures++
+			backoff := s.retryDelay(d.failures)
+			d.nextTry = time.Now().Add(backoff)
+			logger.Error("Failed to send metrics, will retry later",
+				zap.String("destination", d.name),
+				zap.Int("count", len(metrics)),
+				zap.Int("failures", d.failures),
+				zap.Duration("backoff", backoff),
+				zap.Error(err))
+			return
+		}
+
+		if err := s.buffer.Commit(d.name, len(metrics)); err != nil {
+			// 确认失败时退回读取位置，避免后续批次跳过未确认的数据
+			logger.Error("Failed to commit buffer",
+				zap.String("destination", d.name),
+				zap.Error(err))
+			if err := s.buffer.Nack(d.name); err != nil {
+				logger.Error("Failed to nack buffer",
+					zap.String("destination", d.name),
+					zap.Error(err))
+			}
+			return
+		}
+		d.failures = 0
+		d.nextTry = time.Time{}
+		s.successCount.Add(int64(len(metrics)))
+		logger.Debug("Sent metrics",
+			zap.String("destination", d.name),
+			zap.Int("count", len(metrics)))
+
+		if len(metrics) < s.config.BatchSize {
+			return
+		}
 	}
 }
 
-// sendToCore 发送到中心端
-func (s *Sender) sendToCore(ctx context.Context, metrics []*plugin.Metric) {
-	if s.coreSender == nil {
-		logger.Error("Core sender not configured")
-		s.failedCount.Add(int64(len(metrics)))
-		return
+// sendWithRetry 发送一批数据，失败时按 RetryTimes 和 RetryInterval 指数退避重试
+func (s *Sender) sendWithRetry(ctx context.Context, d *destination, metrics []*plugin.Metric) error {
+	retryTimes := s.config.RetryTimes
+	if retryTimes < 0 {
+		retryTimes = 0
 	}
 
-	if err := s.coreSender.Send(ctx, metrics); err != nil {
-		logger.Error("Failed to send to core", zap.Error(err))
-		// 重新放回缓冲区
-		s.buffer.Push(metrics)
-		s.failedCount.Add(int64(len(metrics)))
-		return
-	}
+	var err error
+	for attempt := 0; attempt <= retryTimes; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(s.retryDelay(attempt)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
 
-	s.successCount.Add(int64(len(metrics)))
-	logger.Debug("Sent to core", zap.Int("count", len(metrics)))
-}
+		sendCtx, cancel := context.WithTimeout(ctx, s.sendTimeout())
+		err = d.send(sendCtx, metrics)
+		cancel()
+		if err == nil {
+			return nil
+		}
 
-// sendDirect 直连发送
-func (s *Sender) sendDirect(ctx context.Context, metrics []*plugin.Metric) {
-	if s.directSender == nil {
-		logger.Error("Direct sender not configured")
-		s.failedCount.Add(int64(len(metrics)))
-		return
+		logger.Warn("Send attempt failed",
+			zap.String("destination", d.name),
+			zap.Int("attempt", attempt+1),
+			zap.Error(err))
 	}
+	return err
+}
 
-	if err := s.directSender.Send(ctx, metrics); err != nil {
-		logger.Error("Failed to send direct", zap.Error(err))
-		s.buffer.Push(metrics)
-		s.failedCount.Add(int64(len(metrics)))
-		return
+// retryDelay 第 n 次重试的等待时间
+func (s *Sender) retryDelay(n int) time.Duration {
+	interval := s.config.RetryInterval
+	if interval <= 0 {
+		interval = defaultRetryInterval
 	}
 
-	s.successCount.Add(int64(len(metrics)))
-	logger.Debug("Sent direct", zap.Int("count", len(metrics)))
+	delay := interval
+	for i := 1; i < n && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryBackoff {
+		delay = maxRetryBackoff
+	}
+	return delay
 }
 
-// sendHybrid 混合模式发送
-func (s *Sender) sendHybrid(ctx context.Context, metrics []*plugin.Metric) {
-	// 简化实现：所有数据都发送到两个目标
-	// 实际可以根据标签或指标名称进行分离
-
-	// 发送到中心端
-	go s.sendToCore(ctx, metrics)
-
-	// 发送到直连
-	go s.sendDirect(ctx, metrics)
+func (s *Sender) sendTimeout() time.Duration {
+	if s.config.Timeout > 0 {
+		return s.config.Timeout
+	}
+	return defaultSendTimeout
 }
 
-// GetStats 获取统计信息
+// GetStats 获取统计信息（按目标累计）
 func (s *Sender) GetStats() (success, failed int64) {
 	return s.successCount.Load(), s.failedCount.Load()
 }
@@ -0,0 +1,171 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/celestial/orbital-sentinels/internal/buffer"
+	"github.com/celestial/orbital-sentinels/internal/plugin"
+)
+
+// fakeTarget 记录收到的数据，可以模拟失败
+type fakeTarget struct {
+	mu       sync.Mutex
+	received []float64
+	calls    int
+	failures int // 前 failures 次调用失败
+}
+
+func (f *fakeTarget) send(ctx context.Context, metrics []*plugin.Metric) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls++
+	if f.calls <= f.failures {
+		return errors.New("target unavailable")
+	}
+	for _, m := range metrics {
+		f.received = append(f.received, m.Value)
+	}
+	return nil
+}
+
+func newTestSender(retryTimes int, targets map[string]*fakeTarget) *Sender {
+	s := NewSender(&Config{
+		Mode:          SendModeHybrid,
+		BatchSize:     2,
+		Timeout:       time.Second,
+		RetryTimes:    retryTimes,
+		RetryInterval: time.Millisecond,
+	}, buffer.NewMemoryBuffer(100))
+
+	names := make([]string, 0, len(targets))
+	for name, target := range targets {
+		s.destinations = append(s.destinations, &destination{name: name, send: target.send})
+		names = append(names, name)
+	}
+	s.buffer.SetConsumers(names)
+	return s
+}
+
+func pushTestMetrics(t *testing.T, s *Sender, values ...float64) {
+	t.Helper()
+
+	metrics := make([]*plugin.Metric, 0, len(values))
+	for _, v := range values {
+		metrics = append(metrics, &plugin.Metric{Name: "test", Value: v})
+	}
+	if err := s.buffer.Push(metrics); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+}
+
+func TestSender_FlushAllBatches(t *testing.T) {
+	core := &fakeTarget{}
+	s := newTestSender(0, map[string]*fakeTarget{"core": core})
+
+	pushTestMetrics(t, s, 1, 2, 3, 4, 5)
+	s.flush(context.Background())
+
+	// 一次刷新发送完所有批次，顺序不变
+	if len(core.received) != 5 {
+		t.Fatalf("Expected 5 metrics, got %d", len(core.received))
+	}
+	for i, v := range core.received {
+		if v != float64(i+1) {
+			t.Errorf("Expected value %d at %d, got %f", i+1, i, v)
+		}
+	}
+	if s.buffer.Size() != 0 {
+		t.Errorf("Expected empty buffer, got %d", s.buffer.Size())
+	}
+}
+
+func TestSender_RetryWithinFlush(t *testing.T) {
+	core := &fakeTarget{failures: 2}
+	s := newTestSender(2, map[string]*fakeTarget{"core": core})
+
+	pushTestMetrics(t, s, 1, 2)
+	s.flush(context.Background())
+
+	if core.calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", core.calls)
+	}
+	if len(core.received) != 2 {
+		t.Errorf("Expected 2 metrics after retry, got %d", len(core.received))
+	}
+}
+
+func TestSender_RetryBounded(t *testing.T) {
+	core := &fakeTarget{failures: 10}
+	s := newTestSender(1, map[string]*fakeTarget{"core": core})
+
+	pushTestMetrics(t, s, 1, 2)
+	s.flush(context.Background())
+
+	// 重试次数用完后停止，数据保留在缓冲区
+	if core.calls != 2 {
+		t.Errorf("Expected 2 attempts, got %d", core.calls)
+	}
+	if s.buffer.Size() != 2 {
+		t.Errorf("Expected 2 metrics kept in buffer, got %d", s.buffer.Size())
+	}
+	if _, failed := s.GetStats(); failed != 2 {
+		t.Errorf("Expected failed count 2, got %d", failed)
+	}
+
+	// 退避期间不再发送
+	s.flush(context.Background())
+	if core.calls != 2 {
+		t.Errorf("Expected no attempts during backoff, got %d", core.calls)
+	}
+}
+
+func TestSender_HybridNoDuplicates(t *testing.T) {
+	core := &fakeTarget{}
+	prometheus := &fakeTarget{failures: 1}
+	s := newTestSender(0, map[string]*fakeTarget{"core": core, "prometheus": prometheus})
+
+	pushTestMetrics(t, s, 1, 2)
+	s.flush(context.Background())
+
+	if len(core.received) != 2 {
+		t.Fatalf("Expected 2 metrics for core, got %d", len(core.received))
+	}
+	if len(prometheus.received) != 0 {
+		t.Fatalf("Expected prometheus to fail, got %d", len(prometheus.received))
+	}
+
+	// prometheus 退避结束后重新发送，core 不会重复收到
+	for _, d := range s.destinations {
+		d.nextTry = time.Time{}
+	}
+	s.flush(context.Background())
+
+	if len(core.received) != 2 {
+		t.Errorf("Expected no duplicates for core, got %d", len(core.received))
+	}
+	if len(prometheus.received) != 2 || prometheus.received[0] != 1 {
+		t.Errorf("Expected prometheus to receive [1 2], got %v", prometheus.received)
+	}
+	if s.buffer.Size() != 0 {
+		t.Errorf("Expected empty buffer, got %d", s.buffer.Size())
+	}
+}
+
+func TestSender_RetryDelay(t *testing.T) {
+	s := NewSender(&Config{RetryInterval: time.Second}, buffer.NewMemoryBuffer(1))
+
+	if d := s.retryDelay(1); d != time.Second {
+		t.Errorf("Expected 1s, got %v", d)
+	}
+	if d := s.retryDelay(3); d != 4*time.Second {
+		t.Errorf("Expected 4s, got %v", d)
+	}
+	if d := s.retryDelay(100); d != maxRetryBackoff {
+		t.Errorf("Expected %v, got %v", maxRetryBackoff, d)
+	}
+}
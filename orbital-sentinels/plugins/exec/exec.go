@@ -0,0 +1,353 @@
+package exec
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/celestial/orbital-sentinels/internal/pkg/metricfmt"
+	"github.com/celestial/orbital-sentinels/internal/plugin"
+	"github.com/celestial/orbital-sentinels/sdk"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// schemaYAML 插件 Schema，设备字段在 plugin.yaml 中声明
+//
+//go:embed plugin.yaml
+var schemaYAML []byte
+
+// 输出格式
+const (
+	formatNagios     = "nagios"
+	formatPrometheus = "prometheus"
+	formatInflux     = "influx"
+	formatJSON       = "json"
+)
+
+const defaultMaxOutput = 1 << 20
+
+// ExecPlugin 脚本执行插件
+type ExecPlugin struct {
+	sdk.BasePlugin
+	schema     plugin.PluginSchema
+	scriptsDir string // 为空时拒绝执行任何命令
+	sandboxDir string
+	maxOutput  int
+}
+
+// execConfig 设备配置
+type execConfig struct {
+	command     string // 解析后的可执行文件路径
+	args        []string
+	format      string
+	timeout     time.Duration
+	env         map[string]string
+	onlineCodes map[int]bool
+	prefix      string
+}
+
+// NewPlugin 创建插件实例
+func NewPlugin() plugin.Plugin {
+	return &ExecPlugin{}
+}
+
+// Meta 返回插件元信息
+func (p *ExecPlugin) Meta() plugin.PluginMeta {
+	return p.schema.Meta
+}
+
+// Schema 返回配置 Schema
+func (p *ExecPlugin) Schema() plugin.PluginSchema {
+	return p.schema
+}
+
+// Init 初始化插件，config 为插件级配置（scripts_dir、sandbox_dir、max_output）
+func (p *ExecPlugin) Init(config map[string]interface{}) error {
+	if err := yaml.Unmarshal(schemaYAML, &p.schema); err != nil {
+		return fmt.Errorf("failed to parse plugin schema: %w", err)
+	}
+
+	p.maxOutput = getInt(config, "max_output", defaultMaxOutput)
+	if p.maxOutput <= 0 {
+		p.maxOutput = defaultMaxOutput
+	}
+
+	if dir := getString(config, "scripts_dir", ""); dir != "" {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return fmt.Errorf("invalid scripts_dir: %w", err)
+		}
+		if abs, err = filepath.EvalSymlinks(abs); err != nil {
+			return fmt.Errorf("invalid scripts_dir: %w", err)
+		}
+		p.scriptsDir = abs
+	}
+
+	p.sandboxDir = getString(config, "sandbox_dir", filepath.Join(os.TempDir(), "celestial-exec"))
+	if err := os.MkdirAll(p.sandboxDir, 0700); err != nil {
+		return fmt.Errorf("failed to create sandbox_dir: %w", err)
+	}
+	return nil
+}
+
+// ValidateConfig 验证设备配置：先按 Schema 校验字段，再检查命令是否存在且允许执行
+func (p *ExecPlugin) ValidateConfig(deviceConfig map[string]interface{}) error {
+	if err := p.BasePlugin.ValidateConfig(deviceConfig, p.schema); err != nil {
+		return err
+	}
+	_, err := p.parseConfig(deviceConfig)
+	return err
+}
+
+// TestConnection 执行一次命令，退出码不在 online_exit_codes 中或输出无法解析时返回错误
+func (p *ExecPlugin) TestConnection(deviceConfig map[string]interface{}) error {
+	cfg, err := p.parseConfig(deviceConfig)
+	if err != nil {
+		return err
+	}
+
+	task := &plugin.CollectionTask{DeviceConfig: deviceConfig}
+	result, err := p.run(context.Background(), cfg, buildEnv(task, cfg))
+	if err != nil {
+		return err
+	}
+	parsed, err := parseOutput(cfg.format, result.stdout)
+	if !cfg.onlineCodes[result.exitCode] {
+		return fmt.Errorf("command exited with code %d: %s", result.exitCode, result.summary(parsed))
+	}
+	return err
+}
+
+// Collect 执行命令并解析输出
+// 超时、无法启动和输出无法解析时返回错误；命令正常结束时按退出码上报 device_status。
+func (p *ExecPlugin) Collect(ctx context.Context, task *plugin.CollectionTask) ([]*plugin.Metric, error) {
+	cfg, err := p.parseConfig(task.DeviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := p.run(ctx, cfg, buildEnv(task, cfg))
+	if err != nil {
+		return nil, err
+	}
+	if result.truncated {
+		p.Log().Warn("Command output truncated",
+			zap.String("device_id", task.DeviceID),
+			zap.String("command", cfg.command),
+			zap.Int("max_output", p.maxOutput))
+	}
+
+	online := cfg.onlineCodes[result.exitCode]
+	parsed, err := parseOutput(cfg.format, result.stdout)
+	if err != nil {
+		// 失败的命令输出的往往是错误信息，只上报退出码
+		if online {
+			return nil, fmt.Errorf("failed to parse %s output: %w", cfg.format, err)
+		}
+		parsed = nil
+	}
+	if !online {
+		p.Log().Debug("Command reported device offline",
+			zap.String("device_id", task.DeviceID),
+			zap.String("command", cfg.command),
+			zap.Int("exit_code", result.exitCode),
+			zap.String("output", result.summary(parsed)))
+	}
+
+	return buildMetrics(task, cfg, parsed, result, online, time.Now()), nil
+}
+
+// Close 关闭插件
+func (p *ExecPlugin) Close() error {
+	return nil
+}
+
+// parsedOutput 解析后的命令输出
+type parsedOutput struct {
+	metrics []*plugin.Metric
+	status  string // Nagios 状态文本
+}
+
+// parseOutput 按格式解析标准输出
+func parseOutput(format string, stdout []byte) (*parsedOutput, error) {
+	var (
+		out = &parsedOutput{}
+		err error
+	)
+	switch format {
+	case formatPrometheus:
+		out.metrics, err = metricfmt.ParsePrometheus(stdout, "")
+	case formatInflux:
+		out.metrics, err = metricfmt.ParseInflux(stdout)
+	case formatJSON:
+		out.metrics, err = metricfmt.ParseJSON(stdout)
+	default:
+		var nagios *metricfmt.NagiosOutput
+		if nagios, err = metricfmt.ParseNagios(stdout); err == nil {
+			out.metrics, out.status = nagios.Metrics, nagios.Status
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// buildMetrics 为解析出的指标补充 device_id 标签和时间戳，并追加执行结果指标
+func buildMetrics(task *plugin.CollectionTask, cfg *execConfig, parsed *parsedOutput, result *runResult, online bool, now time.Time) []*plugin.Metric {
+	var metrics []*plugin.Metric
+	if parsed != nil {
+		for _, m := range parsed.metrics {
+			if m.Labels == nil {
+				m.Labels = make(map[string]string)
+			}
+			m.Labels["device_id"] = task.DeviceID
+			m.Name = cfg.prefix + m.Name
+			if m.Timestamp == 0 {
+				m.Timestamp = now.Unix()
+			}
+			metrics = append(metrics, m)
+		}
+	}
+
+	labels := map[string]string{
+		"device_id": task.DeviceID,
+		"command":   filepath.Base(cfg.command),
+	}
+	metrics = append(metrics,
+		&plugin.Metric{
+			Name:      "exec_exit_code",
+			Value:     float64(result.exitCode),
+			Timestamp: now.Unix(),
+			Labels:    labels,
+			Type:      plugin.MetricTypeGauge,
+		},
+		&plugin.Metric{
+			Name:      "exec_duration_ms",
+			Value:     float64(result.duration) / float64(time.Millisecond),
+			Timestamp: now.Unix(),
+			Labels:    labels,
+			Type:      plugin.MetricTypeGauge,
+		},
+	)
+
+	// 与调度器生成的 device_status 标签一致，调度器看到插件已上报时不再重复生成
+	deviceType := "unknown"
+	if dt, ok := task.DeviceConfig["device_type"].(string); ok {
+		deviceType = dt
+	}
+	return append(metrics, &plugin.Metric{
+		Name:      "device_status",
+		Value:     boolValue(online),
+		Timestamp: now.Unix(),
+		Labels: map[string]string{
+			"device_id":   task.DeviceID,
+			"device_type": deviceType,
+			"task_id":     task.TaskID,
+			"plugin":      task.PluginName,
+		},
+		Type: plugin.MetricTypeGauge,
+	})
+}
+
+// parseConfig 解析设备配置
+func (p *ExecPlugin) parseConfig(config map[string]interface{}) (*execConfig, error) {
+	cfg := &execConfig{
+		format:  getString(config, "format", formatNagios),
+		timeout: time.Duration(getInt(config, "timeout", 10)) * time.Second,
+		env:     make(map[string]string),
+		prefix:  getString(config, "metric_prefix", ""),
+	}
+	if cfg.timeout <= 0 {
+		cfg.timeout = 10 * time.Second
+	}
+
+	switch cfg.format {
+	case formatNagios, formatPrometheus, formatInflux, formatJSON:
+	default:
+		return nil, fmt.Errorf("unsupported format %q", cfg.format)
+	}
+
+	command, err := p.resolveCommand(getString(config, "command", ""))
+	if err != nil {
+		return nil, err
+	}
+	cfg.command = command
+
+	if args, ok := config["args"].([]interface{}); ok {
+		for _, arg := range args {
+			cfg.args = append(cfg.args, fmt.Sprint(arg))
+		}
+	}
+
+	switch env := config["env"].(type) {
+	case map[string]interface{}:
+		for k, v := range env {
+			cfg.env[k] = fmt.Sprint(v)
+		}
+	case map[string]string:
+		for k, v := range env {
+			cfg.env[k] = v
+		}
+	}
+
+	codes, ok := config["online_exit_codes"].([]interface{})
+	if !ok || len(codes) == 0 {
+		codes = []interface{}{metricfmt.NagiosOK}
+		if cfg.format == formatNagios {
+			codes = append(codes, metricfmt.NagiosWarning)
+		}
+	}
+	cfg.onlineCodes = make(map[int]bool, len(codes))
+	for _, c := range codes {
+		code, err := strconv.Atoi(strings.TrimSpace(fmt.Sprint(c)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid online_exit_codes value %v", c)
+		}
+		cfg.onlineCodes[code] = true
+	}
+
+	return cfg, nil
+}
+
+// boolValue 将布尔值转换为 1/0
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// getString 获取字符串配置
+func getString(config map[string]interface{}, key string, defaultValue string) string {
+	if val, ok := config[key].(string); ok && val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+// getInt 获取整数配置
+func getInt(config map[string]interface{}, key string, defaultValue int) int {
+	switch v := config[key].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	case string:
+		if i, err := strconv.Atoi(v); err == nil {
+			return i
+		}
+	}
+	return defaultValue
+}
+
+// 确保实现了接口
+var _ plugin.Plugin = (*ExecPlugin)(nil)
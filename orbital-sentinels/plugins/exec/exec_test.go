@@ -0,0 +1,301 @@
+//go:build unix
+
+package exec
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/celestial/orbital-sentinels/internal/plugin"
+)
+
+// newTestPlugin 创建以临时目录为脚本目录和沙箱目录的插件
+func newTestPlugin(t *testing.T, settings map[string]interface{}) (*ExecPlugin, string) {
+	scripts := t.TempDir()
+	config := map[string]interface{}{
+		"scripts_dir": scripts,
+		"sandbox_dir": t.TempDir(),
+	}
+	for k, v := range settings {
+		config[k] = v
+	}
+
+	p := NewPlugin().(*ExecPlugin)
+	if err := p.Init(config); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	return p, scripts
+}
+
+// writeScript 在脚本目录中创建可执行脚本
+func writeScript(t *testing.T, dir, name, body string) {
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+body), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTask(config map[string]interface{}) *plugin.CollectionTask {
+	return &plugin.CollectionTask{
+		TaskID:       "task-1",
+		DeviceID:     "dev-1",
+		PluginName:   "exec",
+		DeviceConfig: config,
+	}
+}
+
+// findMetric 返回指定名称的指标
+func findMetric(metrics []*plugin.Metric, name string) *plugin.Metric {
+	for _, m := range metrics {
+		if m.Name == name {
+			return m
+		}
+	}
+	return nil
+}
+
+func TestSchemaFromYAML(t *testing.T) {
+	p, _ := newTestPlugin(t, nil)
+	if p.Meta().Name != "exec" {
+		t.Errorf("unexpected name %q", p.Meta().Name)
+	}
+	if len(p.Schema().DeviceFields) == 0 || len(p.Schema().ConfigFields) == 0 {
+		t.Error("expected device and config fields from plugin.yaml")
+	}
+}
+
+func TestCollect_Nagios(t *testing.T) {
+	p, scripts := newTestPlugin(t, nil)
+	writeScript(t, scripts, "check_disk", `echo "DISK CRITICAL - / 95% | used=95%;80;90 'free space'=512MB"; exit 2`)
+
+	metrics, err := p.Collect(context.Background(), newTask(map[string]interface{}{
+		"command":     "check_disk",
+		"device_type": "server",
+	}))
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	used := findMetric(metrics, "used")
+	if used == nil || used.Value != 95 || used.Labels["unit"] != "%" || used.Labels["device_id"] != "dev-1" || used.Timestamp == 0 {
+		t.Errorf("unexpected perfdata metric: %+v", used)
+	}
+	if m := findMetric(metrics, "free_space"); m == nil || m.Value != 512 {
+		t.Errorf("unexpected perfdata metric: %+v", m)
+	}
+	if m := findMetric(metrics, "exec_exit_code"); m == nil || m.Value != 2 {
+		t.Errorf("unexpected exit code: %+v", m)
+	}
+	status := findMetric(metrics, "device_status")
+	if status == nil || status.Value != 0 {
+		t.Fatalf("expected device_status=0 for CRITICAL, got %+v", status)
+	}
+	if status.Labels["device_type"] != "server" || status.Labels["task_id"] != "task-1" || status.Labels["plugin"] != "exec" {
+		t.Errorf("unexpected device_status labels: %v", status.Labels)
+	}
+}
+
+func TestCollect_Formats(t *testing.T) {
+	p, scripts := newTestPlugin(t, nil)
+	writeScript(t, scripts, "prom", `printf '# TYPE jobs_total counter\njobs_total{queue="a"} 3\n'`)
+	writeScript(t, scripts, "influx", `echo "queue,name=a depth=7i"`)
+	writeScript(t, scripts, "json", `echo '{"queue":{"depth":7}}'`)
+
+	tests := []struct {
+		command string
+		format  string
+		metric  string
+		value   float64
+	}{
+		{"prom", "prometheus", "app_jobs_total", 3},
+		{"influx", "influx", "app_queue_depth", 7},
+		{"json", "json", "app_queue_depth", 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			metrics, err := p.Collect(context.Background(), newTask(map[string]interface{}{
+				"command":       tt.command,
+				"format":        tt.format,
+				"metric_prefix": "app_",
+			}))
+			if err != nil {
+				t.Fatalf("Collect failed: %v", err)
+			}
+			if m := findMetric(metrics, tt.metric); m == nil || m.Value != tt.value {
+				t.Errorf("unexpected %s: %+v", tt.metric, m)
+			}
+			if m := findMetric(metrics, "device_status"); m == nil || m.Value != 1 {
+				t.Errorf("expected device_status=1, got %+v", m)
+			}
+		})
+	}
+}
+
+func TestCollect_ParseError(t *testing.T) {
+	p, scripts := newTestPlugin(t, nil)
+	writeScript(t, scripts, "bad", `echo "not json"`)
+	writeScript(t, scripts, "failing", `echo "connection refused" >&2; echo "not json"; exit 1`)
+
+	if _, err := p.Collect(context.Background(), newTask(map[string]interface{}{
+		"command": "bad",
+		"format":  "json",
+	})); err == nil {
+		t.Error("expected parse error when the command succeeds")
+	}
+
+	// 命令失败时输出通常是错误信息，不视为采集错误
+	metrics, err := p.Collect(context.Background(), newTask(map[string]interface{}{
+		"command": "failing",
+		"format":  "json",
+	}))
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if m := findMetric(metrics, "device_status"); m == nil || m.Value != 0 {
+		t.Errorf("expected device_status=0, got %+v", m)
+	}
+}
+
+func TestCollect_OnlineExitCodes(t *testing.T) {
+	p, scripts := newTestPlugin(t, nil)
+	writeScript(t, scripts, "warn", `echo "WARNING - slow"; exit 1`)
+
+	metrics, err := p.Collect(context.Background(), newTask(map[string]interface{}{"command": "warn"}))
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if m := findMetric(metrics, "device_status"); m == nil || m.Value != 1 {
+		t.Errorf("nagios WARNING should be online by default, got %+v", m)
+	}
+
+	metrics, err = p.Collect(context.Background(), newTask(map[string]interface{}{
+		"command":           "warn",
+		"online_exit_codes": []interface{}{0},
+	}))
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if m := findMetric(metrics, "device_status"); m == nil || m.Value != 0 {
+		t.Errorf("expected device_status=0, got %+v", m)
+	}
+}
+
+func TestCollect_Environment(t *testing.T) {
+	t.Setenv("SENTINEL_SECRET", "leak")
+	p, scripts := newTestPlugin(t, nil)
+	writeScript(t, scripts, "env", `printf '{"metrics":[{"name":"ok","value":1,"labels":{"host":"%s","port":"%s","id":"%s","level":"%s","secret":"%s","pwd":"%s","home":"%s"}}]}' "$DEVICE_HOST" "$DEVICE_SNMP_PORT" "$DEVICE_ID" "$LOG_LEVEL" "$SENTINEL_SECRET" "$(pwd)" "$HOME"`)
+
+	metrics, err := p.Collect(context.Background(), newTask(map[string]interface{}{
+		"command":   "env",
+		"format":    "json",
+		"host":      "10.0.0.1",
+		"snmp-port": 161,
+		"env":       map[string]interface{}{"LOG_LEVEL": "debug"},
+	}))
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	labels := findMetric(metrics, "ok").Labels
+	if labels["host"] != "10.0.0.1" || labels["port"] != "161" || labels["id"] != "dev-1" || labels["level"] != "debug" {
+		t.Errorf("unexpected environment: %v", labels)
+	}
+	if labels["secret"] != "" {
+		t.Error("sentinel environment should not be inherited")
+	}
+	if !strings.HasPrefix(labels["pwd"], p.sandboxDir) || labels["home"] != labels["pwd"] {
+		t.Errorf("expected working directory under sandbox, got pwd=%s home=%s", labels["pwd"], labels["home"])
+	}
+	if _, err := os.Stat(labels["pwd"]); !os.IsNotExist(err) {
+		t.Errorf("working directory should be removed after run")
+	}
+}
+
+func TestCollect_Timeout(t *testing.T) {
+	p, scripts := newTestPlugin(t, nil)
+	writeScript(t, scripts, "hang", "sleep 30 &\nsleep 30\n")
+
+	start := time.Now()
+	_, err := p.Collect(context.Background(), newTask(map[string]interface{}{
+		"command": "hang",
+		"timeout": 1,
+	}))
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("process group was not killed in time: %s", elapsed)
+	}
+}
+
+func TestCollect_OutputLimit(t *testing.T) {
+	p, scripts := newTestPlugin(t, map[string]interface{}{"max_output": 16})
+	writeScript(t, scripts, "big", `echo "OK | a=1"; head -c 100000 /dev/zero`)
+
+	metrics, err := p.Collect(context.Background(), newTask(map[string]interface{}{"command": "big"}))
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if m := findMetric(metrics, "a"); m == nil || m.Value != 1 {
+		t.Errorf("unexpected metric: %+v", m)
+	}
+}
+
+func TestValidateConfig_ScriptsDir(t *testing.T) {
+	p, scripts := newTestPlugin(t, nil)
+	writeScript(t, scripts, "ok", "exit 0")
+	if err := os.WriteFile(filepath.Join(scripts, "data.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	outside := t.TempDir()
+	writeScript(t, outside, "evil", "exit 0")
+	if err := os.Symlink(filepath.Join(outside, "evil"), filepath.Join(scripts, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.ValidateConfig(map[string]interface{}{"command": "ok"}); err != nil {
+		t.Errorf("expected valid config, got %v", err)
+	}
+	for _, command := range []string{"../evil", filepath.Join(outside, "evil"), "link", "data.txt", "missing", "sh"} {
+		if err := p.ValidateConfig(map[string]interface{}{"command": command}); err == nil {
+			t.Errorf("expected error for command %q", command)
+		}
+	}
+	if err := p.ValidateConfig(map[string]interface{}{"command": "ok", "format": "xml"}); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestValidateConfig_NoScriptsDir(t *testing.T) {
+	p := NewPlugin().(*ExecPlugin)
+	if err := p.Init(map[string]interface{}{"sandbox_dir": t.TempDir()}); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	for _, command := range []string{"sh", "/bin/sh"} {
+		if err := p.ValidateConfig(map[string]interface{}{"command": command}); err == nil {
+			t.Errorf("expected error for command %q without scripts_dir", command)
+		}
+	}
+	if _, err := p.Collect(context.Background(), &plugin.CollectionTask{
+		DeviceConfig: map[string]interface{}{"command": "/bin/sh"},
+	}); err == nil {
+		t.Error("expected Collect to fail without scripts_dir")
+	}
+}
+
+func TestTestConnection(t *testing.T) {
+	p, scripts := newTestPlugin(t, nil)
+	writeScript(t, scripts, "ok", `echo "OK - fine"`)
+	writeScript(t, scripts, "down", `echo "CRITICAL - host down"; exit 2`)
+
+	if err := p.TestConnection(map[string]interface{}{"command": "ok"}); err != nil {
+		t.Errorf("TestConnection failed: %v", err)
+	}
+	err := p.TestConnection(map[string]interface{}{"command": "down"})
+	if err == nil || !strings.Contains(err.Error(), "CRITICAL - host down") {
+		t.Errorf("expected error with status text, got %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+package exec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/celestial/orbital-sentinels/internal/plugin"
+)
+
+const (
+	// maxStderr 标准错误最多保留的字节数，只用于日志
+	maxStderr = 4096
+	// waitDelay 进程退出或被终止后等待输出管道关闭的时间
+	waitDelay = 2 * time.Second
+)
+
+// runResult 命令执行结果
+type runResult struct {
+	stdout    []byte
+	stderr    []byte
+	truncated bool // 标准输出超过 max_output
+	exitCode  int
+	duration  time.Duration
+}
+
+// summary 返回用于日志和错误信息的输出摘要：Nagios 状态文本，或标准输出/标准错误的第一行
+func (r *runResult) summary(parsed *parsedOutput) string {
+	if parsed != nil && parsed.status != "" {
+		return parsed.status
+	}
+	for _, out := range [][]byte{r.stdout, r.stderr} {
+		if line, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n"); line != "" {
+			return line
+		}
+	}
+	return "no output"
+}
+
+// run 在独立的临时工作目录中执行命令，超时后终止整个进程组
+// 命令以非零退出码结束不是错误，退出码记录在结果中。
+func (p *ExecPlugin) run(ctx context.Context, cfg *execConfig, env []string) (*runResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, cfg.timeout)
+	defer cancel()
+
+	workDir, err := os.MkdirTemp(p.sandboxDir, "run-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create working directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	stdout := &limitedBuffer{limit: p.maxOutput}
+	stderr := &limitedBuffer{limit: maxStderr}
+
+	cmd := osexec.CommandContext(ctx, cfg.command, cfg.args...)
+	cmd.Dir = workDir
+	cmd.Env = append(env, "HOME="+workDir, "TMPDIR="+workDir)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.WaitDelay = waitDelay
+	setProcessGroup(cmd)
+
+	start := time.Now()
+	err = cmd.Run()
+	duration := time.Since(start)
+	// 脚本留在后台的子进程不能比脚本活得更久
+	killProcessGroup(cmd)
+
+	if err != nil && ctx.Err() != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("command timed out after %s", duration.Round(time.Millisecond))
+		}
+		return nil, ctx.Err()
+	}
+
+	result := &runResult{
+		stdout:    stdout.buf.Bytes(),
+		stderr:    stderr.buf.Bytes(),
+		truncated: stdout.truncated,
+		duration:  duration,
+	}
+
+	var exitErr *osexec.ExitError
+	switch {
+	case err == nil, errors.Is(err, osexec.ErrWaitDelay):
+		result.exitCode = cmd.ProcessState.ExitCode()
+	case errors.As(err, &exitErr):
+		result.exitCode = exitErr.ExitCode()
+	default:
+		return nil, fmt.Errorf("failed to run command: %w", err)
+	}
+	return result, nil
+}
+
+// resolveCommand 解析可执行文件路径
+// 命令必须位于 scripts_dir 下（解析符号链接后判断），未配置 scripts_dir 时拒绝执行任何命令。
+func (p *ExecPlugin) resolveCommand(command string) (string, error) {
+	if command == "" {
+		return "", fmt.Errorf("command is required")
+	}
+	if p.scriptsDir == "" {
+		return "", fmt.Errorf("exec plugin is disabled: scripts_dir is not configured")
+	}
+
+	path := command
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(p.scriptsDir, path)
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("command %q not found: %w", command, err)
+	}
+	rel, err := filepath.Rel(p.scriptsDir, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("command %q is outside scripts_dir %s", command, p.scriptsDir)
+	}
+
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return "", fmt.Errorf("command %q not found: %w", command, err)
+	}
+	if !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 {
+		return "", fmt.Errorf("command %q is not an executable file", command)
+	}
+	return resolved, nil
+}
+
+// buildEnv 构建子进程环境变量，不继承 Sentinel 自身的环境变量（只保留 PATH）
+// 设备配置中的标量值以 DEVICE_<KEY> 传入，另有 DEVICE_ID、TASK_ID，最后是 env 中配置的变量。
+func buildEnv(task *plugin.CollectionTask, cfg *execConfig) []string {
+	vars := map[string]string{
+		"PATH": os.Getenv("PATH"),
+	}
+	for k, v := range task.DeviceConfig {
+		switch v.(type) {
+		case string, bool, int, int64, float64:
+			vars["DEVICE_"+envName(k)] = fmt.Sprint(v)
+		}
+	}
+	vars["DEVICE_ID"] = task.DeviceID
+	vars["TASK_ID"] = task.TaskID
+	for k, v := range cfg.env {
+		vars[k] = v
+	}
+
+	env := make([]string, 0, len(vars))
+	for k, v := range vars {
+		env = append(env, k+"="+v)
+	}
+	sort.Strings(env)
+	return env
+}
+
+// envName 将配置键转换为环境变量名：大写，非字母数字替换为下划线
+func envName(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9'):
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+}
+
+// limitedBuffer 最多保留 limit 字节的输出，超出部分丢弃但不返回错误，避免子进程因管道写失败退出
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+// Write 实现 io.Writer
+func (b *limitedBuffer) Write(data []byte) (int, error) {
+	if remaining := b.limit - b.buf.Len(); remaining < len(data) {
+		b.truncated = true
+		if remaining > 0 {
+			b.buf.Write(data[:remaining])
+		}
+		return len(data), nil
+	}
+	b.buf.Write(data)
+	return len(data), nil
+}
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package exec
+
+import (
+	osexec "os/exec"
+)
+
+// setProcessGroup 非 Unix 平台不支持进程组，超时时只终止命令本身
+func setProcessGroup(cmd *osexec.Cmd) {}
+
+// killProcessGroup 非 Unix 平台不支持进程组
+func killProcessGroup(cmd *osexec.Cmd) {}
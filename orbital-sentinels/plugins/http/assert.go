@@ -0,0 +1,224 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// statusRange 期望的状态码区间
+type statusRange struct {
+	min, max int
+}
+
+// parseStatusRanges 解析期望状态码，支持 200、"200"、"2xx"
+func parseStatusRanges(values []interface{}) ([]statusRange, error) {
+	ranges := make([]statusRange, 0, len(values))
+	for _, v := range values {
+		var s string
+		switch val := v.(type) {
+		case int:
+			s = strconv.Itoa(val)
+		case float64:
+			s = strconv.Itoa(int(val))
+		case string:
+			s = strings.TrimSpace(val)
+		default:
+			return nil, fmt.Errorf("invalid expected status %v", v)
+		}
+
+		if len(s) == 3 && strings.EqualFold(s[1:], "xx") && s[0] >= '1' && s[0] <= '5' {
+			base := int(s[0]-'0') * 100
+			ranges = append(ranges, statusRange{min: base, max: base + 99})
+			continue
+		}
+		code, err := strconv.Atoi(s)
+		if err != nil || code < 100 || code > 599 {
+			return nil, fmt.Errorf("invalid expected status %q", s)
+		}
+		ranges = append(ranges, statusRange{min: code, max: code})
+	}
+	return ranges, nil
+}
+
+// statusMatches 判断状态码是否在任一期望区间内
+func statusMatches(ranges []statusRange, code int) bool {
+	for _, r := range ranges {
+		if code >= r.min && code <= r.max {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonAssertion 对响应体中 JSONPath 指向的值的断言
+// equals 和 regex 都未配置时只检查字段存在；路径匹配多个值时任一满足即通过。
+type jsonAssertion struct {
+	path   string
+	steps  []pathStep
+	equals *string
+	regex  *regexp.Regexp
+}
+
+// parseJSONAssertions 解析 json_assertions 配置
+func parseJSONAssertions(values []interface{}) ([]jsonAssertion, error) {
+	assertions := make([]jsonAssertion, 0, len(values))
+	for _, v := range values {
+		item, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("json assertion must be an object, got %T", v)
+		}
+
+		path, _ := item["path"].(string)
+		steps, err := parseJSONPath(path)
+		if err != nil {
+			return nil, err
+		}
+		a := jsonAssertion{path: path, steps: steps}
+
+		if equals, ok := item["equals"]; ok {
+			s := jsonString(equals)
+			a.equals = &s
+		}
+		if pattern, ok := item["regex"].(string); ok && pattern != "" {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("json assertion %s: invalid regex: %w", path, err)
+			}
+			a.regex = re
+		}
+		assertions = append(assertions, a)
+	}
+	return assertions, nil
+}
+
+// check 判断文档是否满足断言
+func (a *jsonAssertion) check(doc interface{}) bool {
+	for _, v := range evalJSONPath(doc, a.steps) {
+		s := jsonString(v)
+		if a.equals != nil && s != *a.equals {
+			continue
+		}
+		if a.regex != nil && !a.regex.MatchString(s) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// pathStep JSONPath 的一级：字段名、数组下标或通配符
+type pathStep struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// parseJSONPath 解析 JSONPath 子集：$.a.b、$['a']、$.items[0]、$.items[-1]、$.items[*].name
+func parseJSONPath(path string) ([]pathStep, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("invalid json path %q: must start with $", path)
+	}
+
+	var steps []pathStep
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			key := rest[:end]
+			if key == "" {
+				return nil, fmt.Errorf("invalid json path %q: empty field name", path)
+			}
+			if key == "*" {
+				steps = append(steps, pathStep{wildcard: true})
+			} else {
+				steps = append(steps, pathStep{key: key})
+			}
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid json path %q: missing ]", path)
+			}
+			inner := rest[1:end]
+			rest = rest[end+1:]
+
+			switch {
+			case inner == "*":
+				steps = append(steps, pathStep{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				steps = append(steps, pathStep{key: inner[1 : len(inner)-1]})
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid json path %q: bad index %q", path, inner)
+				}
+				steps = append(steps, pathStep{index: index, isIndex: true})
+			}
+		default:
+			return nil, fmt.Errorf("invalid json path %q: unexpected %q", path, rest[0])
+		}
+	}
+	return steps, nil
+}
+
+// evalJSONPath 返回路径匹配的所有值
+func evalJSONPath(doc interface{}, steps []pathStep) []interface{} {
+	current := []interface{}{doc}
+	for _, step := range steps {
+		var next []interface{}
+		for _, node := range current {
+			switch n := node.(type) {
+			case map[string]interface{}:
+				if step.wildcard {
+					for _, v := range n {
+						next = append(next, v)
+					}
+				} else if v, ok := n[step.key]; ok && !step.isIndex {
+					next = append(next, v)
+				}
+			case []interface{}:
+				if step.wildcard {
+					next = append(next, n...)
+				} else if step.isIndex {
+					i := step.index
+					if i < 0 {
+						i += len(n)
+					}
+					if i >= 0 && i < len(n) {
+						next = append(next, n[i])
+					}
+				}
+			}
+		}
+		current = next
+	}
+	return current
+}
+
+// jsonString 将 JSON 值格式化为用于比较的字符串，数字不带多余的小数位
+func jsonString(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(val)
+	case bool:
+		return strconv.FormatBool(val)
+	case nil:
+		return "null"
+	default:
+		data, _ := json.Marshal(val)
+		return string(data)
+	}
+}
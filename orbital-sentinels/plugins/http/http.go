@@ -0,0 +1,264 @@
+package http
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/celestial/orbital-sentinels/internal/plugin"
+	"github.com/celestial/orbital-sentinels/sdk"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// schemaYAML 插件 Schema，设备字段在 plugin.yaml 中声明
+//
+//go:embed plugin.yaml
+var schemaYAML []byte
+
+// HTTPPlugin HTTP/HTTPS 拨测插件
+type HTTPPlugin struct {
+	sdk.BasePlugin
+	schema plugin.PluginSchema
+}
+
+// NewPlugin 创建插件实例
+func NewPlugin() plugin.Plugin {
+	return &HTTPPlugin{}
+}
+
+// Meta 返回插件元信息
+func (p *HTTPPlugin) Meta() plugin.PluginMeta {
+	return p.schema.Meta
+}
+
+// Schema 返回配置 Schema
+func (p *HTTPPlugin) Schema() plugin.PluginSchema {
+	return p.schema
+}
+
+// Init 初始化插件
+func (p *HTTPPlugin) Init(config map[string]interface{}) error {
+	if err := yaml.Unmarshal(schemaYAML, &p.schema); err != nil {
+		return fmt.Errorf("failed to parse plugin schema: %w", err)
+	}
+	return nil
+}
+
+// ValidateConfig 验证设备配置：先按 Schema 校验字段，再检查正则、状态码和 JSONPath 能否解析
+func (p *HTTPPlugin) ValidateConfig(deviceConfig map[string]interface{}) error {
+	if err := p.BasePlugin.ValidateConfig(deviceConfig, p.schema); err != nil {
+		return err
+	}
+	_, err := parseConfig(deviceConfig)
+	return err
+}
+
+// TestConnection 测试连接
+func (p *HTTPPlugin) TestConnection(deviceConfig map[string]interface{}) error {
+	cfg, err := parseConfig(deviceConfig)
+	if err != nil {
+		return err
+	}
+
+	result := probe(context.Background(), cfg)
+	if result.err != nil {
+		return result.err
+	}
+	if !result.statusOK {
+		return fmt.Errorf("unexpected status code: %d", result.statusCode)
+	}
+	if result.bodyChecked && !result.bodyOK {
+		return fmt.Errorf("response body assertion failed")
+	}
+	return nil
+}
+
+// Collect 采集数据，请求失败时返回 http_up=0 而不是错误
+func (p *HTTPPlugin) Collect(ctx context.Context, task *plugin.CollectionTask) ([]*plugin.Metric, error) {
+	cfg, err := parseConfig(task.DeviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	result := probe(ctx, cfg)
+	if result.err != nil {
+		p.Log().Debug("HTTP probe failed",
+			zap.String("url", cfg.url),
+			zap.Error(result.err))
+	}
+
+	return buildMetrics(task.DeviceID, cfg, result, time.Now()), nil
+}
+
+// Close 关闭插件
+func (p *HTTPPlugin) Close() error {
+	return nil
+}
+
+// buildMetrics 将拨测结果转换为指标，请求未完成时只上报 http_up 和已完成阶段的耗时
+func buildMetrics(deviceID string, cfg *probeConfig, result *probeResult, now time.Time) []*plugin.Metric {
+	labels := map[string]string{
+		"device_id": deviceID,
+		"url":       cfg.url,
+		"method":    cfg.method,
+	}
+
+	var metrics []*plugin.Metric
+	add := func(name string, value float64) {
+		metrics = append(metrics, &plugin.Metric{
+			Name:      name,
+			Value:     value,
+			Timestamp: now.Unix(),
+			Labels:    labels,
+			Type:      plugin.MetricTypeGauge,
+		})
+	}
+
+	add("http_up", boolValue(result.up()))
+	if result.dns > 0 {
+		add("http_dns_ms", milliseconds(result.dns))
+	}
+	if result.connect > 0 {
+		add("http_connect_ms", milliseconds(result.connect))
+	}
+	if result.tlsHandshake > 0 {
+		add("http_tls_ms", milliseconds(result.tlsHandshake))
+	}
+	if !result.certNotAfter.IsZero() {
+		add("http_ssl_cert_expiry_days", result.certNotAfter.Sub(now).Hours()/24)
+	}
+	if result.err != nil {
+		return metrics
+	}
+
+	add("http_status_code", float64(result.statusCode))
+	add("http_status_ok", boolValue(result.statusOK))
+	if result.bodyChecked {
+		add("http_body_match", boolValue(result.bodyOK))
+	}
+	add("http_content_length", float64(result.contentLength))
+	add("http_ttfb_ms", milliseconds(result.ttfb))
+	add("http_total_ms", milliseconds(result.total))
+	add("http_redirects", float64(result.redirects))
+	return metrics
+}
+
+// parseConfig 解析设备配置
+func parseConfig(config map[string]interface{}) (*probeConfig, error) {
+	cfg := &probeConfig{
+		url:             getString(config, "url", ""),
+		method:          strings.ToUpper(getString(config, "method", "GET")),
+		headers:         make(map[string]string),
+		body:            getString(config, "body", ""),
+		timeout:         time.Duration(getInt(config, "timeout", 10)) * time.Second,
+		followRedirects: getBool(config, "follow_redirects", true),
+		maxRedirects:    getInt(config, "max_redirects", 10),
+		tlsSkipVerify:   getBool(config, "tls_skip_verify", false),
+		tlsServerName:   getString(config, "tls_server_name", ""),
+	}
+
+	u, err := url.Parse(cfg.url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid url %q", cfg.url)
+	}
+	if cfg.timeout <= 0 {
+		cfg.timeout = 10 * time.Second
+	}
+
+	switch headers := config["headers"].(type) {
+	case map[string]interface{}:
+		for k, v := range headers {
+			cfg.headers[k] = fmt.Sprint(v)
+		}
+	case map[string]string:
+		for k, v := range headers {
+			cfg.headers[k] = v
+		}
+	}
+
+	expected, ok := config["expected_status"].([]interface{})
+	if !ok || len(expected) == 0 {
+		expected = []interface{}{"2xx"}
+	}
+	if cfg.expectedStatus, err = parseStatusRanges(expected); err != nil {
+		return nil, err
+	}
+
+	if pattern := getString(config, "body_regex", ""); pattern != "" {
+		if cfg.bodyRegex, err = regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("invalid body_regex: %w", err)
+		}
+	}
+
+	if assertions, ok := config["json_assertions"].([]interface{}); ok {
+		if cfg.jsonAssertions, err = parseJSONAssertions(assertions); err != nil {
+			return nil, err
+		}
+	}
+
+	if proxy := getString(config, "proxy", ""); proxy != "" {
+		if cfg.proxy, err = url.Parse(proxy); err != nil || cfg.proxy.Host == "" {
+			return nil, fmt.Errorf("invalid proxy %q", proxy)
+		}
+	}
+
+	return cfg, nil
+}
+
+// milliseconds 将耗时转换为毫秒
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// boolValue 将布尔值转换为 1/0
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// getString 获取字符串配置
+func getString(config map[string]interface{}, key string, defaultValue string) string {
+	if val, ok := config[key].(string); ok && val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+// getInt 获取整数配置
+func getInt(config map[string]interface{}, key string, defaultValue int) int {
+	switch v := config[key].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	case string:
+		if i, err := strconv.Atoi(v); err == nil {
+			return i
+		}
+	}
+	return defaultValue
+}
+
+// getBool 获取布尔配置，支持 "true"/"false" 字符串
+func getBool(config map[string]interface{}, key string, defaultValue bool) bool {
+	switch v := config[key].(type) {
+	case bool:
+		return v
+	case string:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
+// 确保实现了接口
+var _ plugin.Plugin = (*HTTPPlugin)(nil)
@@ -0,0 +1,340 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/celestial/orbital-sentinels/internal/plugin"
+)
+
+func newTestPlugin(t *testing.T) *HTTPPlugin {
+	p := NewPlugin().(*HTTPPlugin)
+	if err := p.Init(nil); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	return p
+}
+
+// metricValue 返回指定名称的指标值
+func metricValue(metrics []*plugin.Metric, name string) (float64, bool) {
+	for _, m := range metrics {
+		if m.Name == name {
+			return m.Value, true
+		}
+	}
+	return 0, false
+}
+
+func newTestServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Token") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"status":"ok","version":2,"items":[{"name":"db","up":true},{"name":"cache","up":false}]}`)
+	})
+	mux.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		buf := new(strings.Builder)
+		fmt.Fprintf(buf, "%s ", r.Method)
+		b := make([]byte, 64)
+		n, _ := r.Body.Read(b)
+		buf.Write(b[:n])
+		fmt.Fprint(w, buf.String())
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/echo", http.StatusFound)
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestSchemaFromYAML(t *testing.T) {
+	p := newTestPlugin(t)
+	schema := p.Schema()
+
+	if schema.Meta.Name != "http" {
+		t.Errorf("meta name = %q, want http", schema.Meta.Name)
+	}
+	fields := make(map[string]plugin.DeviceField)
+	for _, f := range schema.DeviceFields {
+		fields[f.Name] = f
+	}
+	for _, name := range []string{"url", "method", "headers", "body", "expected_status", "body_regex", "json_assertions", "follow_redirects", "proxy"} {
+		if _, ok := fields[name]; !ok {
+			t.Errorf("device field %s missing from schema", name)
+		}
+	}
+	if !fields["url"].Required || len(fields["method"].Options) == 0 {
+		t.Errorf("unexpected url/method definitions: %+v %+v", fields["url"], fields["method"])
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	p := newTestPlugin(t)
+
+	cases := []struct {
+		name   string
+		config map[string]interface{}
+		ok     bool
+	}{
+		{"minimal", map[string]interface{}{"url": "https://example.com"}, true},
+		{"full", map[string]interface{}{
+			"url":             "http://example.com/api",
+			"method":          "POST",
+			"headers":         map[string]interface{}{"X-Token": "a"},
+			"expected_status": []interface{}{200.0, "3xx"},
+			"json_assertions": []interface{}{map[string]interface{}{"path": "$.items[0].name", "equals": "db"}},
+			"timeout":         30.0,
+		}, true},
+		{"missing url", map[string]interface{}{}, false},
+		{"bad scheme", map[string]interface{}{"url": "ftp://example.com"}, false},
+		{"bad method", map[string]interface{}{"url": "http://a", "method": "FETCH"}, false},
+		{"bad headers", map[string]interface{}{"url": "http://a", "headers": "X-Token: a"}, false},
+		{"bad timeout", map[string]interface{}{"url": "http://a", "timeout": 600.0}, false},
+		{"bad status", map[string]interface{}{"url": "http://a", "expected_status": []interface{}{"6xx"}}, false},
+		{"bad regex", map[string]interface{}{"url": "http://a", "body_regex": "("}, false},
+		{"bad json path", map[string]interface{}{"url": "http://a", "json_assertions": []interface{}{map[string]interface{}{"path": "status"}}}, false},
+		{"bad proxy", map[string]interface{}{"url": "http://a", "proxy": "proxy:3128"}, false},
+	}
+	for _, c := range cases {
+		err := p.ValidateConfig(c.config)
+		if (err == nil) != c.ok {
+			t.Errorf("%s: ValidateConfig error = %v, want ok=%v", c.name, err, c.ok)
+		}
+	}
+}
+
+func TestCollect_Assertions(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	p := newTestPlugin(t)
+
+	task := &plugin.CollectionTask{
+		DeviceID: "dev-1",
+		DeviceConfig: map[string]interface{}{
+			"url":        server.URL + "/health",
+			"headers":    map[string]interface{}{"X-Token": "secret"},
+			"body_regex": `"status":"ok"`,
+			"json_assertions": []interface{}{
+				map[string]interface{}{"path": "$.version", "equals": 2.0},
+				map[string]interface{}{"path": "$.items[*].name", "equals": "cache"},
+				map[string]interface{}{"path": "$['items'][-1].up", "regex": "^false$"},
+			},
+		},
+	}
+	metrics, err := p.Collect(context.Background(), task)
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	want := map[string]float64{
+		"http_up":          1,
+		"http_status_code": 200,
+		"http_status_ok":   1,
+		"http_body_match":  1,
+		"http_redirects":   0,
+	}
+	for name, v := range want {
+		if got, ok := metricValue(metrics, name); !ok || got != v {
+			t.Errorf("%s = %v (present %v), want %v", name, got, ok, v)
+		}
+	}
+	if v, _ := metricValue(metrics, "http_content_length"); v <= 0 {
+		t.Errorf("http_content_length = %v", v)
+	}
+	if v, _ := metricValue(metrics, "http_connect_ms"); v <= 0 {
+		t.Errorf("http_connect_ms = %v", v)
+	}
+	if metrics[0].Labels["url"] != server.URL+"/health" || metrics[0].Labels["method"] != "GET" {
+		t.Errorf("labels = %v", metrics[0].Labels)
+	}
+
+	// JSON 断言失败
+	task.DeviceConfig["json_assertions"] = []interface{}{map[string]interface{}{"path": "$.status", "equals": "degraded"}}
+	metrics, _ = p.Collect(context.Background(), task)
+	if up, _ := metricValue(metrics, "http_up"); up != 0 {
+		t.Errorf("http_up = %v with failing assertion, want 0", up)
+	}
+	if match, _ := metricValue(metrics, "http_body_match"); match != 0 {
+		t.Errorf("http_body_match = %v, want 0", match)
+	}
+
+	// 状态码不符合
+	delete(task.DeviceConfig, "headers")
+	metrics, _ = p.Collect(context.Background(), task)
+	if code, _ := metricValue(metrics, "http_status_code"); code != 401 {
+		t.Errorf("http_status_code = %v, want 401", code)
+	}
+	if ok, _ := metricValue(metrics, "http_status_ok"); ok != 0 {
+		t.Errorf("http_status_ok = %v, want 0", ok)
+	}
+}
+
+func TestCollect_MethodBodyAndRedirects(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	p := newTestPlugin(t)
+
+	metrics, _ := p.Collect(context.Background(), &plugin.CollectionTask{DeviceConfig: map[string]interface{}{
+		"url":        server.URL + "/echo",
+		"method":     "post",
+		"body":       "ping",
+		"body_regex": "^POST ping$",
+	}})
+	if up, _ := metricValue(metrics, "http_up"); up != 1 {
+		t.Errorf("http_up = %v for POST echo, want 1", up)
+	}
+
+	metrics, _ = p.Collect(context.Background(), &plugin.CollectionTask{DeviceConfig: map[string]interface{}{
+		"url": server.URL + "/redirect",
+	}})
+	if n, _ := metricValue(metrics, "http_redirects"); n != 1 {
+		t.Errorf("http_redirects = %v, want 1", n)
+	}
+
+	metrics, _ = p.Collect(context.Background(), &plugin.CollectionTask{DeviceConfig: map[string]interface{}{
+		"url":              server.URL + "/redirect",
+		"follow_redirects": false,
+		"expected_status":  []interface{}{302.0},
+	}})
+	if up, _ := metricValue(metrics, "http_up"); up != 1 {
+		t.Errorf("http_up = %v without following redirect, want 1", up)
+	}
+
+	metrics, _ = p.Collect(context.Background(), &plugin.CollectionTask{DeviceConfig: map[string]interface{}{
+		"url":           server.URL + "/loop",
+		"max_redirects": 3.0,
+	}})
+	if up, _ := metricValue(metrics, "http_up"); up != 0 {
+		t.Errorf("http_up = %v for redirect loop, want 0", up)
+	}
+	if _, ok := metricValue(metrics, "http_status_code"); ok {
+		t.Error("http_status_code should not be reported when the request fails")
+	}
+}
+
+func TestCollect_TLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+	p := newTestPlugin(t)
+
+	// 自签名证书校验失败，但仍上报证书有效期
+	metrics, _ := p.Collect(context.Background(), &plugin.CollectionTask{DeviceConfig: map[string]interface{}{
+		"url": server.URL,
+	}})
+	if up, _ := metricValue(metrics, "http_up"); up != 0 {
+		t.Errorf("http_up = %v with untrusted certificate, want 0", up)
+	}
+
+	metrics, _ = p.Collect(context.Background(), &plugin.CollectionTask{DeviceConfig: map[string]interface{}{
+		"url":             server.URL,
+		"tls_skip_verify": true,
+	}})
+	if up, _ := metricValue(metrics, "http_up"); up != 1 {
+		t.Errorf("http_up = %v with tls_skip_verify, want 1", up)
+	}
+	if v, _ := metricValue(metrics, "http_tls_ms"); v <= 0 {
+		t.Errorf("http_tls_ms = %v", v)
+	}
+	days, ok := metricValue(metrics, "http_ssl_cert_expiry_days")
+	wantDays := server.Certificate().NotAfter.Sub(time.Now()).Hours() / 24
+	if !ok || days < wantDays-1 || days > wantDays+1 {
+		t.Errorf("http_ssl_cert_expiry_days = %v, want about %v", days, wantDays)
+	}
+}
+
+func TestCollect_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(3 * time.Second):
+		}
+	}))
+	defer server.Close()
+	p := newTestPlugin(t)
+
+	start := time.Now()
+	metrics, err := p.Collect(context.Background(), &plugin.CollectionTask{DeviceConfig: map[string]interface{}{
+		"url":     server.URL,
+		"timeout": 1,
+	}})
+	if err != nil {
+		t.Fatalf("Collect should not return error on probe failure: %v", err)
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Errorf("probe took %v, timeout not applied", time.Since(start))
+	}
+	if up, _ := metricValue(metrics, "http_up"); up != 0 {
+		t.Errorf("http_up = %v on timeout, want 0", up)
+	}
+}
+
+func TestProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		fmt.Fprint(w, "via proxy")
+	}))
+	defer proxy.Close()
+	p := newTestPlugin(t)
+
+	metrics, _ := p.Collect(context.Background(), &plugin.CollectionTask{DeviceConfig: map[string]interface{}{
+		"url":        "http://target.invalid/status",
+		"proxy":      proxy.URL,
+		"body_regex": "via proxy",
+	}})
+	if up, _ := metricValue(metrics, "http_up"); up != 1 {
+		t.Errorf("http_up = %v through proxy, want 1", up)
+	}
+	if proxied != "http://target.invalid/status" {
+		t.Errorf("proxy received %q", proxied)
+	}
+}
+
+func TestJSONPath(t *testing.T) {
+	doc := map[string]interface{}{
+		"a": map[string]interface{}{"b.c": 1.5, "list": []interface{}{"x", "y"}},
+	}
+
+	cases := []struct {
+		path string
+		want []string
+	}{
+		{"$.a['b.c']", []string{"1.5"}},
+		{"$.a.list[1]", []string{"y"}},
+		{"$.a.list[-2]", []string{"x"}},
+		{"$.a.list[*]", []string{"x", "y"}},
+		{"$.a.missing", nil},
+		{"$.a.list[5]", nil},
+	}
+	for _, c := range cases {
+		steps, err := parseJSONPath(c.path)
+		if err != nil {
+			t.Fatalf("parseJSONPath(%q) failed: %v", c.path, err)
+		}
+		var got []string
+		for _, v := range evalJSONPath(doc, steps) {
+			got = append(got, jsonString(v))
+		}
+		if strings.Join(got, ",") != strings.Join(c.want, ",") {
+			t.Errorf("%s = %v, want %v", c.path, got, c.want)
+		}
+	}
+
+	for _, bad := range []string{"a.b", "$.", "$[abc]", "$[0", "$x"} {
+		if _, err := parseJSONPath(bad); err == nil {
+			t.Errorf("parseJSONPath(%q) should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxAssertBodySize 用于断言的响应体最大长度，超出部分只计入大小
+const maxAssertBodySize = 1 << 20
+
+// userAgent 未配置 User-Agent 时使用的默认值
+const userAgent = "Celestial-Sentinel/1.0"
+
+// probeConfig 拨测配置
+type probeConfig struct {
+	url             string
+	method          string
+	headers         map[string]string
+	body            string
+	timeout         time.Duration
+	expectedStatus  []statusRange
+	bodyRegex       *regexp.Regexp
+	jsonAssertions  []jsonAssertion
+	followRedirects bool
+	maxRedirects    int
+	proxy           *url.URL
+	tlsSkipVerify   bool
+	tlsServerName   string
+}
+
+// probeResult 拨测结果，err 不为空时表示请求未完成
+type probeResult struct {
+	statusCode    int
+	contentLength int64
+	redirects     int
+	dns           time.Duration
+	connect       time.Duration
+	tlsHandshake  time.Duration
+	ttfb          time.Duration
+	total         time.Duration
+	certNotAfter  time.Time // 零值表示没有证书
+	statusOK      bool
+	bodyChecked   bool
+	bodyOK        bool
+	err           error
+}
+
+// up 请求成功且所有断言通过
+func (r *probeResult) up() bool {
+	return r.err == nil && r.statusOK && (!r.bodyChecked || r.bodyOK)
+}
+
+// timings 通过 httptrace 记录各阶段耗时，重定向时累加
+// Happy Eyeballs 可能并发建立多个连接，只统计成功的连接。
+type timings struct {
+	mu           sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	dns          time.Duration
+	connectStart map[string]time.Time
+	connect      time.Duration
+	tlsStart     time.Time
+	tlsHandshake time.Duration
+	firstByte    time.Time
+	cert         *tls.ConnectionState
+}
+
+// trace 返回记录耗时的 ClientTrace
+func (t *timings) trace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mu.Lock()
+			t.dnsStart = time.Now()
+			t.mu.Unlock()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mu.Lock()
+			t.dns += time.Since(t.dnsStart)
+			t.mu.Unlock()
+		},
+		ConnectStart: func(network, addr string) {
+			t.mu.Lock()
+			t.connectStart[network+addr] = time.Now()
+			t.mu.Unlock()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			t.mu.Lock()
+			if start, ok := t.connectStart[network+addr]; ok && err == nil {
+				t.connect += time.Since(start)
+			}
+			t.mu.Unlock()
+		},
+		TLSHandshakeStart: func() {
+			t.mu.Lock()
+			t.tlsStart = time.Now()
+			t.mu.Unlock()
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			t.mu.Lock()
+			t.tlsHandshake += time.Since(t.tlsStart)
+			// 证书以第一次握手（配置的 URL）为准
+			if err == nil && t.cert == nil {
+				t.cert = &state
+			}
+			t.mu.Unlock()
+		},
+		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			t.firstByte = time.Now()
+			t.mu.Unlock()
+		},
+	}
+}
+
+// probe 执行一次拨测
+func probe(ctx context.Context, cfg *probeConfig) *probeResult {
+	result := &probeResult{}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.timeout)
+	defer cancel()
+
+	var body io.Reader
+	if cfg.body != "" {
+		body = strings.NewReader(cfg.body)
+	}
+	req, err := http.NewRequestWithContext(ctx, cfg.method, cfg.url, body)
+	if err != nil {
+		result.err = fmt.Errorf("failed to create request: %w", err)
+		return result
+	}
+	req.Header.Set("User-Agent", userAgent)
+	for k, v := range cfg.headers {
+		if strings.EqualFold(k, "Host") {
+			req.Host = v
+			continue
+		}
+		req.Header.Set(k, v)
+	}
+
+	// 每次拨测使用独立连接，耗时包含完整的 DNS、TCP 和 TLS 阶段
+	transport := &http.Transport{
+		DialContext:         (&net.Dialer{Timeout: cfg.timeout}).DialContext,
+		TLSClientConfig:     &tls.Config{InsecureSkipVerify: cfg.tlsSkipVerify, ServerName: cfg.tlsServerName},
+		TLSHandshakeTimeout: cfg.timeout,
+		DisableKeepAlives:   true,
+		ForceAttemptHTTP2:   true,
+	}
+	if cfg.proxy != nil {
+		transport.Proxy = http.ProxyURL(cfg.proxy)
+	}
+	defer transport.CloseIdleConnections()
+
+	client := &http.Client{
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if !cfg.followRedirects {
+				return http.ErrUseLastResponse
+			}
+			if len(via) > cfg.maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", cfg.maxRedirects)
+			}
+			result.redirects = len(via)
+			return nil
+		},
+	}
+
+	t := &timings{connectStart: make(map[string]time.Time)}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), t.trace()))
+
+	t.start = time.Now()
+	resp, err := client.Do(req)
+	defer func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		result.total = time.Since(t.start)
+		result.dns = t.dns
+		result.connect = t.connect
+		result.tlsHandshake = t.tlsHandshake
+		if !t.firstByte.IsZero() {
+			result.ttfb = t.firstByte.Sub(t.start)
+		}
+		if t.cert != nil && len(t.cert.PeerCertificates) > 0 {
+			result.certNotAfter = t.cert.PeerCertificates[0].NotAfter
+		}
+	}()
+	if err != nil {
+		result.err = err
+		return result
+	}
+	defer resp.Body.Close()
+
+	result.statusCode = resp.StatusCode
+	result.statusOK = statusMatches(cfg.expectedStatus, resp.StatusCode)
+
+	// 断言只使用响应体的前 maxAssertBodySize 字节
+	head, err := io.ReadAll(io.LimitReader(resp.Body, maxAssertBodySize))
+	if err == nil {
+		var n int64
+		n, err = io.Copy(io.Discard, resp.Body)
+		result.contentLength = int64(len(head)) + n
+	}
+	if err != nil {
+		result.err = fmt.Errorf("failed to read response body: %w", err)
+		return result
+	}
+
+	if cfg.bodyRegex != nil || len(cfg.jsonAssertions) > 0 {
+		result.bodyChecked = true
+		result.bodyOK = checkBody(cfg, head)
+	}
+	return result
+}
+
+// checkBody 检查响应体正则和 JSON 断言
+func checkBody(cfg *probeConfig, body []byte) bool {
+	if cfg.bodyRegex != nil && !cfg.bodyRegex.Match(body) {
+		return false
+	}
+	if len(cfg.jsonAssertions) == 0 {
+		return true
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return false
+	}
+	for i := range cfg.jsonAssertions {
+		if !cfg.jsonAssertions[i].check(doc) {
+			return false
+		}
+	}
+	return true
+}
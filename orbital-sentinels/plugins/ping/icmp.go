@@ -0,0 +1,87 @@
+package ping
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// ICMP Echo 报文类型
+const (
+	icmpv4EchoRequest = 8
+	icmpv4EchoReply   = 0
+	icmpv6EchoRequest = 128
+	icmpv6EchoReply   = 129
+)
+
+// icmpHeaderLen ICMP Echo 头部长度
+const icmpHeaderLen = 8
+
+// minPayloadSize 负载至少包含 8 字节的探测标识
+const minPayloadSize = 8
+
+// echoPacket ICMP Echo 报文
+type echoPacket struct {
+	typ   byte
+	id    uint16
+	seq   uint16
+	token uint64 // 负载前 8 字节，用于匹配请求和应答
+}
+
+// marshalEcho 构造 Echo Request，负载为探测标识加填充
+// ICMPv6 校验和包含伪首部，由内核计算。
+func marshalEcho(ipv6 bool, id, seq uint16, token uint64, payloadSize int) []byte {
+	if payloadSize < minPayloadSize {
+		payloadSize = minPayloadSize
+	}
+	b := make([]byte, icmpHeaderLen+payloadSize)
+	if ipv6 {
+		b[0] = icmpv6EchoRequest
+	} else {
+		b[0] = icmpv4EchoRequest
+	}
+	binary.BigEndian.PutUint16(b[4:], id)
+	binary.BigEndian.PutUint16(b[6:], seq)
+	binary.BigEndian.PutUint64(b[8:], token)
+	for i := icmpHeaderLen + minPayloadSize; i < len(b); i++ {
+		b[i] = byte(i)
+	}
+	if !ipv6 {
+		binary.BigEndian.PutUint16(b[2:], checksum(b))
+	}
+	return b
+}
+
+// parseEcho 解析收到的 ICMP 报文，原始套接字可能带 IPv4 头
+func parseEcho(b []byte) (*echoPacket, error) {
+	if len(b) >= 20 && b[0]>>4 == 4 {
+		ihl := int(b[0]&0x0f) * 4
+		if len(b) < ihl {
+			return nil, errors.New("truncated ipv4 header")
+		}
+		b = b[ihl:]
+	}
+	if len(b) < icmpHeaderLen+minPayloadSize {
+		return nil, errors.New("packet too short")
+	}
+	return &echoPacket{
+		typ:   b[0],
+		id:    binary.BigEndian.Uint16(b[4:]),
+		seq:   binary.BigEndian.Uint16(b[6:]),
+		token: binary.BigEndian.Uint64(b[8:]),
+	}, nil
+}
+
+// checksum 计算 Internet 校验和（RFC 1071）
+func checksum(b []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(b); i += 2 {
+		sum += uint32(b[i])<<8 | uint32(b[i+1])
+	}
+	if len(b)%2 == 1 {
+		sum += uint32(b[len(b)-1]) << 8
+	}
+	for sum>>16 != 0 {
+		sum = sum&0xffff + sum>>16
+	}
+	return ^uint16(sum)
+}
@@ -2,26 +2,43 @@ package ping
 
 import (
 	"context"
+	_ "embed"
+	"errors"
 	"fmt"
-	"os/exec"
-	"regexp"
-	"runtime"
+	"net"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/celestial/orbital-sentinels/internal/plugin"
 	"github.com/celestial/orbital-sentinels/sdk"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
 )
 
+// schemaYAML 插件 Schema，设备字段在 plugin.yaml 中声明
+//
+//go:embed plugin.yaml
+var schemaYAML []byte
+
+// pingerKey 共享套接字的分类：地址族和 DF 位
+type pingerKey struct {
+	ipv6 bool
+	df   bool
+}
+
 // PingPlugin Ping 插件
 type PingPlugin struct {
 	sdk.BasePlugin
 	schema plugin.PluginSchema
+
+	mu      sync.Mutex
+	pingers map[pingerKey]*Pinger
 }
 
 // NewPlugin 创建插件实例
 func NewPlugin() plugin.Plugin {
-	return &PingPlugin{}
+	return &PingPlugin{pingers: make(map[pingerKey]*Pinger)}
 }
 
 // Meta 返回插件元信息
@@ -36,180 +53,212 @@ func (p *PingPlugin) Schema() plugin.PluginSchema {
 
 // Init 初始化插件
 func (p *PingPlugin) Init(config map[string]interface{}) error {
-	// 加载 schema
-	// 在实际使用中，schema 应该从 plugin.yaml 加载
-	p.schema = plugin.PluginSchema{
-		Meta: plugin.PluginMeta{
-			Name:        "ping",
-			Version:     "1.0.0",
-			Description: "ICMP Ping 连通性检测插件",
-			Author:      "Celestial Team",
-			DeviceTypes: []string{"server", "switch", "router", "any"},
-		},
+	if err := yaml.Unmarshal(schemaYAML, &p.schema); err != nil {
+		return fmt.Errorf("failed to parse plugin schema: %w", err)
 	}
-
 	return nil
 }
 
 // ValidateConfig 验证设备配置
 func (p *PingPlugin) ValidateConfig(deviceConfig map[string]interface{}) error {
-	// 检查必填字段
-	if _, ok := deviceConfig["host"]; !ok {
-		return fmt.Errorf("host is required")
-	}
-
-	return nil
+	return p.BasePlugin.ValidateConfig(deviceConfig, p.schema)
 }
 
 // TestConnection 测试连接
 func (p *PingPlugin) TestConnection(deviceConfig map[string]interface{}) error {
-	host := deviceConfig["host"].(string)
+	host, _ := deviceConfig["host"].(string)
+	opts := p.options(deviceConfig)
+	opts.count = 1
 
-	// 执行一次 ping
-	_, err := p.ping(host, 1, 5)
-	return err
+	result, err := p.ping(context.Background(), host, deviceConfig, opts)
+	if err != nil {
+		return err
+	}
+	if result.Received == 0 {
+		return fmt.Errorf("no reply from %s", host)
+	}
+	return nil
 }
 
 // Collect 采集数据
 func (p *PingPlugin) Collect(ctx context.Context, task *plugin.CollectionTask) ([]*plugin.Metric, error) {
-	host := task.DeviceConfig["host"].(string)
-	count := p.getInt(task.DeviceConfig, "count", 4)
-	timeout := p.getInt(task.DeviceConfig, "timeout", 5)
+	host, _ := task.DeviceConfig["host"].(string)
+	if host == "" {
+		return nil, fmt.Errorf("host is required")
+	}
 
-	// 执行 ping
-	result, err := p.ping(host, count, timeout)
+	result, err := p.ping(ctx, host, task.DeviceConfig, p.options(task.DeviceConfig))
 	if err != nil {
-		// Ping 失败，返回不可达指标
-		return []*plugin.Metric{
-			{
-				Name:      "ping_reachable",
-				Value:     0,
-				Timestamp: time.Now().Unix(),
-				Labels: map[string]string{
-					"device_id": task.DeviceID,
-					"host":      host,
-				},
-				Type: plugin.MetricTypeGauge,
-			},
-		}, nil
-	}
-
-	// 构建指标
-	metrics := []*plugin.Metric{
-		{
-			Name:      "ping_reachable",
-			Value:     1,
-			Timestamp: time.Now().Unix(),
-			Labels: map[string]string{
-				"device_id": task.DeviceID,
-				"host":      host,
-			},
-			Type: plugin.MetricTypeGauge,
-		},
-		{
-			Name:      "ping_rtt_ms",
-			Value:     result.AvgRTT,
-			Timestamp: time.Now().Unix(),
-			Labels: map[string]string{
-				"device_id": task.DeviceID,
-				"host":      host,
-			},
-			Type: plugin.MetricTypeGauge,
-		},
-		{
-			Name:      "ping_packet_loss",
-			Value:     result.PacketLoss,
-			Timestamp: time.Now().Unix(),
-			Labels: map[string]string{
-				"device_id": task.DeviceID,
-				"host":      host,
-			},
-			Type: plugin.MetricTypeGauge,
-		},
-	}
-
-	return metrics, nil
-}
-
-// Close 关闭插件
+		var dnsErr *net.DNSError
+		if !errors.As(err, &dnsErr) {
+			return nil, err
+		}
+		// 域名解析失败视为不可达
+		p.Log().Debug("Failed to resolve ping target", zap.String("host", host), zap.Error(err))
+		result = &PingResult{PacketLoss: 100}
+	}
+
+	return buildMetrics(task.DeviceID, host, result, time.Now()), nil
+}
+
+// Close 关闭插件，释放共享套接字
 func (p *PingPlugin) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for key, pinger := range p.pingers {
+		pinger.Close()
+		delete(p.pingers, key)
+	}
 	return nil
 }
 
-// PingResult Ping 结果
-type PingResult struct {
-	AvgRTT     float64
-	PacketLoss float64
+// ping 解析目标地址并执行探测
+func (p *PingPlugin) ping(ctx context.Context, host string, config map[string]interface{}, opts pingOptions) (*PingResult, error) {
+	ip, err := resolve(ctx, host, getString(config, "ip_version", "auto"))
+	if err != nil {
+		return nil, err
+	}
+
+	pinger, err := p.pinger(ip.To4() == nil, getBool(config, "df", false))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open icmp socket: %w", err)
+	}
+	return pinger.Ping(ctx, ip, opts)
+}
+
+// pinger 返回共享的 Ping 引擎，首次使用时创建套接字
+func (p *PingPlugin) pinger(ipv6, df bool) (*Pinger, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := pingerKey{ipv6: ipv6, df: df}
+	if pinger, ok := p.pingers[key]; ok {
+		return pinger, nil
+	}
+	pinger, err := newPinger(ipv6, df)
+	if err != nil {
+		return nil, err
+	}
+	p.pingers[key] = pinger
+	return pinger, nil
 }
 
-// ping 执行 ping 命令
-func (p *PingPlugin) ping(host string, count, timeout int) (*PingResult, error) {
-	var cmd *exec.Cmd
+// options 读取探测参数
+func (p *PingPlugin) options(config map[string]interface{}) pingOptions {
+	return pingOptions{
+		count:    getInt(config, "count", 4),
+		interval: time.Duration(getInt(config, "packet_interval", 1000)) * time.Millisecond,
+		timeout:  time.Duration(getInt(config, "timeout", 5)) * time.Second,
+		size:     getInt(config, "size", 56),
+	}
+}
 
-	// 根据操作系统构建命令
-	switch runtime.GOOS {
-	case "linux", "darwin":
-		cmd = exec.Command("ping", "-c", strconv.Itoa(count), "-W", strconv.Itoa(timeout), host)
-	case "windows":
-		cmd = exec.Command("ping", "-n", strconv.Itoa(count), "-w", strconv.Itoa(timeout*1000), host)
-	default:
-		return nil, fmt.Errorf("unsupported OS: %s", runtime.GOOS)
+// resolve 解析目标地址，auto 时优先使用 IPv4
+func resolve(ctx context.Context, host, ipVersion string) (net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return ip, nil
 	}
 
-	// 执行命令
-	output, err := cmd.CombinedOutput()
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
 	if err != nil {
-		return nil, fmt.Errorf("ping failed: %w", err)
+		return nil, err
+	}
+
+	var v4, v6 net.IP
+	for _, addr := range addrs {
+		if addr.IP.To4() != nil {
+			if v4 == nil {
+				v4 = addr.IP
+			}
+		} else if v6 == nil {
+			v6 = addr.IP
+		}
 	}
 
-	// 解析输出
-	return p.parseOutput(string(output))
+	switch {
+	case ipVersion == "6" && v6 != nil:
+		return v6, nil
+	case ipVersion == "4" && v4 != nil:
+		return v4, nil
+	case ipVersion != "4" && ipVersion != "6" && v4 != nil:
+		return v4, nil
+	case ipVersion != "4" && ipVersion != "6" && v6 != nil:
+		return v6, nil
+	}
+	return nil, &net.DNSError{Err: "no IPv" + ipVersion + " address", Name: host, IsNotFound: true}
 }
 
-// parseOutput 解析 ping 输出
-func (p *PingPlugin) parseOutput(output string) (*PingResult, error) {
-	result := &PingResult{}
+// buildMetrics 将 Ping 结果转换为指标，没有收到应答时只上报可达性和丢包率
+func buildMetrics(deviceID, host string, result *PingResult, now time.Time) []*plugin.Metric {
+	labels := map[string]string{
+		"device_id": deviceID,
+		"host":      host,
+	}
 
-	// 解析平均 RTT (Linux/macOS)
-	// 示例: rtt min/avg/max/mdev = 0.123/0.456/0.789/0.012 ms
-	rttRegex := regexp.MustCompile(`rtt min/avg/max/[a-z]+ = [\d.]+/([\d.]+)/[\d.]+/[\d.]+ ms`)
-	if matches := rttRegex.FindStringSubmatch(output); len(matches) > 1 {
-		if avg, err := strconv.ParseFloat(matches[1], 64); err == nil {
-			result.AvgRTT = avg
-		}
+	var metrics []*plugin.Metric
+	add := func(name string, value float64) {
+		metrics = append(metrics, &plugin.Metric{
+			Name:      name,
+			Value:     value,
+			Timestamp: now.Unix(),
+			Labels:    labels,
+			Type:      plugin.MetricTypeGauge,
+		})
 	}
 
-	// 解析丢包率
-	// 示例: 4 packets transmitted, 4 received, 0% packet loss
-	lossRegex := regexp.MustCompile(`(\d+)% packet loss`)
-	if matches := lossRegex.FindStringSubmatch(output); len(matches) > 1 {
-		if loss, err := strconv.ParseFloat(matches[1], 64); err == nil {
-			result.PacketLoss = loss
-		}
+	reachable := 0.0
+	if result.Received > 0 {
+		reachable = 1
+	}
+	add("ping_reachable", reachable)
+	add("ping_packet_loss", result.PacketLoss)
+	if result.Received == 0 {
+		return metrics
 	}
 
-	return result, nil
+	add("ping_rtt_ms", result.AvgRTT)
+	add("ping_rtt_min_ms", result.MinRTT)
+	add("ping_rtt_max_ms", result.MaxRTT)
+	add("ping_rtt_stddev_ms", result.StdDevRTT)
+	add("ping_jitter_ms", result.Jitter)
+	return metrics
+}
+
+// getString 获取字符串配置
+func getString(config map[string]interface{}, key string, defaultValue string) string {
+	if val, ok := config[key].(string); ok && val != "" {
+		return val
+	}
+	return defaultValue
 }
 
 // getInt 获取整数配置
-func (p *PingPlugin) getInt(config map[string]interface{}, key string, defaultValue int) int {
-	if val, ok := config[key]; ok {
-		switch v := val.(type) {
-		case int:
-			return v
-		case float64:
-			return int(v)
+func getInt(config map[string]interface{}, key string, defaultValue int) int {
+	switch v := config[key].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	case string:
+		if i, err := strconv.Atoi(v); err == nil {
+			return i
 		}
 	}
 	return defaultValue
 }
 
-// 加载 schema 的辅助函数
-func loadSchema(path string) (plugin.PluginSchema, error) {
-	var schema plugin.PluginSchema
-	// 这里应该读取 plugin.yaml 文件
-	// 为了简化，暂时返回空
-	return schema, nil
+// getBool 获取布尔配置
+func getBool(config map[string]interface{}, key string, defaultValue bool) bool {
+	switch v := config[key].(type) {
+	case bool:
+		return v
+	case string:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return defaultValue
 }
 
 // 确保实现了接口
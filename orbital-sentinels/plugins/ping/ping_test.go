@@ -0,0 +1,225 @@
+package ping
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/celestial/orbital-sentinels/internal/plugin"
+)
+
+func TestMarshalEcho(t *testing.T) {
+	b := marshalEcho(false, 0x1234, 7, 0xdeadbeef, 56)
+	if len(b) != icmpHeaderLen+56 || b[0] != icmpv4EchoRequest {
+		t.Fatalf("unexpected packet: len=%d type=%d", len(b), b[0])
+	}
+	// 带校验和的报文重新计算结果为 0
+	if checksum(b) != 0 {
+		t.Errorf("invalid checksum %#x", binary.BigEndian.Uint16(b[2:]))
+	}
+
+	// 模拟应答：类型改为 Echo Reply 并加上 IPv4 头
+	reply := append([]byte(nil), b...)
+	reply[0] = icmpv4EchoReply
+	header := make([]byte, 20)
+	header[0] = 0x45
+	pkt, err := parseEcho(append(header, reply...))
+	if err != nil {
+		t.Fatalf("parseEcho failed: %v", err)
+	}
+	if pkt.typ != icmpv4EchoReply || pkt.id != 0x1234 || pkt.seq != 7 || pkt.token != 0xdeadbeef {
+		t.Errorf("parsed = %+v", pkt)
+	}
+
+	v6 := marshalEcho(true, 1, 1, 1, 0)
+	if len(v6) != icmpHeaderLen+minPayloadSize || v6[0] != icmpv6EchoRequest || v6[2] != 0 || v6[3] != 0 {
+		t.Errorf("unexpected icmpv6 packet % x", v6)
+	}
+
+	if _, err := parseEcho([]byte{0, 0, 0}); err == nil {
+		t.Error("parseEcho should reject short packets")
+	}
+}
+
+func TestNewPingResult(t *testing.T) {
+	ms := time.Millisecond
+	result := newPingResult(5, []time.Duration{10 * ms, -1, 14 * ms, 12 * ms, -1})
+
+	if result.Sent != 5 || result.Received != 3 || result.PacketLoss != 40 {
+		t.Errorf("sent=%d received=%d loss=%v", result.Sent, result.Received, result.PacketLoss)
+	}
+	if result.MinRTT != 10 || result.MaxRTT != 14 || result.AvgRTT != 12 {
+		t.Errorf("min=%v avg=%v max=%v", result.MinRTT, result.AvgRTT, result.MaxRTT)
+	}
+	if math.Abs(result.StdDevRTT-math.Sqrt(8.0/3)) > 1e-9 {
+		t.Errorf("stddev = %v", result.StdDevRTT)
+	}
+	// |14-10| 和 |12-14| 的平均值
+	if result.Jitter != 3 {
+		t.Errorf("jitter = %v, want 3", result.Jitter)
+	}
+
+	lost := newPingResult(3, []time.Duration{-1, -1, -1})
+	if lost.Received != 0 || lost.PacketLoss != 100 || lost.AvgRTT != 0 {
+		t.Errorf("all lost result = %+v", lost)
+	}
+}
+
+// newTestPinger 创建 Ping 引擎，没有 ICMP 权限时跳过测试
+func newTestPinger(t *testing.T, ipv6 bool) *Pinger {
+	pinger, err := newPinger(ipv6, false)
+	if err != nil {
+		t.Skipf("icmp socket not available: %v", err)
+	}
+	t.Cleanup(func() { pinger.Close() })
+	return pinger
+}
+
+func TestPinger_Loopback(t *testing.T) {
+	pinger := newTestPinger(t, false)
+
+	result, err := pinger.Ping(context.Background(), net.ParseIP("127.0.0.1"), pingOptions{
+		count:    3,
+		interval: 10 * time.Millisecond,
+		timeout:  time.Second,
+		size:     100,
+	})
+	if err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+	if result.Sent != 3 || result.Received != 3 || result.PacketLoss != 0 {
+		t.Errorf("result = %+v", result)
+	}
+	if result.MinRTT <= 0 || result.MinRTT > result.MaxRTT {
+		t.Errorf("min=%v max=%v", result.MinRTT, result.MaxRTT)
+	}
+}
+
+func TestPinger_ManyTargetsOneSocket(t *testing.T) {
+	pinger := newTestPinger(t, false)
+
+	// 127.0.0.0/8 都由本机应答
+	var wg sync.WaitGroup
+	results := make([]*PingResult, 200)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ip := net.IPv4(127, 0, byte(i/250), byte(i%250+1))
+			results[i], _ = pinger.Ping(context.Background(), ip, pingOptions{
+				count:    2,
+				interval: 10 * time.Millisecond,
+				timeout:  2 * time.Second,
+				size:     56,
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	for i, r := range results {
+		if r == nil || r.Received != 2 {
+			t.Fatalf("target %d: result = %+v", i, r)
+		}
+	}
+	pinger.mu.Lock()
+	defer pinger.mu.Unlock()
+	if len(pinger.pending) != 0 {
+		t.Errorf("%d probes left pending", len(pinger.pending))
+	}
+}
+
+func TestPinger_IPv6Loopback(t *testing.T) {
+	pinger := newTestPinger(t, true)
+
+	result, err := pinger.Ping(context.Background(), net.IPv6loopback, pingOptions{count: 2, interval: 10 * time.Millisecond, timeout: time.Second, size: 56})
+	if err != nil {
+		t.Skipf("ipv6 not available: %v", err)
+	}
+	if result.Received != 2 {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestPinger_DontFragment(t *testing.T) {
+	pinger, err := newPinger(false, true)
+	if err != nil {
+		t.Skipf("df socket not available: %v", err)
+	}
+	defer pinger.Close()
+
+	result, err := pinger.Ping(context.Background(), net.ParseIP("127.0.0.1"), pingOptions{count: 1, timeout: time.Second, size: 1472})
+	if err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+	if result.Received != 1 {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestCollect(t *testing.T) {
+	p := NewPlugin().(*PingPlugin)
+	if err := p.Init(nil); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer p.Close()
+	if _, err := p.pinger(false, false); err != nil {
+		t.Skipf("icmp socket not available: %v", err)
+	}
+
+	metrics, err := p.Collect(context.Background(), &plugin.CollectionTask{
+		DeviceID:     "dev-1",
+		DeviceConfig: map[string]interface{}{"host": "127.0.0.1", "count": 2, "packet_interval": 10},
+	})
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	names := make(map[string]float64)
+	for _, m := range metrics {
+		names[m.Name] = m.Value
+		if m.Labels["device_id"] != "dev-1" || m.Labels["host"] != "127.0.0.1" {
+			t.Errorf("labels = %v", m.Labels)
+		}
+	}
+	for _, name := range []string{"ping_reachable", "ping_packet_loss", "ping_rtt_ms", "ping_rtt_min_ms", "ping_rtt_max_ms", "ping_rtt_stddev_ms", "ping_jitter_ms"} {
+		if _, ok := names[name]; !ok {
+			t.Errorf("metric %s missing", name)
+		}
+	}
+	if names["ping_reachable"] != 1 {
+		t.Errorf("ping_reachable = %v", names["ping_reachable"])
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	p := NewPlugin().(*PingPlugin)
+	if err := p.Init(nil); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	valid := []map[string]interface{}{
+		{"host": "10.0.0.1"},
+		{"host": "2001:db8::1", "size": 1472, "df": true, "ip_version": "6"},
+	}
+	for _, cfg := range valid {
+		if err := p.ValidateConfig(cfg); err != nil {
+			t.Errorf("ValidateConfig(%v) = %v", cfg, err)
+		}
+	}
+
+	invalid := []map[string]interface{}{
+		{},
+		{"host": "10.0.0.1", "size": 4},
+		{"host": "10.0.0.1", "ip_version": "5"},
+		{"host": "10.0.0.1", "count": 0.0, "packet_interval": 1.0},
+	}
+	for _, cfg := range invalid {
+		if err := p.ValidateConfig(cfg); err == nil {
+			t.Errorf("ValidateConfig(%v) should fail", cfg)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package ping
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/celestial/orbital-sentinels/internal/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Pinger 共享一个 ICMP 套接字的 Ping 引擎
+// 所有设备的探测从同一个套接字发出，由接收协程按负载中的探测标识分发应答，
+// 不需要为每个设备创建进程或套接字。
+type Pinger struct {
+	conn       net.PacketConn
+	ipv6       bool
+	privileged bool   // 原始套接字会收到本机所有 ICMP 报文，需要按 Echo ID 过滤
+	id         uint16 // 数据报套接字的 Echo ID 由内核改写
+
+	mu      sync.Mutex
+	pending map[uint64]*pendingProbe
+	token   uint64
+	seq     uint16
+	done    chan struct{}
+}
+
+// pendingProbe 等待应答的探测
+type pendingProbe struct {
+	index   int
+	sent    time.Time
+	replies chan<- echoReply
+}
+
+// echoReply 收到的应答
+type echoReply struct {
+	index int
+	rtt   time.Duration
+}
+
+// pingOptions 单个设备的探测参数
+type pingOptions struct {
+	count    int
+	interval time.Duration // 两次发送的间隔
+	timeout  time.Duration // 最后一次发送后等待应答的时间
+	size     int           // ICMP 负载大小（字节）
+}
+
+// PingResult Ping 结果，时延单位为毫秒
+type PingResult struct {
+	Sent       int
+	Received   int
+	MinRTT     float64
+	AvgRTT     float64
+	MaxRTT     float64
+	StdDevRTT  float64
+	Jitter     float64 // 相邻应答时延差的平均值
+	PacketLoss float64 // 丢包率（%）
+}
+
+// readBufferSize 套接字接收缓冲区大小，大量设备同时应答时避免丢包
+// 实际大小受 net.core.rmem_max 限制。
+const readBufferSize = 4 << 20
+
+// newPinger 创建 Ping 引擎并启动接收协程
+func newPinger(ipv6, df bool) (*Pinger, error) {
+	conn, privileged, err := listen(ipv6, df)
+	if err != nil {
+		return nil, err
+	}
+	if c, ok := conn.(interface{ SetReadBuffer(int) error }); ok {
+		if err := c.SetReadBuffer(readBufferSize); err != nil {
+			logger.Debug("Failed to set icmp read buffer", zap.Error(err))
+		}
+	}
+
+	p := &Pinger{
+		conn:       conn,
+		ipv6:       ipv6,
+		privileged: privileged,
+		id:         uint16(os.Getpid()),
+		pending:    make(map[uint64]*pendingProbe),
+		token:      uint64(time.Now().UnixNano()),
+		done:       make(chan struct{}),
+	}
+	go p.readLoop()
+	return p, nil
+}
+
+// Close 关闭套接字，等待接收协程退出
+func (p *Pinger) Close() error {
+	err := p.conn.Close()
+	<-p.done
+	return err
+}
+
+// readLoop 接收应答并交给对应的探测
+func (p *Pinger) readLoop() {
+	defer close(p.done)
+
+	replyType := byte(icmpv4EchoReply)
+	if p.ipv6 {
+		replyType = icmpv6EchoReply
+	}
+
+	buf := make([]byte, 65536)
+	for {
+		n, _, err := p.conn.ReadFrom(buf)
+		received := time.Now()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			logger.Debug("ICMP read failed", zap.Error(err))
+			continue
+		}
+
+		pkt, err := parseEcho(buf[:n])
+		if err != nil || pkt.typ != replyType || (p.privileged && pkt.id != p.id) {
+			continue
+		}
+
+		p.mu.Lock()
+		probe, ok := p.pending[pkt.token]
+		if ok {
+			delete(p.pending, pkt.token)
+		}
+		p.mu.Unlock()
+
+		// 重复的应答和已超时的探测被忽略
+		if ok {
+			probe.replies <- echoReply{index: probe.index, rtt: received.Sub(probe.sent)}
+		}
+	}
+}
+
+// register 登记一次探测，返回探测标识和序号
+func (p *Pinger) register(index int, replies chan<- echoReply) (uint64, uint16) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.token++
+	p.seq++
+	p.pending[p.token] = &pendingProbe{index: index, sent: time.Now(), replies: replies}
+	return p.token, p.seq
+}
+
+// forget 取消未收到应答的探测
+func (p *Pinger) forget(tokens []uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, token := range tokens {
+		delete(p.pending, token)
+	}
+}
+
+// Ping 向目标发送 count 个 Echo Request 并等待应答
+// 所有报文都发送失败时返回错误（如网络不可达、开启 DF 时报文超过 MTU）。
+func (p *Pinger) Ping(ctx context.Context, ip net.IP, opts pingOptions) (*PingResult, error) {
+	replies := make(chan echoReply, opts.count)
+	tokens := make([]uint64, 0, opts.count)
+	defer func() { p.forget(tokens) }()
+
+	dst := destination(ip, p.privileged)
+	sent, failed := 0, 0
+	var sendErr error
+
+send:
+	for i := 0; i < opts.count; i++ {
+		if i > 0 {
+			select {
+			case <-time.After(opts.interval):
+			case <-ctx.Done():
+				break send
+			}
+		}
+
+		token, seq := p.register(i, replies)
+		tokens = append(tokens, token)
+		if _, err := p.conn.WriteTo(marshalEcho(p.ipv6, p.id, seq, token, opts.size), dst); err != nil {
+			sendErr = err
+			failed++
+		}
+		sent++
+	}
+	if sent > 0 && failed == sent {
+		return nil, sendErr
+	}
+
+	rtts := make([]time.Duration, sent)
+	for i := range rtts {
+		rtts[i] = -1
+	}
+	received := 0
+	timer := time.NewTimer(opts.timeout)
+	defer timer.Stop()
+
+wait:
+	for received < sent {
+		select {
+		case r := <-replies:
+			rtts[r.index] = r.rtt
+			received++
+		case <-timer.C:
+			break wait
+		case <-ctx.Done():
+			break wait
+		}
+	}
+
+	return newPingResult(sent, rtts), nil
+}
+
+// newPingResult 按序号顺序的时延计算统计值，未收到应答的位置为负数
+func newPingResult(sent int, rtts []time.Duration) *PingResult {
+	result := &PingResult{Sent: sent, PacketLoss: 100}
+
+	var values []float64
+	for _, rtt := range rtts {
+		if rtt >= 0 {
+			values = append(values, float64(rtt)/float64(time.Millisecond))
+		}
+	}
+	result.Received = len(values)
+	if sent == 0 || len(values) == 0 {
+		return result
+	}
+	result.PacketLoss = float64(sent-len(values)) / float64(sent) * 100
+
+	result.MinRTT, result.MaxRTT = values[0], values[0]
+	var sum, sumSquares, jitter float64
+	for i, v := range values {
+		sum += v
+		sumSquares += v * v
+		result.MinRTT = math.Min(result.MinRTT, v)
+		result.MaxRTT = math.Max(result.MaxRTT, v)
+		if i > 0 {
+			jitter += math.Abs(v - values[i-1])
+		}
+	}
+
+	n := float64(len(values))
+	result.AvgRTT = sum / n
+	result.StdDevRTT = math.Sqrt(math.Max(sumSquares/n-result.AvgRTT*result.AvgRTT, 0))
+	if len(values) > 1 {
+		result.Jitter = jitter / (n - 1)
+	}
+	return result
+}
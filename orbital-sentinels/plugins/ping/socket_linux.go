@@ -0,0 +1,102 @@
+package ping
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// listen 创建 ICMP 套接字，优先使用无需特权的 ICMP 数据报套接字
+// （需要 net.ipv4.ping_group_range 包含运行用户的组），失败时使用原始套接字。
+func listen(ipv6, df bool) (conn net.PacketConn, privileged bool, err error) {
+	conn, dgramErr := listenDatagram(ipv6, df)
+	if dgramErr == nil {
+		return conn, false, nil
+	}
+
+	network, address := "ip4:icmp", "0.0.0.0"
+	if ipv6 {
+		network, address = "ip6:ipv6-icmp", "::"
+	}
+	lc := net.ListenConfig{
+		Control: func(_, _ string, c syscall.RawConn) error {
+			var sockErr error
+			if err := c.Control(func(fd uintptr) {
+				sockErr = setDontFragment(int(fd), ipv6, df)
+			}); err != nil {
+				return err
+			}
+			return sockErr
+		},
+	}
+	conn, err = lc.ListenPacket(context.Background(), network, address)
+	if err != nil {
+		return nil, false, fmt.Errorf("unprivileged icmp socket: %v; raw socket: %w", dgramErr, err)
+	}
+	return conn, true, nil
+}
+
+// listenDatagram 创建 ICMP 数据报套接字，内核负责按 Echo ID 分发应答
+func listenDatagram(ipv6, df bool) (net.PacketConn, error) {
+	family, proto := unix.AF_INET, unix.IPPROTO_ICMP
+	var sa unix.Sockaddr = &unix.SockaddrInet4{}
+	if ipv6 {
+		family, proto = unix.AF_INET6, unix.IPPROTO_ICMPV6
+		sa = &unix.SockaddrInet6{}
+	}
+
+	fd, err := unix.Socket(family, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, proto)
+	if err != nil {
+		return nil, err
+	}
+	if err := setDontFragment(fd, ipv6, df); err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+	if err := unix.Bind(fd, sa); err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+
+	f := os.NewFile(uintptr(fd), "icmp")
+	defer f.Close()
+	return net.FilePacketConn(f)
+}
+
+// setDontFragment 设置 DF 位：开启时超过路径 MTU 的报文直接丢弃，关闭时允许分片
+func setDontFragment(fd int, ipv6, df bool) error {
+	if ipv6 {
+		mode, dontFrag := unix.IPV6_PMTUDISC_DONT, 0
+		if df {
+			mode, dontFrag = unix.IPV6_PMTUDISC_DO, 1
+		}
+		if err := unix.SetsockoptInt(fd, unix.IPPROTO_IPV6, unix.IPV6_MTU_DISCOVER, mode); err != nil {
+			return fmt.Errorf("failed to set IPV6_MTU_DISCOVER: %w", err)
+		}
+		if err := unix.SetsockoptInt(fd, unix.IPPROTO_IPV6, unix.IPV6_DONTFRAG, dontFrag); err != nil {
+			return fmt.Errorf("failed to set IPV6_DONTFRAG: %w", err)
+		}
+		return nil
+	}
+
+	mode := unix.IP_PMTUDISC_DONT
+	if df {
+		mode = unix.IP_PMTUDISC_DO
+	}
+	if err := unix.SetsockoptInt(fd, unix.IPPROTO_IP, unix.IP_MTU_DISCOVER, mode); err != nil {
+		return fmt.Errorf("failed to set IP_MTU_DISCOVER: %w", err)
+	}
+	return nil
+}
+
+// destination 返回发送目标地址，数据报套接字使用 UDPAddr
+func destination(ip net.IP, privileged bool) net.Addr {
+	if privileged {
+		return &net.IPAddr{IP: ip}
+	}
+	return &net.UDPAddr{IP: ip}
+}
@@ -0,0 +1,29 @@
+//go:build !linux
+
+package ping
+
+import (
+	"errors"
+	"net"
+)
+
+// listen 创建 ICMP 原始套接字（需要特权），非 Linux 平台不支持设置 DF 位
+func listen(ipv6, df bool) (conn net.PacketConn, privileged bool, err error) {
+	if df {
+		return nil, false, errors.New("df is only supported on linux")
+	}
+	network, address := "ip4:icmp", "0.0.0.0"
+	if ipv6 {
+		network, address = "ip6:ipv6-icmp", "::"
+	}
+	conn, err = net.ListenPacket(network, address)
+	if err != nil {
+		return nil, false, err
+	}
+	return conn, true, nil
+}
+
+// destination 返回发送目标地址
+func destination(ip net.IP, privileged bool) net.Addr {
+	return &net.IPAddr{IP: ip}
+}
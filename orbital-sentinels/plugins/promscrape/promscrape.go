@@ -0,0 +1,241 @@
+package promscrape
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/celestial/orbital-sentinels/internal/pkg/metricfmt"
+	"github.com/celestial/orbital-sentinels/internal/plugin"
+	"github.com/celestial/orbital-sentinels/sdk"
+	"github.com/prometheus/prometheus/model/relabel"
+	"gopkg.in/yaml.v3"
+)
+
+// schemaYAML 插件 Schema，设备字段在 plugin.yaml 中声明
+//
+//go:embed plugin.yaml
+var schemaYAML []byte
+
+// ScrapePlugin Prometheus Exporter 采集插件
+type ScrapePlugin struct {
+	sdk.BasePlugin
+	schema plugin.PluginSchema
+}
+
+// scrapeConfig 抓取配置
+type scrapeConfig struct {
+	url             string
+	timeout         time.Duration
+	username        string
+	password        string
+	bearerToken     string
+	headers         map[string]string
+	honorTimestamps bool
+	relabelConfigs  []*relabel.Config
+	tlsSkipVerify   bool
+	tlsServerName   string
+	tlsCAFile       string
+	tlsCertFile     string
+	tlsKeyFile      string
+}
+
+// NewPlugin 创建插件实例
+func NewPlugin() plugin.Plugin {
+	return &ScrapePlugin{}
+}
+
+// Meta 返回插件元信息
+func (p *ScrapePlugin) Meta() plugin.PluginMeta {
+	return p.schema.Meta
+}
+
+// Schema 返回配置 Schema
+func (p *ScrapePlugin) Schema() plugin.PluginSchema {
+	return p.schema
+}
+
+// Init 初始化插件
+func (p *ScrapePlugin) Init(config map[string]interface{}) error {
+	if err := yaml.Unmarshal(schemaYAML, &p.schema); err != nil {
+		return fmt.Errorf("failed to parse plugin schema: %w", err)
+	}
+	return nil
+}
+
+// ValidateConfig 验证设备配置：先按 Schema 校验字段，再检查认证、TLS 和重标记规则
+func (p *ScrapePlugin) ValidateConfig(deviceConfig map[string]interface{}) error {
+	if err := p.BasePlugin.ValidateConfig(deviceConfig, p.schema); err != nil {
+		return err
+	}
+	_, err := parseConfig(deviceConfig)
+	return err
+}
+
+// TestConnection 抓取一次并检查响应能否解析
+func (p *ScrapePlugin) TestConnection(deviceConfig map[string]interface{}) error {
+	cfg, err := parseConfig(deviceConfig)
+	if err != nil {
+		return err
+	}
+
+	result, err := scrape(context.Background(), cfg)
+	if err != nil {
+		return err
+	}
+	_, err = metricfmt.ParsePrometheus(result.body, result.contentType)
+	return err
+}
+
+// Collect 抓取并解析指标，请求失败或响应无法解析时返回错误
+func (p *ScrapePlugin) Collect(ctx context.Context, task *plugin.CollectionTask) ([]*plugin.Metric, error) {
+	cfg, err := parseConfig(task.DeviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := scrape(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	samples, err := metricfmt.ParsePrometheus(result.body, result.contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse metrics: %w", err)
+	}
+
+	return buildMetrics(task.DeviceID, cfg, samples, result, time.Now()), nil
+}
+
+// Close 关闭插件
+func (p *ScrapePlugin) Close() error {
+	return nil
+}
+
+// buildMetrics 对抓取到的样本执行重标记、补充 device_id 标签，并追加抓取统计指标
+// Exporter 自带的 device_id 标签与设备不一致时改名为 exported_device_id。
+func buildMetrics(deviceID string, cfg *scrapeConfig, samples []*plugin.Metric, result *scrapeResult, now time.Time) []*plugin.Metric {
+	metrics := make([]*plugin.Metric, 0, len(samples)+4)
+	for _, m := range samples {
+		if !applyRelabel(m, cfg.relabelConfigs) {
+			continue
+		}
+		if existing, ok := m.Labels["device_id"]; ok && existing != deviceID {
+			m.Labels["exported_device_id"] = existing
+		}
+		m.Labels["device_id"] = deviceID
+		if m.Timestamp == 0 || !cfg.honorTimestamps {
+			m.Timestamp = now.Unix()
+		}
+		metrics = append(metrics, m)
+	}
+	kept := len(metrics)
+
+	labels := map[string]string{
+		"device_id": deviceID,
+		"url":       cfg.url,
+	}
+	add := func(name string, value float64) {
+		metrics = append(metrics, &plugin.Metric{
+			Name:      name,
+			Value:     value,
+			Timestamp: now.Unix(),
+			Labels:    labels,
+			Type:      plugin.MetricTypeGauge,
+		})
+	}
+	add("scrape_duration_seconds", result.duration.Seconds())
+	add("scrape_samples_scraped", float64(len(samples)))
+	add("scrape_samples_post_metric_relabeling", float64(kept))
+	add("scrape_response_size_bytes", float64(len(result.body)))
+	return metrics
+}
+
+// parseConfig 解析设备配置
+func parseConfig(config map[string]interface{}) (*scrapeConfig, error) {
+	cfg := &scrapeConfig{
+		url:             getString(config, "url", ""),
+		timeout:         time.Duration(getInt(config, "timeout", 10)) * time.Second,
+		username:        getString(config, "username", ""),
+		password:        getString(config, "password", ""),
+		bearerToken:     getString(config, "bearer_token", ""),
+		headers:         make(map[string]string),
+		honorTimestamps: getBool(config, "honor_timestamps", true),
+		tlsSkipVerify:   getBool(config, "tls_skip_verify", false),
+		tlsServerName:   getString(config, "tls_server_name", ""),
+		tlsCAFile:       getString(config, "tls_ca_file", ""),
+		tlsCertFile:     getString(config, "tls_cert_file", ""),
+		tlsKeyFile:      getString(config, "tls_key_file", ""),
+	}
+
+	u, err := url.Parse(cfg.url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid url %q", cfg.url)
+	}
+	if cfg.timeout <= 0 {
+		cfg.timeout = 10 * time.Second
+	}
+	if cfg.bearerToken != "" && cfg.username != "" {
+		return nil, fmt.Errorf("bearer_token and username are mutually exclusive")
+	}
+	if (cfg.tlsCertFile == "") != (cfg.tlsKeyFile == "") {
+		return nil, fmt.Errorf("tls_cert_file and tls_key_file must be configured together")
+	}
+
+	switch headers := config["headers"].(type) {
+	case map[string]interface{}:
+		for k, v := range headers {
+			cfg.headers[k] = fmt.Sprint(v)
+		}
+	case map[string]string:
+		for k, v := range headers {
+			cfg.headers[k] = v
+		}
+	}
+
+	if cfg.relabelConfigs, err = parseRelabelConfigs(config["metric_relabel_configs"]); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// getString 获取字符串配置
+func getString(config map[string]interface{}, key string, defaultValue string) string {
+	if val, ok := config[key].(string); ok && val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+// getInt 获取整数配置
+func getInt(config map[string]interface{}, key string, defaultValue int) int {
+	switch v := config[key].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	case string:
+		if i, err := strconv.Atoi(v); err == nil {
+			return i
+		}
+	}
+	return defaultValue
+}
+
+// getBool 获取布尔配置，支持 "true"/"false" 字符串
+func getBool(config map[string]interface{}, key string, defaultValue bool) bool {
+	switch v := config[key].(type) {
+	case bool:
+		return v
+	case string:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
+// 确保实现了接口
+var _ plugin.Plugin = (*ScrapePlugin)(nil)
@@ -0,0 +1,342 @@
+package promscrape
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/celestial/orbital-sentinels/internal/plugin"
+)
+
+const textExposition = `# HELP node_cpu_seconds_total Seconds the CPUs spent in each mode.
+# TYPE node_cpu_seconds_total counter
+node_cpu_seconds_total{cpu="0",mode="idle"} 1234.5
+node_cpu_seconds_total{cpu="0",mode="user"} 56.7
+# TYPE node_load1 gauge
+node_load1 0.42
+# TYPE http_request_duration_seconds histogram
+http_request_duration_seconds_bucket{le="0.1"} 10
+http_request_duration_seconds_bucket{le="+Inf"} 12
+http_request_duration_seconds_sum 1.5
+http_request_duration_seconds_count 12
+# TYPE rpc_latency_seconds summary
+rpc_latency_seconds{quantile="0.5"} 0.02
+rpc_latency_seconds{quantile="0.99"} 0.3
+rpc_latency_seconds_sum 8.1
+rpc_latency_seconds_count 200
+# TYPE go_goroutines gauge
+go_goroutines{device_id="exporter-1"} 17
+`
+
+const openMetricsExposition = `# TYPE jobs counter
+jobs_total{queue="mail"} 4 1700000000.5
+jobs_created{queue="mail"} 1600000000
+# EOF
+`
+
+func newTestPlugin(t *testing.T) *ScrapePlugin {
+	p := NewPlugin().(*ScrapePlugin)
+	if err := p.Init(nil); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	return p
+}
+
+func newTestServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.Write([]byte(textExposition))
+	})
+	mux.HandleFunc("/openmetrics", func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Accept"), "application/openmetrics-text") {
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+		w.Write([]byte(openMetricsExposition))
+	})
+	mux.HandleFunc("/basic", func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "prom" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("up 1\n"))
+	})
+	mux.HandleFunc("/bearer", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("up 1\n"))
+	})
+	mux.HandleFunc("/broken", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("metric{ 1\n"))
+	})
+	return httptest.NewServer(mux)
+}
+
+func newTask(config map[string]interface{}) *plugin.CollectionTask {
+	return &plugin.CollectionTask{
+		TaskID:       "task-1",
+		DeviceID:     "dev-1",
+		PluginName:   "prometheus_scrape",
+		DeviceConfig: config,
+	}
+}
+
+// findMetric 返回名称和标签匹配的指标
+func findMetric(metrics []*plugin.Metric, name string, labels map[string]string) *plugin.Metric {
+	for _, m := range metrics {
+		if m.Name != name {
+			continue
+		}
+		match := true
+		for k, v := range labels {
+			if m.Labels[k] != v {
+				match = false
+				break
+			}
+		}
+		if match {
+			return m
+		}
+	}
+	return nil
+}
+
+func TestSchemaFromYAML(t *testing.T) {
+	p := newTestPlugin(t)
+	if p.Meta().Name != "prometheus_scrape" {
+		t.Errorf("unexpected name %q", p.Meta().Name)
+	}
+	if len(p.Schema().DeviceFields) == 0 {
+		t.Error("expected device fields from plugin.yaml")
+	}
+}
+
+func TestCollect_Text(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	p := newTestPlugin(t)
+
+	metrics, err := p.Collect(context.Background(), newTask(map[string]interface{}{
+		"url": server.URL + "/metrics",
+	}))
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		labels map[string]string
+		value  float64
+		typ    plugin.MetricType
+	}{
+		{"node_cpu_seconds_total", map[string]string{"cpu": "0", "mode": "idle"}, 1234.5, plugin.MetricTypeCounter},
+		{"node_load1", nil, 0.42, plugin.MetricTypeGauge},
+		{"http_request_duration_seconds", nil, 12, plugin.MetricTypeHistogram},
+		{"rpc_latency_seconds", nil, 200, plugin.MetricTypeSummary},
+		{"scrape_samples_scraped", nil, 6, plugin.MetricTypeGauge},
+	}
+	for _, tt := range tests {
+		m := findMetric(metrics, tt.name, tt.labels)
+		if m == nil {
+			t.Errorf("metric %s%v not found", tt.name, tt.labels)
+			continue
+		}
+		if m.Value != tt.value || m.Type != tt.typ {
+			t.Errorf("%s: got value=%v type=%s, want value=%v type=%s", tt.name, m.Value, m.Type, tt.value, tt.typ)
+		}
+		if m.Labels["device_id"] != "dev-1" || m.Timestamp == 0 {
+			t.Errorf("%s: missing device_id or timestamp: %+v", tt.name, m)
+		}
+	}
+
+	if m := findMetric(metrics, "http_request_duration_seconds", nil); m == nil || m.Histogram == nil ||
+		len(m.Histogram.Buckets) != 1 || m.Histogram.Buckets[0] != (plugin.Bucket{UpperBound: 0.1, Count: 10}) || m.Histogram.Sum != 1.5 {
+		t.Errorf("unexpected histogram: %+v", m)
+	}
+	if m := findMetric(metrics, "rpc_latency_seconds", nil); m == nil || m.Summary == nil ||
+		len(m.Summary.Quantiles) != 2 || m.Summary.Quantiles[1] != (plugin.Quantile{Quantile: 0.99, Value: 0.3}) || m.Summary.Sum != 8.1 {
+		t.Errorf("unexpected summary: %+v", m)
+	}
+
+	if m := findMetric(metrics, "go_goroutines", nil); m == nil || m.Labels["exported_device_id"] != "exporter-1" {
+		t.Errorf("conflicting device_id should be kept as exported_device_id: %+v", m)
+	}
+}
+
+func TestCollect_OpenMetrics(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	p := newTestPlugin(t)
+
+	metrics, err := p.Collect(context.Background(), newTask(map[string]interface{}{
+		"url": server.URL + "/openmetrics",
+	}))
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	m := findMetric(metrics, "jobs_total", map[string]string{"queue": "mail"})
+	if m == nil || m.Value != 4 || m.Type != plugin.MetricTypeCounter || m.Timestamp != 1700000000 {
+		t.Errorf("unexpected metric: %+v", m)
+	}
+	if findMetric(metrics, "jobs_created", nil) != nil {
+		t.Error("_created series should be skipped")
+	}
+
+	metrics, err = p.Collect(context.Background(), newTask(map[string]interface{}{
+		"url":              server.URL + "/openmetrics",
+		"honor_timestamps": false,
+	}))
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if m := findMetric(metrics, "jobs_total", nil); m == nil || m.Timestamp == 1700000000 {
+		t.Errorf("exposed timestamp should be ignored: %+v", m)
+	}
+}
+
+func TestCollect_Relabel(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	p := newTestPlugin(t)
+
+	metrics, err := p.Collect(context.Background(), newTask(map[string]interface{}{
+		"url": server.URL + "/metrics",
+		"metric_relabel_configs": []interface{}{
+			map[string]interface{}{"source_labels": []interface{}{"__name__"}, "regex": "go_.*", "action": "drop"},
+			map[string]interface{}{"source_labels": []interface{}{"__name__"}, "regex": "node_.*|scrape_.*", "action": "keep"},
+			map[string]interface{}{"source_labels": []interface{}{"__name__"}, "regex": "node_load1", "target_label": "__name__", "replacement": "load_1m"},
+			map[string]interface{}{"regex": "cpu", "action": "labeldrop"},
+		},
+	}))
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	if findMetric(metrics, "go_goroutines", nil) != nil || findMetric(metrics, "rpc_latency_seconds", nil) != nil {
+		t.Error("dropped metrics should not be reported")
+	}
+	if m := findMetric(metrics, "load_1m", nil); m == nil || m.Value != 0.42 || m.Labels["device_id"] != "dev-1" {
+		t.Errorf("renamed metric not found: %+v", m)
+	}
+	if m := findMetric(metrics, "node_cpu_seconds_total", map[string]string{"mode": "idle"}); m == nil {
+		t.Error("kept metric not found")
+	} else if _, ok := m.Labels["cpu"]; ok {
+		t.Error("cpu label should be dropped")
+	}
+	if m := findMetric(metrics, "scrape_samples_post_metric_relabeling", nil); m == nil || m.Value != 3 {
+		t.Errorf("unexpected post relabel count: %+v", m)
+	}
+}
+
+func TestCollect_Auth(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	p := newTestPlugin(t)
+
+	if _, err := p.Collect(context.Background(), newTask(map[string]interface{}{
+		"url": server.URL + "/basic",
+	})); err == nil {
+		t.Error("expected error without credentials")
+	}
+	if _, err := p.Collect(context.Background(), newTask(map[string]interface{}{
+		"url":      server.URL + "/basic",
+		"username": "prom",
+		"password": "secret",
+	})); err != nil {
+		t.Errorf("basic auth failed: %v", err)
+	}
+	if _, err := p.Collect(context.Background(), newTask(map[string]interface{}{
+		"url":          server.URL + "/bearer",
+		"bearer_token": "token-1",
+	})); err != nil {
+		t.Errorf("bearer auth failed: %v", err)
+	}
+}
+
+func TestCollect_TLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("up 1\n"))
+	}))
+	defer server.Close()
+	p := newTestPlugin(t)
+
+	if _, err := p.Collect(context.Background(), newTask(map[string]interface{}{
+		"url": server.URL,
+	})); err == nil {
+		t.Error("expected certificate verification error")
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, ca, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Collect(context.Background(), newTask(map[string]interface{}{
+		"url":             server.URL,
+		"tls_ca_file":     caFile,
+		"tls_server_name": "example.com",
+	})); err != nil {
+		t.Errorf("Collect with CA file failed: %v", err)
+	}
+	if _, err := p.Collect(context.Background(), newTask(map[string]interface{}{
+		"url":             server.URL,
+		"tls_skip_verify": true,
+	})); err != nil {
+		t.Errorf("Collect with tls_skip_verify failed: %v", err)
+	}
+}
+
+func TestCollect_Errors(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	p := newTestPlugin(t)
+
+	for _, path := range []string{"/missing", "/broken"} {
+		if _, err := p.Collect(context.Background(), newTask(map[string]interface{}{
+			"url": server.URL + path,
+		})); err == nil {
+			t.Errorf("expected error for %s", path)
+		}
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	p := newTestPlugin(t)
+
+	valid := map[string]interface{}{
+		"url": "http://10.0.0.1:9100/metrics",
+		"metric_relabel_configs": []interface{}{
+			map[string]interface{}{"source_labels": []interface{}{"__name__"}, "regex": "go_.*", "action": "drop"},
+		},
+	}
+	if err := p.ValidateConfig(valid); err != nil {
+		t.Errorf("expected valid config, got %v", err)
+	}
+
+	invalid := []map[string]interface{}{
+		{"url": "ftp://10.0.0.1/metrics"},
+		{"url": "http://10.0.0.1/metrics", "username": "a", "bearer_token": "b"},
+		{"url": "http://10.0.0.1/metrics", "tls_cert_file": "/tmp/cert.pem"},
+		{"url": "http://10.0.0.1/metrics", "metric_relabel_configs": []interface{}{
+			map[string]interface{}{"action": "explode"},
+		}},
+		{"url": "http://10.0.0.1/metrics", "metric_relabel_configs": []interface{}{
+			map[string]interface{}{"regex": "(", "action": "drop", "source_labels": []interface{}{"__name__"}},
+		}},
+	}
+	for _, config := range invalid {
+		if err := p.ValidateConfig(config); err == nil {
+			t.Errorf("expected error for %v", config)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package promscrape
+
+import (
+	"fmt"
+
+	"github.com/celestial/orbital-sentinels/internal/plugin"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+	"gopkg.in/yaml.v3"
+)
+
+// parseRelabelConfigs 解析设备配置中的 metric_relabel_configs
+// 规则先转换为 YAML 再按 Prometheus 的格式解析，未配置的字段使用 Prometheus 的默认值。
+func parseRelabelConfigs(value interface{}) ([]*relabel.Config, error) {
+	rules, ok := value.([]interface{})
+	if !ok || len(rules) == 0 {
+		return nil, nil
+	}
+
+	data, err := yaml.Marshal(rules)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metric_relabel_configs: %w", err)
+	}
+	var configs []*relabel.Config
+	if err := yaml.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("invalid metric_relabel_configs: %w", err)
+	}
+	for i, cfg := range configs {
+		if cfg == nil {
+			return nil, fmt.Errorf("metric_relabel_configs[%d]: empty rule", i)
+		}
+		if err := cfg.Validate(model.UTF8Validation); err != nil {
+			return nil, fmt.Errorf("metric_relabel_configs[%d]: %w", i, err)
+		}
+	}
+	return configs, nil
+}
+
+// applyRelabel 对指标名（__name__）和标签执行重标记，返回 false 表示丢弃该指标
+func applyRelabel(m *plugin.Metric, configs []*relabel.Config) bool {
+	if len(configs) == 0 {
+		return true
+	}
+
+	builder := labels.NewScratchBuilder(len(m.Labels) + 1)
+	builder.Add(labels.MetricName, m.Name)
+	for k, v := range m.Labels {
+		builder.Add(k, v)
+	}
+	builder.Sort()
+
+	lset, keep := relabel.Process(builder.Labels(), configs...)
+	if !keep {
+		return false
+	}
+
+	name := lset.Get(labels.MetricName)
+	if name == "" {
+		return false
+	}
+	m.Name = name
+	m.Labels = make(map[string]string, lset.Len())
+	lset.Range(func(l labels.Label) {
+		if l.Name != labels.MetricName {
+			m.Labels[l.Name] = l.Value
+		}
+	})
+	return true
+}
@@ -0,0 +1,121 @@
+package promscrape
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	// acceptHeader 优先请求 OpenMetrics，与 Prometheus 的协商顺序一致
+	acceptHeader = "application/openmetrics-text;version=1.0.0,application/openmetrics-text;version=0.0.1;q=0.75,text/plain;version=0.0.4;q=0.5,*/*;q=0.1"
+	// userAgent 请求使用的 User-Agent
+	userAgent = "Celestial-Sentinel/1.0"
+	// maxBodySize 响应体最大长度，超出时抓取失败
+	maxBodySize = 32 << 20
+)
+
+// scrapeResult 抓取结果
+type scrapeResult struct {
+	body        []byte
+	contentType string
+	duration    time.Duration
+}
+
+// scrape 抓取一次 /metrics，状态码不是 200 时返回错误
+func scrape(ctx context.Context, cfg *scrapeConfig) (*scrapeResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, cfg.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", acceptHeader)
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("X-Prometheus-Scrape-Timeout-Seconds", strconv.FormatFloat(cfg.timeout.Seconds(), 'f', -1, 64))
+	for k, v := range cfg.headers {
+		req.Header.Set(k, v)
+	}
+	switch {
+	case cfg.bearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+cfg.bearerToken)
+	case cfg.username != "":
+		req.SetBasicAuth(cfg.username, cfg.password)
+	}
+
+	tlsConfig, err := cfg.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	transport := &http.Transport{
+		DialContext:         (&net.Dialer{Timeout: cfg.timeout}).DialContext,
+		TLSClientConfig:     tlsConfig,
+		TLSHandshakeTimeout: cfg.timeout,
+		DisableKeepAlives:   true,
+		ForceAttemptHTTP2:   true,
+	}
+	defer transport.CloseIdleConnections()
+	client := &http.Client{Transport: transport}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if len(body) > maxBodySize {
+		return nil, fmt.Errorf("response body exceeds %d bytes", maxBodySize)
+	}
+
+	return &scrapeResult{
+		body:        body,
+		contentType: resp.Header.Get("Content-Type"),
+		duration:    time.Since(start),
+	}, nil
+}
+
+// tlsConfig 构建 TLS 配置，每次抓取重新读取证书文件以便证书轮换后生效
+func (cfg *scrapeConfig) tlsConfig() (*tls.Config, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: cfg.tlsSkipVerify,
+		ServerName:         cfg.tlsServerName,
+	}
+
+	if cfg.tlsCAFile != "" {
+		pem, err := os.ReadFile(cfg.tlsCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tls_ca_file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in tls_ca_file %s", cfg.tlsCAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if cfg.tlsCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.tlsCertFile, cfg.tlsKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
@@ -0,0 +1,560 @@
+package snmp
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/celestial/orbital-sentinels/internal/plugin"
+	"github.com/gosnmp/gosnmp"
+)
+
+// walker SNMP 查询接口
+type walker interface {
+	walk(oid string) ([]gosnmp.SnmpPDU, error)
+	get(oids []string) ([]gosnmp.SnmpPDU, error)
+}
+
+// snmpWalker 基于 gosnmp 的查询实现
+type snmpWalker struct {
+	client *gosnmp.GoSNMP
+}
+
+func (w *snmpWalker) walk(oid string) ([]gosnmp.SnmpPDU, error) {
+	// SNMPv1 不支持 GetBulk
+	if w.client.Version == gosnmp.Version1 {
+		return w.client.WalkAll(oid)
+	}
+	return w.client.BulkWalkAll(oid)
+}
+
+func (w *snmpWalker) get(oids []string) ([]gosnmp.SnmpPDU, error) {
+	var pdus []gosnmp.SnmpPDU
+	for start := 0; start < len(oids); start += gosnmp.MaxOids {
+		end := start + gosnmp.MaxOids
+		if end > len(oids) {
+			end = len(oids)
+		}
+		result, err := w.client.Get(oids[start:end])
+		if err != nil {
+			return nil, err
+		}
+		pdus = append(pdus, result.Variables...)
+	}
+	return pdus, nil
+}
+
+// table 表查询结果，行按索引出现的顺序排列
+type table struct {
+	indexes []string
+	rows    map[string]map[string]gosnmp.SnmpPDU // 索引 -> 列 OID -> 值
+}
+
+// collector 单次采集的上下文
+type collector struct {
+	w       walker
+	labels  map[string]string // 所有指标共有的标签
+	now     time.Time
+	uptime  time.Duration // 设备 sysUpTime，为 0 表示未知
+	rates   *rateTracker
+	series  string // 计数器速率的序列前缀
+	metrics []*plugin.Metric
+}
+
+// loadUptime 读取设备 sysUpTime，用于判断计数器减小是否因设备重启，读取失败时视为未知
+func (c *collector) loadUptime() {
+	pdus, err := c.w.get([]string{sysUpTimeOID})
+	if err != nil || len(pdus) == 0 || pdus[0].Type != gosnmp.TimeTicks {
+		return
+	}
+	if ticks, ok := pduCounter(pdus[0]); ok {
+		c.uptime = time.Duration(ticks) * 10 * time.Millisecond
+	}
+}
+
+// walkColumns 遍历多个列并按行索引合并
+func (c *collector) walkColumns(columns []string) (*table, error) {
+	t := &table{rows: make(map[string]map[string]gosnmp.SnmpPDU)}
+
+	for _, column := range columns {
+		column = normalizeOID(column)
+		pdus, err := c.w.walk(column)
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk %s: %w", column, err)
+		}
+		prefix := column + "."
+		for _, pdu := range pdus {
+			name := normalizeOID(pdu.Name)
+			if !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			index := name[len(prefix):]
+			row, ok := t.rows[index]
+			if !ok {
+				row = make(map[string]gosnmp.SnmpPDU)
+				t.rows[index] = row
+				t.indexes = append(t.indexes, index)
+			}
+			row[column] = pdu
+		}
+	}
+
+	return t, nil
+}
+
+// collectScalars 查询单个值的指标，OID 需要包含实例号（如 .0）
+func (c *collector) collectScalars(defs []metricDef) error {
+	if len(defs) == 0 {
+		return nil
+	}
+
+	oids := make([]string, len(defs))
+	for i, def := range defs {
+		oids[i] = normalizeOID(def.OID)
+	}
+	pdus, err := c.w.get(oids)
+	if err != nil {
+		return err
+	}
+
+	byOID := make(map[string]gosnmp.SnmpPDU, len(pdus))
+	for _, pdu := range pdus {
+		byOID[normalizeOID(pdu.Name)] = pdu
+	}
+	for _, def := range defs {
+		if pdu, ok := byOID[normalizeOID(def.OID)]; ok {
+			c.addValue(def, pdu, nil, "", 0)
+		}
+	}
+	return nil
+}
+
+// collectTable 按表定义采集
+func (c *collector) collectTable(def tableDef) error {
+	columns := make([]string, 0, len(def.Metrics)+len(def.Labels))
+	for _, m := range def.Metrics {
+		columns = append(columns, normalizeOID(m.OID))
+	}
+	for _, oid := range def.Labels {
+		columns = append(columns, normalizeOID(oid))
+	}
+	if def.Speed != nil {
+		columns = append(columns, normalizeOID(def.Speed.OID))
+	}
+
+	t, err := c.walkColumns(columns)
+	if err != nil {
+		return err
+	}
+
+	indexLabel := def.IndexLabel
+	if indexLabel == "" {
+		indexLabel = "index"
+	}
+
+	for _, index := range t.indexes {
+		row := t.rows[index]
+
+		if def.SkipEmptyRows && rowEmpty(row, def.Metrics) {
+			continue
+		}
+
+		labels := map[string]string{indexLabel: index}
+		for name, oid := range def.Labels {
+			if pdu, ok := row[normalizeOID(oid)]; ok {
+				labels[name] = pduString(pdu)
+			}
+		}
+
+		// 行内计数器（字节、包、错误数）每秒不超过接口速率对应的字节数，留一倍余量容忍采样时间误差
+		var maxRate float64
+		if def.Speed != nil {
+			if bps, ok := pduFloat(row[normalizeOID(def.Speed.OID)]); ok {
+				maxRate = bps * scale(def.Speed.Scale) / 8 * 2
+			}
+		}
+
+		for _, m := range def.Metrics {
+			if pdu, ok := row[normalizeOID(m.OID)]; ok {
+				c.addValue(m, pdu, labels, index, maxRate)
+			}
+		}
+	}
+
+	return nil
+}
+
+// addValue 根据指标定义添加指标，计数器同时计算速率
+// maxRate 为计数器每秒增量的上限，为 0 表示未知
+func (c *collector) addValue(def metricDef, pdu gosnmp.SnmpPDU, labels map[string]string, index string, maxRate float64) {
+	if def.Type == string(plugin.MetricTypeCounter) {
+		value, ok := pduCounter(pdu)
+		if !ok {
+			return
+		}
+		c.add(def.Name, float64(value)*scale(def.Scale), plugin.MetricTypeCounter, labels)
+
+		series := c.series + "/" + def.Name + "/" + index
+		sample := counterSample{value: value, at: c.now, uptime: c.uptime}
+		if rate, ok := c.rates.rate(series, sample, pdu.Type == gosnmp.Counter32, maxRate); ok {
+			c.add(def.Name+"_rate", rate*scale(def.Scale), plugin.MetricTypeGauge, labels)
+		}
+		return
+	}
+
+	value, ok := pduFloat(pdu)
+	if !ok {
+		return
+	}
+	c.add(def.Name, value*scale(def.Scale), plugin.MetricTypeGauge, labels)
+}
+
+// add 添加指标，合并公共标签
+func (c *collector) add(name string, value float64, metricType plugin.MetricType, labels map[string]string) {
+	merged := make(map[string]string, len(c.labels)+len(labels))
+	for k, v := range c.labels {
+		merged[k] = v
+	}
+	for k, v := range labels {
+		merged[k] = v
+	}
+
+	c.metrics = append(c.metrics, &plugin.Metric{
+		Name:      name,
+		Value:     value,
+		Timestamp: c.now.Unix(),
+		Labels:    merged,
+		Type:      metricType,
+	})
+}
+
+// HOST-RESOURCES-MIB hrStorageTable
+const (
+	hrStorageType       = "1.3.6.1.2.1.25.2.3.1.2"
+	hrStorageDescr      = "1.3.6.1.2.1.25.2.3.1.3"
+	hrStorageAllocUnits = "1.3.6.1.2.1.25.2.3.1.4"
+	hrStorageSize       = "1.3.6.1.2.1.25.2.3.1.5"
+	hrStorageUsed       = "1.3.6.1.2.1.25.2.3.1.6"
+	hrStorageTypes      = "1.3.6.1.2.1.25.2.1."
+)
+
+// storageTypes hrStorageType 取值
+var storageTypes = map[string]string{
+	"2":  "ram",
+	"3":  "virtual_memory",
+	"4":  "fixed_disk",
+	"5":  "removable_disk",
+	"6":  "floppy_disk",
+	"7":  "compact_disc",
+	"8":  "ram_disk",
+	"9":  "flash_memory",
+	"10": "network_disk",
+}
+
+// collectStorage 采集存储容量，物理内存同时输出内存指标
+func collectStorage(c *collector) error {
+	t, err := c.walkColumns([]string{hrStorageType, hrStorageDescr, hrStorageAllocUnits, hrStorageSize, hrStorageUsed})
+	if err != nil {
+		return err
+	}
+
+	for _, index := range t.indexes {
+		row := t.rows[index]
+		units, ok1 := pduFloat(row[hrStorageAllocUnits])
+		size, ok2 := pduFloat(row[hrStorageSize])
+		used, ok3 := pduFloat(row[hrStorageUsed])
+		if !ok1 || !ok2 || !ok3 {
+			continue
+		}
+
+		storageType := "other"
+		if pdu, ok := row[hrStorageType]; ok {
+			if name, ok := storageTypes[strings.TrimPrefix(normalizeOID(pduString(pdu)), hrStorageTypes)]; ok {
+				storageType = name
+			}
+		}
+
+		labels := map[string]string{
+			"storage":      pduString(row[hrStorageDescr]),
+			"storage_type": storageType,
+			"index":        index,
+		}
+		total, usedBytes := size*units, used*units
+
+		c.add("snmp_storage_size_bytes", total, plugin.MetricTypeGauge, labels)
+		c.add("snmp_storage_used_bytes", usedBytes, plugin.MetricTypeGauge, labels)
+		if total > 0 {
+			c.add("snmp_storage_usage", usedBytes/total*100, plugin.MetricTypeGauge, labels)
+		}
+
+		if storageType == "ram" {
+			c.add("snmp_memory_total_bytes", total, plugin.MetricTypeGauge, labels)
+			c.add("snmp_memory_used_bytes", usedBytes, plugin.MetricTypeGauge, labels)
+			if total > 0 {
+				c.add("snmp_memory_usage", usedBytes/total*100, plugin.MetricTypeGauge, labels)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ENTITY-SENSOR-MIB entPhySensorTable
+const (
+	entPhySensorType       = "1.3.6.1.2.1.99.1.1.1.1"
+	entPhySensorScale      = "1.3.6.1.2.1.99.1.1.1.2"
+	entPhySensorPrecision  = "1.3.6.1.2.1.99.1.1.1.3"
+	entPhySensorValue      = "1.3.6.1.2.1.99.1.1.1.4"
+	entPhySensorOperStatus = "1.3.6.1.2.1.99.1.1.1.5"
+)
+
+// sensorMetrics entPhySensorType 对应的指标名
+var sensorMetrics = map[int]string{
+	3:  "snmp_sensor_voltage_volts", // voltsAC
+	4:  "snmp_sensor_voltage_volts", // voltsDC
+	5:  "snmp_sensor_current_amperes",
+	6:  "snmp_sensor_power_watts",
+	7:  "snmp_sensor_frequency_hertz",
+	8:  "snmp_sensor_temperature_celsius",
+	9:  "snmp_sensor_humidity_percent",
+	10: "snmp_sensor_fan_rpm",
+	11: "snmp_sensor_airflow_cmm",
+	12: "snmp_sensor_state", // truthvalue
+}
+
+// collectSensors 采集传感器，按类型输出指标并换算单位
+func collectSensors(c *collector) error {
+	t, err := c.walkColumns([]string{entPhySensorType, entPhySensorScale, entPhySensorPrecision, entPhySensorValue, entPhySensorOperStatus})
+	if err != nil {
+		return err
+	}
+	if len(t.indexes) == 0 {
+		return nil
+	}
+
+	names, err := c.walkColumns([]string{entPhysicalName})
+	if err != nil {
+		return err
+	}
+
+	for _, index := range t.indexes {
+		row := t.rows[index]
+
+		// operStatus: 1=ok, 2=unavailable, 3=nonoperational
+		if status, ok := pduFloat(row[entPhySensorOperStatus]); ok && status != 1 {
+			continue
+		}
+		raw, ok := pduFloat(row[entPhySensorValue])
+		if !ok {
+			continue
+		}
+		sensorType, _ := pduFloat(row[entPhySensorType])
+		scaleCode, ok := pduFloat(row[entPhySensorScale])
+		if !ok {
+			scaleCode = 9 // units
+		}
+		precision, _ := pduFloat(row[entPhySensorPrecision])
+
+		name, ok := sensorMetrics[int(sensorType)]
+		if !ok {
+			name = "snmp_sensor_value"
+		}
+
+		labels := map[string]string{
+			"entPhysicalIndex": index,
+			"sensor":           index,
+		}
+		if pdu, ok := names.rows[index][entPhysicalName]; ok && pduString(pdu) != "" {
+			labels["sensor"] = pduString(pdu)
+		}
+
+		c.add(name, sensorValue(raw, int(scaleCode), int(precision)), plugin.MetricTypeGauge, labels)
+	}
+
+	return nil
+}
+
+// sensorValue 按 entPhySensorScale 和 entPhySensorPrecision 换算传感器读数
+// scale 取值 1(yocto) 到 17(yotta)，9 表示单位本身，每级相差 10^3
+func sensorValue(raw float64, scaleCode, precision int) float64 {
+	return raw * math.Pow10((scaleCode-9)*3) / math.Pow10(precision)
+}
+
+// CISCO-MEMORY-POOL-MIB ciscoMemoryPoolTable
+const (
+	ciscoMemoryPoolName = "1.3.6.1.4.1.9.9.48.1.1.1.2"
+	ciscoMemoryPoolUsed = "1.3.6.1.4.1.9.9.48.1.1.1.5"
+	ciscoMemoryPoolFree = "1.3.6.1.4.1.9.9.48.1.1.1.6"
+)
+
+// collectCiscoMemory 采集 Cisco 内存池，计算使用率
+func collectCiscoMemory(c *collector) error {
+	t, err := c.walkColumns([]string{ciscoMemoryPoolName, ciscoMemoryPoolUsed, ciscoMemoryPoolFree})
+	if err != nil {
+		return err
+	}
+
+	for _, index := range t.indexes {
+		row := t.rows[index]
+		used, ok1 := pduFloat(row[ciscoMemoryPoolUsed])
+		free, ok2 := pduFloat(row[ciscoMemoryPoolFree])
+		if !ok1 || !ok2 {
+			continue
+		}
+
+		labels := map[string]string{
+			"pool":  pduString(row[ciscoMemoryPoolName]),
+			"index": index,
+		}
+		c.add("snmp_memory_total_bytes", used+free, plugin.MetricTypeGauge, labels)
+		c.add("snmp_memory_used_bytes", used, plugin.MetricTypeGauge, labels)
+		if used+free > 0 {
+			c.add("snmp_memory_usage", used/(used+free)*100, plugin.MetricTypeGauge, labels)
+		}
+	}
+
+	return nil
+}
+
+// rateStaleAfter 超过该时间未更新的计数器样本被清理
+const rateStaleAfter = time.Hour
+
+// rateTracker 记录计数器的上次采样，用于计算速率
+type rateTracker struct {
+	mu      sync.Mutex
+	samples map[string]counterSample
+}
+
+type counterSample struct {
+	value  uint64
+	at     time.Time
+	uptime time.Duration // 采样时的设备 sysUpTime，为 0 表示未知
+}
+
+func newRateTracker() *rateTracker {
+	return &rateTracker{samples: make(map[string]counterSample)}
+}
+
+// rate 记录新样本并返回与上次样本之间的每秒速率，首次采样或计数器重置时返回 false
+// maxRate 为每秒增量的上限，为 0 表示未知。
+func (r *rateTracker) rate(series string, sample counterSample, counter32 bool, maxRate float64) (float64, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	prev, ok := r.samples[series]
+	r.samples[series] = sample
+	if !ok {
+		return 0, false
+	}
+
+	elapsed := sample.at.Sub(prev.at).Seconds()
+	if elapsed <= 0 {
+		return 0, false
+	}
+
+	if sample.value >= prev.value {
+		return float64(sample.value-prev.value) / elapsed, true
+	}
+
+	// 64 位计数器减小视为重置；32 位计数器在设备重启过或回绕后的速率不合理时视为重置，否则按回绕计算
+	if !counter32 || rebooted(prev, sample) {
+		return 0, false
+	}
+	rate := float64(sample.value+(math.MaxUint32+1)-prev.value) / elapsed
+	if maxRate > 0 && rate > maxRate {
+		return 0, false
+	}
+	return rate, true
+}
+
+// rebooted 根据 sysUpTime 判断两次采样之间设备是否重启过，sysUpTime 未知时返回 false
+func rebooted(prev, cur counterSample) bool {
+	if cur.uptime == 0 {
+		return false
+	}
+	return cur.uptime < prev.uptime || cur.uptime < cur.at.Sub(prev.at)
+}
+
+// prune 清理长时间未更新的样本（设备或接口已删除）
+func (r *rateTracker) prune(now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for series, sample := range r.samples {
+		if now.Sub(sample.at) > rateStaleAfter {
+			delete(r.samples, series)
+		}
+	}
+}
+
+// rowEmpty 判断行中的指标是否都为 0
+func rowEmpty(row map[string]gosnmp.SnmpPDU, metrics []metricDef) bool {
+	for _, m := range metrics {
+		if v, ok := pduFloat(row[normalizeOID(m.OID)]); ok && v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// pduCounter 返回计数器的值
+func pduCounter(pdu gosnmp.SnmpPDU) (uint64, bool) {
+	switch pdu.Type {
+	case gosnmp.Counter32, gosnmp.Counter64, gosnmp.Gauge32, gosnmp.Uinteger32, gosnmp.TimeTicks, gosnmp.Integer:
+		v := gosnmp.ToBigInt(pdu.Value)
+		if v.Sign() < 0 {
+			return 0, false
+		}
+		return v.Uint64(), true
+	default:
+		return 0, false
+	}
+}
+
+// pduFloat 返回数值，部分设备以字符串返回数值
+func pduFloat(pdu gosnmp.SnmpPDU) (float64, bool) {
+	switch pdu.Type {
+	case gosnmp.Counter32, gosnmp.Counter64, gosnmp.Gauge32, gosnmp.Uinteger32, gosnmp.TimeTicks, gosnmp.Integer:
+		f, _ := new(big.Float).SetInt(gosnmp.ToBigInt(pdu.Value)).Float64()
+		return f, true
+	case gosnmp.OctetString:
+		b, ok := pdu.Value.([]byte)
+		if !ok {
+			return 0, false
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(string(b)), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// pduString 返回字符串值，用作标签
+func pduString(pdu gosnmp.SnmpPDU) string {
+	switch v := pdu.Value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return strings.TrimRight(string(v), "\x00")
+	case string:
+		return v
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// normalizeOID 去掉 OID 开头的点
+func normalizeOID(oid string) string {
+	return strings.TrimPrefix(strings.TrimSpace(oid), ".")
+}
+
+func scale(s float64) float64 {
+	if s == 0 {
+		return 1
+	}
+	return s
+}
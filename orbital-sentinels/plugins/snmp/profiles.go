@@ -0,0 +1,194 @@
+package snmp
+
+import "strings"
+
+// metricDef 指标定义
+type metricDef struct {
+	Name  string  `json:"name"`
+	OID   string  `json:"oid"`
+	Type  string  `json:"type"`  // gauge, counter；counter 同时输出 <name>_rate 每秒速率
+	Scale float64 `json:"scale"` // 乘数，为 0 时不缩放
+}
+
+// tableDef 表定义，按行输出指标，列 OID 之后的部分作为行索引
+type tableDef struct {
+	Name          string            `json:"name"`
+	IndexLabel    string            `json:"index_label"` // 行索引的标签名，默认 index
+	Labels        map[string]string `json:"labels"`      // 标签名 -> 列 OID，可以是索引相同的其他表
+	Metrics       []metricDef       `json:"metrics"`
+	SkipEmptyRows bool              `json:"skip_empty_rows"` // 跳过所有指标都为 0 的行（如厂商实体表中的端口）
+	// Speed 行的速率上限（乘以 scale 后为 bit/s），32 位计数器减小后按回绕计算的速率超过上限时视为重置
+	Speed *metricDef `json:"speed"`
+}
+
+// module 一组采集定义
+type module struct {
+	scalars []metricDef
+	tables  []tableDef
+	// collect 需要跨列计算的采集（如存储容量、传感器精度）
+	collect func(c *collector) error
+}
+
+// 通用模块名
+const (
+	moduleInterfaces    = "interfaces"
+	moduleHostResources = "host_resources"
+	moduleEntitySensors = "entity_sensors"
+)
+
+// defaultModules 未指定 modules 时采集的通用模块
+var defaultModules = []string{moduleInterfaces, moduleHostResources, moduleEntitySensors}
+
+// entPhysicalName ENTITY-MIB 实体名称列，厂商实体表使用相同的索引
+const entPhysicalName = "1.3.6.1.2.1.47.1.1.1.1.7"
+
+// interfacesModule IF-MIB 接口指标，SNMPv1 不支持 64 位计数器
+func interfacesModule(v1 bool) *module {
+	inOctets, outOctets := "1.3.6.1.2.1.31.1.1.1.6", "1.3.6.1.2.1.31.1.1.1.10"
+	inPkts, outPkts := "1.3.6.1.2.1.31.1.1.1.7", "1.3.6.1.2.1.31.1.1.1.11"
+	speed := &metricDef{OID: "1.3.6.1.2.1.31.1.1.1.15", Scale: 1e6} // ifHighSpeed，Mbps
+	if v1 {
+		inOctets, outOctets = "1.3.6.1.2.1.2.2.1.10", "1.3.6.1.2.1.2.2.1.16"
+		inPkts, outPkts = "1.3.6.1.2.1.2.2.1.11", "1.3.6.1.2.1.2.2.1.17"
+		speed = &metricDef{OID: "1.3.6.1.2.1.2.2.1.5"} // ifSpeed，bit/s
+	}
+
+	return &module{
+		tables: []tableDef{{
+			Name:       "ifTable",
+			IndexLabel: "ifIndex",
+			Labels: map[string]string{
+				"ifName":  "1.3.6.1.2.1.31.1.1.1.1",
+				"ifAlias": "1.3.6.1.2.1.31.1.1.1.18",
+				"ifDescr": "1.3.6.1.2.1.2.2.1.2",
+			},
+			Metrics: []metricDef{
+				{Name: "snmp_if_in_octets", OID: inOctets, Type: "counter"},
+				{Name: "snmp_if_out_octets", OID: outOctets, Type: "counter"},
+				{Name: "snmp_if_in_packets", OID: inPkts, Type: "counter"},
+				{Name: "snmp_if_out_packets", OID: outPkts, Type: "counter"},
+				{Name: "snmp_if_in_errors", OID: "1.3.6.1.2.1.2.2.1.14", Type: "counter"},
+				{Name: "snmp_if_out_errors", OID: "1.3.6.1.2.1.2.2.1.20", Type: "counter"},
+				{Name: "snmp_if_in_discards", OID: "1.3.6.1.2.1.2.2.1.13", Type: "counter"},
+				{Name: "snmp_if_out_discards", OID: "1.3.6.1.2.1.2.2.1.19", Type: "counter"},
+				{Name: "snmp_if_admin_status", OID: "1.3.6.1.2.1.2.2.1.7", Type: "gauge"},
+				{Name: "snmp_if_oper_status", OID: "1.3.6.1.2.1.2.2.1.8", Type: "gauge"},
+				{Name: "snmp_if_speed_mbps", OID: "1.3.6.1.2.1.31.1.1.1.15", Type: "gauge"},
+			},
+			Speed: speed,
+		}},
+	}
+}
+
+// hostResourcesModule HOST-RESOURCES-MIB CPU、内存和存储
+func hostResourcesModule() *module {
+	return &module{
+		tables: []tableDef{{
+			Name:       "hrProcessorTable",
+			IndexLabel: "cpu",
+			Metrics: []metricDef{
+				{Name: "snmp_cpu_usage", OID: "1.3.6.1.2.1.25.3.3.1.2", Type: "gauge"},
+			},
+		}},
+		collect: collectStorage,
+	}
+}
+
+// entitySensorsModule ENTITY-SENSOR-MIB 传感器
+func entitySensorsModule() *module {
+	return &module{collect: collectSensors}
+}
+
+// 厂商 Profile 输出与通用模块相同的指标名，便于跨厂商统一展示
+var vendorProfiles = map[string]*module{
+	"cisco": {
+		tables: []tableDef{
+			{
+				Name:       "cpmCPUTotalTable",
+				IndexLabel: "cpu",
+				Metrics: []metricDef{
+					{Name: "snmp_cpu_usage", OID: "1.3.6.1.4.1.9.9.109.1.1.1.1.7", Type: "gauge"},      // cpmCPUTotal1minRev
+					{Name: "snmp_cpu_usage_5min", OID: "1.3.6.1.4.1.9.9.109.1.1.1.1.8", Type: "gauge"}, // cpmCPUTotal5minRev
+				},
+			},
+			{
+				Name:       "ciscoEnvMonTemperatureStatusTable",
+				IndexLabel: "index",
+				Labels:     map[string]string{"sensor": "1.3.6.1.4.1.9.9.13.1.3.1.2"},
+				Metrics: []metricDef{
+					{Name: "snmp_temperature_celsius", OID: "1.3.6.1.4.1.9.9.13.1.3.1.3", Type: "gauge"},
+				},
+			},
+		},
+		collect: collectCiscoMemory,
+	},
+	"huawei": {
+		tables: []tableDef{{
+			Name:          "hwEntityStateTable",
+			IndexLabel:    "entPhysicalIndex",
+			Labels:        map[string]string{"entity": entPhysicalName},
+			SkipEmptyRows: true,
+			Metrics: []metricDef{
+				{Name: "snmp_cpu_usage", OID: "1.3.6.1.4.1.2011.5.25.31.1.1.1.1.5", Type: "gauge"},
+				{Name: "snmp_memory_usage", OID: "1.3.6.1.4.1.2011.5.25.31.1.1.1.1.7", Type: "gauge"},
+				{Name: "snmp_temperature_celsius", OID: "1.3.6.1.4.1.2011.5.25.31.1.1.1.1.11", Type: "gauge"},
+			},
+		}},
+	},
+	"h3c": {
+		tables: []tableDef{{
+			Name:          "hh3cEntityExtStateTable",
+			IndexLabel:    "entPhysicalIndex",
+			Labels:        map[string]string{"entity": entPhysicalName},
+			SkipEmptyRows: true,
+			Metrics: []metricDef{
+				{Name: "snmp_cpu_usage", OID: "1.3.6.1.4.1.25506.2.6.1.1.1.1.6", Type: "gauge"},
+				{Name: "snmp_memory_usage", OID: "1.3.6.1.4.1.25506.2.6.1.1.1.1.8", Type: "gauge"},
+				{Name: "snmp_temperature_celsius", OID: "1.3.6.1.4.1.25506.2.6.1.1.1.1.12", Type: "gauge"},
+			},
+		}},
+	},
+	"juniper": {
+		tables: []tableDef{{
+			Name:          "jnxOperatingTable",
+			IndexLabel:    "index",
+			Labels:        map[string]string{"component": "1.3.6.1.4.1.2636.3.1.13.1.5"},
+			SkipEmptyRows: true,
+			Metrics: []metricDef{
+				{Name: "snmp_cpu_usage", OID: "1.3.6.1.4.1.2636.3.1.13.1.8", Type: "gauge"},
+				{Name: "snmp_memory_usage", OID: "1.3.6.1.4.1.2636.3.1.13.1.11", Type: "gauge"},
+				{Name: "snmp_temperature_celsius", OID: "1.3.6.1.4.1.2636.3.1.13.1.7", Type: "gauge"},
+			},
+		}},
+	},
+}
+
+// profileFor 根据设备类型返回厂商 Profile 名称，与 LLDP 插件识别的设备类型一致
+func profileFor(deviceType string) string {
+	switch strings.ToLower(deviceType) {
+	case "cisco", "ios", "ios-xe":
+		return "cisco"
+	case "huawei", "vrp":
+		return "huawei"
+	case "h3c", "comware":
+		return "h3c"
+	case "juniper", "junos":
+		return "juniper"
+	default:
+		return ""
+	}
+}
+
+// lookupModule 根据名称返回模块，可以是通用模块或厂商 Profile
+func lookupModule(name string, v1 bool) (*module, bool) {
+	switch name {
+	case moduleInterfaces:
+		return interfacesModule(v1), true
+	case moduleHostResources:
+		return hostResourcesModule(), true
+	case moduleEntitySensors:
+		return entitySensorsModule(), true
+	}
+	m, ok := vendorProfiles[name]
+	return m, ok
+}
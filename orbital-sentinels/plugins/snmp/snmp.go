@@ -0,0 +1,485 @@
+package snmp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/celestial/orbital-sentinels/internal/plugin"
+	"github.com/celestial/orbital-sentinels/sdk"
+	"github.com/gosnmp/gosnmp"
+	"go.uber.org/zap"
+)
+
+// sysUpTimeOID sysUpTime，用于测试连接和判断设备是否重启
+const sysUpTimeOID = "1.3.6.1.2.1.1.3.0"
+
+// SNMPPlugin SNMP 指标采集插件
+type SNMPPlugin struct {
+	sdk.BasePlugin
+	schema plugin.PluginSchema
+	rates  *rateTracker
+}
+
+// NewPlugin 创建插件实例
+func NewPlugin() plugin.Plugin {
+	return &SNMPPlugin{rates: newRateTracker()}
+}
+
+// Meta 返回插件元信息
+func (p *SNMPPlugin) Meta() plugin.PluginMeta {
+	return p.schema.Meta
+}
+
+// Schema 返回配置 Schema
+func (p *SNMPPlugin) Schema() plugin.PluginSchema {
+	return p.schema
+}
+
+// Init 初始化插件
+func (p *SNMPPlugin) Init(config map[string]interface{}) error {
+	p.schema = plugin.PluginSchema{
+		Meta: plugin.PluginMeta{
+			Name:        "snmp",
+			Version:     "1.0.0",
+			Description: "SNMP 指标采集插件（接口、CPU、内存、存储、传感器）",
+			Author:      "Celestial Team",
+			DeviceTypes: []string{"switch", "router", "firewall", "server", "network_device"},
+		},
+		DeviceFields: []plugin.DeviceField{
+			{
+				Name:        "host",
+				Type:        "string",
+				Required:    true,
+				Description: "设备 IP 地址或主机名",
+			},
+			{
+				Name:        "port",
+				Type:        "int",
+				Required:    false,
+				Default:     161,
+				Description: "SNMP 端口",
+			},
+			{
+				Name:        "snmp_version",
+				Type:        "string",
+				Required:    false,
+				Default:     "2c",
+				Description: "SNMP 版本 (1/2c/3)",
+			},
+			{
+				Name:        "snmp_community",
+				Type:        "string",
+				Required:    false,
+				Default:     "public",
+				Description: "SNMP Community (v1/v2c，未配置 auth 时使用)",
+			},
+			{
+				Name:        "timeout",
+				Type:        "int",
+				Required:    false,
+				Default:     10,
+				Description: "单次请求超时时间（秒）",
+				Min:         1,
+				Max:         60,
+			},
+			{
+				Name:        "retries",
+				Type:        "int",
+				Required:    false,
+				Default:     2,
+				Description: "请求重试次数",
+			},
+			{
+				Name:        "modules",
+				Type:        "list",
+				Required:    false,
+				Description: "采集模块 (interfaces/host_resources/entity_sensors/cisco/huawei/h3c/juniper)，默认通用模块加设备类型对应的厂商 Profile",
+			},
+			{
+				Name:        "profile",
+				Type:        "string",
+				Required:    false,
+				Description: "厂商 Profile (cisco/huawei/h3c/juniper)，默认根据 device_type 选择",
+			},
+			{
+				Name:        "oids",
+				Type:        "list",
+				Required:    false,
+				Description: "自定义标量 OID 列表 [{name, oid, type, scale}]",
+			},
+			{
+				Name:        "tables",
+				Type:        "list",
+				Required:    false,
+				Description: "自定义表 [{name, index_label, labels, metrics, skip_empty_rows}]",
+			},
+		},
+	}
+
+	return nil
+}
+
+// ValidateConfig 验证设备配置
+func (p *SNMPPlugin) ValidateConfig(deviceConfig map[string]interface{}) error {
+	if p.getString(deviceConfig, "host", "") == "" {
+		return fmt.Errorf("host is required")
+	}
+
+	version := p.getString(deviceConfig, "snmp_version", "2c")
+	switch version {
+	case "1", "2c":
+	case "3":
+		if p.getString(p.authConfig(deviceConfig), "username", "") == "" {
+			return fmt.Errorf("username is required for SNMP v3")
+		}
+	default:
+		return fmt.Errorf("unsupported SNMP version: %s", version)
+	}
+
+	_, err := p.loadModules(deviceConfig)
+	return err
+}
+
+// TestConnection 测试连接
+func (p *SNMPPlugin) TestConnection(deviceConfig map[string]interface{}) error {
+	client, err := p.connect(context.Background(), deviceConfig)
+	if err != nil {
+		return err
+	}
+	defer client.Conn.Close()
+
+	result, err := client.Get([]string{sysUpTimeOID})
+	if err != nil {
+		return fmt.Errorf("failed to get sysUpTime: %w", err)
+	}
+	if len(result.Variables) == 0 || result.Variables[0].Type == gosnmp.NoSuchObject {
+		return fmt.Errorf("sysUpTime not available")
+	}
+	return nil
+}
+
+// Collect 采集数据
+func (p *SNMPPlugin) Collect(ctx context.Context, task *plugin.CollectionTask) ([]*plugin.Metric, error) {
+	modules, err := p.loadModules(task.DeviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := p.connect(ctx, task.DeviceConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Conn.Close()
+
+	host := p.getString(task.DeviceConfig, "host", "")
+	c := &collector{
+		w: &snmpWalker{client: client},
+		labels: map[string]string{
+			"device_id": task.DeviceID,
+			"host":      host,
+		},
+		now:    time.Now(),
+		rates:  p.rates,
+		series: task.TaskID + "/" + task.DeviceID + "/" + host,
+	}
+
+	return p.collect(c, modules)
+}
+
+// collect 依次采集各模块，部分模块失败时返回已采集的指标
+func (p *SNMPPlugin) collect(c *collector, modules []namedModule) ([]*plugin.Metric, error) {
+	c.loadUptime()
+
+	var errs []string
+	for _, m := range modules {
+		if err := runModule(c, m.module); err != nil {
+			p.Log().Warn("SNMP module collection failed",
+				zap.String("module", m.name),
+				zap.String("host", c.labels["host"]),
+				zap.Error(err))
+			errs = append(errs, m.name+": "+err.Error())
+		}
+	}
+	p.rates.prune(c.now)
+
+	// 全部失败时视为设备不可达
+	if len(c.metrics) == 0 && len(errs) > 0 {
+		return nil, fmt.Errorf("snmp collection failed: %s", strings.Join(errs, "; "))
+	}
+	return c.metrics, nil
+}
+
+// runModule 采集单个模块
+func runModule(c *collector, m *module) error {
+	if err := c.collectScalars(m.scalars); err != nil {
+		return err
+	}
+	for _, t := range m.tables {
+		if err := c.collectTable(t); err != nil {
+			return err
+		}
+	}
+	if m.collect != nil {
+		return m.collect(c)
+	}
+	return nil
+}
+
+// Close 关闭插件
+func (p *SNMPPlugin) Close() error {
+	return nil
+}
+
+// namedModule 带名称的模块，用于日志
+type namedModule struct {
+	name   string
+	module *module
+}
+
+// loadModules 根据配置确定采集模块
+// 未指定 modules 时采集通用模块，并根据 profile 或 device_type 追加厂商 Profile
+func (p *SNMPPlugin) loadModules(config map[string]interface{}) ([]namedModule, error) {
+	v1 := p.getString(config, "snmp_version", "2c") == "1"
+
+	names := p.getStringList(config, "modules")
+	if len(names) == 0 {
+		names = append([]string{}, defaultModules...)
+		profile := p.getString(config, "profile", "")
+		if profile == "" {
+			profile = profileFor(p.getString(config, "device_type", ""))
+		}
+		if profile != "" {
+			names = append(names, profile)
+		}
+	}
+
+	modules := make([]namedModule, 0, len(names)+1)
+	for _, name := range names {
+		m, ok := lookupModule(name, v1)
+		if !ok {
+			return nil, fmt.Errorf("unknown snmp module: %s", name)
+		}
+		modules = append(modules, namedModule{name: name, module: m})
+	}
+
+	// 自定义 OID 和表
+	custom := &module{}
+	if err := decodeDefs(config["oids"], &custom.scalars); err != nil {
+		return nil, fmt.Errorf("invalid oids: %w", err)
+	}
+	if err := decodeDefs(config["tables"], &custom.tables); err != nil {
+		return nil, fmt.Errorf("invalid tables: %w", err)
+	}
+	for _, def := range custom.scalars {
+		if err := validateMetricDef(def); err != nil {
+			return nil, fmt.Errorf("invalid oids: %w", err)
+		}
+	}
+	for _, t := range custom.tables {
+		if len(t.Metrics) == 0 {
+			return nil, fmt.Errorf("invalid tables: table %s has no metrics", t.Name)
+		}
+		for _, def := range t.Metrics {
+			if err := validateMetricDef(def); err != nil {
+				return nil, fmt.Errorf("invalid tables: %w", err)
+			}
+		}
+	}
+	if len(custom.scalars) > 0 || len(custom.tables) > 0 {
+		modules = append(modules, namedModule{name: "custom", module: custom})
+	}
+
+	return modules, nil
+}
+
+// decodeDefs 将任务配置中的列表解码为定义结构
+func decodeDefs(raw interface{}, out interface{}) error {
+	if raw == nil {
+		return nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// validateMetricDef 验证自定义指标定义
+func validateMetricDef(def metricDef) error {
+	if def.Name == "" || def.OID == "" {
+		return fmt.Errorf("name and oid are required")
+	}
+	switch def.Type {
+	case "", string(plugin.MetricTypeGauge), string(plugin.MetricTypeCounter):
+		return nil
+	default:
+		return fmt.Errorf("unsupported metric type %s for %s", def.Type, def.Name)
+	}
+}
+
+// connect 创建 SNMP 客户端并连接
+func (p *SNMPPlugin) connect(ctx context.Context, config map[string]interface{}) (*gosnmp.GoSNMP, error) {
+	host := p.getString(config, "host", "")
+	if host == "" {
+		return nil, fmt.Errorf("host is required")
+	}
+
+	client := &gosnmp.GoSNMP{
+		Target:  host,
+		Port:    uint16(p.getInt(config, "port", 161)),
+		Timeout: time.Duration(p.getInt(config, "timeout", 10)) * time.Second,
+		Retries: p.getInt(config, "retries", 2),
+		Context: ctx,
+		MaxOids: gosnmp.MaxOids,
+	}
+
+	// 认证信息优先从 auth.config 读取，与 LLDP 插件一致
+	auth := p.authConfig(config)
+	version := p.getString(config, "snmp_version", "2c")
+	switch version {
+	case "1", "2c":
+		client.Version = gosnmp.Version2c
+		if version == "1" {
+			client.Version = gosnmp.Version1
+		}
+		client.Community = p.getString(auth, "community", "")
+		if client.Community == "" {
+			client.Community = p.getString(config, "snmp_community", "public")
+		}
+	case "3":
+		client.Version = gosnmp.Version3
+		client.SecurityModel = gosnmp.UserSecurityModel
+		securityLevel := p.getString(auth, "security_level", "noAuthNoPriv")
+		client.MsgFlags = msgFlags(securityLevel)
+		client.SecurityParameters = p.usmParameters(auth, securityLevel)
+	default:
+		return nil, fmt.Errorf("unsupported SNMP version: %s", version)
+	}
+
+	if err := client.Connect(); err != nil {
+		return nil, fmt.Errorf("failed to connect to SNMP device: %w", err)
+	}
+	return client, nil
+}
+
+// authConfig 返回 auth.config 中的认证配置
+func (p *SNMPPlugin) authConfig(config map[string]interface{}) map[string]interface{} {
+	if authMap, ok := config["auth"].(map[string]interface{}); ok {
+		if configMap, ok := authMap["config"].(map[string]interface{}); ok {
+			return configMap
+		}
+	}
+	return map[string]interface{}{}
+}
+
+// usmParameters 构建 SNMPv3 USM 参数
+func (p *SNMPPlugin) usmParameters(auth map[string]interface{}, securityLevel string) *gosnmp.UsmSecurityParameters {
+	params := &gosnmp.UsmSecurityParameters{
+		UserName: p.getString(auth, "username", ""),
+	}
+
+	if securityLevel == "authNoPriv" || securityLevel == "authPriv" {
+		switch p.getString(auth, "auth_protocol", "MD5") {
+		case "SHA":
+			params.AuthenticationProtocol = gosnmp.SHA
+		case "SHA224":
+			params.AuthenticationProtocol = gosnmp.SHA224
+		case "SHA256":
+			params.AuthenticationProtocol = gosnmp.SHA256
+		case "SHA384":
+			params.AuthenticationProtocol = gosnmp.SHA384
+		case "SHA512":
+			params.AuthenticationProtocol = gosnmp.SHA512
+		default:
+			params.AuthenticationProtocol = gosnmp.MD5
+		}
+		params.AuthenticationPassphrase = p.getString(auth, "auth_password", "")
+	}
+
+	if securityLevel == "authPriv" {
+		switch p.getString(auth, "priv_protocol", "DES") {
+		case "AES":
+			params.PrivacyProtocol = gosnmp.AES
+		case "AES192":
+			params.PrivacyProtocol = gosnmp.AES192
+		case "AES256":
+			params.PrivacyProtocol = gosnmp.AES256
+		default:
+			params.PrivacyProtocol = gosnmp.DES
+		}
+		params.PrivacyPassphrase = p.getString(auth, "priv_password", "")
+	}
+
+	return params
+}
+
+// msgFlags 获取 SNMP v3 消息标志
+func msgFlags(securityLevel string) gosnmp.SnmpV3MsgFlags {
+	switch securityLevel {
+	case "authNoPriv":
+		return gosnmp.AuthNoPriv
+	case "authPriv":
+		return gosnmp.AuthPriv
+	default:
+		return gosnmp.NoAuthNoPriv
+	}
+}
+
+// getString 获取字符串配置
+func (p *SNMPPlugin) getString(config map[string]interface{}, key string, defaultValue string) string {
+	if val, ok := config[key]; ok {
+		if str, ok := val.(string); ok {
+			return str
+		}
+	}
+	return defaultValue
+}
+
+// getInt 获取整数配置
+func (p *SNMPPlugin) getInt(config map[string]interface{}, key string, defaultValue int) int {
+	if val, ok := config[key]; ok {
+		switch v := val.(type) {
+		case int:
+			return v
+		case float64:
+			return int(v)
+		case string:
+			if i, err := strconv.Atoi(v); err == nil {
+				return i
+			}
+		}
+	}
+	return defaultValue
+}
+
+// getStringList 获取字符串列表配置，支持逗号分隔的字符串
+func (p *SNMPPlugin) getStringList(config map[string]interface{}, key string) []string {
+	var list []string
+	switch v := config[key].(type) {
+	case []string:
+		list = v
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+	case string:
+		list = strings.Split(v, ",")
+	}
+
+	result := make([]string, 0, len(list))
+	for _, s := range list {
+		if s = strings.TrimSpace(s); s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// 确保实现了接口
+var _ plugin.Plugin = (*SNMPPlugin)(nil)
@@ -0,0 +1,305 @@
+package snmp
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/celestial/orbital-sentinels/internal/plugin"
+	"github.com/gosnmp/gosnmp"
+)
+
+// fakeWalker 按 OID 前缀返回预置数据
+type fakeWalker struct {
+	pdus []gosnmp.SnmpPDU
+}
+
+func (f *fakeWalker) walk(oid string) ([]gosnmp.SnmpPDU, error) {
+	var result []gosnmp.SnmpPDU
+	for _, pdu := range f.pdus {
+		if strings.HasPrefix(pdu.Name, "."+oid+".") {
+			result = append(result, pdu)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeWalker) get(oids []string) ([]gosnmp.SnmpPDU, error) {
+	var result []gosnmp.SnmpPDU
+	for _, oid := range oids {
+		for _, pdu := range f.pdus {
+			if pdu.Name == "."+oid {
+				result = append(result, pdu)
+			}
+		}
+	}
+	return result, nil
+}
+
+func pdu(oid string, t gosnmp.Asn1BER, value interface{}) gosnmp.SnmpPDU {
+	return gosnmp.SnmpPDU{Name: "." + oid, Type: t, Value: value}
+}
+
+func newTestCollector(w walker, rates *rateTracker, now time.Time) *collector {
+	return &collector{
+		w:      w,
+		labels: map[string]string{"device_id": "dev-1"},
+		now:    now,
+		rates:  rates,
+		series: "task-1",
+	}
+}
+
+// findMetric 按名称和标签查找指标
+func findMetric(metrics []*plugin.Metric, name, label, value string) *plugin.Metric {
+	for _, m := range metrics {
+		if m.Name == name && m.Labels[label] == value {
+			return m
+		}
+	}
+	return nil
+}
+
+func TestInterfaces_LabelsAndRates(t *testing.T) {
+	rates := newRateTracker()
+	start := time.Now()
+	walker := &fakeWalker{pdus: []gosnmp.SnmpPDU{
+		pdu("1.3.6.1.2.1.31.1.1.1.1.1", gosnmp.OctetString, []byte("Gi0/1")),
+		pdu("1.3.6.1.2.1.31.1.1.1.18.1", gosnmp.OctetString, []byte("uplink")),
+		pdu("1.3.6.1.2.1.31.1.1.1.6.1", gosnmp.Counter64, uint64(1000)),
+		pdu("1.3.6.1.2.1.2.2.1.8.1", gosnmp.Integer, 1),
+	}}
+
+	c := newTestCollector(walker, rates, start)
+	if err := runModule(c, interfacesModule(false)); err != nil {
+		t.Fatalf("runModule failed: %v", err)
+	}
+
+	m := findMetric(c.metrics, "snmp_if_in_octets", "ifName", "Gi0/1")
+	if m == nil || m.Value != 1000 || m.Type != plugin.MetricTypeCounter {
+		t.Fatalf("Unexpected in_octets metric: %+v", m)
+	}
+	if m.Labels["ifAlias"] != "uplink" || m.Labels["ifIndex"] != "1" || m.Labels["device_id"] != "dev-1" {
+		t.Errorf("Unexpected labels: %v", m.Labels)
+	}
+	if findMetric(c.metrics, "snmp_if_oper_status", "ifName", "Gi0/1") == nil {
+		t.Error("Expected oper status metric")
+	}
+	// 首次采集没有速率
+	if findMetric(c.metrics, "snmp_if_in_octets_rate", "ifName", "Gi0/1") != nil {
+		t.Error("Expected no rate on first collection")
+	}
+
+	walker.pdus[2].Value = uint64(3000)
+	c = newTestCollector(walker, rates, start.Add(10*time.Second))
+	if err := runModule(c, interfacesModule(false)); err != nil {
+		t.Fatalf("runModule failed: %v", err)
+	}
+	rate := findMetric(c.metrics, "snmp_if_in_octets_rate", "ifName", "Gi0/1")
+	if rate == nil || rate.Value != 200 {
+		t.Fatalf("Expected rate 200, got %+v", rate)
+	}
+}
+
+func TestInterfaces_Counter32Reboot(t *testing.T) {
+	rates := newRateTracker()
+	start := time.Now()
+	walker := &fakeWalker{pdus: []gosnmp.SnmpPDU{
+		pdu("1.3.6.1.2.1.1.3.0", gosnmp.TimeTicks, uint32(360000)),
+		pdu("1.3.6.1.2.1.2.2.1.2.1", gosnmp.OctetString, []byte("eth0")),
+		pdu("1.3.6.1.2.1.2.2.1.5.1", gosnmp.Gauge32, uint(1000000000)),
+		pdu("1.3.6.1.2.1.2.2.1.10.1", gosnmp.Counter32, uint(3000000000)),
+	}}
+	collect := func(at time.Time) *plugin.Metric {
+		c := newTestCollector(walker, rates, at)
+		c.loadUptime()
+		if err := runModule(c, interfacesModule(true)); err != nil {
+			t.Fatalf("runModule failed: %v", err)
+		}
+		return findMetric(c.metrics, "snmp_if_in_octets_rate", "ifDescr", "eth0")
+	}
+	collect(start)
+
+	// 设备在两次采集之间重启，sysUpTime 和计数器都从头开始
+	walker.pdus[0].Value = uint32(2000)
+	walker.pdus[3].Value = uint(5000)
+	if rate := collect(start.Add(time.Minute)); rate != nil {
+		t.Errorf("Expected no rate after reboot, got %v", rate.Value)
+	}
+
+	walker.pdus[0].Value = uint32(8000)
+	walker.pdus[3].Value = uint(65000)
+	if rate := collect(start.Add(2 * time.Minute)); rate == nil || rate.Value != 1000 {
+		t.Errorf("Expected rate 1000 after reboot, got %+v", rate)
+	}
+}
+
+func TestRateTracker_Wrap(t *testing.T) {
+	rates := newRateTracker()
+	now := time.Now()
+	sample := func(value uint64, at time.Time, uptime time.Duration) counterSample {
+		return counterSample{value: value, at: at, uptime: uptime}
+	}
+
+	rates.rate("c32", sample(math.MaxUint32-99, now, 0), true, 0)
+	// 32 位计数器回绕
+	if r, ok := rates.rate("c32", sample(100, now.Add(time.Second), 0), true, 0); !ok || r != 200 {
+		t.Errorf("Expected wrapped rate 200, got %v %v", r, ok)
+	}
+
+	// sysUpTime 持续增长时按回绕计算
+	rates.rate("c32-up", sample(math.MaxUint32-99, now, time.Hour), true, 0)
+	if r, ok := rates.rate("c32-up", sample(100, now.Add(time.Second), time.Hour+time.Second), true, 0); !ok || r != 200 {
+		t.Errorf("Expected wrapped rate 200, got %v %v", r, ok)
+	}
+
+	// 设备重启：sysUpTime 变小，计数器减小视为重置
+	rates.rate("c32-reboot", sample(3000000000, now, time.Hour), true, 0)
+	if _, ok := rates.rate("c32-reboot", sample(5000, now.Add(time.Minute), 30*time.Second), true, 0); ok {
+		t.Error("Expected no rate after reboot")
+	}
+	if r, ok := rates.rate("c32-reboot", sample(6000, now.Add(2*time.Minute), 90*time.Second), true, 0); !ok || r != 1000.0/60 {
+		t.Errorf("Expected rate %v after reboot, got %v %v", 1000.0/60, r, ok)
+	}
+
+	// 按回绕计算的速率超过接口速率时视为重置
+	rates.rate("c32-speed", sample(1000000, now, 0), true, 0)
+	if _, ok := rates.rate("c32-speed", sample(10, now.Add(time.Minute), 0), true, 1e6); ok {
+		t.Error("Expected no rate when wrapped delta exceeds interface speed")
+	}
+
+	rates.rate("c64", sample(1000, now, 0), false, 0)
+	// 64 位计数器减小视为重置，不输出速率
+	if _, ok := rates.rate("c64", sample(10, now.Add(time.Second), 0), false, 0); ok {
+		t.Error("Expected no rate after counter reset")
+	}
+	if r, ok := rates.rate("c64", sample(20, now.Add(2*time.Second), 0), false, 0); !ok || r != 10 {
+		t.Errorf("Expected rate 10 after reset, got %v %v", r, ok)
+	}
+
+	rates.prune(now.Add(2 * rateStaleAfter))
+	if len(rates.samples) != 0 {
+		t.Errorf("Expected stale samples pruned, got %d", len(rates.samples))
+	}
+}
+
+func TestHostResources_Storage(t *testing.T) {
+	walker := &fakeWalker{pdus: []gosnmp.SnmpPDU{
+		pdu("1.3.6.1.2.1.25.3.3.1.2.196608", gosnmp.Integer, 35),
+		pdu(hrStorageType+".1", gosnmp.ObjectIdentifier, ".1.3.6.1.2.1.25.2.1.2"),
+		pdu(hrStorageDescr+".1", gosnmp.OctetString, []byte("Physical memory")),
+		pdu(hrStorageAllocUnits+".1", gosnmp.Integer, 1024),
+		pdu(hrStorageSize+".1", gosnmp.Integer, 1000),
+		pdu(hrStorageUsed+".1", gosnmp.Integer, 250),
+	}}
+
+	c := newTestCollector(walker, newRateTracker(), time.Now())
+	if err := runModule(c, hostResourcesModule()); err != nil {
+		t.Fatalf("runModule failed: %v", err)
+	}
+
+	if m := findMetric(c.metrics, "snmp_cpu_usage", "cpu", "196608"); m == nil || m.Value != 35 {
+		t.Errorf("Unexpected cpu metric: %+v", m)
+	}
+	if m := findMetric(c.metrics, "snmp_storage_size_bytes", "storage", "Physical memory"); m == nil || m.Value != 1024000 {
+		t.Errorf("Unexpected storage size: %+v", m)
+	}
+	if m := findMetric(c.metrics, "snmp_memory_usage", "storage_type", "ram"); m == nil || m.Value != 25 {
+		t.Errorf("Unexpected memory usage: %+v", m)
+	}
+}
+
+func TestEntitySensors(t *testing.T) {
+	walker := &fakeWalker{pdus: []gosnmp.SnmpPDU{
+		// 温度 45.5℃: value=455, scale=units(9), precision=1
+		pdu(entPhySensorType+".10", gosnmp.Integer, 8),
+		pdu(entPhySensorScale+".10", gosnmp.Integer, 9),
+		pdu(entPhySensorPrecision+".10", gosnmp.Integer, 1),
+		pdu(entPhySensorValue+".10", gosnmp.Integer, 455),
+		pdu(entPhySensorOperStatus+".10", gosnmp.Integer, 1),
+		// 电压 1200mV: scale=milli(8)
+		pdu(entPhySensorType+".11", gosnmp.Integer, 4),
+		pdu(entPhySensorScale+".11", gosnmp.Integer, 8),
+		pdu(entPhySensorPrecision+".11", gosnmp.Integer, 0),
+		pdu(entPhySensorValue+".11", gosnmp.Integer, 1200),
+		pdu(entPhySensorOperStatus+".11", gosnmp.Integer, 1),
+		// 不可用的传感器被跳过
+		pdu(entPhySensorType+".12", gosnmp.Integer, 10),
+		pdu(entPhySensorValue+".12", gosnmp.Integer, 0),
+		pdu(entPhySensorOperStatus+".12", gosnmp.Integer, 2),
+		pdu(entPhysicalName+".10", gosnmp.OctetString, []byte("CPU Temp")),
+	}}
+
+	c := newTestCollector(walker, newRateTracker(), time.Now())
+	if err := runModule(c, entitySensorsModule()); err != nil {
+		t.Fatalf("runModule failed: %v", err)
+	}
+
+	if len(c.metrics) != 2 {
+		t.Fatalf("Expected 2 metrics, got %d", len(c.metrics))
+	}
+	if m := findMetric(c.metrics, "snmp_sensor_temperature_celsius", "sensor", "CPU Temp"); m == nil || m.Value != 45.5 {
+		t.Errorf("Unexpected temperature: %+v", m)
+	}
+	if m := findMetric(c.metrics, "snmp_sensor_voltage_volts", "sensor", "11"); m == nil || math.Abs(m.Value-1.2) > 1e-9 {
+		t.Errorf("Unexpected voltage: %+v", m)
+	}
+}
+
+func TestVendorProfile_SkipEmptyRows(t *testing.T) {
+	walker := &fakeWalker{pdus: []gosnmp.SnmpPDU{
+		pdu("1.3.6.1.4.1.2011.5.25.31.1.1.1.1.5.16842753", gosnmp.Integer, 12),
+		pdu("1.3.6.1.4.1.2011.5.25.31.1.1.1.1.7.16842753", gosnmp.Integer, 40),
+		pdu("1.3.6.1.4.1.2011.5.25.31.1.1.1.1.5.16842754", gosnmp.Integer, 0),
+		pdu("1.3.6.1.4.1.2011.5.25.31.1.1.1.1.7.16842754", gosnmp.Integer, 0),
+		pdu(entPhysicalName+".16842753", gosnmp.OctetString, []byte("MPU Board 1")),
+	}}
+
+	c := newTestCollector(walker, newRateTracker(), time.Now())
+	if err := runModule(c, vendorProfiles["huawei"]); err != nil {
+		t.Fatalf("runModule failed: %v", err)
+	}
+
+	// 没有 CPU 和内存数据的实体（如端口）不输出
+	if len(c.metrics) != 2 {
+		t.Fatalf("Expected 2 metrics, got %d", len(c.metrics))
+	}
+	if m := findMetric(c.metrics, "snmp_cpu_usage", "entity", "MPU Board 1"); m == nil || m.Value != 12 {
+		t.Errorf("Unexpected cpu metric: %+v", m)
+	}
+}
+
+func TestLoadModules(t *testing.T) {
+	p := NewPlugin().(*SNMPPlugin)
+
+	modules, err := p.loadModules(map[string]interface{}{"device_type": "ios-xe"})
+	if err != nil {
+		t.Fatalf("loadModules failed: %v", err)
+	}
+	if len(modules) != 4 || modules[3].name != "cisco" {
+		t.Errorf("Expected default modules with cisco profile, got %v", modules)
+	}
+
+	modules, err = p.loadModules(map[string]interface{}{
+		"modules": []interface{}{"interfaces"},
+		"oids": []interface{}{
+			map[string]interface{}{"name": "sys_uptime", "oid": "1.3.6.1.2.1.1.3.0"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("loadModules failed: %v", err)
+	}
+	if len(modules) != 2 || modules[1].name != "custom" || len(modules[1].module.scalars) != 1 {
+		t.Errorf("Expected interfaces and custom modules, got %v", modules)
+	}
+
+	if _, err := p.loadModules(map[string]interface{}{"modules": "interfaces,unknown"}); err == nil {
+		t.Error("Expected error for unknown module")
+	}
+	if _, err := p.loadModules(map[string]interface{}{
+		"tables": []interface{}{map[string]interface{}{"name": "empty"}},
+	}); err == nil {
+		t.Error("Expected error for table without metrics")
+	}
+}
@@ -47,9 +47,20 @@ func (bp *BasePlugin) ValidateConfig(deviceConfig map[string]interface{}, schema
 			}
 		}
 
-		// 范围验证
+		// 可选值验证
+		if len(field.Options) > 0 {
+			if strValue, ok := value.(string); ok && !containsString(field.Options, strValue) {
+				return fmt.Errorf("field '%s': value %q is not one of %v", field.Name, strValue, field.Options)
+			}
+		}
+
+		// 范围验证（JSON 解析的数字为 float64）
 		if field.Type == "int" {
-			if intValue, ok := value.(int); ok {
+			intValue, ok := value.(int)
+			if f, isFloat := value.(float64); isFloat {
+				intValue, ok = int(f), true
+			}
+			if ok {
 				if field.Min != 0 && intValue < field.Min {
 					return fmt.Errorf("field '%s': value %d is less than minimum %d", field.Name, intValue, field.Min)
 				}
@@ -84,6 +95,12 @@ func (bp *BasePlugin) validateFieldType(field plugin.DeviceField, value interfac
 	case "list":
 		// 允许数组或切片
 		// 这里简化处理，实际可以更严格
+	case "map":
+		switch value.(type) {
+		case map[string]interface{}, map[string]string:
+		default:
+			return fmt.Errorf("expected map, got %T", value)
+		}
 	default:
 		// 未知类型，跳过验证
 	}
@@ -91,6 +108,16 @@ func (bp *BasePlugin) validateFieldType(field plugin.DeviceField, value interfac
 	return nil
 }
 
+// containsString 判断列表是否包含指定值
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
 // Log 获取日志记录器
 func (bp *BasePlugin) Log() *zap.Logger {
 	return logger.GetLogger()
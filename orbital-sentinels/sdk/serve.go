@@ -0,0 +1,30 @@
+package sdk
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/celestial/orbital-sentinels/internal/pkg/logger"
+	"github.com/celestial/orbital-sentinels/internal/plugin"
+)
+
+// EnvLogLevel 外部插件的日志级别
+const EnvLogLevel = "CELESTIAL_PLUGIN_LOG_LEVEL"
+
+// Serve 作为外部插件运行，在插件可执行文件的 main 中调用
+// 标准输出用于与 Sentinel 通信，日志写到标准错误输出，由 Sentinel 收集。
+func Serve(p plugin.Plugin) {
+	level := os.Getenv(EnvLogLevel)
+	if level == "" {
+		level = "info"
+	}
+	if err := logger.Init(level, "json", "stderr", ""); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if err := plugin.Serve(p); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
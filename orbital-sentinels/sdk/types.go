@@ -0,0 +1,27 @@
+package sdk
+
+import "github.com/celestial/orbital-sentinels/internal/plugin"
+
+// 插件接口和数据类型的别名
+// 外部插件位于其他模块时无法导入 internal 包，通过 sdk 使用这些类型。
+type (
+	Plugin         = plugin.Plugin
+	PluginMeta     = plugin.PluginMeta
+	PluginSchema   = plugin.PluginSchema
+	DeviceField    = plugin.DeviceField
+	CollectionTask = plugin.CollectionTask
+	Metric         = plugin.Metric
+	MetricType     = plugin.MetricType
+	Histogram      = plugin.Histogram
+	Bucket         = plugin.Bucket
+	Summary        = plugin.Summary
+	Quantile       = plugin.Quantile
+)
+
+// 指标类型
+const (
+	MetricTypeGauge     = plugin.MetricTypeGauge
+	MetricTypeCounter   = plugin.MetricTypeCounter
+	MetricTypeHistogram = plugin.MetricTypeHistogram
+	MetricTypeSummary   = plugin.MetricTypeSummary
+)